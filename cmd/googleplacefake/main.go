@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/wanrun-develop/wanrun/configs"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/adapters/googleplace"
+)
+
+/*
+google places api(v1)のフェイクサーバー
+フィクスチャの場所情報を、places apiと同じJSON形式で返す
+wanrun側は google.place.base.url にこのサーバーのURL(例: http://localhost:8090/v1)を設定する
+*/
+func main() {
+	places, err := googleplace.LoadFakePlaces(
+		configs.FetchConfigStr("google.place.fake.fixture"),
+		configs.FetchConfigStr("google.place.fake.photo.uri"),
+	)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/places/{placeId}", func(w http.ResponseWriter, r *http.Request) {
+		place, ok := places.PlaceInfo(r.PathValue("placeId"))
+		if !ok {
+			writeError(w, http.StatusNotFound, "place not found")
+			return
+		}
+		writeJSON(w, place)
+	})
+	mux.HandleFunc("GET /v1/places/{placeId}/photos/{photoId}/media", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, places.Photo("places/"+r.PathValue("placeId")+"/photos/"+r.PathValue("photoId")))
+	})
+	mux.HandleFunc("POST /v1/places:searchText", func(w http.ResponseWriter, r *http.Request) {
+		payload := googleplace.SearchTextPayLoad{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := places.SearchText(payload)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, res)
+	})
+	mux.HandleFunc("POST /v1/places:searchNearby", func(w http.ResponseWriter, r *http.Request) {
+		payload := googleplace.SearchNearbyPayLoad{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, googleplace.SearchTextBaseResource{Places: places.SearchNearby(payload)})
+	})
+
+	addr := getEnv("GOOGLE_PLACE_FAKE_ADDR", ":8090")
+	log.Printf("google place fake server listening on %s", addr)
+	log.Fatalln(http.ListenAndServe(addr, mux))
+}
+
+func writeJSON(w http.ResponseWriter, res any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Println(err)
+	}
+}
+
+/*
+places apiと同じ形式のエラーレスポンス
+*/
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    status,
+			"message": message,
+		},
+	})
+}
+
+func getEnv(key string, defaultVal string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultVal
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -15,7 +16,10 @@ import (
 	"github.com/wanrun-develop/wanrun/internal"
 
 	//auth
+	"github.com/wanrun-develop/wanrun/internal/auth/adapters/google"
+	"github.com/wanrun-develop/wanrun/internal/auth/adapters/mailer"
 	authRepository "github.com/wanrun-develop/wanrun/internal/auth/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/auth/adapters/sms"
 	authController "github.com/wanrun-develop/wanrun/internal/auth/controller"
 	authFacade "github.com/wanrun-develop/wanrun/internal/auth/core/facade"
 	authHandler "github.com/wanrun-develop/wanrun/internal/auth/core/handler"
@@ -26,6 +30,7 @@ import (
 	cmsRepository "github.com/wanrun-develop/wanrun/internal/cms/adapters/repository"
 	cmsController "github.com/wanrun-develop/wanrun/internal/cms/controller"
 	cmsHandler "github.com/wanrun-develop/wanrun/internal/cms/core/handler"
+	cmsFacade "github.com/wanrun-develop/wanrun/internal/cms/facade"
 
 	//dog
 	dogRepository "github.com/wanrun-develop/wanrun/internal/dog/adapters/repository"
@@ -47,6 +52,9 @@ import (
 
 	//dogrunmg
 	dogrunmgRepository "github.com/wanrun-develop/wanrun/internal/dogrunmg/adapters/repository"
+	dogrunmgController "github.com/wanrun-develop/wanrun/internal/dogrunmg/controller"
+	dogrunmgFacade "github.com/wanrun-develop/wanrun/internal/dogrunmg/core/facade"
+	dogrunmgHandler "github.com/wanrun-develop/wanrun/internal/dogrunmg/core/handler"
 
 	//org
 	orgRepository "github.com/wanrun-develop/wanrun/internal/org/adapters/repository"
@@ -54,10 +62,12 @@ import (
 	orgHandler "github.com/wanrun-develop/wanrun/internal/org/core/handler"
 
 	//interaction
+	"github.com/wanrun-develop/wanrun/internal/interaction/adapters/eventhub"
 	interactionR "github.com/wanrun-develop/wanrun/internal/interaction/adapters/repository"
 	interactionC "github.com/wanrun-develop/wanrun/internal/interaction/controller"
 	interactionH "github.com/wanrun-develop/wanrun/internal/interaction/core/handler"
 	interactionFacade "github.com/wanrun-develop/wanrun/internal/interaction/facade"
+	interactionW "github.com/wanrun-develop/wanrun/internal/interaction/worker"
 
 	//other
 	"github.com/wanrun-develop/wanrun/internal/db"
@@ -65,6 +75,7 @@ import (
 
 	"github.com/wanrun-develop/wanrun/pkg/errors"
 	logger "github.com/wanrun-develop/wanrun/pkg/log"
+	"golang.org/x/time/rate"
 	"gorm.io/gorm"
 )
 
@@ -88,6 +99,17 @@ func Main() {
 	// アプリケーション終了時にロガーを同期
 	defer zap.Sync()
 
+	// ドッグランのイベント配信ハブ
+	eventHub := newEventHub(dbConn)
+	defer eventHub.Close()
+
+	// 自動チェックアウトのワーカー
+	if configs.FetchConfigBool("auto.checkout.enabled") {
+		workerCtx, stopWorker := context.WithCancel(context.Background())
+		defer stopWorker()
+		go newAutoCheckoutWorker(dbConn, eventHub).Run(workerCtx)
+	}
+
 	// CORSの設定
 	e.Use(middleware.CORS())
 
@@ -97,11 +119,13 @@ func Main() {
 	e.Use(logger.RequestLoggerMiddleware(zap))
 
 	// JWTミドルウェアの設定
+	// ストリーム系はクエリパラメータのトークンも受け付ける(検証は同じJWTミドルウェア)
+	e.Pre(authMW.StreamTokenFromQuery())
 	authMiddleware := newAuthMiddleware(dbConn)
 	e.Use(authMiddleware.NewJwtValidationMiddleware())
 
 	// Router設定
-	newRouter(e, dbConn)
+	newRouter(e, dbConn, eventHub)
 	e.GET("/test", internal.Test, authMW.RoleAuthorization(authMW.ALL))
 
 	// 最大リクエストボディサイズの指定
@@ -110,7 +134,10 @@ func Main() {
 	e.Logger.Fatal(e.Start(":8080"))
 }
 
-func newRouter(e *echo.Echo, dbConn *gorm.DB) {
+func newRouter(e *echo.Echo, dbConn *gorm.DB, eventHub eventhub.IEventHub) {
+	// 確認済みアカウントの認可
+	verificationMW := newVerificationMiddleware(dbConn)
+
 	// dog関連
 	dogController := newDog(dbConn)
 	dog := e.Group("dog")
@@ -122,42 +149,133 @@ func newRouter(e *echo.Echo, dbConn *gorm.DB) {
 	dog.PUT("", dogController.UpdateDog, authMW.RoleAuthorization(authMW.DOG_MANAGE))
 	dog.DELETE("", dogController.DeleteDog, authMW.RoleAuthorization(authMW.DOG_MANAGE))
 	// dog.PUT("/:dogID", dogController.UpdateDog)
+	// 予防接種証明書
+	injectionCertificationController := newInjectionCertification(dbConn)
+	dog.GET("/:dogID/vaccination", injectionCertificationController.GetCertifications, authMW.RoleAuthorization(authMW.DOG_REFER))
+	dog.POST("/:dogID/vaccination", injectionCertificationController.CreateCertification, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	dog.PUT("/vaccination/:certificationId", injectionCertificationController.ReplaceCertification, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	dog.DELETE("/vaccination/:certificationId", injectionCertificationController.DeleteCertification, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	dog.PUT("/vaccination/:certificationId/verify", injectionCertificationController.VerifyCertification, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
 
 	// dogrun関連
 	dogrunController := newDogrun(dbConn)
 	dogrun := e.Group("dogrun")
 	dogrun.GET("/detail/:placeId", dogrunController.GetDogrunDetail, authMW.RoleAuthorization(authMW.DOGRUN_REFER))
 	dogrun.GET("/:id", dogrunController.GetDogrun, authMW.RoleAuthorization(authMW.DOGRUN_REFER))
+	dogrun.GET("/:id/occupancy", dogrunController.GetDogrunOccupancy, authMW.RoleAuthorization(authMW.DOGRUN_REFER))
+	dogrun.POST("/occupancy", dogrunController.GetDogrunOccupancies, authMW.RoleAuthorization(authMW.DOGRUN_REFER))
 	dogrun.GET("/photo/src", dogrunController.GetDogrunPhoto, authMW.RoleAuthorization(authMW.DOGRUN_REFER))
 	dogrun.GET("/mst/tag", dogrunController.GetDogrunTagMst, authMW.RoleAuthorization(authMW.ALL))
 	dogrun.POST("/search", dogrunController.SearchAroundDogruns, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	dogrun.POST("/search/nearby", dogrunController.SearchNearbyDogruns, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	dogrun.PUT("/:id/profile", dogrunController.UpdateDogrunProfile, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+	dogrun.PUT("/:id/tag", dogrunController.UpdateDogrunTags, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+	dogrun.PUT("/:id/businessHour", dogrunController.UpdateDogrunBusinessHours, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+
+	dogrunImageController := newDogrunImage(dbConn)
+	dogrun.GET("/:id/image", dogrunImageController.GetDogrunImages, authMW.RoleAuthorization(authMW.DOGRUN_REFER))
+	dogrun.GET("/:id/image/manage", dogrunImageController.GetManagedDogrunImages, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+	dogrun.POST("/:id/image", dogrunImageController.AddDogrunImage, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+	dogrun.PUT("/:id/image/order", dogrunImageController.ReorderDogrunImages, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+	dogrun.PUT("/:id/image/:imageId", dogrunImageController.UpdateDogrunImageCaption, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+	dogrun.DELETE("/:id/image/:imageId", dogrunImageController.DeleteDogrunImage, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+	dogrun.PUT("/:id/image/:imageId/approve", dogrunImageController.ApproveDogrunImage, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+	dogrun.PUT("/:id/image/:imageId/reject", dogrunImageController.RejectDogrunImage, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+	dogrun.POST("/:id/image/submission", dogrunImageController.SubmitDogrunImage, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	dogrun.GET("/image/submission", dogrunImageController.GetSubmittedDogrunImages, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	dogrun.DELETE("/image/submission/:imageId", dogrunImageController.DeleteSubmittedDogrunImage, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	// ドッグランのクレーム
+	dogrunClaimController := newDogrunClaim(dbConn)
+	dogrun.POST("/claim", dogrunClaimController.SubmitClaim, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE), verificationMW.VerifiedAuthorization(authMW.DOGRUN_MANAGE))
+	dogrun.GET("/claim", dogrunClaimController.GetOrgClaims, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+	dogrun.GET("/claim/pending", dogrunClaimController.GetPendingClaims, authMW.RoleAuthorization(authMW.SYSTEM))
+	dogrun.PUT("/claim/:claimId/approve", dogrunClaimController.ApproveClaim, authMW.RoleAuthorization(authMW.SYSTEM))
+	dogrun.PUT("/claim/:claimId/reject", dogrunClaimController.RejectClaim, authMW.RoleAuthorization(authMW.SYSTEM))
 
 	// dogOwner関連
 	dogOwnerController := newDogOwner(dbConn)
 	dogOwner := e.Group("dogowner")
 	dogOwner.POST("/signUp", dogOwnerController.DogOwnerSignUp)
 
+	// dogrunmg関連
+	dogrunmgController := newDogrunmg(dbConn)
+	dogrunmg := e.Group("dogrunmg")
+	dogrunmg.POST("/signUp", dogrunmgController.DogrunmgSignUp)
+	dogrunmg.POST("/invitation", dogrunmgController.InviteDogrunmg, authMW.RoleAuthorization(authMW.DOGRUN_SUPER_MANAGE), verificationMW.VerifiedAuthorization(authMW.DOGRUN_SUPER_MANAGE))
+	dogrunmg.GET("/staff", dogrunmgController.GetStaffs, authMW.RoleAuthorization(authMW.DOGRUN_SUPER_MANAGE), verificationMW.VerifiedAuthorization(authMW.DOGRUN_SUPER_MANAGE))
+	dogrunmg.PUT("/staff/:id/deactivate", dogrunmgController.DeactivateStaff, authMW.RoleAuthorization(authMW.DOGRUN_SUPER_MANAGE), verificationMW.VerifiedAuthorization(authMW.DOGRUN_SUPER_MANAGE))
+	dogrunmg.PUT("/staff/:id/role", dogrunmgController.UpdateStaffRole, authMW.RoleAuthorization(authMW.DOGRUN_SUPER_MANAGE), verificationMW.VerifiedAuthorization(authMW.DOGRUN_SUPER_MANAGE))
+
 	// auth関連
 	authController := newAuth(dbConn)
 	auth := e.Group("auth")
 	// dogowner
 	auth.POST("/dogowner/token", authController.LogInDogowner)
 	auth.POST("dogowner/revoke", authController.RevokeDogowner, authMW.RoleAuthorization(authMW.DOG_MANAGE))
-	// auth.GET("/google/oauth", authController.GoogleOAuth)
+	auth.POST("/dogowner/refresh", authController.RefreshDogowner)
+	auth.POST("/dogowner/google/token", authController.GoogleOAuth)
+	auth.POST("/dogowner/password/reset", authController.RequestPasswordResetDogowner)
+	auth.POST("/dogowner/password/reset/confirm", authController.ConfirmPasswordResetDogowner)
 	// dogrunmg
 	auth.POST("dogrunmg/token", authController.LogInDogrunmg)
 	auth.POST("dogrunmg/revoke", authController.RevokeDogrunmg, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+	auth.POST("/dogrunmg/refresh", authController.RefreshDogrunmg)
+	auth.POST("/dogrunmg/password/reset", authController.RequestPasswordResetDogrunmg)
+	auth.POST("/dogrunmg/password/reset/confirm", authController.ConfirmPasswordResetDogrunmg)
+	auth.GET("/session", authController.GetSessions, authMW.RoleAuthorization(authMW.SESSION_MANAGE))
+	auth.DELETE("/session", authController.RevokeAllSessions, authMW.RoleAuthorization(authMW.SESSION_MANAGE))
+	auth.DELETE("/session/:sessionId", authController.RevokeSession, authMW.RoleAuthorization(authMW.SESSION_MANAGE))
+	// メールアドレス・電話番号の確認
+	auth.GET("/verification", authController.GetVerificationStatus, authMW.RoleAuthorization(authMW.SESSION_MANAGE))
+	auth.POST("/verification/email", authController.SendEmailVerification, authMW.RoleAuthorization(authMW.SESSION_MANAGE))
+	auth.POST("/verification/email/confirm", authController.ConfirmEmailVerification)
+	auth.POST("/verification/phone", authController.SendPhoneVerification, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	auth.POST("/verification/phone/confirm", authController.ConfirmPhoneVerification, authMW.RoleAuthorization(authMW.DOG_MANAGE))
 
 	//interaction関連
-	interactionController := newInteraction(dbConn)
+	interactionController := newInteraction(dbConn, eventHub)
 	bookmark := e.Group("bookmark")
 	bookmark.POST("/dogrun", interactionController.AddBookmark, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	bookmark.GET("/dogrun", dogrunController.GetBookmarkedDogruns, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
 	bookmark.DELETE("/dogrun", interactionController.DeleteBookmarks, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	// ブックマークのコレクション
+	bookmark.GET("/collection", interactionController.GetBookmarkCollections, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	bookmark.POST("/collection", interactionController.CreateBookmarkCollection, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	bookmark.PUT("/collection/:collectionId", interactionController.UpdateBookmarkCollection, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	bookmark.DELETE("/collection/:collectionId", interactionController.DeleteBookmarkCollection, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	bookmark.POST("/collection/:collectionId/dogrun", interactionController.AddBookmarkCollectionItems, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	bookmark.PUT("/collection/:collectionId/dogrun/:dogrunId", interactionController.UpdateBookmarkCollectionItem, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	bookmark.DELETE("/collection/:collectionId/dogrun", interactionController.DeleteBookmarkCollectionItems, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	bookmark.POST("/collection/:collectionId/share", interactionController.ShareBookmarkCollection, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	bookmark.DELETE("/collection/:collectionId/share", interactionController.RevokeBookmarkCollectionShare, authMW.RoleAuthorization(authMW.DOGRUN_SEARCH))
+	// 共有リンクからのコレクション閲覧(ログイン不要のため、IPごとにリクエスト数を制限する)
+	share := e.Group("share", newShareRateLimiter())
+	share.GET("/bookmark/:token", dogrunController.GetSharedBookmarkCollection)
 
 	access := e.Group("access")
 	access.GET("/today/checkins", interactionController.GetTodayCheckins, authMW.RoleAuthorization(authMW.DOG_MANAGE))
-	access.POST("/checkin", interactionController.CheckinDogrun, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	access.GET("/history", interactionController.GetVisitHistory, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	access.GET("/history/stats", interactionController.GetVisitStats, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	access.POST("/checkin", interactionController.CheckinDogrun, authMW.RoleAuthorization(authMW.DOG_MANAGE), verificationMW.VerifiedAuthorization(authMW.DOG_MANAGE))
+	access.POST("/checkin/eligibility", interactionController.EvaluateCheckinEligibility, authMW.RoleAuthorization(authMW.DOG_MANAGE))
 	access.DELETE("/checkout", interactionController.CheckoutDogrun, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	access.GET("/checkin/qr/:dogrunId", interactionController.IssueCheckinQrToken, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+
+	// ドッグランのレビュー
+	dogrunReviewController := newDogrunReview(dbConn)
+	review := e.Group("review")
+	review.GET("/dogrun/:dogrunId", dogrunReviewController.GetDogrunReviews, authMW.RoleAuthorization(authMW.DOGRUN_REFER))
+	review.POST("/dogrun/:dogrunId", dogrunReviewController.PostDogrunReview, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	review.PUT("/:reviewId", dogrunReviewController.UpdateDogrunReview, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	review.DELETE("/:reviewId", dogrunReviewController.DeleteDogrunReview, authMW.RoleAuthorization(authMW.DOG_MANAGE))
+	review.PUT("/:reviewId/reply", dogrunReviewController.ReplyDogrunReview, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+	review.DELETE("/:reviewId/reply", dogrunReviewController.DeleteDogrunReviewReply, authMW.RoleAuthorization(authMW.DOGRUN_MANAGE))
+
+	// ドッグランのイベント配信(SSE・WebSocket)
+	dogrunEventController := newDogrunEvent(dbConn, eventHub)
+	stream := e.Group("stream")
+	stream.GET("/dogrun/events", dogrunEventController.StreamEvents, authMW.RoleAuthorization(authMW.DOGRUN_REFER))
+	stream.GET("/dogrun/events/ws", dogrunEventController.StreamEventsWebSocket, authMW.RoleAuthorization(authMW.DOGRUN_REFER))
 
 	// cms関連
 	cmsController := newCms(dbConn)
@@ -185,47 +303,262 @@ func newDog(dbConn *gorm.DB) dogController.IDogController {
 	return dogController
 }
 
+// 予防接種証明書の初期化
+func newInjectionCertification(dbConn *gorm.DB) dogController.IInjectionCertificationController {
+	// facade層
+	cmsFacade := newCmsFacade(dbConn)
+	dogrunFacade := dogrunF.NewDogrunFacade(dogrunR.NewDogrunRepository(dbConn))
+
+	// repository層
+	dr := dogRepository.NewDogRepository(dbConn)
+	icr := dogRepository.NewInjectionCertificationRepository(dbConn)
+
+	// handler層
+	ich := dogHandler.NewInjectionCertificationHandler(dr, icr, cmsFacade, dogrunFacade)
+
+	// controller層
+	return dogController.NewInjectionCertificationController(ich)
+}
+
 func newDogrun(dbConn *gorm.DB) dogrunC.IDogrunController {
 	//facadeの準備
 	interactionRepository := interactionR.NewBookmarkRepository(dbConn)
-	dogrunFacade := interactionFacade.NewBookmarkFacade(interactionRepository)
+	bookmarkCollectionRepository := interactionR.NewBookmarkCollectionRepository(dbConn)
+	dogrunFacade := interactionFacade.NewBookmarkFacade(interactionRepository, bookmarkCollectionRepository)
+	checkInOutRepository := interactionR.NewCheckInOutRepository(dbConn)
+	visitFacade := interactionFacade.NewVisitFacade(checkInOutRepository)
+	dogrunReviewRepository := interactionR.NewDogrunReviewRepository(dbConn)
+	reviewFacade := interactionFacade.NewReviewFacade(dogrunReviewRepository)
 
-	dogrunRest := googleplace.NewRest()
+	dogrunRest := newGooglePlaceRest(dbConn)
 	dogrunRepository := dogrunR.NewDogrunRepository(dbConn)
-	dogrunHandler := dogrunH.NewDogrunHandler(dogrunRest, dogrunRepository, dogrunFacade)
-	return dogrunC.NewDogrunController(dogrunHandler)
+	dogrunImageRepository := dogrunR.NewDogrunImageRepository(dbConn)
+	dogrunHandler := dogrunH.NewDogrunHandler(dogrunRest, dogrunRepository, dogrunFacade, visitFacade, reviewFacade, dogrunImageRepository)
+
+	//ドッグラン管理者向け
+	dogrunScopeRepository := dogrunR.NewDogrunScopeRepository()
+	transactionManager := transaction.NewTransactionManager(dbConn)
+	dogrunManageHandler := dogrunH.NewDogrunManageHandler(dogrunRepository, dogrunScopeRepository, transactionManager)
+	return dogrunC.NewDogrunController(dogrunHandler, dogrunManageHandler)
+}
+
+// ドッグランのギャラリー画像の初期化
+func newDogrunImage(dbConn *gorm.DB) dogrunC.IDogrunImageController {
+	// facade層
+	cf := newCmsFacade(dbConn)
+
+	// repository層
+	drr := dogrunR.NewDogrunRepository(dbConn)
+	dir := dogrunR.NewDogrunImageRepository(dbConn)
+
+	// scopeRepository層
+	disr := dogrunR.NewDogrunImageScopeRepository()
+
+	// transaction層
+	transactionManager := transaction.NewTransactionManager(dbConn)
+
+	// handler層
+	dih := dogrunH.NewDogrunImageHandler(drr, dir, disr, transactionManager, cf)
+
+	// controller層
+	return dogrunC.NewDogrunImageController(dih)
+}
+
+// ドッグランのクレームの初期化
+func newDogrunClaim(dbConn *gorm.DB) dogrunC.IDogrunClaimController {
+	// facade層
+	dmr := dogrunmgRepository.NewDogrunmgRepository(dbConn)
+	dmf := dogrunmgFacade.NewDogrunmgFacade(dmr)
+
+	// repository層
+	drr := dogrunR.NewDogrunRepository(dbConn)
+	dcr := dogrunR.NewDogrunClaimRepository(dbConn)
+
+	// scopeRepository層
+	drsr := dogrunR.NewDogrunScopeRepository()
+	dcsr := dogrunR.NewDogrunClaimScopeRepository()
+
+	// transaction層
+	transactionManager := transaction.NewTransactionManager(dbConn)
+
+	// handler層
+	dch := dogrunH.NewDogrunClaimHandler(drr, drsr, dcr, dcsr, transactionManager, dmf)
+
+	// controller層
+	return dogrunC.NewDogrunClaimController(dch)
+}
+
+// google place apiクライアントの初期化
+func newGooglePlaceRest(dbConn *gorm.DB) googleplace.IRest {
+	// フィクスチャを使うオフライン用(キャッシュ不要)
+	if configs.FetchConfigStr("google.place.mode") == "fake" {
+		places, err := googleplace.LoadFakePlaces(
+			configs.FetchConfigStr("google.place.fake.fixture"),
+			configs.FetchConfigStr("google.place.fake.photo.uri"),
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return googleplace.NewFakeRest(places)
+	}
+
+	rest := googleplace.NewRest()
+	if !configs.FetchConfigBool("google.place.cache.enabled") {
+		return rest
+	}
+	store, err := googleplace.NewCacheStore(dbConn)
+	if err != nil {
+		log.Fatalf("google place apiのキャッシュの初期化に失敗: %v", err)
+	}
+	cachedRest := googleplace.NewCachedRest(rest, store, googleplace.NewCacheTTL())
+
+	// キャッシュのヒット/ミス数の定期的なログ出力(0以下の場合は出力しない)
+	if interval := configs.FetchConfigInt("google.place.cache.metrics.interval"); interval > 0 {
+		go cachedRest.ReportMetrics(context.Background(), time.Duration(interval)*time.Second)
+	}
+	return cachedRest
 }
 
 func newAuth(dbConn *gorm.DB) authController.IAuthController {
 	authRepository := authRepository.NewAuthRepository(dbConn)
-	// googleOAuth := google.NewOAuthGoogle()
-	// authHandler := authHandler.NewAuthHandler(authRepository, googleOAuth)
-	authHandler := authHandler.NewAuthHandler(authRepository)
+	authHandler := newAuthHandler(authRepository)
 	authController := authController.NewAuthController(authHandler)
 	return authController
 }
 
+// authHandlerの初期化(外部サービスのアダプターを含む)
+func newAuthHandler(ar authRepository.IAuthRepository) authHandler.IAuthHandler {
+	googleOAuth := google.NewOAuthGoogle()
+	smsGateway, err := sms.NewSmsGateway()
+	if err != nil {
+		log.Fatalf("SMS送信の初期化に失敗: %v", err)
+	}
+	return authHandler.NewAuthHandler(ar, googleOAuth, newMailer(), smsGateway)
+}
+
+// メール送信の初期化
+func newMailer() mailer.IMailer {
+	m, err := mailer.NewMailer()
+	if err != nil {
+		log.Fatalf("メール送信の初期化に失敗: %v", err)
+	}
+	return m
+}
+
 func newAuthMiddleware(dbConn *gorm.DB) authMW.IAuthJwt {
 	authRepository := authRepository.NewAuthRepository(dbConn)
 	return authMW.NewAuthJwt(authRepository)
 }
 
-func newInteraction(dbConn *gorm.DB) interactionC.IInteractionController {
+func newVerificationMiddleware(dbConn *gorm.DB) authMW.IVerificationAuthorization {
+	authRepository := authRepository.NewAuthRepository(dbConn)
+	return authMW.NewVerificationAuthorization(authRepository)
+}
+
+func newInteraction(dbConn *gorm.DB, eventHub eventhub.IEventHub) interactionC.IInteractionController {
 	//dogrun facadeの準備
 	dogrunRepository := dogrunR.NewDogrunRepository(dbConn)
 	dogrunFacade := dogrunF.NewDogrunFacade(dogrunRepository)
 	//dog facadeの準備
-	dogRepository := dogRepository.NewDogRepository(dbConn)
-	dogFacade := dogF.NewDogFacade(dogRepository)
+	dr := dogRepository.NewDogRepository(dbConn)
+	icr := dogRepository.NewInjectionCertificationRepository(dbConn)
+	dogFacade := dogF.NewDogFacade(dr, icr)
 
 	//bookmark
 	bookmarkRepository := interactionR.NewBookmarkRepository(dbConn)
 	bookmarkHandler := interactionH.NewBookmarkHandler(bookmarkRepository, dogrunFacade)
 	//checkinout
 	checkInOutRepository := interactionR.NewCheckInOutRepository(dbConn)
-	checkInOutHandler := interactionH.NewCheckInOutHandler(checkInOutRepository, dogrunFacade, dogFacade)
+	checkInOutHandler := interactionH.NewCheckInOutHandler(checkInOutRepository, dogrunFacade, dogFacade, eventHub)
+	//チェックイン履歴
+	visitHistoryHandler := interactionH.NewVisitHistoryHandler(checkInOutRepository, dogFacade)
+	//ブックマークのコレクション
+	bookmarkCollectionRepository := interactionR.NewBookmarkCollectionRepository(dbConn)
+	bookmarkCollectionHandler := interactionH.NewBookmarkCollectionHandler(bookmarkCollectionRepository, bookmarkRepository)
+
+	return interactionC.NewInteractionController(bookmarkHandler, checkInOutHandler, visitHistoryHandler, bookmarkCollectionHandler)
+}
 
-	return interactionC.NewInteractionController(bookmarkHandler, checkInOutHandler)
+// ドッグランのレビューの初期化
+func newDogrunReview(dbConn *gorm.DB) interactionC.IDogrunReviewController {
+	// facade層
+	dogrunRepository := dogrunR.NewDogrunRepository(dbConn)
+	dogrunFacade := dogrunF.NewDogrunFacade(dogrunRepository)
+
+	// repository層
+	dogrunReviewRepository := interactionR.NewDogrunReviewRepository(dbConn)
+	checkInOutRepository := interactionR.NewCheckInOutRepository(dbConn)
+
+	// handler層
+	drh := interactionH.NewDogrunReviewHandler(dogrunReviewRepository, checkInOutRepository, dogrunFacade)
+
+	// controller層
+	return interactionC.NewDogrunReviewController(drh)
+}
+
+// ドッグランのイベント配信の初期化
+func newDogrunEvent(dbConn *gorm.DB, eventHub eventhub.IEventHub) interactionC.IDogrunEventController {
+	// facade層
+	dogrunRepository := dogrunR.NewDogrunRepository(dbConn)
+	dogrunFacade := dogrunF.NewDogrunFacade(dogrunRepository)
+
+	// repository層
+	checkInOutRepository := interactionR.NewCheckInOutRepository(dbConn)
+
+	// handler層
+	deh := interactionH.NewDogrunEventHandler(checkInOutRepository, dogrunFacade, eventHub)
+
+	// controller層
+	return interactionC.NewDogrunEventController(deh)
+}
+
+// 自動チェックアウトのワーカーの初期化
+func newAutoCheckoutWorker(dbConn *gorm.DB, eventHub eventhub.IEventHub) interactionW.IAutoCheckoutWorker {
+	// repository層
+	acr := interactionR.NewAutoCheckoutRepository(dbConn)
+
+	// handler層
+	maxDuration := time.Duration(configs.FetchConfigInt("auto.checkout.max.duration")) * time.Hour
+	ach := interactionH.NewAutoCheckoutHandler(acr, eventHub, maxDuration)
+
+	interval := time.Duration(configs.FetchConfigInt("auto.checkout.interval")) * time.Second
+	return interactionW.NewAutoCheckoutWorker(ach, interval)
+}
+
+// ドッグランのイベント配信ハブの初期化
+// local: プロセス内のみで配信, postgres: LISTEN/NOTIFYで全インスタンスに配信
+func newEventHub(dbConn *gorm.DB) eventhub.IEventHub {
+	broker := eventhub.NewLocalBroker()
+	if configs.FetchConfigStr("event.hub.backend") == "postgres" {
+		broker = eventhub.NewPostgresBroker(dbConn, db.PostgresURL(), configs.FetchConfigStr("event.hub.postgres.channel"))
+	}
+
+	eventHub, err := eventhub.NewEventHub(broker, configs.FetchConfigInt("event.hub.buffer"))
+	if err != nil {
+		log.Fatalf("イベントハブの初期化に失敗: %v", err)
+	}
+	return eventHub
+}
+
+// 共有リンクのレート制限の初期化
+// ログインなしでgoogle place apiを呼び出せるため、IPごとのリクエスト数を制限する
+func newShareRateLimiter() echo.MiddlewareFunc {
+	perMinute := configs.FetchConfigInt("share.rate.limit.minute")
+	store := middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+		Rate:      rate.Limit(float64(perMinute) / 60),
+		Burst:     configs.FetchConfigInt("share.rate.limit.burst"),
+		ExpiresIn: 3 * time.Minute,
+	})
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Store: store,
+		ErrorHandler: func(c echo.Context, err error) error {
+			return errors.NewWRError(err, "リクエスト元を特定できません。", errors.NewDogrunClientErrorEType())
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			logger.GetLogger(c).Sugar().Warnf("共有リンクのレート制限を超過: %s", identifier)
+			return errors.NewWRError(err, "リクエストが多すぎます。しばらくしてから再度お試しください。", errors.NewDogrunClientErrorEType())
+		},
+	})
 }
 
 // dogOwnerの初期化
@@ -242,13 +575,18 @@ func newDogOwner(dbConn *gorm.DB) dogOwnerController.IDogOwnerController {
 	asr := authRepository.NewAuthScopeRepository()
 
 	// handler層
-	authHandler := authHandler.NewAuthHandler(ar)
+	authHandler := newAuthHandler(ar)
+
+	// facade層
+	authFacade := authFacade.NewAuthFacade(ar, authHandler)
+
 	dogOwnerHandler := dogOwnerHandler.NewDogOwnerHandler(
 		dosr,
 		transactionManager,
 		asr,
 		dor,
 		ar,
+		authFacade,
 	)
 
 	// controller層
@@ -257,6 +595,20 @@ func newDogOwner(dbConn *gorm.DB) dogOwnerController.IDogOwnerController {
 
 func newCms(dbConn *gorm.DB) cmsController.ICmsController {
 	cmsRepository := cmsRepository.NewCmsRepository(dbConn)
+	cmsHandler := newCmsHandler(cmsRepository)
+	cmsController := cmsController.NewCmsController(cmsHandler)
+	return cmsController
+}
+
+// cmsのfacadeの初期化
+func newCmsFacade(dbConn *gorm.DB) cmsFacade.ICmsFacade {
+	cmsRepository := cmsRepository.NewCmsRepository(dbConn)
+	cmsHandler := newCmsHandler(cmsRepository)
+	return cmsFacade.NewCmsFacade(cmsHandler, cmsRepository)
+}
+
+// cmsのhandlerの初期化(S3の準備)
+func newCmsHandler(cr cmsRepository.ICmsRepository) cmsHandler.ICmsHandler {
 	// aws設定
 	sdkCfg, err := loadAWSConfig()
 
@@ -264,9 +616,7 @@ func newCms(dbConn *gorm.DB) cmsController.ICmsController {
 		log.Fatalf("AWSのクレデンシャル取得に失敗: %v", err)
 	}
 	cmsAWS := cmsAWS.NewS3Provider(sdkCfg)
-	cmsHandler := cmsHandler.NewCmsHandler(cmsAWS, cmsRepository)
-	cmsController := cmsController.NewCmsController(cmsHandler)
-	return cmsController
+	return cmsHandler.NewCmsHandler(cmsAWS, cr)
 }
 
 func loadAWSConfig() (aws.Config, error) {
@@ -290,6 +640,36 @@ func loadAWSConfig() (aws.Config, error) {
 	)
 }
 
+// dogrunmgの初期化
+func newDogrunmg(dbConn *gorm.DB) dogrunmgController.IDogrunmgController {
+	// repository層
+	dmr := dogrunmgRepository.NewDogrunmgRepository(dbConn)
+	ar := authRepository.NewAuthRepository(dbConn)
+
+	// scopeRepository層
+	dmsr := dogrunmgRepository.NewDogrunmgScopeRepository()
+	asr := authRepository.NewAuthScopeRepository()
+
+	// transaction層
+	transactionManager := transaction.NewTransactionManager(dbConn)
+
+	// facade層
+	authFacade := authFacade.NewAuthFacade(ar, newAuthHandler(ar))
+
+	// handler層
+	dmh := dogrunmgHandler.NewDogrunmgHandler(
+		dmr,
+		dmsr,
+		asr,
+		transactionManager,
+		authFacade,
+		newMailer(),
+	)
+
+	// controller層
+	return dogrunmgController.NewDogrunmgController(dmh)
+}
+
 func newOrg(dbConn *gorm.DB) orgController.IOrgController {
 	// repository層
 	// orgRepository := orgRepository.NewOrgRepository(dbConn)
@@ -304,7 +684,7 @@ func newOrg(dbConn *gorm.DB) orgController.IOrgController {
 	transactionManager := transaction.NewTransactionManager(dbConn)
 
 	// facade層
-	authFacade := authFacade.NewAuthFacade(ar)
+	authFacade := authFacade.NewAuthFacade(ar, newAuthHandler(ar))
 
 	// handler層
 	orgHandler := orgHandler.NewOrgHandler(
@@ -75,14 +75,45 @@ func bindEnvs() {
 	_ = v.BindEnv("stage", "STAGE")
 	_ = v.BindEnv("env", "ENV")
 	_ = v.BindEnv("google.place.api.key", "GOOGLE_PLACE_API_KEY")
-	_ = v.BindEnv("jwt.os.secret.key", "SECRET_KEY")                // jwt生成用の秘密鍵
-	_ = v.BindEnv("jwt.exp.time", "JWT_EXP_TIME")                   // jwt生成用の秘密鍵
-	_ = v.BindEnv("gcp.client.id", "GCP_CLIENT_ID")                 // oauthの際のgcp credentials
-	_ = v.BindEnv("gcp.client.secret", "GCP_CLIENT_SECRET")         // oauthの際のgcp credentials
-	_ = v.BindEnv("gcp.redirect.uri", "GCP_REDIRECT_URI")           // oauthの際のgcp credentials
-	_ = v.BindEnv("aws.access.key", "AWS_ACCESS_KEY")               // awsのアクセスキー
-	_ = v.BindEnv("aws.secret.access.key", "AWS_SECRET_ACCESS_KEY") // awsのシークレットアクセスキー
-	_ = v.BindEnv("aws.s3.bucket.name", "AWS_S3_BUCKET_NAME")       // awsのbucket名
+	_ = v.BindEnv("google.place.mode", "GOOGLE_PLACE_MODE")                 // api: 実API, fake: フィクスチャ
+	_ = v.BindEnv("google.place.base.url", "GOOGLE_PLACE_BASE_URL")         // places apiのベースURL(フェイクサーバー用)
+	_ = v.BindEnv("google.place.fake.fixture", "GOOGLE_PLACE_FAKE_FIXTURE") // フェイクのフィクスチャファイル
+	_ = v.BindEnv("jwt.os.secret.key", "SECRET_KEY")                        // jwt生成用の秘密鍵
+	_ = v.BindEnv("jwt.access.exp.minute", "JWT_ACCESS_EXP_MINUTE")         // アクセストークン(jwt)の有効期間(分)
+	_ = v.BindEnv("jwt.refresh.exp.hour", "JWT_REFRESH_EXP_HOUR")           // リフレッシュトークンの有効期間(時間)
+	_ = v.BindEnv("jwt.system.id", "SYSTEM_JWT_ID")                         // システムユーザーのjwt_id
+	_ = v.BindEnv("gcp.client.id", "GCP_CLIENT_ID")                         // oauthの際のgcp credentials
+	_ = v.BindEnv("gcp.client.secret", "GCP_CLIENT_SECRET")                 // oauthの際のgcp credentials
+	_ = v.BindEnv("gcp.redirect.uri", "GCP_REDIRECT_URI")                   // oauthの際のgcp credentials
+	_ = v.BindEnv("gcp.oauth.token.url", "GCP_OAUTH_TOKEN_URL")             // oauthのトークンエンドポイント(ローカルのOIDCスタブ用)
+	_ = v.BindEnv("gcp.oauth.jwks.url", "GCP_OAUTH_JWKS_URL")               // IDトークン検証用の公開鍵(JWKS)のURL
+	_ = v.BindEnv("gcp.oauth.issuer", "GCP_OAUTH_ISSUER")                   // IDトークンの発行者
+	_ = v.BindEnv("mail.backend", "MAIL_BACKEND")                           // smtp: SMTPで送信, log: ファイル・ログに出力
+	_ = v.BindEnv("mail.from", "MAIL_FROM")                                 // 送信元のメールアドレス
+	_ = v.BindEnv("mail.smtp.host", "MAIL_SMTP_HOST")                       // SMTPサーバーのホスト
+	_ = v.BindEnv("mail.smtp.port", "MAIL_SMTP_PORT")                       // SMTPサーバーのポート
+	_ = v.BindEnv("mail.smtp.user", "MAIL_SMTP_USER")                       // SMTPの認証ユーザー(未設定の場合は認証なし)
+	_ = v.BindEnv("mail.smtp.password", "MAIL_SMTP_PASSWORD")               // SMTPの認証パスワード
+	_ = v.BindEnv("mail.log.dir", "MAIL_LOG_DIR")                           // logの場合の出力先ディレクトリ(未設定の場合はログに出力)
+	_ = v.BindEnv("password.reset.url", "PASSWORD_RESET_URL")               // パスワード再設定画面のURL(tokenをクエリに付与する)
+	_ = v.BindEnv("dogrunmg.invitation.url", "DOGRUNMG_INVITATION_URL")     // スタッフ招待の登録画面のURL(tokenをクエリに付与する)
+	_ = v.BindEnv("sms.backend", "SMS_BACKEND")                             // http: SMSゲートウェイで送信, fake: ファイル・ログに出力
+	_ = v.BindEnv("sms.from", "SMS_FROM")                                   // 送信元の番号・名称
+	_ = v.BindEnv("sms.http.url", "SMS_HTTP_URL")                           // SMSゲートウェイのURL
+	_ = v.BindEnv("sms.http.token", "SMS_HTTP_TOKEN")                       // SMSゲートウェイの認証トークン
+	_ = v.BindEnv("sms.fake.dir", "SMS_FAKE_DIR")                           // fakeの場合の出力先ディレクトリ(未設定の場合はログに出力)
+	_ = v.BindEnv("verification.required", "VERIFICATION_REQUIRED")         // 確認済みアカウントのみ利用できる機能の制限の有効化
+	_ = v.BindEnv("verification.email.url", "VERIFICATION_EMAIL_URL")       // メールアドレス確認画面のURL(tokenをクエリに付与する)
+	_ = v.BindEnv("verification.secret.key", "VERIFICATION_SECRET_KEY")     // メールアドレス確認用トークンの署名鍵(未設定時はjwtの秘密鍵)
+	_ = v.BindEnv("aws.access.key", "AWS_ACCESS_KEY")                       // awsのアクセスキー
+	_ = v.BindEnv("aws.secret.access.key", "AWS_SECRET_ACCESS_KEY")         // awsのシークレットアクセスキー
+	_ = v.BindEnv("aws.s3.bucket.name", "AWS_S3_BUCKET_NAME")               // awsのbucket名
+	_ = v.BindEnv("event.hub.backend", "EVENT_HUB_BACKEND")                 // local: プロセス内, postgres: LISTEN/NOTIFY
+	_ = v.BindEnv("auto.checkout.enabled", "AUTO_CHECKOUT_ENABLED")         // 自動チェックアウトのワーカーの有効化
+	_ = v.BindEnv("checkin.qr.secret.key", "CHECKIN_QR_SECRET_KEY")         // チェックイン用QRコードの署名鍵(未設定時はjwtの秘密鍵)
+	_ = v.BindEnv("checkin.presence.required", "CHECKIN_PRESENCE_REQUIRED") // チェックイン時の現地確認の有効化
+	// フェイクのgoogle placeが返す写真のURL(google.place.mode=fake・フェイクサーバー用)
+	_ = v.BindEnv("google.place.fake.photo.uri", "GOOGLE_PLACE_FAKE_PHOTO_URI")
 }
 
 /*
@@ -94,6 +125,62 @@ func setDefaults() {
 	v.SetDefault("postgres.user", "wanrun")
 	v.SetDefault("postgres.password", "__dummdy__")
 	v.SetDefault("postgres.dbname", "dbname")
+	v.SetDefault("dogrunmg.invitation.exp.hour", 72) // dogrunmg招待トークンの有効期限(時間)
+	v.SetDefault("dogrunmg.invitation.url", "http://localhost:3000/dogrunmg/signup")
+	// 認証トークン
+	v.SetDefault("jwt.access.exp.minute", 15) // アクセストークン(jwt)の有効期間(分)
+	v.SetDefault("jwt.refresh.exp.hour", 720) // リフレッシュトークンの有効期間(時間)
+	// パスワード再設定
+	v.SetDefault("password.reset.exp.minute", 30)    // 再設定トークンの有効期間(分)
+	v.SetDefault("password.reset.resend.second", 60) // 再設定メールの再送信の間隔(秒)
+	v.SetDefault("password.reset.url", "http://localhost:3000/password/reset")
+	// メールアドレス・電話番号の確認
+	v.SetDefault("verification.required", false) // 既存アカウントは未確認のため、確認を促してから有効化する
+	v.SetDefault("verification.email.url", "http://localhost:3000/verification/email")
+	v.SetDefault("verification.email.exp.hour", 24)      // 確認メールのトークンの有効期間(時間)
+	v.SetDefault("verification.phone.exp.minute", 10)    // 確認コードの有効期間(分)
+	v.SetDefault("verification.phone.max.attempts", 5)   // 確認コードの入力回数の上限
+	v.SetDefault("verification.phone.resend.second", 60) // 確認コードの再送信の間隔(秒)
+	// SMS送信
+	v.SetDefault("sms.backend", "fake")
+	v.SetDefault("sms.from", "WanRun")
+	// メール送信
+	v.SetDefault("mail.backend", "log")
+	v.SetDefault("mail.from", "no-reply@wanrun.local")
+	v.SetDefault("mail.smtp.port", 587)
+	// google oauth
+	v.SetDefault("gcp.oauth.token.url", "https://oauth2.googleapis.com/token")
+	v.SetDefault("gcp.oauth.jwks.url", "https://www.googleapis.com/oauth2/v3/certs")
+	v.SetDefault("gcp.oauth.issuer", "https://accounts.google.com")
+	// google place api
+	v.SetDefault("google.place.mode", "api")
+	v.SetDefault("google.place.fake.fixture", "./misc/googleplace/places.json")
+	v.SetDefault("google.place.fake.photo.uri", "https://placehold.jp/1200x800.png")
+	// google place apiのキャッシュ
+	v.SetDefault("google.place.cache.enabled", true)
+	v.SetDefault("google.place.cache.backend", "lru")        // lru: インプロセス, postgres: DB(インスタンス間で共有)
+	v.SetDefault("google.place.cache.lru.size", 1000)        // LRUの最大保持件数
+	v.SetDefault("google.place.cache.ttl.placeInfo", 3600)   // 詳細情報の有効期限(秒)
+	v.SetDefault("google.place.cache.ttl.searchNearby", 600) // search nearbyの有効期限(秒)
+	v.SetDefault("google.place.cache.ttl.searchText", 600)   // search textの有効期限(秒)
+	v.SetDefault("google.place.cache.ttl.photo", 1800)       // 写真の有効期限(秒)
+	v.SetDefault("google.place.cache.metrics.interval", 300) // ヒット/ミス数のログ出力間隔(秒)。0以下で出力しない
+	// 共有リンクのレート制限(IPごと)
+	v.SetDefault("share.rate.limit.minute", 30) // 1分あたりのリクエスト数
+	v.SetDefault("share.rate.limit.burst", 10)  // 連続で受け付けるリクエスト数
+	// ドッグランのイベント配信
+	v.SetDefault("event.hub.backend", "local")
+	v.SetDefault("event.hub.postgres.channel", "dogrun_events") // LISTEN/NOTIFYのチャネル名
+	v.SetDefault("event.hub.buffer", 32)                        // 購読者ごとのバッファ件数
+	v.SetDefault("event.stream.heartbeat", 15)                  // ストリームのハートビート間隔(秒)
+	// 自動チェックアウト
+	v.SetDefault("auto.checkout.enabled", true)
+	v.SetDefault("auto.checkout.interval", 300)    // 実行間隔(秒)
+	v.SetDefault("auto.checkout.max.duration", 12) // 閉店時刻がない(24時間営業など)場合の最大滞在時間(時間)
+	// チェックイン時の現地確認(QRコード・ジオフェンス)
+	v.SetDefault("checkin.presence.required", true)
+	v.SetDefault("checkin.qr.rotation", 180)     // QRコードのトークンの切り替え間隔(秒)
+	v.SetDefault("checkin.geofence.radius", 200) // ドッグランの位置からの許容半径(m)
 }
 
 // 環境変数の取得
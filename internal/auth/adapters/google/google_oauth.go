@@ -1,111 +1,159 @@
 package google
 
 import (
-	// "context"
-
-	// "github.com/labstack/echo/v4"
-	_ "github.com/wanrun-develop/wanrun/configs"
-
-	// _wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
-	_ "github.com/wanrun-develop/wanrun/pkg/log"
-	// "golang.org/x/oauth2"
-
-	// _googleOAuth "golang.org/x/oauth2/google"
-	// v2 "google.golang.org/api/oauth2/v2"
-	_ "google.golang.org/api/option"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/configs"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
 )
 
 const (
-	GOOGLE_TOKEN_BASE_ENDPOINT = "https://oauth2.googleapis.com/token"
+	GOOGLE_PROVIDER_NAME       = "google"
 	GOOGLE_REQ_BODY_GRANT_TYPE = "authorization_code"
-	GOOGLE_OAUTH_SCOPES_PREFIX = "https://www.googleapis.com/auth/"
+	GOOGLE_HTTP_TIMEOUT        = 10 * time.Second
 )
 
-// type IOAuthGoogle interface {
-// 	GetGoogleUserInfo(c echo.Context, token *oauth2.Token, ctx context.Context) (*v2.Tokeninfo, error)
-// 	GetAccessToken(c echo.Context, authorizationCode string, ctx context.Context) (*oauth2.Token, error)
-// }
-
-// type oauthGoogle struct {
-// 	config *oauth2.Config
-// }
-
-// func NewOAuthGoogle() IOAuthGoogle {
-// 	return &oauthGoogle{
-// 		config: &oauth2.Config{
-// 			ClientID:     configs.FetchConfigStr("os.secret.key"),
-// 			ClientSecret: configs.FetchConfigStr("gcp.client.secret"),
-// 			RedirectURL:  configs.FetchConfigStr("gcp.redirect.uri"),
-// 			Scopes: []string{
-// 				GOOGLE_OAUTH_SCOPES_PREFIX + "userinfo.email",
-// 			},
-// 			Endpoint: googleOAuth.Endpoint,
-// 		},
-// 	}
-// }
-
-// func (og *oauthGoogle) GetLoginURL(state string) (clientID string) {
-// 	return og.config.AuthCodeURL(state)
-// }
-
-/*
-AccessTokenの取得
-*/
-// func (og *oauthGoogle) GetAccessToken(c echo.Context, authorizationCode string, ctx context.Context) (*oauth2.Token, error) {
-// 	logger := log.GetLogger(c).Sugar()
-
-// 	// アクセストークンなどを取得
-// 	token, err := og.config.Exchange(ctx, authorizationCode)
-// 	if err != nil {
-// 		wrErr := wrErrors.NewWRError(
-// 			err,
-// 			"failed to exchange authorization code for token",
-// 			wrErrors.NewAuthClientErrorEType()) // Question 認証エラーかな？
-
-// 		logger.Errorf("OAuth Exchange error: %v", wrErr)
-
-// 		return nil, wrErr
-// 	}
-
-// 	logger.Info("token info: %v", token)
-
-// 	return token, nil
-// }
-
-// /*
-// Googleのユーザー情報の取得
-// */
-// func (og *oauthGoogle) GetGoogleUserInfo(c echo.Context, token *oauth2.Token, ctx context.Context) (*v2.Tokeninfo, error) {
-// 	logger := log.GetLogger(c).Sugar()
-
-// 	httpClient := og.config.Client(ctx, token)
-
-// 	// Google API サービスの初期化
-// 	service, err := v2.NewService(ctx, option.WithHTTPClient(httpClient))
-// 	if err != nil {
-// 		wrErr := wrErrors.NewWRError(
-// 			err,
-// 			"failed to initialize Google OAuth2 service",
-// 			wrErrors.NewAuthServerErrorEType(),
-// 		) // Question
-
-// 		logger.Error("Google OAuth2 service initialization error: %v", wrErr)
-
-// 		return nil, wrErr
-// 	}
-
-// 	// Googleユーザーの取得
-// 	userInfo, err := service.Tokeninfo().AccessToken(token.AccessToken).Context(ctx).Do()
-// 	if err != nil {
-// 		wrErr := wrErrors.NewWRError(
-// 			err,
-// 			"failed to retrieve user info from Google OAuth2 service",
-// 			wrErrors.NewAuthServerErrorEType(),
-// 		)
-// 		logger.Errorf("Google user info retrieval error: %v", wrErr)
-// 		return nil, wrErr
-// 	}
-
-// 	logger.Infof("google user info: %v", userInfo)
-// 	return userInfo, nil
-// }
+type IOAuthGoogle interface {
+	ExchangeCode(c echo.Context, authorizationCode string) (GoogleToken, error)
+	VerifyIDToken(c echo.Context, rawIDToken string) (GoogleIDTokenClaims, error)
+}
+
+// トークンエンドポイントのレスポンス
+type GoogleToken struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+	TokenType    string `json:"token_type"`
+}
+
+// トークンエンドポイントのエラーレスポンス
+type googleTokenError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type oauthGoogle struct {
+	clientID     string
+	clientSecret string
+	redirectURI  string
+	tokenURL     string
+	issuer       string
+	jwks         *jwksCache
+	client       *http.Client
+}
+
+// NewOAuthGoogle: Google OAuthクライアントの生成
+// トークン・JWKSのエンドポイントと発行者は設定で変更でき、ローカルのOIDCスタブに向けられる
+//
+// return:
+//   - IOAuthGoogle: Google OAuthクライアント
+func NewOAuthGoogle() IOAuthGoogle {
+	client := &http.Client{Timeout: GOOGLE_HTTP_TIMEOUT}
+	return &oauthGoogle{
+		clientID:     configs.FetchConfigStr("gcp.client.id"),
+		clientSecret: configs.FetchConfigStr("gcp.client.secret"),
+		redirectURI:  configs.FetchConfigStr("gcp.redirect.uri"),
+		tokenURL:     configs.FetchConfigStr("gcp.oauth.token.url"),
+		issuer:       configs.FetchConfigStr("gcp.oauth.issuer"),
+		jwks:         newJwksCache(configs.FetchConfigStr("gcp.oauth.jwks.url"), client),
+		client:       client,
+	}
+}
+
+// ExchangeCode: 認可コードをトークンエンドポイントでトークンに交換
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: 認可コード
+//
+// return:
+//   - GoogleToken: トークン(IDトークンを含む)
+//   - error: error情報。認可コードが無効な場合はクライアントエラー
+func (og *oauthGoogle) ExchangeCode(c echo.Context, authorizationCode string) (GoogleToken, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	form := url.Values{}
+	form.Set("code", authorizationCode)
+	form.Set("client_id", og.clientID)
+	form.Set("client_secret", og.clientSecret)
+	form.Set("redirect_uri", og.redirectURI)
+	form.Set("grant_type", GOOGLE_REQ_BODY_GRANT_TYPE)
+
+	req, err := http.NewRequestWithContext(
+		c.Request().Context(),
+		http.MethodPost,
+		og.tokenURL,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		wrErr := wrErrors.NewWRError(err, "リクエストの生成に失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Error(wrErr)
+		return GoogleToken{}, wrErr
+	}
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationJSON)
+
+	resp, err := og.client.Do(req)
+	if err != nil {
+		wrErr := wrErrors.NewWRError(err, "Googleとの通信に失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Errorf("OAuth token request error: %v", wrErr)
+		return GoogleToken{}, wrErr
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		wrErr := wrErrors.NewWRError(err, "Googleからのレスポンスの読み込みに失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Error(wrErr)
+		return GoogleToken{}, wrErr
+	}
+
+	// 認可コードの期限切れ・使用済み等は400で返ってくる
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		var tokenErr googleTokenError
+		_ = json.Unmarshal(body, &tokenErr)
+		wrErr := wrErrors.NewWRError(
+			fmt.Errorf("%s: %s", tokenErr.Error, tokenErr.ErrorDescription),
+			"認可コードが無効です。再度Googleで認証してください。",
+			wrErrors.NewAuthClientErrorEType(),
+		)
+		logger.Errorf("OAuth exchange rejected: %v", wrErr)
+		return GoogleToken{}, wrErr
+	}
+	if resp.StatusCode != http.StatusOK {
+		wrErr := wrErrors.NewWRError(
+			fmt.Errorf("status code: %d", resp.StatusCode),
+			"Googleでのトークンの取得に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+		logger.Errorf("OAuth exchange error: %v", wrErr)
+		return GoogleToken{}, wrErr
+	}
+
+	var token GoogleToken
+	if err := json.Unmarshal(body, &token); err != nil {
+		wrErr := wrErrors.NewWRError(err, "Googleからのレスポンスの解析に失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Error(wrErr)
+		return GoogleToken{}, wrErr
+	}
+	if token.IDToken == "" {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			"GoogleからIDトークンが返却されませんでした。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+		logger.Error(wrErr)
+		return GoogleToken{}, wrErr
+	}
+
+	return token, nil
+}
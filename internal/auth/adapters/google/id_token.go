@@ -0,0 +1,246 @@
+package google
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+const (
+	JWKS_CACHE_TTL        = time.Hour        // 公開鍵のキャッシュ期間
+	JWKS_REFETCH_INTERVAL = time.Minute      // 公開鍵の再取得の最短間隔(未知のkidによる取得の連発を防ぐ)
+	ID_TOKEN_LEEWAY       = 30 * time.Second // 時刻ずれの許容範囲
+	ISSUER_HTTPS_SCHEME   = "https://"
+)
+
+// IDトークンのクレーム
+type GoogleIDTokenClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	jwt.RegisteredClaims
+}
+
+// VerifyIDToken: IDトークンの署名とクレームの検証
+// 署名はJWKSの公開鍵(RS256)で検証し、aud・iss・expとメールアドレスの確認済みを必須とする
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: IDトークン
+//
+// return:
+//   - GoogleIDTokenClaims: 検証済みのクレーム
+//   - error: error情報。検証に失敗した場合はクライアントエラー
+func (og *oauthGoogle) VerifyIDToken(c echo.Context, rawIDToken string) (GoogleIDTokenClaims, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	claims := GoogleIDTokenClaims{}
+	_, err := jwt.ParseWithClaims(
+		rawIDToken,
+		&claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return og.jwks.getKey(c, kid)
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithAudience(og.clientID),
+		jwt.WithLeeway(ID_TOKEN_LEEWAY),
+	)
+	if err != nil {
+		wrErr := wrErrors.NewWRError(err, "IDトークンの検証に失敗しました。", wrErrors.NewAuthClientErrorEType())
+		logger.Errorf("ID token verification error: %v", wrErr)
+		return GoogleIDTokenClaims{}, wrErr
+	}
+
+	// Googleは"accounts.google.com"と"https://accounts.google.com"のどちらも発行者として使用する
+	if claims.Issuer != og.issuer && ISSUER_HTTPS_SCHEME+claims.Issuer != og.issuer {
+		wrErr := wrErrors.NewWRError(
+			fmt.Errorf("unexpected issuer: %s", claims.Issuer),
+			"IDトークンの発行者が不正です。",
+			wrErrors.NewAuthClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return GoogleIDTokenClaims{}, wrErr
+	}
+
+	// jwt/v5.0.0にはexpを必須にするオプションがないため個別に確認
+	if claims.ExpiresAt == nil {
+		wrErr := wrErrors.NewWRError(
+			errors.New("exp claim is missing"),
+			"IDトークンに有効期限がありません。",
+			wrErrors.NewAuthClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return GoogleIDTokenClaims{}, wrErr
+	}
+
+	if claims.Subject == "" || claims.Email == "" {
+		wrErr := wrErrors.NewWRError(
+			errors.New("sub or email claim is missing"),
+			"IDトークンにユーザー情報がありません。",
+			wrErrors.NewAuthClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return GoogleIDTokenClaims{}, wrErr
+	}
+
+	if !claims.EmailVerified {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			"Googleアカウントのメールアドレスが確認されていません。",
+			wrErrors.NewAuthClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return GoogleIDTokenClaims{}, wrErr
+	}
+
+	return claims, nil
+}
+
+// JWKSの公開鍵のキャッシュ
+type jwksCache struct {
+	url         string
+	client      *http.Client
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time // 最後に取得を試みた日時
+}
+
+// JWKSのレスポンス
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+/*
+JWKSキャッシュの生成
+*/
+func newJwksCache(url string, client *http.Client) *jwksCache {
+	return &jwksCache{
+		url:    url,
+		client: client,
+		keys:   map[string]*rsa.PublicKey{},
+	}
+}
+
+/*
+kidに対応する公開鍵の取得
+キャッシュ期限切れ、または未知のkid(鍵のローテーション)の場合は取得し直す
+任意のkidのトークンで取得を連発させられないよう、取得し直すのは再取得の間隔ごとに1回とし、
+間隔内の未知のkidは取得せずにエラーとする。取得中もロックは保持せず、他のリクエストを待たせない
+*/
+func (jc *jwksCache) getKey(c echo.Context, kid string) (*rsa.PublicKey, error) {
+	jc.mu.Lock()
+	key, ok := jc.keys[kid]
+	if ok && time.Since(jc.fetchedAt) < JWKS_CACHE_TTL {
+		jc.mu.Unlock()
+		return key, nil
+	}
+	if time.Since(jc.attemptedAt) < JWKS_REFETCH_INTERVAL {
+		jc.mu.Unlock()
+		// キャッシュ期限切れの既知のkidは、取得し直すまでキャッシュの鍵を使用する
+		if ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown kid: %s", kid)
+	}
+	jc.attemptedAt = time.Now()
+	jc.mu.Unlock()
+
+	keys, err := jc.fetch(c)
+	if err != nil {
+		return nil, err
+	}
+
+	jc.mu.Lock()
+	jc.keys = keys
+	jc.fetchedAt = time.Now()
+	jc.mu.Unlock()
+
+	key, ok = keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid: %s", kid)
+	}
+	return key, nil
+}
+
+/*
+JWKSエンドポイントから公開鍵を取得
+*/
+func (jc *jwksCache) fetch(c echo.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(c.Request().Context(), http.MethodGet, jc.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := jc.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var set jwks
+	if err := json.Unmarshal(body, &set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		key, err := k.rsaPublicKey()
+		if err != nil {
+			return nil, err
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+/*
+JWKのn・eからRSA公開鍵を生成
+*/
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
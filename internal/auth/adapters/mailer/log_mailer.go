@@ -0,0 +1,66 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+type logMailer struct {
+	dir  string
+	from string
+}
+
+// NewLogMailer: 開発用のメール送信の生成
+// 実際には送信せず、ディレクトリが指定されていれば.emlファイルに、未指定の場合はログに出力する
+//
+// args:
+//   - string: 出力先のディレクトリ。空の場合はログに出力
+//   - string: 送信元のメールアドレス
+//
+// return:
+//   - IMailer: メール送信
+func NewLogMailer(dir string, from string) IMailer {
+	return &logMailer{dir, from}
+}
+
+// Send: メールをファイル・ログに出力
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。ログの出力に使用
+//   - Mail: 送信するメール
+//
+// return:
+//   - error: error情報
+func (lm *logMailer) Send(c echo.Context, mail Mail) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if lm.dir == "" {
+		logger.Infof("Mail (not sent). to: %s, subject: %s\n%s", mail.To, mail.Subject, mail.Body)
+		return nil
+	}
+
+	if err := os.MkdirAll(lm.dir, 0o755); err != nil {
+		wrErr := wrErrors.NewWRError(err, "メールの出力に失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102150405.000000"), mail.To)
+	path := filepath.Join(lm.dir, filepath.Base(name))
+
+	if err := os.WriteFile(path, buildMessage(lm.from, mail), 0o644); err != nil {
+		wrErr := wrErrors.NewWRError(err, "メールの出力に失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	logger.Infof("Mail written to %s. to: %s, subject: %s", path, mail.To, mail.Subject)
+
+	return nil
+}
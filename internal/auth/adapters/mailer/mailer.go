@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/configs"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+const (
+	MAILER_BACKEND_SMTP = "smtp" // SMTPサーバーで送信
+	MAILER_BACKEND_LOG  = "log"  // 送信せずにファイル・ログに出力(開発用)
+)
+
+type IMailer interface {
+	Send(c echo.Context, mail Mail) error
+}
+
+// 送信するメール(テキストのみ)
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// NewMailer: 設定のバックエンドに応じたメール送信の生成
+// smtp: SMTPサーバーで送信, log: 送信せずにファイル・ログに出力
+// 送信されないまま気づかないことがないよう、不明なバックエンドはエラーとする
+//
+// return:
+//   - IMailer: メール送信
+//   - error: 不明なバックエンドの場合のエラー
+func NewMailer() (IMailer, error) {
+	switch backend := configs.FetchConfigStr("mail.backend"); backend {
+	case MAILER_BACKEND_SMTP:
+		return NewSmtpMailer(
+			configs.FetchConfigStr("mail.smtp.host"),
+			configs.FetchConfigInt("mail.smtp.port"),
+			configs.FetchConfigStr("mail.smtp.user"),
+			configs.FetchConfigStr("mail.smtp.password"),
+			configs.FetchConfigStr("mail.from"),
+		), nil
+	case MAILER_BACKEND_LOG:
+		return NewLogMailer(configs.FetchConfigStr("mail.log.dir"), configs.FetchConfigStr("mail.from")), nil
+	default:
+		return nil, fmt.Errorf("unknown mail.backend: %q", backend)
+	}
+}
+
+// SendAsync: リクエストの処理とは別にメールを送信する
+// 送信の成否・所要時間をレスポンスに含めないため、失敗はログのみ出力する
+// echo.Contextはレスポンス後に再利用されるため、ロガーのみ引き継いだコンテキストで送信する
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。ロガーの引き継ぎに使用
+//   - IMailer: メール送信
+//   - Mail: 送信するメール
+func SendAsync(c echo.Context, m IMailer, mail Mail) {
+	dc := c.Echo().NewContext(nil, nil)
+	dc.Set("logger", log.GetLogger(c))
+
+	go func() {
+		if err := m.Send(dc, mail); err != nil {
+			log.GetLogger(dc).Sugar().Errorf("Failed to send mail asynchronously. subject: %s, err: %v", mail.Subject, err)
+		}
+	}()
+}
@@ -0,0 +1,89 @@
+package mailer
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+type smtpMailer struct {
+	addr string
+	host string
+	user string
+	pass string
+	from string
+}
+
+// NewSmtpMailer: SMTPサーバーでのメール送信の生成
+// ユーザーが未設定の場合は認証なしで送信する
+//
+// args:
+//   - string: SMTPサーバーのホスト
+//   - int: SMTPサーバーのポート
+//   - string: 認証ユーザー
+//   - string: 認証パスワード
+//   - string: 送信元のメールアドレス
+//
+// return:
+//   - IMailer: メール送信
+func NewSmtpMailer(host string, port int, user string, pass string, from string) IMailer {
+	return &smtpMailer{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		host: host,
+		user: user,
+		pass: pass,
+		from: from,
+	}
+}
+
+// Send: SMTPサーバーでメールを送信
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。ログの出力に使用
+//   - Mail: 送信するメール
+//
+// return:
+//   - error: error情報
+func (sm *smtpMailer) Send(c echo.Context, mail Mail) error {
+	logger := log.GetLogger(c).Sugar()
+
+	var auth smtp.Auth
+	if sm.user != "" {
+		auth = smtp.PlainAuth("", sm.user, sm.pass, sm.host)
+	}
+
+	if err := smtp.SendMail(sm.addr, auth, sm.from, []string{mail.To}, buildMessage(sm.from, mail)); err != nil {
+		wrErr := wrErrors.NewWRError(err, "メールの送信に失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Errorf("Failed to send mail: %v", wrErr)
+		return wrErr
+	}
+
+	logger.Infof("Mail sent. to: %s, subject: %s", mail.To, mail.Subject)
+
+	return nil
+}
+
+/*
+ヘッダーを付与したメール本文(UTF-8)の生成
+*/
+func buildMessage(from string, mail Mail) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", mail.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/wanrun-develop/wanrun/internal/auth/core/dto"
@@ -16,17 +17,38 @@ import (
 type IAuthRepository interface {
 	CreateDogOwner(c echo.Context, doc *model.DogOwnerCredential) (*model.DogOwnerCredential, error)
 	GetDogOwnerByCredentials(c echo.Context, adoReq dto.AuthDogOwnerReq) ([]model.DogOwnerCredential, error)
-	// CreateOAuthDogOwner(c echo.Context, dogOwnerCredential *model.DogOwnerCredential) (*model.DogOwnerCredential, error)
-	UpdateDogownerJwtID(c echo.Context, doID int64, ji string) error
-	GetJwtID(c echo.Context, userID int64, modelType any, result any, columnName string) (string, error)
-	GetDogownerJwtID(c echo.Context, dogownerID int64) (string, error)
-	GetDogrunmgJwtID(c echo.Context, dogownerID int64) (string, error)
-	DeleteDogownerJwtID(c echo.Context, doID int64) error
+	GetDogOwnerCredentialByProvider(c echo.Context, providerName string, providerUserID string) (model.DogOwnerCredential, error)
+	GetDogOwnerCredentialsByEmail(c echo.Context, email string) ([]model.DogOwnerCredential, error)
+	CreateDogOwnerCredential(c echo.Context, doc *model.DogOwnerCredential) error
 	CheckDuplicate(c echo.Context, field string, value sql.NullString) error
 	CountOrgEmail(c echo.Context, email string) (int64, error)
 	GetDogrunmgByCredentials(c echo.Context, email string) ([]model.DogrunmgCredential, error)
-	UpdateDogrunmgJwtID(c echo.Context, dmID int64, ji string) error
-	DeleteDogrunmgJwtID(c echo.Context, dmID int64) error
+	GetAuthDogrunmg(c echo.Context, dmID int64) (model.AuthDogrunmg, error)
+	CreateRefreshToken(c echo.Context, rt *model.RefreshToken) error
+	GetRefreshTokenByHash(c echo.Context, tokenHash string) (model.RefreshToken, error)
+	RotateRefreshToken(c echo.Context, rotatedID int64, rt *model.RefreshToken) (bool, error)
+	CreateAuthSession(c echo.Context, as *model.AuthSession, rt *model.RefreshToken) error
+	GetAuthSessionByJwtID(c echo.Context, jwtID string) (model.AuthSession, error)
+	GetDogownerAuthSessions(c echo.Context, doID int64) ([]model.AuthSession, error)
+	GetDogrunmgAuthSessions(c echo.Context, dmID int64) ([]model.AuthSession, error)
+	TouchAuthSession(c echo.Context, sessionID int64) error
+	RevokeAuthSession(c echo.Context, jwtID string) error
+	RevokeDogownerAuthSessions(c echo.Context, doID int64) error
+	RevokeDogrunmgAuthSessions(c echo.Context, dmID int64) error
+	CreatePasswordResetToken(c echo.Context, prt *model.PasswordResetToken) error
+	GetLatestPasswordResetToken(c echo.Context, prt model.PasswordResetToken) (model.PasswordResetToken, error)
+	GetPasswordResetTokenByHash(c echo.Context, tokenHash string) (model.PasswordResetToken, error)
+	ResetPassword(c echo.Context, prt model.PasswordResetToken, passwordHash string) (bool, error)
+	GetDogOwnerCredentialsByDogOwnerID(c echo.Context, doID int64) ([]model.DogOwnerCredential, error)
+	GetDogrunmgCredentialByDogrunmgID(c echo.Context, dmID int64) (model.DogrunmgCredential, error)
+	VerifyDogOwnerEmail(c echo.Context, credentialID int64, email string) (bool, error)
+	VerifyDogrunmgEmail(c echo.Context, credentialID int64, email string) (bool, error)
+	CreatePhoneVerificationCode(c echo.Context, pvc *model.PhoneVerificationCode) error
+	GetLatestPhoneVerificationCode(c echo.Context, credentialID int64) (model.PhoneVerificationCode, error)
+	ConsumePhoneVerificationAttempt(c echo.Context, codeID int64, maxAttempts int) (bool, error)
+	VerifyDogOwnerPhone(c echo.Context, pvc model.PhoneVerificationCode) (bool, error)
+	IsDogOwnerVerified(c echo.Context, doID int64) (bool, error)
+	IsDogrunmgVerified(c echo.Context, dmID int64) (bool, error)
 }
 
 type authRepository struct {
@@ -104,100 +126,59 @@ func (ar *authRepository) CreateDogOwner(c echo.Context, doc *model.DogOwnerCred
 	return doc, nil
 }
 
-/*
-OAuthユーザーの作成
-*/
-// func (ar *authRepository) CreateOAuthDogOwner(c echo.Context, doc *model.DogOwnerCredential) (*model.DogOwnerCredential, error) {
-// 	logger := log.GetLogger(c).Sugar()
-
-// 	// Emailの確認
-// 	if wrErr := ar.checkDuplicate(c, model.PhoneNumberField, doc); wrErr != nil {
-// 		return nil, wrErr
-// 	}
-
-// 	// PhoneNumberの確認
-
-// 	// トランザクションの開始
-// 	err := ar.db.Transaction(func(tx *gorm.DB) error {
-// 		// dog_ownersテーブルにレコード作成
-// 		if err := tx.Create(&doc.AuthDogOwner.DogOwner).Error; err != nil {
-// 			logger.Error("Failed to DogOwner: ", err)
-// 			return err
-// 		}
-
-// 		// DogOwnerが作成された後、そのIDをauthDogOwnerに設定
-// 		doc.AuthDogOwner.DogOwnerID = doc.AuthDogOwner.DogOwner.DogOwnerID
-
-// 		// auth_dog_ownersテーブルにレコード作成
-// 		if err := tx.Create(&doc.AuthDogOwner).Error; err != nil {
-// 			logger.Error("Failed to AuthDogOwner: ", err)
-// 			return err
-// 		}
-
-// 		// AuthDogOwnerが作成された後、そのIDをdogOwnerCredentialに設定
-// doc.AuthDogOwnerID = doc.AuthDogOwner.AuthDogOwnerID
-// 		// dog_owner_credentialsテーブルにレコード作成
-// 		if err := tx.Create(&doc).Error; err != nil {
-// 			logger.Error("Failed to DogOwnerCredential: ", err)
-// 			return err
-// 		}
-// 		return nil
-// 	})
-
-// 	if err != nil {
-// 		wrErr := wrErrors.NewWRError(
-// 			err,
-// 			"transaction failed",
-// 			wrErrors.NewDogOwnerClientErrorEType())
-
-// 		logger.Errorf("Transaction failed error: %v", wrErr)
-
-// 		return nil, wrErr
-// 	}
-
-// 	logger.Infof("Created DogOwner Detail: %v", doc.AuthDogOwner.DogOwner)
-// 	logger.Infof("Created AuthDogOwner Detail: %v", doc.AuthDogOwner)
-// 	logger.Infof("Created DogOwnerCredential Detail: %v", doc)
-
-// 	// レスポンス用にDogOwnerのクレデンシャル取得
-// 	var result model.DogOwnerCredential
-// 	err = ar.db.Preload("AuthDogOwner").Preload("AuthDogOwner.DogOwner").First(&result, doc.CredentialID).Error
-
-// 	if err != nil {
-// 		wrErr := wrErrors.NewWRError(
-// 			err,
-// 			"failed to fetch created record",
-// 			wrErrors.NewDogOwnerServerErrorEType())
-
-// 		logger.Errorf("Failed to fetch created record: %v", wrErr)
-
-// 		return nil, wrErr
-// 	}
-
-// 	logger.Infof("Created DogOwnerCredential Detail: %v", result)
-
-// 	return &result, nil
-// }
+// GetDogOwnerCredentialByProvider: OAuthプロバイダのユーザーIDからクレデンシャルの取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: プロバイダ名
+//   - string: プロバイダのユーザーID
+//
+// return:
+//   - model.DogOwnerCredential: ドッグオーナーのクレデンシャル。存在しない場合は空
+//   - error: error情報
+func (ar *authRepository) GetDogOwnerCredentialByProvider(c echo.Context, providerName string, providerUserID string) (model.DogOwnerCredential, error) {
+	logger := log.GetLogger(c).Sugar()
 
-// GetDogOwnerByCredentials: ドッグオーナーのクレデンシャル取得
+	var result model.DogOwnerCredential
+
+	if err := ar.db.Model(&model.DogOwnerCredential{}).
+		Preload("AuthDogOwner").
+		Where("provider_name = ? AND provider_user_id = ?", providerName, providerUserID).
+		First(&result).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.DogOwnerCredential{}, nil
+		}
+		wrErr := wrErrors.NewWRError(
+			err,
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewAuthServerErrorEType())
+
+		logger.Errorf("DB search failure: %v", wrErr)
+
+		return model.DogOwnerCredential{}, wrErr
+	}
+
+	return result, nil
+}
+
+// GetDogOwnerCredentialsByEmail: Emailからドッグオーナーのクレデンシャル取得(認証方式は問わない)
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - dto.AuthDogOwnerReq: authDogOwnerのリクエスト情報
+//   - string: Email
 //
 // return:
-//   - *model.DogOwnerCredential: ドッグオーナーのクレデンシャル
+//   - []model.DogOwnerCredential: ドッグオーナーのクレデンシャル
 //   - error: error情報
-func (ar *authRepository) GetDogOwnerByCredentials(c echo.Context, adoReq dto.AuthDogOwnerReq) ([]model.DogOwnerCredential, error) {
+func (ar *authRepository) GetDogOwnerCredentialsByEmail(c echo.Context, email string) ([]model.DogOwnerCredential, error) {
 	logger := log.GetLogger(c).Sugar()
 
 	var results []model.DogOwnerCredential
 
-	// EmailまたはPhoneNumberとgrantTypeがPASSWORDに基づくレコードを検索
 	if err := ar.db.Model(&model.DogOwnerCredential{}).
 		Preload("AuthDogOwner").
-		Preload("AuthDogOwner.DogOwner").
-		Where("(email = ? OR phone_number = ?) AND grant_type = ?", adoReq.Email, adoReq.PhoneNumber, model.PASSWORD_GRANT_TYPE).
+		Where("email = ?", email).
 		Find(&results).
 		Error; err != nil {
 		wrErr := wrErrors.NewWRError(
@@ -210,214 +191,203 @@ func (ar *authRepository) GetDogOwnerByCredentials(c echo.Context, adoReq dto.Au
 		return []model.DogOwnerCredential{}, wrErr
 	}
 
-	logger.Debugf("Query Results: %v", results)
-
 	return results, nil
 }
 
-// UpdateDogownerJwtID: 対象のdogownerのjwt_idの更新
+// CreateDogOwnerCredential: 既存のドッグオーナーへのクレデンシャルの追加(OAuthの紐づけ)
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - int64: dogownerのPK
-//   - string: 更新用のjwt_id
+//   - *model.DogOwnerCredential: 追加するクレデンシャル。AuthDogOwnerIDは設定済み
 //
 // return:
 //   - error: error情報
-func (ar *authRepository) UpdateDogownerJwtID(
-	c echo.Context,
-	doID int64,
-	ji string,
-) error {
+func (ar *authRepository) CreateDogOwnerCredential(c echo.Context, doc *model.DogOwnerCredential) error {
 	logger := log.GetLogger(c).Sugar()
 
-	// 対象のdogownerのjwt_idの更新
-	err := ar.db.Model(&model.AuthDogOwner{}).
-		Where("dog_owner_id= ?", doID).
-		Update("jwt_id", ji).Error
-
-	if err != nil {
+	// AuthDogOwnerを再作成しないよう、関連は保存しない
+	if err := ar.db.Omit("AuthDogOwner").Create(doc).Error; err != nil {
 		wrErr := wrErrors.NewWRError(
 			err,
-			"DBへの更新が失敗しました。",
-			wrErrors.NewAuthServerErrorEType())
+			"DBへの登録が失敗しました。",
+			wrErrors.NewDogOwnerServerErrorEType())
 
-		logger.Errorf("Failed to update JWT ID: %v", wrErr)
+		logger.Errorf("Failed to create DogOwnerCredential: %v", wrErr)
 
 		return wrErr
 	}
 
+	logger.Infof("Created DogOwnerCredential Detail: %v", doc)
+
 	return nil
 }
 
-// DeleteDogownerJwtID: 対象のdogownerのjwt_idの削除
+// GetDogOwnerByCredentials: ドッグオーナーのクレデンシャル取得
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - dto.DogownerDTO: dogownerの情報
+//   - dto.AuthDogOwnerReq: authDogOwnerのリクエスト情報
 //
 // return:
+//   - *model.DogOwnerCredential: ドッグオーナーのクレデンシャル
 //   - error: error情報
-func (ar *authRepository) DeleteDogownerJwtID(c echo.Context, doID int64) error {
+func (ar *authRepository) GetDogOwnerByCredentials(c echo.Context, adoReq dto.AuthDogOwnerReq) ([]model.DogOwnerCredential, error) {
 	logger := log.GetLogger(c).Sugar()
 
-	// 対象のdogOwnerのjwt_idの更新
-	if err := ar.db.Model(&model.AuthDogOwner{}).
-		Where("dog_owner_id= ?", doID).
-		Update("jwt_id", nil).Error; err != nil {
+	var results []model.DogOwnerCredential
+
+	// EmailまたはPhoneNumberとgrantTypeがPASSWORDに基づくレコードを検索
+	if err := ar.db.Model(&model.DogOwnerCredential{}).
+		Preload("AuthDogOwner").
+		Preload("AuthDogOwner.DogOwner").
+		Where("(email = ? OR phone_number = ?) AND grant_type = ?", adoReq.Email, adoReq.PhoneNumber, model.PASSWORD_GRANT_TYPE).
+		Find(&results).
+		Error; err != nil {
 		wrErr := wrErrors.NewWRError(
 			err,
-			"DBへの同期が失敗しました。",
-			wrErrors.NewAuthServerErrorEType(),
-		)
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewAuthServerErrorEType())
 
-		logger.Errorf("Failed to delete dogowner JWT ID: %v", wrErr)
+		logger.Errorf("DB search failure: %v", wrErr)
 
-		return wrErr
+		return []model.DogOwnerCredential{}, wrErr
 	}
 
-	return nil
+	logger.Debugf("Query Results: %v", results)
+
+	return results, nil
 }
 
-// DeleteDogrunmgJwtID: 対象のdogrunmgのjwt_idの削除
+// CheckDuplicate:  Password認証のバリデーション
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - int64: dogrunmgのID
+//   - string: 対象のdbのフィールド名
+//   - sql.NullString: 対象のgrant_typeの値
 //
 // return:
 //   - error: error情報
-func (ar *authRepository) DeleteDogrunmgJwtID(c echo.Context, dmID int64) error {
+func (ar *authRepository) CheckDuplicate(c echo.Context, field string, value sql.NullString) error {
 	logger := log.GetLogger(c).Sugar()
 
-	// 対象のdogrunmgのjwt_idの更新
-	if err := ar.db.Model(&model.AuthDogrunmg{}).
-		Where("dogrun_manager_id= ?", dmID).
-		Update("jwt_id", nil).Error; err != nil {
+	// 重複のvalidate
+	var existingCount int64
+
+	// grant_typeがPASSWORDで重複していないかの確認
+	if err := ar.db.Model(&model.DogOwnerCredential{}).
+		Where(field+" = ? AND grant_type = ?", value, model.PASSWORD_GRANT_TYPE).
+		Count(&existingCount).
+		Error; err != nil {
 		wrErr := wrErrors.NewWRError(
 			err,
-			"DBへの同期が失敗しました。",
+			"DBからのデータ取得に失敗しました。",
 			wrErrors.NewAuthServerErrorEType(),
 		)
 
-		logger.Errorf("Failed to delete dogrunmg JWT ID: %v", wrErr)
+		logger.Errorf("Failed to check existing value error: %v", wrErr)
 
 		return wrErr
 	}
 
+	if existingCount > 0 {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			fmt.Sprintf("%sの%sが既に登録されています。", field, value.String),
+			wrErrors.NewDogOwnerClientErrorEType(),
+		)
+
+		logger.Errorf("%s already exists error: %v", field, wrErr)
+
+		return wrErr
+	}
 	return nil
 }
 
-// GetJwtID: dogrunmgとdogonwerのjwtIDの取得(共通処理)
+// CountOrgEmail:  OrgのEmail数の取得
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - int64: 取得したいdogrunmgIDかdogownerID
-//   - any: modelType クエリ対象の構造体
-//   - any: result 検索結果の格納する構造体
-//   - string: 検索カラム名(dogrunmgIDかdogownerID)
+//   - string: 対象のEmail
 //
 // return:
-//   - string: 対象のjwt_id
+//   - int64: OrgのEmailの数
 //   - error: error情報
-func (ar *authRepository) GetJwtID(
-	c echo.Context,
-	userID int64,
-	modelType any,
-	result any,
-	columnName string,
-) (string, error) {
+func (ar *authRepository) CountOrgEmail(c echo.Context, email string) (int64, error) {
 	logger := log.GetLogger(c).Sugar()
 
-	// バリデーション関数
-	validateModel := func(value any, purpose string) error {
-		switch value.(type) {
-		// Authのdogrunmgかdogownerの型チェック
-		case *model.AuthDogOwner, *model.AuthDogrunmg:
-			// バリデーションOK
-		default:
-			logger.Errorf("Invalid %s model type: %T", purpose, value)
-
-			return wrErrors.NewWRError(
-				nil,
-				fmt.Sprintf("%sが無効な構造体の型です。", purpose),
-				wrErrors.NewUnexpectedErrorEType(),
-			)
-		}
-		return nil
-	}
+	// email数の取得数
+	var existingCount int64
 
-	// modelTypeのバリデーション
-	if err := validateModel(modelType, "クエリ対象"); err != nil {
-		return "", err
-	}
+	// Emailの数の取得
+	if err := ar.db.Model(&model.DogrunmgCredential{}).
+		Where("email"+" = ?", email).
+		Count(&existingCount).
+		Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
 
-	// resultのバリデーション
-	if err := validateModel(result, "結果格納先"); err != nil {
-		return "", err
-	}
+		logger.Errorf("Failed to check existing value error: %v", wrErr)
 
-	// 対象のjwt_id取得
-	err := ar.db.Model(modelType).
-		Where(columnName+" = ?", userID).
-		First(result).
-		Error
+		return 0, wrErr
+	}
 
-	if err != nil {
-		// jwt_idが見つからない場合
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			logger.Errorf("Not found jwt id error for %s: %v", columnName, err)
-			return "", wrErrors.NewWRError(
-				err,
-				"認証情報がありません",
-				wrErrors.NewAuthClientErrorEType(),
-			)
-		}
+	return existingCount, nil
+}
 
-		logger.Errorf("Failed to get JWT ID for %s: %v", columnName, err)
+// GetDogrunmgByCredentials: Emailを元にdogrunmgのクレデンシャル取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: authDogrunmgのemail
+//
+// return:
+//   - []model.DogrunmgCredential: 取得したdogrunmgの情報
+//   - error: error情報
+func (ar *authRepository) GetDogrunmgByCredentials(c echo.Context, email string) ([]model.DogrunmgCredential, error) {
+	logger := log.GetLogger(c).Sugar()
 
-		// その他DB関連のエラー処理
-		return "", wrErrors.NewWRError(
+	var results []model.DogrunmgCredential
+	// Emailに基づくレコードを検索
+	if err := ar.db.Model(&model.DogrunmgCredential{}).
+		Preload("AuthDogrunmg").          // AuthDogrunmgをロード
+		Preload("AuthDogrunmg.Dogrunmg"). // AuthDogrunmgに紐づくDogrunmgをロード
+		Where("email = ?", email).
+		Find(&results).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
 			err,
 			"DBからのデータ取得に失敗しました。",
 			wrErrors.NewAuthServerErrorEType(),
 		)
+
+		logger.Errorf("DB search failure: %v", wrErr)
+
+		return nil, wrErr
 	}
-	logger.Debugf("Query Result: %v", result)
-
-	// JWT ID取得
-	switch v := result.(type) {
-	case *model.AuthDogOwner:
-		return v.JwtID.String, nil
-	case *model.AuthDogrunmg:
-		return v.JwtID.String, nil
-	default:
-		logger.Errorf("Unexpected model type after query: %T", result)
-		return "", wrErrors.NewWRError(
-			nil,
-			"予期しないモデルタイプです。",
-			wrErrors.NewUnexpectedErrorEType(),
-		)
-	}
+
+	logger.Debugf("Query Result: %v", results)
+
+	return results, nil
 }
 
-// GetDogownerJwtID: dogonwerのjwtIDの取得
+// GetAuthDogrunmg: dogrunmgの認証情報の取得
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - int64: 取得したいdogownerID
+//   - int64: dogrunmgのID
 //
 // return:
-//   - string: 対象のjwt_id
-//   - error: error情報
-func (ar *authRepository) GetDogownerJwtID(c echo.Context, dogownerID int64) (string, error) {
+//   - model.AuthDogrunmg: dogrunmgの認証情報
+//   - error: error情報。存在しない場合はクライアントエラー
+func (ar *authRepository) GetAuthDogrunmg(c echo.Context, dmID int64) (model.AuthDogrunmg, error) {
 	logger := log.GetLogger(c).Sugar()
 
-	var result model.AuthDogOwner
+	var result model.AuthDogrunmg
 
-	// 対象のdogOwnerのjwt_idの取得
-	err := ar.db.Model(&model.AuthDogOwner{}).
-		Where("dog_owner_id= ?", dogownerID).
+	err := ar.db.Model(&model.AuthDogrunmg{}).
+		Where("dogrun_manager_id= ?", dmID).
 		First(&result).
 		Error
 
@@ -429,221 +399,361 @@ func (ar *authRepository) GetDogownerJwtID(c echo.Context, dogownerID int64) (st
 				"認証情報がありません",
 				wrErrors.NewAuthClientErrorEType())
 
-			logger.Errorf("Not found jwt id error: %v", wrErr)
+			logger.Errorf("Not found auth dogrunmg error: %v", wrErr)
 
-			return "", wrErr
+			return model.AuthDogrunmg{}, wrErr
 		}
 
-		// その他のエラー処理
 		wrErr := wrErrors.NewWRError(
 			err,
 			"DBからのデータ取得に失敗しました。",
 			wrErrors.NewAuthServerErrorEType())
 
-		logger.Errorf("Failed to get JWT ID: %v", wrErr)
+		logger.Errorf("Failed to get auth dogrunmg: %v", wrErr)
 
-		return "", wrErr
+		return model.AuthDogrunmg{}, wrErr
 	}
 
-	logger.Debugf("Query Result: %v", result)
-
-	return result.JwtID.String, nil
-
+	return result, nil
 }
 
-// GetDogrunmgJwtID: dogrunmgのjwtIDの取得
+// CreateRefreshToken: リフレッシュトークンの登録
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - int64: 取得したいdogrunmgID
+//   - *model.RefreshToken: 登録するリフレッシュトークン(ハッシュ値)
 //
 // return:
-//   - string: 対象のjwt_id
 //   - error: error情報
-func (ar *authRepository) GetDogrunmgJwtID(c echo.Context, dogrunmgID int64) (string, error) {
+func (ar *authRepository) CreateRefreshToken(c echo.Context, rt *model.RefreshToken) error {
 	logger := log.GetLogger(c).Sugar()
 
-	var result model.AuthDogrunmg
+	if err := ar.db.Create(rt).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"リフレッシュトークンの登録に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
 
-	// 対象のdogrunmgのjwt_idの取得
-	err := ar.db.Model(&model.AuthDogrunmg{}).
-		Where("dogrun_manager_id= ?", dogrunmgID).
-		First(&result).
-		Error
+		logger.Errorf("Failed to create refresh token: %v", wrErr)
 
-	if err != nil {
-		// 空だった時
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			wrErr := wrErrors.NewWRError(
-				err,
-				"認証情報がありません",
-				wrErrors.NewAuthClientErrorEType())
+		return wrErr
+	}
 
-			logger.Errorf("Not found jwt id error: %v", wrErr)
+	return nil
+}
 
-			return "", wrErr
-		}
+// GetRefreshTokenByHash: ハッシュ値からリフレッシュトークンの取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: リフレッシュトークンのハッシュ値
+//
+// return:
+//   - model.RefreshToken: リフレッシュトークン。存在しない場合は空
+//   - error: error情報
+func (ar *authRepository) GetRefreshTokenByHash(c echo.Context, tokenHash string) (model.RefreshToken, error) {
+	logger := log.GetLogger(c).Sugar()
 
-		// その他のエラー処理
+	var result model.RefreshToken
+
+	if err := ar.db.Model(&model.RefreshToken{}).
+		Where("token_hash = ?", tokenHash).
+		Find(&result).Error; err != nil {
 		wrErr := wrErrors.NewWRError(
 			err,
 			"DBからのデータ取得に失敗しました。",
-			wrErrors.NewAuthServerErrorEType())
+			wrErrors.NewAuthServerErrorEType(),
+		)
 
-		logger.Errorf("Failed to get JWT ID: %v", wrErr)
+		logger.Errorf("Failed to get refresh token: %v", wrErr)
 
-		return "", wrErr
+		return model.RefreshToken{}, wrErr
 	}
 
-	logger.Debugf("Query Result: %v", result)
-
-	return result.JwtID.String, nil
-
+	return result, nil
 }
 
-// CheckDuplicate:  Password認証のバリデーション
+// RotateRefreshToken: リフレッシュトークンのローテーション
+// 使用したトークンを使用済みにして、同じファミリーの新しいトークンを登録する
+// あわせてファミリーのセッションの有効期限を延長する
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - string: 対象のdbのフィールド名
-//   - sql.NullString: 対象のgrant_typeの値
+//   - int64: 使用したリフレッシュトークンのID
+//   - *model.RefreshToken: 新しく登録するリフレッシュトークン(ハッシュ値)
 //
 // return:
+//   - bool: ローテーションできたか。同時に使用されて使用済み・失効済みだった場合はfalse
 //   - error: error情報
-func (ar *authRepository) CheckDuplicate(c echo.Context, field string, value sql.NullString) error {
+func (ar *authRepository) RotateRefreshToken(c echo.Context, rotatedID int64, rt *model.RefreshToken) (bool, error) {
 	logger := log.GetLogger(c).Sugar()
 
-	// 重複のvalidate
-	var existingCount int64
+	rotated := false
+	err := ar.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.RefreshToken{}).
+			Where("refresh_token_id = ?", rotatedID).
+			Where("rotated_at IS NULL").
+			Where("revoked_at IS NULL").
+			Update("rotated_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Create(rt).Error; err != nil {
+			return err
+		}
+		// セッションの有効期限を新しいトークンにあわせて延長
+		if err := tx.Model(&model.AuthSession{}).
+			Where("jwt_id = ?", rt.FamilyID).
+			Updates(map[string]interface{}{
+				"last_seen_at": time.Now(),
+				"expires_at":   rt.ExpiresAt,
+			}).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
 
-	// grant_typeがPASSWORDで重複していないかの確認
-	if err := ar.db.Model(&model.DogOwnerCredential{}).
-		Where(field+" = ? AND grant_type = ?", value, model.PASSWORD_GRANT_TYPE).
-		Count(&existingCount).
-		Error; err != nil {
+	if err != nil {
 		wrErr := wrErrors.NewWRError(
 			err,
-			"DBからのデータ取得に失敗しました。",
+			"リフレッシュトークンのローテーションに失敗しました。",
 			wrErrors.NewAuthServerErrorEType(),
 		)
 
-		logger.Errorf("Failed to check existing value error: %v", wrErr)
+		logger.Errorf("Failed to rotate refresh token: %v", wrErr)
 
-		return wrErr
+		return false, wrErr
 	}
 
-	if existingCount > 0 {
+	return rotated, nil
+}
+
+// CreateAuthSession: ログインセッションとリフレッシュトークンの登録
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - *model.AuthSession: 登録するセッション
+//   - *model.RefreshToken: セッションのリフレッシュトークン(ハッシュ値)
+//
+// return:
+//   - error: error情報
+func (ar *authRepository) CreateAuthSession(c echo.Context, as *model.AuthSession, rt *model.RefreshToken) error {
+	logger := log.GetLogger(c).Sugar()
+
+	err := ar.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(as).Error; err != nil {
+			return err
+		}
+		return tx.Create(rt).Error
+	})
+
+	if err != nil {
 		wrErr := wrErrors.NewWRError(
-			nil,
-			fmt.Sprintf("%sの%sが既に登録されています。", field, value.String),
-			wrErrors.NewDogOwnerClientErrorEType(),
+			err,
+			"セッションの登録に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
 		)
 
-		logger.Errorf("%s already exists error: %v", field, wrErr)
+		logger.Errorf("Failed to create auth session: %v", wrErr)
 
 		return wrErr
 	}
+
 	return nil
 }
 
-// CountOrgEmail:  OrgのEmail数の取得
+// GetAuthSessionByJwtID: jwt_idからセッションの取得
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - string: 対象のEmail
+//   - string: jwt_id
 //
 // return:
-//   - int64: OrgのEmailの数
+//   - model.AuthSession: セッション。存在しない場合は空
 //   - error: error情報
-func (ar *authRepository) CountOrgEmail(c echo.Context, email string) (int64, error) {
+func (ar *authRepository) GetAuthSessionByJwtID(c echo.Context, jwtID string) (model.AuthSession, error) {
 	logger := log.GetLogger(c).Sugar()
 
-	// email数の取得数
-	var existingCount int64
+	var result model.AuthSession
 
-	// Emailの数の取得
-	if err := ar.db.Model(&model.DogrunmgCredential{}).
-		Where("email"+" = ?", email).
-		Count(&existingCount).
-		Error; err != nil {
+	if err := ar.db.Model(&model.AuthSession{}).
+		Where("jwt_id = ?", jwtID).
+		Find(&result).Error; err != nil {
 		wrErr := wrErrors.NewWRError(
 			err,
 			"DBからのデータ取得に失敗しました。",
 			wrErrors.NewAuthServerErrorEType(),
 		)
 
-		logger.Errorf("Failed to check existing value error: %v", wrErr)
+		logger.Errorf("Failed to get auth session: %v", wrErr)
 
-		return 0, wrErr
+		return model.AuthSession{}, wrErr
 	}
 
-	return existingCount, nil
+	return result, nil
 }
 
-// GetDogrunmgByCredentials: Emailを元にdogrunmgのクレデンシャル取得
+// GetDogownerAuthSessions: dogownerの有効なセッション一覧の取得(最終利用日時の降順)
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - string: authDogrunmgのemail
+//   - int64: dogownerのID
 //
 // return:
-//   - []model.DogrunmgCredential: 取得したdogrunmgの情報
+//   - []model.AuthSession: セッション一覧
 //   - error: error情報
-func (ar *authRepository) GetDogrunmgByCredentials(c echo.Context, email string) ([]model.DogrunmgCredential, error) {
+func (ar *authRepository) GetDogownerAuthSessions(c echo.Context, doID int64) ([]model.AuthSession, error) {
+	return ar.getActiveAuthSessions(c, "dog_owner_id = ?", doID)
+}
+
+// GetDogrunmgAuthSessions: dogrunmgの有効なセッション一覧の取得(最終利用日時の降順)
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: dogrunmgのID
+//
+// return:
+//   - []model.AuthSession: セッション一覧
+//   - error: error情報
+func (ar *authRepository) GetDogrunmgAuthSessions(c echo.Context, dmID int64) ([]model.AuthSession, error) {
+	return ar.getActiveAuthSessions(c, "dogrun_manager_id = ?", dmID)
+}
+
+// TouchAuthSession: セッションの最終利用日時の更新
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: セッションのID
+//
+// return:
+//   - error: error情報
+func (ar *authRepository) TouchAuthSession(c echo.Context, sessionID int64) error {
 	logger := log.GetLogger(c).Sugar()
 
-	var results []model.DogrunmgCredential
-	// Emailに基づくレコードを検索
-	if err := ar.db.Model(&model.DogrunmgCredential{}).
-		Preload("AuthDogrunmg").          // AuthDogrunmgをロード
-		Preload("AuthDogrunmg.Dogrunmg"). // AuthDogrunmgに紐づくDogrunmgをロード
-		Where("email = ?", email).
-		Find(&results).Error; err != nil {
+	if err := ar.db.Model(&model.AuthSession{}).
+		Where("session_id = ?", sessionID).
+		Update("last_seen_at", time.Now()).Error; err != nil {
 		wrErr := wrErrors.NewWRError(
 			err,
-			"DBからのデータ取得に失敗しました。",
+			"DBへの更新が失敗しました。",
 			wrErrors.NewAuthServerErrorEType(),
 		)
 
-		logger.Errorf("DB search failure: %v", wrErr)
+		logger.Errorf("Failed to touch auth session: %v", wrErr)
 
-		return nil, wrErr
+		return wrErr
 	}
 
-	logger.Debugf("Query Result: %v", results)
+	return nil
+}
 
-	return results, nil
+// RevokeAuthSession: セッションとそのリフレッシュトークンの失効
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: セッションのjwt_id
+//
+// return:
+//   - error: error情報
+func (ar *authRepository) RevokeAuthSession(c echo.Context, jwtID string) error {
+	return ar.revokeAuthSessions(c, "jwt_id = ?", "family_id = ?", jwtID)
 }
 
-// UpdateDogrunmgJwtID: 対象のdogrunmgのjwt_idの更新
+// RevokeDogownerAuthSessions: dogownerのセッションとリフレッシュトークンをすべて失効
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - int64: dogrunmgのPK
-//   - string: 更新用のjwt_id
+//   - int64: dogownerのID
 //
 // return:
 //   - error: error情報
-func (ar *authRepository) UpdateDogrunmgJwtID(
-	c echo.Context,
-	dmID int64,
-	ji string,
-) error {
+func (ar *authRepository) RevokeDogownerAuthSessions(c echo.Context, doID int64) error {
+	return ar.revokeAuthSessions(c, "dog_owner_id = ?", "dog_owner_id = ?", doID)
+}
+
+// RevokeDogrunmgAuthSessions: dogrunmgのセッションとリフレッシュトークンをすべて失効
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: dogrunmgのID
+//
+// return:
+//   - error: error情報
+func (ar *authRepository) RevokeDogrunmgAuthSessions(c echo.Context, dmID int64) error {
+	return ar.revokeAuthSessions(c, "dogrun_manager_id = ?", "dogrun_manager_id = ?", dmID)
+}
+
+/*
+条件に一致する有効なセッションの取得(共通処理)
+*/
+func (ar *authRepository) getActiveAuthSessions(c echo.Context, query string, arg any) ([]model.AuthSession, error) {
 	logger := log.GetLogger(c).Sugar()
 
-	// 対象のdogrunmgのjwt_idの更新
-	if err := ar.db.Model(&model.AuthDogrunmg{}).
-		Where("dogrun_manager_id= ?", dmID).
-		Update("jwt_id", ji).Error; err != nil {
+	var results []model.AuthSession
+
+	if err := ar.db.Model(&model.AuthSession{}).
+		Where(query, arg).
+		Where("revoked_at IS NULL").
+		Where("expires_at > ?", time.Now()).
+		Order("last_seen_at DESC").
+		Find(&results).Error; err != nil {
 		wrErr := wrErrors.NewWRError(
 			err,
-			"DBへの更新が失敗しました。",
-			wrErrors.NewAuthServerErrorEType())
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
 
-		logger.Errorf("Failed to update JWT ID: %v", wrErr)
+		logger.Errorf("Failed to get auth sessions: %v", wrErr)
+
+		return nil, wrErr
+	}
+
+	return results, nil
+}
+
+/*
+条件に一致する未失効のセッションとリフレッシュトークンを失効(共通処理)
+*/
+func (ar *authRepository) revokeAuthSessions(c echo.Context, sessionQuery string, refreshTokenQuery string, arg any) error {
+	logger := log.GetLogger(c).Sugar()
+
+	err := ar.db.Transaction(func(tx *gorm.DB) error {
+		return revokeAuthSessionsTx(tx, sessionQuery, refreshTokenQuery, arg)
+	})
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"セッションの失効に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("Failed to revoke auth sessions: %v", wrErr)
 
 		return wrErr
 	}
 
 	return nil
 }
+
+/*
+条件に一致する未失効のセッションとリフレッシュトークンを失効(トランザクション内)
+*/
+func revokeAuthSessionsTx(tx *gorm.DB, sessionQuery string, refreshTokenQuery string, arg any) error {
+	now := time.Now()
+	if err := tx.Model(&model.AuthSession{}).
+		Where(sessionQuery, arg).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.RefreshToken{}).
+		Where(refreshTokenQuery, arg).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", now).Error
+}
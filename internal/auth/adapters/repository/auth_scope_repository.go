@@ -15,6 +15,8 @@ type IAuthScopeRepository interface {
 	CreateDogOwnerCredential(tx *gorm.DB, c echo.Context, doc *model.DogOwnerCredential) error
 	CreateAuthDogrunmg(tx *gorm.DB, c echo.Context, adm *model.AuthDogrunmg) (sql.NullInt64, error)
 	CreateDogrunmgCredential(tx *gorm.DB, c echo.Context, dmc *model.DogrunmgCredential) error
+	CreateRefreshToken(tx *gorm.DB, c echo.Context, rt *model.RefreshToken) error
+	CreateAuthSession(tx *gorm.DB, c echo.Context, as *model.AuthSession) error
 }
 
 type authScopeRepository struct {
@@ -148,3 +150,59 @@ func (asr *authScopeRepository) CreateDogrunmgCredential(
 
 	return nil
 }
+
+// CreateRefreshToken: リフレッシュトークンの登録処理
+//
+// args:
+//   - *gorm.DB: トランザクションを張っているtx情報
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//   - *model.RefreshToken: 登録するリフレッシュトークン(ハッシュ値)
+//
+// return:
+//   - error: error情報
+func (asr *authScopeRepository) CreateRefreshToken(
+	tx *gorm.DB,
+	c echo.Context,
+	rt *model.RefreshToken,
+) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := tx.Create(rt).Error; err != nil {
+		logger.Error("Failed to create RefreshToken: ", err)
+		return wrErrors.NewWRError(
+			err,
+			"リフレッシュトークンの登録に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+	}
+
+	return nil
+}
+
+// CreateAuthSession: ログインセッションの登録処理
+//
+// args:
+//   - *gorm.DB: トランザクションを張っているtx情報
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//   - *model.AuthSession: 登録するセッション
+//
+// return:
+//   - error: error情報
+func (asr *authScopeRepository) CreateAuthSession(
+	tx *gorm.DB,
+	c echo.Context,
+	as *model.AuthSession,
+) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := tx.Create(as).Error; err != nil {
+		logger.Error("Failed to create AuthSession: ", err)
+		return wrErrors.NewWRError(
+			err,
+			"セッションの登録に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+	}
+
+	return nil
+}
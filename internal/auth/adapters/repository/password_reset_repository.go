@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+)
+
+// errPasswordResetTokenUsed: 同時に使用されて再設定トークンを使用済みにできなかった
+var errPasswordResetTokenUsed = errors.New("password reset token already used")
+
+// CreatePasswordResetToken: パスワード再設定トークンの登録
+// 同じユーザーの未使用のトークンは使用済みにして、最新のトークンのみ使用できるようにする
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - *model.PasswordResetToken: 登録する再設定トークン(ハッシュ値)
+//
+// return:
+//   - error: error情報
+func (ar *authRepository) CreatePasswordResetToken(c echo.Context, prt *model.PasswordResetToken) error {
+	logger := log.GetLogger(c).Sugar()
+
+	query, arg := passwordResetTokenOwner(*prt)
+
+	err := ar.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.PasswordResetToken{}).
+			Where(query, arg).
+			Where("used_at IS NULL").
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(prt).Error
+	})
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"パスワード再設定トークンの登録に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("Failed to create password reset token: %v", wrErr)
+
+		return wrErr
+	}
+
+	return nil
+}
+
+// GetLatestPasswordResetToken: ユーザーの最新のパスワード再設定トークンの取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - model.PasswordResetToken: 発行先のユーザーを設定した再設定トークン
+//
+// return:
+//   - model.PasswordResetToken: 再設定トークン。存在しない場合は空
+//   - error: error情報
+func (ar *authRepository) GetLatestPasswordResetToken(c echo.Context, prt model.PasswordResetToken) (model.PasswordResetToken, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	query, arg := passwordResetTokenOwner(prt)
+
+	var result model.PasswordResetToken
+
+	if err := ar.db.Where(query, arg).
+		Order("password_reset_token_id DESC").
+		Limit(1).
+		Find(&result).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("Failed to get latest password reset token: %v", wrErr)
+
+		return model.PasswordResetToken{}, wrErr
+	}
+
+	return result, nil
+}
+
+// GetPasswordResetTokenByHash: ハッシュ値からパスワード再設定トークンの取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: 再設定トークンのハッシュ値
+//
+// return:
+//   - model.PasswordResetToken: 再設定トークン。存在しない場合は空
+//   - error: error情報
+func (ar *authRepository) GetPasswordResetTokenByHash(c echo.Context, tokenHash string) (model.PasswordResetToken, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	var result model.PasswordResetToken
+
+	if err := ar.db.Where("token_hash = ?", tokenHash).Find(&result).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("Failed to get password reset token: %v", wrErr)
+
+		return model.PasswordResetToken{}, wrErr
+	}
+
+	return result, nil
+}
+
+// ResetPassword: パスワードの再設定
+// 再設定トークンの使用、パスワードの更新、全セッションの失効を1つのトランザクションで行う
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - model.PasswordResetToken: 使用する再設定トークン
+//   - string: 新しいパスワードのハッシュ値
+//
+// return:
+//   - bool: 再設定できたか。同時に使用されて使用済みになっていた場合はfalse
+//   - error: error情報
+func (ar *authRepository) ResetPassword(c echo.Context, prt model.PasswordResetToken, passwordHash string) (bool, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	err := ar.db.Transaction(func(tx *gorm.DB) error {
+		// 未使用の場合のみ使用済みにする
+		result := tx.Model(&model.PasswordResetToken{}).
+			Where("password_reset_token_id = ? AND used_at IS NULL", prt.PasswordResetTokenID.Int64).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errPasswordResetTokenUsed
+		}
+
+		if prt.DogOwnerID.Valid {
+			// OAuthのクレデンシャルにはパスワードがないため、PASSWORDのみ更新
+			if err := tx.Model(&model.DogOwnerCredential{}).
+				Where("grant_type = ?", model.PASSWORD_GRANT_TYPE).
+				Where("auth_dog_owner_id IN (?)",
+					tx.Model(&model.AuthDogOwner{}).
+						Select("auth_dog_owner_id").
+						Where("dog_owner_id = ?", prt.DogOwnerID.Int64)).
+				Update("password", passwordHash).Error; err != nil {
+				return err
+			}
+			return revokeAuthSessionsTx(tx, "dog_owner_id = ?", "dog_owner_id = ?", prt.DogOwnerID.Int64)
+		}
+
+		if err := tx.Model(&model.DogrunmgCredential{}).
+			Where("auth_dogrun_manager_id IN (?)",
+				tx.Model(&model.AuthDogrunmg{}).
+					Select("auth_dogrun_manager_id").
+					Where("dogrun_manager_id = ?", prt.DogrunmgID.Int64)).
+			Update("password", passwordHash).Error; err != nil {
+			return err
+		}
+		return revokeAuthSessionsTx(tx, "dogrun_manager_id = ?", "dogrun_manager_id = ?", prt.DogrunmgID.Int64)
+	})
+
+	if errors.Is(err, errPasswordResetTokenUsed) {
+		return false, nil
+	}
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"パスワードの再設定に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("Failed to reset password: %v", wrErr)
+
+		return false, wrErr
+	}
+
+	return true, nil
+}
+
+/*
+再設定トークンの発行先のユーザーの検索条件
+*/
+func passwordResetTokenOwner(prt model.PasswordResetToken) (string, sql.NullInt64) {
+	if prt.DogOwnerID.Valid {
+		return "dog_owner_id = ?", prt.DogOwnerID
+	}
+	return "dogrun_manager_id = ?", prt.DogrunmgID
+}
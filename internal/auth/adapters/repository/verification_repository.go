@@ -0,0 +1,409 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+)
+
+// errPhoneVerificationCodeUsed: 同時に使用されてワンタイムコードを使用済みにできなかった
+var errPhoneVerificationCodeUsed = errors.New("phone verification code already used")
+
+// GetDogOwnerCredentialsByDogOwnerID: dogownerの全クレデンシャルの取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: dogownerのID
+//
+// return:
+//   - []model.DogOwnerCredential: クレデンシャルのリスト
+//   - error: error情報
+func (ar *authRepository) GetDogOwnerCredentialsByDogOwnerID(c echo.Context, doID int64) ([]model.DogOwnerCredential, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	var results []model.DogOwnerCredential
+
+	if err := ar.db.Model(&model.DogOwnerCredential{}).
+		Where("auth_dog_owner_id IN (?)",
+			ar.db.Model(&model.AuthDogOwner{}).
+				Select("auth_dog_owner_id").
+				Where("dog_owner_id = ?", doID)).
+		Order("credential_id").
+		Find(&results).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("DB search failure: %v", wrErr)
+
+		return []model.DogOwnerCredential{}, wrErr
+	}
+
+	return results, nil
+}
+
+// GetDogrunmgCredentialByDogrunmgID: dogrunmgのクレデンシャルの取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: dogrunmgのID
+//
+// return:
+//   - model.DogrunmgCredential: クレデンシャル。存在しない場合は空
+//   - error: error情報
+func (ar *authRepository) GetDogrunmgCredentialByDogrunmgID(c echo.Context, dmID int64) (model.DogrunmgCredential, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	var result model.DogrunmgCredential
+
+	if err := ar.db.Model(&model.DogrunmgCredential{}).
+		Preload("AuthDogrunmg").
+		Where("auth_dogrun_manager_id IN (?)",
+			ar.db.Model(&model.AuthDogrunmg{}).
+				Select("auth_dogrun_manager_id").
+				Where("dogrun_manager_id = ?", dmID)).
+		Find(&result).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("DB search failure: %v", wrErr)
+
+		return model.DogrunmgCredential{}, wrErr
+	}
+
+	return result, nil
+}
+
+// VerifyDogOwnerEmail: dogownerのメールアドレスを確認済みにする
+// 確認メールの送信後にメールアドレスが変更されていた場合は確認済みにしない
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: クレデンシャルのID
+//   - string: 確認したメールアドレス
+//
+// return:
+//   - bool: 確認済みにできたか(既に確認済みの場合もtrue)
+//   - error: error情報
+func (ar *authRepository) VerifyDogOwnerEmail(c echo.Context, credentialID int64, email string) (bool, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	result := ar.db.Model(&model.DogOwnerCredential{}).
+		Where("credential_id = ? AND email = ?", credentialID, email).
+		Update("email_verified_at", gorm.Expr("COALESCE(email_verified_at, ?)", time.Now()))
+
+	if result.Error != nil {
+		wrErr := wrErrors.NewWRError(
+			result.Error,
+			"メールアドレスの確認に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("Failed to verify dogowner email: %v", wrErr)
+
+		return false, wrErr
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// VerifyDogrunmgEmail: dogrunmgのメールアドレスを確認済みにする
+// 所属するorganizationの連絡先と同じメールアドレスの場合は、連絡先も確認済みにする
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: クレデンシャルのID
+//   - string: 確認したメールアドレス
+//
+// return:
+//   - bool: 確認済みにできたか(既に確認済みの場合もtrue)
+//   - error: error情報
+func (ar *authRepository) VerifyDogrunmgEmail(c echo.Context, credentialID int64, email string) (bool, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	var verified bool
+
+	err := ar.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&model.DogrunmgCredential{}).
+			Where("credential_id = ? AND email = ?", credentialID, email).
+			Update("email_verified_at", gorm.Expr("COALESCE(email_verified_at, ?)", now))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		verified = true
+
+		return tx.Model(&model.Organization{}).
+			Where("contact_email = ?", email).
+			Where("organization_id IN (?)",
+				tx.Model(&model.Dogrunmg{}).
+					Select("organization_id").
+					Where("dogrun_manager_id IN (?)",
+						tx.Model(&model.AuthDogrunmg{}).
+							Select("dogrun_manager_id").
+							Where("auth_dogrun_manager_id IN (?)",
+								tx.Model(&model.DogrunmgCredential{}).
+									Select("auth_dogrun_manager_id").
+									Where("credential_id = ?", credentialID)))).
+			Update("contact_email_verified_at", gorm.Expr("COALESCE(contact_email_verified_at, ?)", now)).Error
+	})
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"メールアドレスの確認に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("Failed to verify dogrunmg email: %v", wrErr)
+
+		return false, wrErr
+	}
+
+	return verified, nil
+}
+
+// CreatePhoneVerificationCode: 電話番号確認用のワンタイムコードの登録
+// 同じクレデンシャルの未使用のコードは使用済みにして、最新のコードのみ使用できるようにする
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - *model.PhoneVerificationCode: 登録するワンタイムコード(ハッシュ値)
+//
+// return:
+//   - error: error情報
+func (ar *authRepository) CreatePhoneVerificationCode(c echo.Context, pvc *model.PhoneVerificationCode) error {
+	logger := log.GetLogger(c).Sugar()
+
+	err := ar.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.PhoneVerificationCode{}).
+			Where("credential_id = ?", pvc.CredentialID).
+			Where("used_at IS NULL").
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(pvc).Error
+	})
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"確認コードの登録に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("Failed to create phone verification code: %v", wrErr)
+
+		return wrErr
+	}
+
+	return nil
+}
+
+// GetLatestPhoneVerificationCode: クレデンシャルの最新の電話番号確認用のワンタイムコードの取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: クレデンシャルのID
+//
+// return:
+//   - model.PhoneVerificationCode: ワンタイムコード。存在しない場合は空
+//   - error: error情報
+func (ar *authRepository) GetLatestPhoneVerificationCode(c echo.Context, credentialID int64) (model.PhoneVerificationCode, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	var result model.PhoneVerificationCode
+
+	if err := ar.db.Where("credential_id = ?", credentialID).
+		Order("phone_verification_code_id DESC").
+		Limit(1).
+		Find(&result).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("Failed to get phone verification code: %v", wrErr)
+
+		return model.PhoneVerificationCode{}, wrErr
+	}
+
+	return result, nil
+}
+
+// ConsumePhoneVerificationAttempt: ワンタイムコードの試行回数を消費
+// 同時に入力された場合も上限を超えないよう、上限未満の場合のみ1つのUPDATEで加算する
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: ワンタイムコードのID
+//   - int: 試行回数の上限
+//
+// return:
+//   - bool: 試行回数を消費できたか。上限に達した、使用済みの場合はfalse
+//   - error: error情報
+func (ar *authRepository) ConsumePhoneVerificationAttempt(c echo.Context, codeID int64, maxAttempts int) (bool, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	result := ar.db.Model(&model.PhoneVerificationCode{}).
+		Where("phone_verification_code_id = ? AND attempts < ? AND used_at IS NULL", codeID, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+
+	if result.Error != nil {
+		wrErr := wrErrors.NewWRError(
+			result.Error,
+			"確認コードの更新に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("Failed to consume phone verification attempt: %v", wrErr)
+
+		return false, wrErr
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// VerifyDogOwnerPhone: dogownerの電話番号を確認済みにする
+// ワンタイムコードの使用と電話番号の確認を1つのトランザクションで行う
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - model.PhoneVerificationCode: 使用するワンタイムコード
+//
+// return:
+//   - bool: 確認済みにできたか。同時に使用されていた、電話番号が変更されていた場合はfalse
+//   - error: error情報
+func (ar *authRepository) VerifyDogOwnerPhone(c echo.Context, pvc model.PhoneVerificationCode) (bool, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	err := ar.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// 未使用の場合のみ使用済みにする
+		result := tx.Model(&model.PhoneVerificationCode{}).
+			Where("phone_verification_code_id = ? AND used_at IS NULL", pvc.PhoneVerificationCodeID.Int64).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errPhoneVerificationCodeUsed
+		}
+
+		// コードの発行後に電話番号が変更されていた場合は確認済みにしない
+		result = tx.Model(&model.DogOwnerCredential{}).
+			Where("credential_id = ? AND phone_number = ?", pvc.CredentialID.Int64, pvc.PhoneNumber.String).
+			Update("phone_verified_at", gorm.Expr("COALESCE(phone_verified_at, ?)", now))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errPhoneVerificationCodeUsed
+		}
+		return nil
+	})
+
+	if errors.Is(err, errPhoneVerificationCodeUsed) {
+		return false, nil
+	}
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"電話番号の確認に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("Failed to verify dogowner phone: %v", wrErr)
+
+		return false, wrErr
+	}
+
+	return true, nil
+}
+
+// IsDogOwnerVerified: dogownerのメールアドレスか電話番号のどちらかが確認済みであるか
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: dogownerのID
+//
+// return:
+//   - bool: 確認済みであるか
+//   - error: error情報
+func (ar *authRepository) IsDogOwnerVerified(c echo.Context, doID int64) (bool, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	var count int64
+
+	if err := ar.db.Model(&model.DogOwnerCredential{}).
+		Where("auth_dog_owner_id IN (?)",
+			ar.db.Model(&model.AuthDogOwner{}).
+				Select("auth_dog_owner_id").
+				Where("dog_owner_id = ?", doID)).
+		Where("email_verified_at IS NOT NULL OR phone_verified_at IS NOT NULL").
+		Count(&count).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("DB search failure: %v", wrErr)
+
+		return false, wrErr
+	}
+
+	return count > 0, nil
+}
+
+// IsDogrunmgVerified: dogrunmgのメールアドレスが確認済みであるか
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: dogrunmgのID
+//
+// return:
+//   - bool: 確認済みであるか
+//   - error: error情報
+func (ar *authRepository) IsDogrunmgVerified(c echo.Context, dmID int64) (bool, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	var count int64
+
+	if err := ar.db.Model(&model.DogrunmgCredential{}).
+		Where("auth_dogrun_manager_id IN (?)",
+			ar.db.Model(&model.AuthDogrunmg{}).
+				Select("auth_dogrun_manager_id").
+				Where("dogrun_manager_id = ?", dmID)).
+		Where("email_verified_at IS NOT NULL").
+		Count(&count).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+
+		logger.Errorf("DB search failure: %v", wrErr)
+
+		return false, wrErr
+	}
+
+	return count > 0, nil
+}
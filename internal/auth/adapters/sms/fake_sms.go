@@ -0,0 +1,65 @@
+package sms
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+type fakeSmsGateway struct {
+	dir string
+}
+
+// NewFakeSmsGateway: 開発用のSMS送信の生成
+// 実際には送信せず、ディレクトリが指定されていれば.txtファイルに、未指定の場合はログに出力する
+//
+// args:
+//   - string: 出力先のディレクトリ。空の場合はログに出力
+//
+// return:
+//   - ISmsGateway: SMS送信
+func NewFakeSmsGateway(dir string) ISmsGateway {
+	return &fakeSmsGateway{dir}
+}
+
+// Send: SMSをファイル・ログに出力
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。ログの出力に使用
+//   - string: 送信先の電話番号
+//   - string: 本文
+//
+// return:
+//   - error: error情報
+func (fs *fakeSmsGateway) Send(c echo.Context, to string, message string) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if fs.dir == "" {
+		logger.Infof("SMS (not sent). to: %s\n%s", to, message)
+		return nil
+	}
+
+	if err := os.MkdirAll(fs.dir, 0o755); err != nil {
+		wrErr := wrErrors.NewWRError(err, "SMSの出力に失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	name := fmt.Sprintf("%s_%s.txt", time.Now().Format("20060102150405.000000"), to)
+	path := filepath.Join(fs.dir, filepath.Base(name))
+
+	if err := os.WriteFile(path, []byte(fmt.Sprintf("To: %s\n\n%s\n", to, message)), 0o644); err != nil {
+		wrErr := wrErrors.NewWRError(err, "SMSの出力に失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	logger.Infof("SMS written to %s. to: %s", path, to)
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package sms
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+const (
+	SMS_HTTP_TIMEOUT = 10 * time.Second
+)
+
+// SMSゲートウェイへのリクエストボディ
+type smsPayload struct {
+	From    string `json:"from,omitempty"`
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+type httpSmsGateway struct {
+	url    string
+	token  string
+	from   string
+	client *http.Client
+}
+
+// NewHttpSmsGateway: HTTP APIのSMSゲートウェイでの送信の生成
+// 送信先・本文をJSONでPOSTする。トークンが設定されている場合はBearer認証を付与する
+//
+// args:
+//   - string: SMSゲートウェイのURL
+//   - string: 認証トークン
+//   - string: 送信元(番号・名称)
+//
+// return:
+//   - ISmsGateway: SMS送信
+func NewHttpSmsGateway(url string, token string, from string) ISmsGateway {
+	return &httpSmsGateway{
+		url:    url,
+		token:  token,
+		from:   from,
+		client: &http.Client{Timeout: SMS_HTTP_TIMEOUT},
+	}
+}
+
+// Send: SMSゲートウェイでSMSを送信
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。ログの出力に使用
+//   - string: 送信先の電話番号
+//   - string: 本文
+//
+// return:
+//   - error: error情報
+func (hs *httpSmsGateway) Send(c echo.Context, to string, message string) error {
+	logger := log.GetLogger(c).Sugar()
+
+	body, err := json.Marshal(smsPayload{From: hs.from, To: to, Message: message})
+	if err != nil {
+		wrErr := wrErrors.NewWRError(err, "SMSの送信に失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	req, err := http.NewRequestWithContext(c.Request().Context(), http.MethodPost, hs.url, bytes.NewReader(body))
+	if err != nil {
+		wrErr := wrErrors.NewWRError(err, "SMSの送信に失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Error(wrErr)
+		return wrErr
+	}
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if hs.token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+hs.token)
+	}
+
+	resp, err := hs.client.Do(req)
+	if err != nil {
+		wrErr := wrErrors.NewWRError(err, "SMSゲートウェイとの通信に失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Errorf("SMS request error: %v", wrErr)
+		return wrErr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		wrErr := wrErrors.NewWRError(
+			fmt.Errorf("status code: %d", resp.StatusCode),
+			"SMSの送信に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+		logger.Errorf("SMS gateway error: %v", wrErr)
+		return wrErr
+	}
+
+	logger.Infof("SMS sent. to: %s", to)
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package sms
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/configs"
+)
+
+const (
+	SMS_BACKEND_HTTP = "http" // SMSゲートウェイのHTTP APIで送信
+	SMS_BACKEND_FAKE = "fake" // 送信せずにファイル・ログに出力(開発用)
+)
+
+type ISmsGateway interface {
+	Send(c echo.Context, to string, message string) error
+}
+
+// NewSmsGateway: 設定のバックエンドに応じたSMS送信の生成
+// http: SMSゲートウェイのHTTP APIで送信, fake: 送信せずにファイル・ログに出力
+// 送信されないまま気づかないことがないよう、不明なバックエンドはエラーとする
+//
+// return:
+//   - ISmsGateway: SMS送信
+//   - error: 不明なバックエンドの場合のエラー
+func NewSmsGateway() (ISmsGateway, error) {
+	switch backend := configs.FetchConfigStr("sms.backend"); backend {
+	case SMS_BACKEND_HTTP:
+		return NewHttpSmsGateway(
+			configs.FetchConfigStr("sms.http.url"),
+			configs.FetchConfigStr("sms.http.token"),
+			configs.FetchConfigStr("sms.from"),
+		), nil
+	case SMS_BACKEND_FAKE:
+		return NewFakeSmsGateway(configs.FetchConfigStr("sms.fake.dir")), nil
+	default:
+		return nil, fmt.Errorf("unknown sms.backend: %q", backend)
+	}
+}
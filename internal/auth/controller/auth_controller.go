@@ -4,9 +4,11 @@ import (
 
 	// "github.com/golang-jwt/jwt/v5"
 	"net/http"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/auth/core"
 	"github.com/wanrun-develop/wanrun/internal/auth/core/dto"
 	"github.com/wanrun-develop/wanrun/internal/auth/core/handler"
 	"github.com/wanrun-develop/wanrun/internal/wrcontext"
@@ -20,7 +22,21 @@ type IAuthController interface {
 	LogInDogrunmg(c echo.Context) error
 	RevokeDogowner(c echo.Context) error
 	RevokeDogrunmg(c echo.Context) error
-	// GoogleOAuth(c echo.Context) error
+	RefreshDogowner(c echo.Context) error
+	RefreshDogrunmg(c echo.Context) error
+	GetSessions(c echo.Context) error
+	RevokeSession(c echo.Context) error
+	RevokeAllSessions(c echo.Context) error
+	GoogleOAuth(c echo.Context) error
+	RequestPasswordResetDogowner(c echo.Context) error
+	RequestPasswordResetDogrunmg(c echo.Context) error
+	ConfirmPasswordResetDogowner(c echo.Context) error
+	ConfirmPasswordResetDogrunmg(c echo.Context) error
+	GetVerificationStatus(c echo.Context) error
+	SendEmailVerification(c echo.Context) error
+	ConfirmEmailVerification(c echo.Context) error
+	SendPhoneVerification(c echo.Context) error
+	ConfirmPhoneVerification(c echo.Context) error
 }
 
 type authController struct {
@@ -31,51 +47,39 @@ func NewAuthController(ah handler.IAuthHandler) IAuthController {
 	return &authController{ah}
 }
 
-/*
-GoogleのOAuth認証
-*/
-// func (ac *authController) GoogleOAuth(c echo.Context) error {
-// 	logger := log.GetLogger(c).Sugar()
-
-// 	// GrantTypeヘッダーを取得
-// 	grantTypeHeader := c.Request().Header.Get(string(types.OAUTH_IDENTIFICATION_HEADER))
-
-// 	// GrantTypeヘッダーのバリデーション
-// 	if err := dto.ValidateGrantTypeHeader(grantTypeHeader, string(types.OAUTH_GRANT_TYPE_HEADER)); err != nil {
-// 		err = wrErrors.NewWRError(err, "ヘッダー情報が異なります。", wrErrors.NewDogrunClientErrorEType())
-// 		logger.Error(err)
-// 		return err
-// 	}
-
-// 	// GrantTypeに型変換
-// 	grantType := types.GrantType(grantTypeHeader)
-// 	logger.Infof("grantTypeHeader: %v", grantType)
-
-// 	// 認証コードの取得
-// 	authorizationCode := c.QueryParam("code")
+// GoogleOAuth: GoogleのOAuthによるdogownerのログイン(未登録の場合はサインアップ)
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) GoogleOAuth(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
 
-// 	// ユーザーが承認しなかったら、エラーのクエリパラメータにくるため
-// 	oauthErrorCode := c.QueryParam("error")
+	agReq := dto.AuthGoogleReq{}
 
-// 	logger.Infof("authorizationCode: %v, oauthErrorCode: %v", authorizationCode, oauthErrorCode)
+	if err := c.Bind(&agReq); err != nil {
+		wrErr := errors.NewWRError(err, "入力項目に不正があります。", errors.NewAuthClientErrorEType())
+		logger.Error(wrErr)
+		return wrErr
+	}
 
-// 	// クエリパラメータのバリデーション
-// 	if err := dto.ValidateOAuthResCode(authorizationCode, oauthErrorCode); err != nil {
-// 		err = wrErrors.NewWRError(err, "承認をしてください。", wrErrors.NewDogrunClientErrorEType())
-// 		logger.Error(err)
-// 		return err
-// 	}
+	// 認可コードは必須
+	if err := validator.New().Struct(&agReq); err != nil {
+		wrErr := errors.NewWRError(err, "必須の項目に不正があります。", errors.NewAuthClientErrorEType())
+		logger.Error(wrErr)
+		return wrErr
+	}
 
-// 	// OAuth認証
-// 	resDogOwner, wrErr := ac.ah.GoogleOAuth(c, authorizationCode, grantType)
+	res, wrErr := ac.ah.GoogleOAuth(c, agReq)
 
-// 	if wrErr != nil {
-// 		return wrErr
-// 	}
+	if wrErr != nil {
+		return wrErr
+	}
 
-// 	// jwt処理
-// 	return jwtProcessing(c, resDogOwner)
-// }
+	return c.JSON(http.StatusOK, res)
+}
 
 // SignUp: Password認証
 //
@@ -137,33 +141,31 @@ func (ac *authController) LogInDogowner(c echo.Context) error {
 	}
 
 	// LogIn機能
-	token, wrErr := ac.ah.LogInDogowner(c, adoReq)
+	res, wrErr := ac.ah.LogInDogowner(c, adoReq)
 
 	if wrErr != nil {
 		return wrErr
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"accessToken": token,
-	})
+	return c.JSON(http.StatusOK, res)
 }
 
-// RevokeDogowner: dogownerのrevoke機能
+// RevokeDogowner: dogownerのrevoke機能(リクエストした端末のログアウト)
 //
 // args:
-//   - echo.Context: c Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
 //
 // return:
 //   - error: error情報
 func (ac *authController) RevokeDogowner(c echo.Context) error {
-	// claimsからdogrunmgのID取得
-	dogownerID, wrErr := wrcontext.GetLoginUserID(c)
+	// claimsからログインユーザーの情報取得
+	uaDTO, wrErr := getLoginUserAuthInfo(c)
 
 	if wrErr != nil {
 		return wrErr
 	}
 
-	if wrErr := ac.ah.RevokeDogowner(c, dogownerID); wrErr != nil {
+	if wrErr := ac.ah.RevokeCurrentSession(c, uaDTO); wrErr != nil {
 		return wrErr
 	}
 
@@ -203,18 +205,16 @@ func (ac *authController) LogInDogrunmg(c echo.Context) error {
 	}
 
 	// dogrunmgのLogIn
-	token, wrErr := ac.ah.LogInDogrunmg(c, admReq)
+	res, wrErr := ac.ah.LogInDogrunmg(c, admReq)
 
 	if wrErr != nil {
 		return wrErr
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"accessToken": token,
-	})
+	return c.JSON(http.StatusOK, res)
 }
 
-// RevokeDogrunmg: dogrunmgのrevoke機能
+// RevokeDogrunmg: dogrunmgのrevoke機能(リクエストした端末のログアウト)
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
@@ -222,20 +222,193 @@ func (ac *authController) LogInDogrunmg(c echo.Context) error {
 // return:
 //   - error: error情報
 func (ac *authController) RevokeDogrunmg(c echo.Context) error {
-	// claimsからdogrunmgのID取得
-	dogrunmgID, wrErr := wrcontext.GetLoginUserID(c)
+	// claimsからログインユーザーの情報取得
+	uaDTO, wrErr := getLoginUserAuthInfo(c)
 
 	if wrErr != nil {
 		return wrErr
 	}
 
-	if wrErr := ac.ah.RevokeDogrunmg(c, dogrunmgID); wrErr != nil {
+	if wrErr := ac.ah.RevokeCurrentSession(c, uaDTO); wrErr != nil {
 		return wrErr
 	}
 
 	return c.JSON(http.StatusOK, map[string]any{})
 }
 
+// RefreshDogowner: dogownerのアクセストークンの再発行
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) RefreshDogowner(c echo.Context) error {
+	rtReq, wrErr := bindRefreshTokenReq(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	// リフレッシュトークンのローテーション
+	res, wrErr := ac.ah.RefreshDogowner(c, rtReq)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// RefreshDogrunmg: dogrunmgのアクセストークンの再発行
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) RefreshDogrunmg(c echo.Context) error {
+	rtReq, wrErr := bindRefreshTokenReq(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	// リフレッシュトークンのローテーション
+	res, wrErr := ac.ah.RefreshDogrunmg(c, rtReq)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetSessions: ログインユーザーの有効なセッション一覧の取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) GetSessions(c echo.Context) error {
+	uaDTO, wrErr := getLoginUserAuthInfo(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	res, wrErr := ac.ah.GetSessions(c, uaDTO)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// RevokeSession: ログインユーザーのセッションを指定して失効
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) RevokeSession(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	uaDTO, wrErr := getLoginUserAuthInfo(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	sessionID, err := strconv.ParseInt(c.Param("sessionId"), 10, 64)
+	if err != nil || sessionID <= 0 {
+		// 認証エラー(401)にしないよう、ユーザーの種別ごとのクライアントエラーにする
+		eType := errors.NewDogrunmgClientErrorEType()
+		if uaDTO.RoleID == core.DOGOWNER_ROLE {
+			eType = errors.NewDogOwnerClientErrorEType()
+		}
+		wrErr := errors.NewWRError(err, errors.M_REQUEST_PARAM_MUST_BE_NATURAL, eType)
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	if wrErr := ac.ah.RevokeSession(c, uaDTO, sessionID); wrErr != nil {
+		return wrErr
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RevokeAllSessions: ログインユーザーのセッションをすべて失効(全端末からログアウト)
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) RevokeAllSessions(c echo.Context) error {
+	uaDTO, wrErr := getLoginUserAuthInfo(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	if wrErr := ac.ah.RevokeAllSessions(c, uaDTO); wrErr != nil {
+		return wrErr
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+/*
+claimsからログインユーザーの情報(セッションのjwt_idを含む)を取得
+*/
+func getLoginUserAuthInfo(c echo.Context) (dto.UserAuthInfoDTO, error) {
+	claims, wrErr := wrcontext.GetVerifiedClaims(c)
+
+	if wrErr != nil {
+		return dto.UserAuthInfoDTO{}, wrErr
+	}
+
+	userID, wrErr := wrcontext.GetLoginUserID(c)
+
+	if wrErr != nil {
+		return dto.UserAuthInfoDTO{}, wrErr
+	}
+
+	return dto.UserAuthInfoDTO{
+		UserID: userID,
+		JwtID:  claims.ID,
+		RoleID: claims.Role,
+	}, nil
+}
+
+/*
+トークン再発行のリクエストのバインドとバリデーション
+*/
+func bindRefreshTokenReq(c echo.Context) (dto.RefreshTokenReq, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	rtReq := dto.RefreshTokenReq{}
+
+	if err := c.Bind(&rtReq); err != nil {
+		wrErr := errors.NewWRError(err, "入力項目に不正があります。", errors.NewAuthClientErrorEType())
+		logger.Error(wrErr)
+		return dto.RefreshTokenReq{}, wrErr
+	}
+
+	if err := validator.New().Struct(&rtReq); err != nil {
+		wrErr := errors.NewWRError(err, "必須の項目に不正があります。", errors.NewAuthClientErrorEType())
+		logger.Error(wrErr)
+		return dto.RefreshTokenReq{}, wrErr
+	}
+
+	return rtReq, nil
+}
+
 // /*
 // OAuthのクエリパラメータのバリデーション
 // */
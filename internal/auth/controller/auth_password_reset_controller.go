@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/auth/core/dto"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+// RequestPasswordResetDogowner: dogownerのパスワード再設定メールの送信
+// アカウントの有無に関わらず同じレスポンスを返す
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) RequestPasswordResetDogowner(c echo.Context) error {
+	prReq := dto.PasswordResetReq{}
+
+	if wrErr := bindAndValidateAuthReq(c, &prReq); wrErr != nil {
+		return wrErr
+	}
+
+	if wrErr := ac.ah.RequestPasswordResetDogowner(c, prReq); wrErr != nil {
+		return wrErr
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// RequestPasswordResetDogrunmg: dogrunmgのパスワード再設定メールの送信
+// アカウントの有無に関わらず同じレスポンスを返す
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) RequestPasswordResetDogrunmg(c echo.Context) error {
+	prReq := dto.PasswordResetReq{}
+
+	if wrErr := bindAndValidateAuthReq(c, &prReq); wrErr != nil {
+		return wrErr
+	}
+
+	if wrErr := ac.ah.RequestPasswordResetDogrunmg(c, prReq); wrErr != nil {
+		return wrErr
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// ConfirmPasswordResetDogowner: 再設定トークンによるdogownerのパスワードの再設定
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) ConfirmPasswordResetDogowner(c echo.Context) error {
+	prcReq := dto.PasswordResetConfirmReq{}
+
+	if wrErr := bindAndValidateAuthReq(c, &prcReq); wrErr != nil {
+		return wrErr
+	}
+
+	if wrErr := ac.ah.ConfirmPasswordResetDogowner(c, prcReq); wrErr != nil {
+		return wrErr
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ConfirmPasswordResetDogrunmg: 再設定トークンによるdogrunmgのパスワードの再設定
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) ConfirmPasswordResetDogrunmg(c echo.Context) error {
+	prcReq := dto.PasswordResetConfirmReq{}
+
+	if wrErr := bindAndValidateAuthReq(c, &prcReq); wrErr != nil {
+		return wrErr
+	}
+
+	if wrErr := ac.ah.ConfirmPasswordResetDogrunmg(c, prcReq); wrErr != nil {
+		return wrErr
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+/*
+認証のリクエストのバインドとバリデーション
+*/
+func bindAndValidateAuthReq(c echo.Context, req any) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := c.Bind(req); err != nil {
+		wrErr := errors.NewWRError(err, "入力項目に不正があります。", errors.NewAuthClientErrorEType())
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		wrErr := errors.NewWRError(err, "必須の項目に不正があります。", errors.NewAuthClientErrorEType())
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	return nil
+}
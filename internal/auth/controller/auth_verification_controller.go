@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/auth/core/dto"
+)
+
+// GetVerificationStatus: ログインユーザーのメールアドレス・電話番号の確認状況の取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) GetVerificationStatus(c echo.Context) error {
+	uaDTO, wrErr := getLoginUserAuthInfo(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	res, wrErr := ac.ah.GetVerificationStatus(c, uaDTO)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// SendEmailVerification: ログインユーザーのメールアドレスに確認メールを送信
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) SendEmailVerification(c echo.Context) error {
+	uaDTO, wrErr := getLoginUserAuthInfo(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	if wrErr := ac.ah.SendEmailVerification(c, uaDTO); wrErr != nil {
+		return wrErr
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// ConfirmEmailVerification: 確認メールのトークンによるメールアドレスの確認
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) ConfirmEmailVerification(c echo.Context) error {
+	evcReq := dto.EmailVerificationConfirmReq{}
+
+	if wrErr := bindAndValidateAuthReq(c, &evcReq); wrErr != nil {
+		return wrErr
+	}
+
+	if wrErr := ac.ah.ConfirmEmailVerification(c, evcReq); wrErr != nil {
+		return wrErr
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SendPhoneVerification: ログインユーザーの電話番号に確認コードをSMSで送信
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) SendPhoneVerification(c echo.Context) error {
+	uaDTO, wrErr := getLoginUserAuthInfo(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	if wrErr := ac.ah.SendPhoneVerification(c, uaDTO); wrErr != nil {
+		return wrErr
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// ConfirmPhoneVerification: SMSで送信した確認コードによる電話番号の確認
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
+//
+// return:
+//   - error: error情報
+func (ac *authController) ConfirmPhoneVerification(c echo.Context) error {
+	uaDTO, wrErr := getLoginUserAuthInfo(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	pvcReq := dto.PhoneVerificationConfirmReq{}
+
+	if wrErr := bindAndValidateAuthReq(c, &pvcReq); wrErr != nil {
+		return wrErr
+	}
+
+	if wrErr := ac.ah.ConfirmPhoneVerification(c, uaDTO, pvcReq); wrErr != nil {
+		return wrErr
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -4,6 +4,9 @@ package core
 const (
 	CONTEXT_KEY   string = "user_info"
 	TOKEN_LOOK_UP string = "header:Authorization:Bearer " // `Bearer `しか切り取れないのでスペースが多い場合は未対応
+	// ヘッダーを指定できないクライアント(EventSource・WebSocket)用のクエリパラメータ
+	STREAM_TOKEN_QUERY_PARAM string = "access_token"
+	STREAM_PATH_PREFIX       string = "/stream/"
 )
 
 // role
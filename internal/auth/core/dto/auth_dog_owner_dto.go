@@ -5,5 +5,12 @@ type AuthDogOwnerReq struct {
 	DogOwnerName      string `json:"dogOwnerName"`
 	Email             string `json:"email"`
 	PhoneNumber       string `json:"phoneNumber"`
+	DeviceName        string `json:"deviceName"` // ログインする端末名(任意)
 	AuthorizationCode string
 }
+
+// Google OAuthのリクエスト
+type AuthGoogleReq struct {
+	Code       string `json:"code" validate:"required"` // Googleから返却された認可コード
+	DeviceName string `json:"deviceName"`               // ログインする端末名(任意)
+}
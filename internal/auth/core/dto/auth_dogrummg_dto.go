@@ -1,6 +1,7 @@
 package dto
 
 type AuthDogrunmgReq struct {
-	Password string `json:"password" validate:"required"`
-	Email    string `json:"email" validate:"required"`
+	Password   string `json:"password" validate:"required"`
+	Email      string `json:"email" validate:"required"`
+	DeviceName string `json:"deviceName"` // ログインする端末名(任意)
 }
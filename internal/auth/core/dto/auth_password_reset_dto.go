@@ -0,0 +1,12 @@
+package dto
+
+// パスワード再設定のリクエスト(再設定メールの送信)
+type PasswordResetReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// パスワード再設定の確定のリクエスト
+type PasswordResetConfirmReq struct {
+	Token    string `json:"token" validate:"required"`    // メールで送信した再設定トークン
+	Password string `json:"password" validate:"required"` // 新しいパスワード
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// ログインセッション一覧のレスポンス
+type AuthSessionRes struct {
+	SessionID  int64     `json:"sessionId"`
+	DeviceName string    `json:"deviceName,omitempty"`
+	UserAgent  string    `json:"userAgent,omitempty"`
+	IPAddress  string    `json:"ipAddress,omitempty"`
+	CreateAt   time.Time `json:"createAt"`   // ログイン日時
+	LastSeenAt time.Time `json:"lastSeenAt"` // 最終利用日時
+	ExpiresAt  time.Time `json:"expiresAt"`
+	IsCurrent  bool      `json:"isCurrent"` // リクエストしたセッションであるか
+}
@@ -0,0 +1,13 @@
+package dto
+
+// ログイン・トークン再発行のレスポンス
+type AuthTokenRes struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int64  `json:"expiresIn"` // アクセストークンの有効期間(秒)
+}
+
+// トークン再発行のリクエスト
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
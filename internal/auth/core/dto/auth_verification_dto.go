@@ -0,0 +1,20 @@
+package dto
+
+// メールアドレス・電話番号の確認状況のレスポンス
+type VerificationStatusRes struct {
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"emailVerified"`
+	PhoneNumber   string `json:"phoneNumber,omitempty"`
+	PhoneVerified bool   `json:"phoneVerified"`
+	Verified      bool   `json:"verified"` // 確認済みのアカウントであるか(認可に使用)
+}
+
+// メールアドレスの確認のリクエスト
+type EmailVerificationConfirmReq struct {
+	Token string `json:"token" validate:"required"` // 確認メールのURLに付与した署名付きトークン
+}
+
+// 電話番号の確認のリクエスト
+type PhoneVerificationConfirmReq struct {
+	Code string `json:"code" validate:"required,numeric"` // SMSで送信した確認コード
+}
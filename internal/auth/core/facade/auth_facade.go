@@ -5,21 +5,26 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/wanrun-develop/wanrun/internal/auth/adapters/repository"
+	authDTO "github.com/wanrun-develop/wanrun/internal/auth/core/dto"
+	"github.com/wanrun-develop/wanrun/internal/auth/core/handler"
 	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
 	"github.com/wanrun-develop/wanrun/pkg/log"
 )
 
 type IAuthFacade interface {
 	OrgEmailValidate(c echo.Context, email string) error
+	SendSignUpVerification(c echo.Context, uaDTO authDTO.UserAuthInfoDTO)
 }
 
 type authFacade struct {
 	ar repository.IAuthRepository
+	ah handler.IAuthHandler
 }
 
-func NewAuthFacade(ar repository.IAuthRepository) IAuthFacade {
+func NewAuthFacade(ar repository.IAuthRepository, ah handler.IAuthHandler) IAuthFacade {
 	return &authFacade{
 		ar: ar,
+		ah: ah,
 	}
 }
 
@@ -56,3 +61,17 @@ func (af *authFacade) OrgEmailValidate(c echo.Context, email string) error {
 
 	return nil
 }
+
+// SendSignUpVerification: サインアップ直後のメールアドレス・電話番号の確認の送信
+// 送信に失敗してもサインアップは完了させ、後から再送信できるようにする
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: 登録したユーザーの情報
+func (af *authFacade) SendSignUpVerification(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) {
+	logger := log.GetLogger(c).Sugar()
+
+	if wrErr := af.ah.SendSignUpVerification(c, uaDTO); wrErr != nil {
+		logger.Warnf("Failed to send sign up verification. role: %d, user: %d, err: %v", uaDTO.RoleID, uaDTO.UserID, wrErr)
+	}
+}
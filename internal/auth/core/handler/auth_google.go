@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/auth/adapters/google"
+	"github.com/wanrun-develop/wanrun/internal/auth/core"
+	authDTO "github.com/wanrun-develop/wanrun/internal/auth/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+)
+
+// GoogleOAuth: GoogleのOAuthによるdogownerのログイン(未登録の場合はサインアップ)
+// 認可コードをトークンに交換し、検証済みのIDトークンからdogownerを解決してセッションを開始する
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.AuthGoogleReq: 認可コードと端末名
+//
+// return:
+//   - authDTO.AuthTokenRes: 署名済みのjwtとリフレッシュトークン
+//   - error: error情報
+func (ah *authHandler) GoogleOAuth(c echo.Context, agReq authDTO.AuthGoogleReq) (authDTO.AuthTokenRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	token, wrErr := ah.ag.ExchangeCode(c, agReq.Code)
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	claims, wrErr := ah.ag.VerifyIDToken(c, token.IDToken)
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	dogOwnerID, wrErr := ah.resolveGoogleDogOwner(c, claims)
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	dogownerDetail := authDTO.UserAuthInfoDTO{
+		UserID: dogOwnerID,
+		RoleID: core.DOGOWNER_ROLE,
+	}
+
+	logger.Infof("dogownerDetail: %v", dogownerDetail)
+
+	// 端末ごとのセッションを開始し、署名済みのjwt tokenとリフレッシュトークンの発行
+	return ah.startSession(c, dogownerDetail, agReq.DeviceName)
+}
+
+// resolveGoogleDogOwner: Googleのユーザーに対応するdogownerの解決
+// 紐づけ済みのクレデンシャル、確認済みのメールアドレスが一致する既存のdogowner、新規作成の順に解決する
+// 既存のdogownerのメールアドレスが未確認の場合は紐づけずにクライアントエラーとする
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - google.GoogleIDTokenClaims: 検証済みのIDトークンのクレーム
+//
+// return:
+//   - int64: dogownerのID
+//   - error: error情報
+func (ah *authHandler) resolveGoogleDogOwner(c echo.Context, claims google.GoogleIDTokenClaims) (int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	// 紐づけ済みのGoogleユーザー
+	linked, wrErr := ah.ar.GetDogOwnerCredentialByProvider(c, google.GOOGLE_PROVIDER_NAME, claims.Subject)
+
+	if wrErr != nil {
+		return 0, wrErr
+	}
+
+	if linked.CredentialID.Valid {
+		return linked.AuthDogOwner.DogOwnerID.Int64, nil
+	}
+
+	credential := model.DogOwnerCredential{
+		ProviderName:   util.NewSqlNullString(google.GOOGLE_PROVIDER_NAME),
+		ProviderUserID: util.NewSqlNullString(claims.Subject),
+		Email:          util.NewSqlNullString(claims.Email),
+		GrantType:      util.NewSqlNullString(model.OAUTH_GRANT_TYPE),
+		// IDトークンの検証でemail_verifiedを確認済みのため、確認済みとして登録
+		EmailVerifiedAt: util.NewSqlNullTime(time.Now()),
+	}
+
+	// 確認済みのメールアドレスが一致する既存のdogownerに紐づける
+	existing, wrErr := ah.ar.GetDogOwnerCredentialsByEmail(c, claims.Email)
+
+	if wrErr != nil {
+		return 0, wrErr
+	}
+
+	if len(existing) > 0 {
+		authDogOwnerID := existing[0].AuthDogOwnerID
+		for _, cred := range existing[1:] {
+			// 同じメールアドレスのdogownerが複数いるため、紐づけ先を決められない(基本的に起きない)
+			if cred.AuthDogOwnerID != authDogOwnerID {
+				wrErr := wrErrors.NewWRError(
+					nil,
+					"データの不整合が起きています",
+					wrErrors.NewAuthServerErrorEType(),
+				)
+				logger.Errorf("Multiple dogowners found by email: %v", wrErr)
+				return 0, wrErr
+			}
+		}
+
+		// 既存のアカウントがメールアドレスの所有を確認していない場合は紐づけない
+		// (第三者が他人のメールアドレスで先に登録したアカウントに、本人のGoogleアカウントが紐づくのを防ぐ)
+		if !hasVerifiedEmail(existing) {
+			wrErr := wrErrors.NewWRError(
+				nil,
+				"このメールアドレスは確認されていないアカウントで登録されています。パスワードでログインし、メールアドレスの確認後にGoogleでログインしてください。",
+				wrErrors.NewAuthClientErrorEType(),
+			)
+			logger.Errorf("Google account not linked to unverified dogowner: %d, err: %v", existing[0].AuthDogOwner.DogOwnerID.Int64, wrErr)
+			return 0, wrErr
+		}
+
+		credential.AuthDogOwnerID = authDogOwnerID
+
+		if wrErr := ah.ar.CreateDogOwnerCredential(c, &credential); wrErr != nil {
+			return 0, wrErr
+		}
+
+		logger.Infof("Linked google account to dogowner: %d", existing[0].AuthDogOwner.DogOwnerID.Int64)
+
+		return existing[0].AuthDogOwner.DogOwnerID.Int64, nil
+	}
+
+	// 未登録の場合はdogownerを新規作成
+	name := claims.Name
+	if name == "" {
+		name = claims.Email
+	}
+	credential.AuthDogOwner = model.AuthDogOwner{
+		DogOwner: model.DogOwner{
+			Name: util.NewSqlNullString(name),
+		},
+	}
+
+	created, wrErr := ah.ar.CreateDogOwner(c, &credential)
+
+	if wrErr != nil {
+		return 0, wrErr
+	}
+
+	return created.AuthDogOwner.DogOwnerID.Int64, nil
+}
+
+/*
+メールアドレスの所有が確認済みのクレデンシャルがあるか
+*/
+func hasVerifiedEmail(credentials []model.DogOwnerCredential) bool {
+	for _, credential := range credentials {
+		if credential.EmailVerifiedAt.Valid {
+			return true
+		}
+	}
+	return false
+}
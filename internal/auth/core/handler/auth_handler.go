@@ -7,7 +7,10 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"github.com/wanrun-develop/wanrun/configs"
+	"github.com/wanrun-develop/wanrun/internal/auth/adapters/google"
+	"github.com/wanrun-develop/wanrun/internal/auth/adapters/mailer"
 	"github.com/wanrun-develop/wanrun/internal/auth/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/auth/adapters/sms"
 	"github.com/wanrun-develop/wanrun/internal/auth/core"
 	authDTO "github.com/wanrun-develop/wanrun/internal/auth/core/dto"
 	"github.com/wanrun-develop/wanrun/pkg/errors"
@@ -18,23 +21,36 @@ import (
 )
 
 type IAuthHandler interface {
-	LogInDogowner(c echo.Context, ador authDTO.AuthDogOwnerReq) (string, error)
-	RevokeDogowner(c echo.Context, dogownerID int64) error
-	LogInDogrunmg(c echo.Context, ador authDTO.AuthDogrunmgReq) (string, error)
-	RevokeDogrunmg(c echo.Context, dmID int64) error
-	// GoogleOAuth(c echo.Context, authorizationCode string, grantType types.GrantType) (dto.ResDogOwnerDto, error)
+	LogInDogowner(c echo.Context, ador authDTO.AuthDogOwnerReq) (authDTO.AuthTokenRes, error)
+	RefreshDogowner(c echo.Context, rtReq authDTO.RefreshTokenReq) (authDTO.AuthTokenRes, error)
+	LogInDogrunmg(c echo.Context, ador authDTO.AuthDogrunmgReq) (authDTO.AuthTokenRes, error)
+	RefreshDogrunmg(c echo.Context, rtReq authDTO.RefreshTokenReq) (authDTO.AuthTokenRes, error)
+	GetSessions(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) ([]authDTO.AuthSessionRes, error)
+	RevokeSession(c echo.Context, uaDTO authDTO.UserAuthInfoDTO, sessionID int64) error
+	RevokeAllSessions(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) error
+	RevokeCurrentSession(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) error
+	GoogleOAuth(c echo.Context, agReq authDTO.AuthGoogleReq) (authDTO.AuthTokenRes, error)
+	RequestPasswordResetDogowner(c echo.Context, prReq authDTO.PasswordResetReq) error
+	RequestPasswordResetDogrunmg(c echo.Context, prReq authDTO.PasswordResetReq) error
+	ConfirmPasswordResetDogowner(c echo.Context, prcReq authDTO.PasswordResetConfirmReq) error
+	ConfirmPasswordResetDogrunmg(c echo.Context, prcReq authDTO.PasswordResetConfirmReq) error
+	GetVerificationStatus(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) (authDTO.VerificationStatusRes, error)
+	SendEmailVerification(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) error
+	ConfirmEmailVerification(c echo.Context, evcReq authDTO.EmailVerificationConfirmReq) error
+	SendPhoneVerification(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) error
+	ConfirmPhoneVerification(c echo.Context, uaDTO authDTO.UserAuthInfoDTO, pvcReq authDTO.PhoneVerificationConfirmReq) error
+	SendSignUpVerification(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) error
 }
 
 type authHandler struct {
 	ar repository.IAuthRepository
-	// ag google.IOAuthGoogle
+	ag google.IOAuthGoogle
+	m  mailer.IMailer
+	s  sms.ISmsGateway
 }
 
-//	func NewAuthHandler(ar repository.IAuthRepository, g google.IOAuthGoogle) IAuthHandler {
-//		return &authHandler{ar, g}
-//	}
-func NewAuthHandler(ar repository.IAuthRepository) IAuthHandler {
-	return &authHandler{ar}
+func NewAuthHandler(ar repository.IAuthRepository, ag google.IOAuthGoogle, m mailer.IMailer, s sms.ISmsGateway) IAuthHandler {
+	return &authHandler{ar, ag, m, s}
 }
 
 // JWTのClaims
@@ -69,22 +85,22 @@ func (claims *AccountClaims) GetDogOwnerIDAsInt64(c echo.Context) (int64, error)
 	return dogOwnerID, nil
 }
 
-// LogInDogowner: dogownerの存在チェックバリデーションとJWTの更新, 署名済みjwtを返す
+// LogInDogowner: dogownerの存在チェックバリデーションとセッションの開始, 署名済みjwtとリフレッシュトークンを返す
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
 //   - dto.AuthDogOwnerReq: authDogOwnerのリクエスト情報
 //
 // return:
-//   - string: 検証済みのjwt
+//   - authDTO.AuthTokenRes: 署名済みのjwtとリフレッシュトークン
 //   - error: error情報
-func (ah *authHandler) LogInDogowner(c echo.Context, adoReq authDTO.AuthDogOwnerReq) (string, error) {
+func (ah *authHandler) LogInDogowner(c echo.Context, adoReq authDTO.AuthDogOwnerReq) (authDTO.AuthTokenRes, error) {
 	logger := log.GetLogger(c).Sugar()
 
 	// EmailとPhoneNumberのバリデーション
 	if wrErr := validateEmailOrPhoneNumber(adoReq); wrErr != nil {
 		logger.Error(wrErr)
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	logger.Debugf("authDogownerReq: %v, Type: %T", adoReq, adoReq)
@@ -93,7 +109,7 @@ func (ah *authHandler) LogInDogowner(c echo.Context, adoReq authDTO.AuthDogOwner
 	results, wrErr := ah.ar.GetDogOwnerByCredentials(c, adoReq)
 
 	if wrErr != nil {
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// 対象のdogownerがいない場合
@@ -104,7 +120,7 @@ func (ah *authHandler) LogInDogowner(c echo.Context, adoReq authDTO.AuthDogOwner
 			wrErrors.NewAuthClientErrorEType(),
 		)
 		logger.Errorf("Dogowner not found: %v", wrErr)
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// 対象のdogownerが複数いるため、データの不整合が起きている(基本的に起きない)
@@ -115,7 +131,7 @@ func (ah *authHandler) LogInDogowner(c echo.Context, adoReq authDTO.AuthDogOwner
 			wrErrors.NewAuthServerErrorEType(),
 		)
 		logger.Errorf("Multiple records found: %v", wrErr)
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// パスワードの確認
@@ -127,67 +143,31 @@ func (ah *authHandler) LogInDogowner(c echo.Context, adoReq authDTO.AuthDogOwner
 
 		logger.Errorf("Password compare failure: %v", wrErr)
 
-		return "", wrErr
-	}
-
-	// 更新用のJWT IDの生成
-	jwtID, wrErr := GenerateJwtID(c)
-
-	if wrErr != nil {
-		return "", wrErr
-	}
-
-	// 取得したdogownerのjtw_idの更新
-	if wrErr := ah.ar.UpdateDogownerJwtID(c, results[0].AuthDogOwner.DogOwner.DogOwnerID.Int64, jwtID); wrErr != nil {
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// 作成したDogownerの情報をdto詰め替え
 	dogownerDetail := authDTO.UserAuthInfoDTO{
 		UserID: results[0].AuthDogOwner.DogOwnerID.Int64,
-		JwtID:  jwtID,
 		RoleID: core.DOGOWNER_ROLE,
 	}
 
 	logger.Infof("dogownerDetail: %v", dogownerDetail)
 
-	// 署名済みのjwt token取得
-	token, wrErr := GetSignedJwt(c, dogownerDetail)
-
-	if wrErr != nil {
-		return "", wrErr
-	}
-
-	return token, nil
+	// 端末ごとのセッションを開始し、署名済みのjwt tokenとリフレッシュトークンの発行
+	return ah.startSession(c, dogownerDetail, adoReq.DeviceName)
 }
 
-// RevokeDogowner: dogownerのRevoke機能
-//
-// args:
-//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - int64: dogownerのID
-//
-// return:
-//   - error: error情報
-func (ah *authHandler) RevokeDogowner(c echo.Context, doID int64) error {
-	// 対象のdogownerのIDからJWT IDの削除
-	if wrErr := ah.ar.DeleteDogownerJwtID(c, doID); wrErr != nil {
-		return wrErr
-	}
-
-	return nil
-}
-
-// LogInDogrunmg: dogrunmgの存在チェックバリデーションとJWTの更新, 署名済みjwtを返す
+// LogInDogrunmg: dogrunmgの存在チェックバリデーションとセッションの開始, 署名済みjwtとリフレッシュトークンを返す
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
 //   - dto.AuthDogrunmgReq: authDogrunmgのリクエスト情報
 //
 // return:
-//   - string: 検証済みのjwt
+//   - authDTO.AuthTokenRes: 署名済みのjwtとリフレッシュトークン
 //   - error: error情報
-func (ah *authHandler) LogInDogrunmg(c echo.Context, admReq authDTO.AuthDogrunmgReq) (string, error) {
+func (ah *authHandler) LogInDogrunmg(c echo.Context, admReq authDTO.AuthDogrunmgReq) (authDTO.AuthTokenRes, error) {
 	logger := log.GetLogger(c).Sugar()
 
 	logger.Debugf("authDogrunmgReq: %v, Type: %T", admReq, admReq)
@@ -196,7 +176,7 @@ func (ah *authHandler) LogInDogrunmg(c echo.Context, admReq authDTO.AuthDogrunmg
 	results, err := ah.ar.GetDogrunmgByCredentials(c, admReq.Email)
 
 	if err != nil {
-		return "", err
+		return authDTO.AuthTokenRes{}, err
 	}
 
 	// 対象のdogrunmgがいない場合
@@ -207,7 +187,7 @@ func (ah *authHandler) LogInDogrunmg(c echo.Context, admReq authDTO.AuthDogrunmg
 			wrErrors.NewAuthClientErrorEType(),
 		)
 		logger.Errorf("Dogrunmg not found: %v", wrErr)
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// 対象のdogrunmgが複数いるため、データの不整合が起きている(emailをuniqueにしているため基本的に起きない)
@@ -218,7 +198,7 @@ func (ah *authHandler) LogInDogrunmg(c echo.Context, admReq authDTO.AuthDogrunmg
 			wrErrors.NewAuthServerErrorEType(),
 		)
 		logger.Errorf("Multiple records found for email (expected unique): %v", wrErr)
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// パスワードの確認
@@ -230,19 +210,18 @@ func (ah *authHandler) LogInDogrunmg(c echo.Context, admReq authDTO.AuthDogrunmg
 
 		logger.Errorf("Password compare failure: %v", wrErr)
 
-		return "", wrErr
-	}
-
-	// 更新用のJWT IDの生成
-	jwtID, wrErr := GenerateJwtID(c)
-
-	if wrErr != nil {
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
-	// 取得したdogrunmgのjwt_idの更新
-	if wrErr = ah.ar.UpdateDogrunmgJwtID(c, results[0].AuthDogrunmg.Dogrunmg.DogrunmgID.Int64, jwtID); wrErr != nil {
-		return "", wrErr
+	// 無効化されたdogrunmgはログイン不可
+	if results[0].AuthDogrunmg.IsDeactivated() {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			"このユーザーは無効化されています",
+			wrErrors.NewAuthClientErrorEType(),
+		)
+		logger.Errorf("Deactivated dogrunmg login: %v", wrErr)
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// dogrunmgがadminかどうかの識別
@@ -256,102 +235,15 @@ func (ah *authHandler) LogInDogrunmg(c echo.Context, admReq authDTO.AuthDogrunmg
 	// 取得したDogrunmgの情報をdto詰め替え
 	dogrunmgDetail := authDTO.UserAuthInfoDTO{
 		UserID: results[0].AuthDogrunmg.DogrunmgID.Int64,
-		JwtID:  jwtID,
 		RoleID: roleID,
 	}
 
 	logger.Infof("dogrunmgDetail: %v", dogrunmgDetail)
 
-	// 署名済みのjwt token取得
-	token, wrErr := GetSignedJwt(c, dogrunmgDetail)
-
-	if wrErr != nil {
-		return "", wrErr
-	}
-
-	return token, nil
+	// 端末ごとのセッションを開始し、署名済みのjwt tokenとリフレッシュトークンの発行
+	return ah.startSession(c, dogrunmgDetail, admReq.DeviceName)
 }
 
-// RevokeDogrunmg: dogrunmgのRevoke機能
-//
-// args:
-//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
-//   - int64: dogrunmgのID
-//
-// return:
-//   - error: error情報
-func (ah *authHandler) RevokeDogrunmg(c echo.Context, dmID int64) error {
-	// 対象のdogrunmgのIDからJWT IDの削除
-	if wrErr := ah.ar.DeleteDogrunmgJwtID(c, dmID); wrErr != nil {
-		return wrErr
-	}
-
-	return nil
-}
-
-/*
-Google OAuth認証
-*/
-// func (ah *authHandler) GoogleOAuth(c echo.Context, authorizationCode string, grantType types.GrantType) (dto.ResDogOwnerDto, error) {
-// 	logger := log.GetLogger(c).Sugar()
-
-// 	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second) // 5秒で設定
-// 	defer cancel()
-
-// 	// 各token情報の取得
-// 	token, wrErr := ah.ag.GetAccessToken(c, authorizationCode, ctx)
-
-// 	if wrErr != nil {
-// 		return dto.ResDogOwnerDto{}, wrErr
-// 	}
-
-// 	// トークン元にGoogleユーザー情報の取得
-// 	googleUserInfo, wrErr := ah.ag.GetGoogleUserInfo(c, token, ctx)
-
-// 	if wrErr != nil {
-// 		return dto.ResDogOwnerDto{}, wrErr
-// 	}
-
-// 	// Googleユーザー情報の確認処理
-// 	if googleUserInfo == nil {
-// 		wrErr := wrErrors.NewWRError(
-// 			errors.New(""),
-// 			"no google user information",
-// 			wrErrors.NewAuthServerErrorEType(),
-// 		)
-// 		logger.Errorf("No google user information error: %v", wrErr)
-// 		return dto.ResDogOwnerDto{}, wrErr
-// 	}
-
-// 	// ドッグオーナーのcredentialの設定と型変換
-// 	dogOwnerCredential := model.DogOwnerCredential{
-// 		ProviderUserID: wrUtil.NewSqlNullString(googleUserInfo.UserId),
-// 		Email:          wrUtil.NewSqlNullString(googleUserInfo.Email),
-// 		AuthDogOwner: model.AuthDogOwner{
-// 			AccessToken:           wrUtil.NewSqlNullString(token.AccessToken),
-// 			RefreshToken:          wrUtil.NewSqlNullString(token.RefreshToken),
-// 			AccessTokenExpiration: wrUtil.NewCustomTime(token.Expiry),
-// 			GrantType:             grantType,
-// 			DogOwner: model.DogOwner{
-// 				Name: wrUtil.NewSqlNullString(googleUserInfo.Email),
-// 			},
-// 		},
-// 	}
-
-// 	// ドッグオーナーの作成
-// 	dogOC, wrErr := ah.ar.CreateOAuthDogOwner(c, &dogOwnerCredential)
-
-// 	if wrErr != nil {
-// 		return dto.ResDogOwnerDto{}, wrErr
-// 	}
-
-// 	resDogOwner := dto.ResDogOwnerDto{
-// 		DogOwnerID: dogOC.AuthDogOwner.DogOwner.DogOwnerID.Int64,
-// 	}
-
-// 	return resDogOwner, nil
-// }
-
 // GetSignedJwt: 署名済みのJWT tokenの取得
 //
 // args:
@@ -364,10 +256,9 @@ Google OAuth認証
 func GetSignedJwt(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) (string, error) {
 	// 秘密鍵取得
 	secretKey := configs.FetchConfigStr("jwt.os.secret.key")
-	jwtExpTime := configs.FetchConfigInt("jwt.exp.time")
 
 	// jwt token生成
-	signedToken, wrErr := createToken(c, secretKey, uaDTO, jwtExpTime)
+	signedToken, wrErr := createToken(c, secretKey, uaDTO, AccessTokenExpTime())
 
 	if wrErr != nil {
 		return "", wrErr
@@ -382,7 +273,7 @@ func GetSignedJwt(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) (string, error)
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
 //   - string: secretKey   トークンの署名に使用する秘密鍵を表す文字列
 //   - authDTO.UserAuthInfoDTO: jwtで使用する情報
-//   - int: expTime トークンの有効期限を秒単位で指定
+//   - time.Duration: expTime トークンの有効期間
 //
 // return:
 //   - string: 生成されたJWTトークンを表す文字列
@@ -391,7 +282,7 @@ func createToken(
 	c echo.Context,
 	secretKey string,
 	uaDTO authDTO.UserAuthInfoDTO,
-	expTime int,
+	expTime time.Duration,
 ) (string, error) {
 	logger := log.GetLogger(c).Sugar()
 
@@ -401,9 +292,7 @@ func createToken(
 		Role:   uaDTO.RoleID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate( // 有効時間
-				time.Now().Add(
-					time.Hour * time.Duration(expTime),
-				),
+				time.Now().Add(expTime),
 			),
 			ID: uaDTO.JwtID,
 		},
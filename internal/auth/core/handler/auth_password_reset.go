@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/configs"
+	"github.com/wanrun-develop/wanrun/internal/auth/adapters/mailer"
+	authDTO "github.com/wanrun-develop/wanrun/internal/auth/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	PASSWORD_RESET_TOKEN_BYTES  = 32 // パスワード再設定トークンのバイト数
+	PASSWORD_RESET_MAIL_SUBJECT = "【WanRun】パスワード再設定のご案内"
+)
+
+// RequestPasswordResetDogowner: dogownerのパスワード再設定メールの送信
+// アカウントの有無が分からないよう、対象のdogownerがいない場合もエラーにしない
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.PasswordResetReq: 再設定するアカウントのEmail
+//
+// return:
+//   - error: error情報
+func (ah *authHandler) RequestPasswordResetDogowner(c echo.Context, prReq authDTO.PasswordResetReq) error {
+	logger := log.GetLogger(c).Sugar()
+
+	// パスワード認証のクレデンシャルのみ対象(OAuthのみのdogownerはパスワードがない)
+	results, wrErr := ah.ar.GetDogOwnerByCredentials(c, authDTO.AuthDogOwnerReq{Email: prReq.Email})
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	if len(results) != 1 {
+		logger.Infof("Password reset requested for unknown dogowner. count: %d", len(results))
+		return nil
+	}
+
+	prt := model.PasswordResetToken{
+		DogOwnerID: results[0].AuthDogOwner.DogOwnerID,
+	}
+
+	return ah.sendPasswordResetMail(c, prReq.Email, prt)
+}
+
+// RequestPasswordResetDogrunmg: dogrunmgのパスワード再設定メールの送信
+// アカウントの有無が分からないよう、対象のdogrunmgがいない場合もエラーにしない
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.PasswordResetReq: 再設定するアカウントのEmail
+//
+// return:
+//   - error: error情報
+func (ah *authHandler) RequestPasswordResetDogrunmg(c echo.Context, prReq authDTO.PasswordResetReq) error {
+	logger := log.GetLogger(c).Sugar()
+
+	results, wrErr := ah.ar.GetDogrunmgByCredentials(c, prReq.Email)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	if len(results) != 1 {
+		logger.Infof("Password reset requested for unknown dogrunmg. count: %d", len(results))
+		return nil
+	}
+
+	// 無効化されたdogrunmgは再設定できない
+	if results[0].AuthDogrunmg.IsDeactivated() {
+		logger.Infof("Password reset requested for deactivated dogrunmg: %d", results[0].AuthDogrunmg.DogrunmgID.Int64)
+		return nil
+	}
+
+	prt := model.PasswordResetToken{
+		DogrunmgID: results[0].AuthDogrunmg.DogrunmgID,
+	}
+
+	return ah.sendPasswordResetMail(c, prReq.Email, prt)
+}
+
+// ConfirmPasswordResetDogowner: 再設定トークンによるdogownerのパスワードの再設定
+// 再設定後は全端末のセッションを失効させる
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.PasswordResetConfirmReq: 再設定トークンと新しいパスワード
+//
+// return:
+//   - error: error情報
+func (ah *authHandler) ConfirmPasswordResetDogowner(c echo.Context, prcReq authDTO.PasswordResetConfirmReq) error {
+	return ah.confirmPasswordReset(c, prcReq, func(prt model.PasswordResetToken) bool {
+		return prt.DogOwnerID.Valid
+	})
+}
+
+// ConfirmPasswordResetDogrunmg: 再設定トークンによるdogrunmgのパスワードの再設定
+// 再設定後は全端末のセッションを失効させる
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.PasswordResetConfirmReq: 再設定トークンと新しいパスワード
+//
+// return:
+//   - error: error情報
+func (ah *authHandler) ConfirmPasswordResetDogrunmg(c echo.Context, prcReq authDTO.PasswordResetConfirmReq) error {
+	return ah.confirmPasswordReset(c, prcReq, func(prt model.PasswordResetToken) bool {
+		return prt.DogrunmgID.Valid
+	})
+}
+
+// sendPasswordResetMail: 再設定トークンを発行し、再設定画面のURLをメールで送信
+// トークンはメールでのみ送信し、DBにはハッシュ値のみ保存する
+// 再発行で以前のトークンが無効になるため、再送信の間隔内は発行しない(アカウントの有無が分からないようエラーにしない)
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: 送信先のEmail
+//   - model.PasswordResetToken: 発行先のユーザーを設定した再設定トークン
+//
+// return:
+//   - error: error情報
+func (ah *authHandler) sendPasswordResetMail(c echo.Context, email string, prt model.PasswordResetToken) error {
+	logger := log.GetLogger(c).Sugar()
+
+	latest, wrErr := ah.ar.GetLatestPasswordResetToken(c, prt)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	if !latest.IsEmpty() && time.Since(latest.CreateAt.Time) < PasswordResetResendInterval() {
+		logger.Infof("Password reset requested within resend interval. dogowner: %d, dogrunmg: %d", prt.DogOwnerID.Int64, prt.DogrunmgID.Int64)
+		return nil
+	}
+
+	token, err := util.GenerateRandomToken(PASSWORD_RESET_TOKEN_BYTES)
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"パスワード再設定トークンの生成に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	expTime := PasswordResetExpTime()
+	prt.TokenHash = util.NewSqlNullString(util.HashToken(token))
+	prt.ExpiresAt = util.NewSqlNullTime(time.Now().Add(expTime))
+
+	if wrErr := ah.ar.CreatePasswordResetToken(c, &prt); wrErr != nil {
+		return wrErr
+	}
+
+	resetURL := fmt.Sprintf("%s?token=%s", configs.FetchConfigStr("password.reset.url"), url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"WanRunをご利用いただきありがとうございます。\n\n"+
+			"以下のURLから、%d分以内にパスワードを再設定してください。\n%s\n\n"+
+			"このメールに心当たりがない場合は、破棄してください。パスワードは変更されません。\n",
+		int(expTime.Minutes()),
+		resetURL,
+	)
+
+	// アカウントの有無が送信の所要時間・失敗から分からないよう、非同期で送信する
+	mailer.SendAsync(c, ah.m, mailer.Mail{
+		To:      email,
+		Subject: PASSWORD_RESET_MAIL_SUBJECT,
+		Body:    body,
+	})
+
+	return nil
+}
+
+// confirmPasswordReset: 再設定トークンの検証とパスワードの再設定(共通処理)
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.PasswordResetConfirmReq: 再設定トークンと新しいパスワード
+//   - func(model.PasswordResetToken) bool: トークンが対象のユーザーの種別のものか
+//
+// return:
+//   - error: error情報。トークンが使用できない場合はクライアントエラー
+func (ah *authHandler) confirmPasswordReset(
+	c echo.Context,
+	prcReq authDTO.PasswordResetConfirmReq,
+	isTarget func(model.PasswordResetToken) bool,
+) error {
+	logger := log.GetLogger(c).Sugar()
+
+	prt, wrErr := ah.ar.GetPasswordResetTokenByHash(c, util.HashToken(prcReq.Token))
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	if prt.IsEmpty() || prt.IsUsed() || prt.IsExpired(time.Now()) || !isTarget(prt) {
+		wrErr := newInvalidPasswordResetTokenError()
+		logger.Errorf("Invalid password reset token: %v", wrErr)
+		return wrErr
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(prcReq.Password), bcrypt.DefaultCost) // 一旦costをデフォルト値
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"パスワードのハッシュ化に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	reset, wrErr := ah.ar.ResetPassword(c, prt, string(hash))
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	// 同時に使用されて使用済みになっていた
+	if !reset {
+		wrErr := newInvalidPasswordResetTokenError()
+		logger.Errorf("Password reset token already used: %v", wrErr)
+		return wrErr
+	}
+
+	logger.Infof("Password reset. dogowner: %d, dogrunmg: %d", prt.DogOwnerID.Int64, prt.DogrunmgID.Int64)
+
+	return nil
+}
+
+/*
+パスワード再設定トークンの有効期間
+*/
+func PasswordResetExpTime() time.Duration {
+	return time.Minute * time.Duration(configs.FetchConfigInt("password.reset.exp.minute"))
+}
+
+/*
+パスワード再設定メールの再送信の間隔
+*/
+func PasswordResetResendInterval() time.Duration {
+	return time.Second * time.Duration(configs.FetchConfigInt("password.reset.resend.second"))
+}
+
+/*
+使用できないパスワード再設定トークンのエラー
+*/
+func newInvalidPasswordResetTokenError() error {
+	return wrErrors.NewWRError(
+		nil,
+		"パスワード再設定のURLが無効です。再度パスワード再設定をしてください。",
+		wrErrors.NewAuthClientErrorEType(),
+	)
+}
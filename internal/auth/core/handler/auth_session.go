@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/auth/core"
+	authDTO "github.com/wanrun-develop/wanrun/internal/auth/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+)
+
+const (
+	SESSION_TOUCH_INTERVAL = time.Minute // 最終利用日時を更新する間隔
+	DEVICE_NAME_MAX_LEN    = 128         // 端末名の最大文字数
+	USER_AGENT_MAX_LEN     = 512         // User-Agentの最大文字数
+)
+
+// GetSessions: ログインユーザーの有効なセッション一覧の取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: ログインユーザーの情報(JwtIDはリクエストしたセッション)
+//
+// return:
+//   - []authDTO.AuthSessionRes: セッション一覧(最終利用日時の降順)
+//   - error: error情報
+func (ah *authHandler) GetSessions(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) ([]authDTO.AuthSessionRes, error) {
+	sessions, wrErr := ah.getUserSessions(c, uaDTO)
+
+	if wrErr != nil {
+		return nil, wrErr
+	}
+
+	res := make([]authDTO.AuthSessionRes, 0, len(sessions))
+	for _, session := range sessions {
+		res = append(res, authDTO.AuthSessionRes{
+			SessionID:  session.SessionID.Int64,
+			DeviceName: session.DeviceName.String,
+			UserAgent:  session.UserAgent.String,
+			IPAddress:  session.IPAddress.String,
+			CreateAt:   session.CreateAt.Time,
+			LastSeenAt: session.LastSeenAt.Time,
+			ExpiresAt:  session.ExpiresAt.Time,
+			IsCurrent:  session.JwtID.String == uaDTO.JwtID,
+		})
+	}
+
+	return res, nil
+}
+
+// RevokeSession: ログインユーザーのセッションを指定して失効
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: ログインユーザーの情報
+//   - int64: 失効させるセッションのID
+//
+// return:
+//   - error: error情報
+func (ah *authHandler) RevokeSession(c echo.Context, uaDTO authDTO.UserAuthInfoDTO, sessionID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	sessions, wrErr := ah.getUserSessions(c, uaDTO)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	for _, session := range sessions {
+		if session.SessionID.Int64 == sessionID {
+			return ah.ar.RevokeAuthSession(c, session.JwtID.String)
+		}
+	}
+
+	// 他のユーザーのセッション・失効済みのセッションは存在しないものとして扱う
+	wrErr = newSessionNotFoundError(uaDTO.RoleID)
+	logger.Errorf("Session not found: %v", wrErr)
+	return wrErr
+}
+
+// RevokeAllSessions: ログインユーザーのセッションをすべて失効(全端末からログアウト)
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: ログインユーザーの情報
+//
+// return:
+//   - error: error情報
+func (ah *authHandler) RevokeAllSessions(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) error {
+	switch uaDTO.RoleID {
+	case core.DOGOWNER_ROLE:
+		return ah.ar.RevokeDogownerAuthSessions(c, uaDTO.UserID)
+	case core.DOGRUNMG_ROLE, core.DOGRUNMG_ADMIN_ROLE:
+		return ah.ar.RevokeDogrunmgAuthSessions(c, uaDTO.UserID)
+	default:
+		return newSessionUnsupportedError(c)
+	}
+}
+
+// RevokeCurrentSession: リクエストしたセッションの失効(ログアウト)
+// 他の端末のセッションはそのまま
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: ログインユーザーの情報(JwtIDはリクエストしたセッション)
+//
+// return:
+//   - error: error情報
+func (ah *authHandler) RevokeCurrentSession(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) error {
+	return ah.ar.RevokeAuthSession(c, uaDTO.JwtID)
+}
+
+// startSession: ログイン時のセッションの開始。アクセストークンとリフレッシュトークンを発行する
+// 他の端末のセッションはそのまま
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: ログインユーザーの情報
+//   - string: 端末名
+//
+// return:
+//   - authDTO.AuthTokenRes: 署名済みのjwtとリフレッシュトークン
+//   - error: error情報
+func (ah *authHandler) startSession(
+	c echo.Context,
+	uaDTO authDTO.UserAuthInfoDTO,
+	deviceName string,
+) (authDTO.AuthTokenRes, error) {
+	refreshToken, session, rt, wrErr := NewLoginSession(c, uaDTO, deviceName)
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	if wrErr := ah.ar.CreateAuthSession(c, &session, &rt); wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	uaDTO.JwtID = session.JwtID.String
+
+	return NewAuthTokenRes(c, uaDTO, refreshToken)
+}
+
+// getActiveSession: jwt_idから使用可能なセッションの取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: jwt_id
+//
+// return:
+//   - model.AuthSession: セッション
+//   - error: error情報。失効・期限切れの場合はクライアントエラー
+func (ah *authHandler) getActiveSession(c echo.Context, jwtID string) (model.AuthSession, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	session, wrErr := ah.ar.GetAuthSessionByJwtID(c, jwtID)
+
+	if wrErr != nil {
+		return model.AuthSession{}, wrErr
+	}
+
+	if !session.IsActive(time.Now()) {
+		wrErr := newInvalidRefreshTokenError()
+		logger.Errorf("Session already revoked or expired: %v", wrErr)
+		return model.AuthSession{}, wrErr
+	}
+
+	return session, nil
+}
+
+/*
+ログインユーザーの有効なセッション一覧の取得
+*/
+func (ah *authHandler) getUserSessions(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) ([]model.AuthSession, error) {
+	switch uaDTO.RoleID {
+	case core.DOGOWNER_ROLE:
+		return ah.ar.GetDogownerAuthSessions(c, uaDTO.UserID)
+	case core.DOGRUNMG_ROLE, core.DOGRUNMG_ADMIN_ROLE:
+		return ah.ar.GetDogrunmgAuthSessions(c, uaDTO.UserID)
+	default:
+		return nil, newSessionUnsupportedError(c)
+	}
+}
+
+// NewLoginSession: ログインセッションとセッションのリフレッシュトークンの生成
+// セッションのjwt_idをリフレッシュトークンのファミリーとして使用する
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストヘッダーから端末情報を取得するために使用
+//   - authDTO.UserAuthInfoDTO: ログインユーザーの情報
+//   - string: 端末名。空の場合は未設定
+//
+// return:
+//   - string: リフレッシュトークン(平文)
+//   - model.AuthSession: DBに保存するセッション
+//   - model.RefreshToken: DBに保存するリフレッシュトークン
+//   - error: error情報
+func NewLoginSession(
+	c echo.Context,
+	uaDTO authDTO.UserAuthInfoDTO,
+	deviceName string,
+) (string, model.AuthSession, model.RefreshToken, error) {
+	jwtID, wrErr := GenerateJwtID(c)
+
+	if wrErr != nil {
+		return "", model.AuthSession{}, model.RefreshToken{}, wrErr
+	}
+
+	refreshToken, rt, wrErr := NewRefreshToken(c, uaDTO, jwtID)
+
+	if wrErr != nil {
+		return "", model.AuthSession{}, model.RefreshToken{}, wrErr
+	}
+
+	now := time.Now()
+	session := model.AuthSession{
+		JwtID:      util.NewSqlNullString(jwtID),
+		DeviceName: util.NewSqlNullString(truncate(deviceName, DEVICE_NAME_MAX_LEN)),
+		UserAgent:  util.NewSqlNullString(truncate(c.Request().UserAgent(), USER_AGENT_MAX_LEN)),
+		IPAddress:  util.NewSqlNullString(c.RealIP()),
+		LastSeenAt: util.NewSqlNullTime(now),
+		ExpiresAt:  rt.ExpiresAt,
+	}
+	if uaDTO.RoleID == core.DOGOWNER_ROLE {
+		session.DogOwnerID = util.NewSqlNullInt64(uaDTO.UserID)
+	} else {
+		session.DogrunmgID = util.NewSqlNullInt64(uaDTO.UserID)
+	}
+
+	return refreshToken, session, rt, nil
+}
+
+/*
+文字数(rune)で切り詰める
+*/
+func truncate(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen])
+}
+
+/*
+セッションを管理できないユーザー(システムユーザー等)のエラー
+*/
+func newSessionUnsupportedError(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	wrErr := wrErrors.NewWRError(
+		nil,
+		"セッションを管理できないユーザーです。",
+		wrErrors.NewAuthClientErrorEType(),
+	)
+	logger.Error(wrErr)
+	return wrErr
+}
+
+/*
+対象のセッションが存在しないエラー
+認証エラー(401)にするとクライアントがログアウトしてしまうため、ユーザーの種別ごとのクライアントエラーにする
+*/
+func newSessionNotFoundError(roleID int) error {
+	eType := wrErrors.NewDogrunmgClientErrorEType()
+	if roleID == core.DOGOWNER_ROLE {
+		eType = wrErrors.NewDogOwnerClientErrorEType()
+	}
+	return wrErrors.NewWRError(nil, "対象のセッションが存在しません。", eType)
+}
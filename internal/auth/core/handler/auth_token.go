@@ -0,0 +1,306 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/configs"
+	"github.com/wanrun-develop/wanrun/internal/auth/core"
+	authDTO "github.com/wanrun-develop/wanrun/internal/auth/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+)
+
+const (
+	REFRESH_TOKEN_BYTES = 32 // リフレッシュトークンのバイト数
+)
+
+// RefreshDogowner: dogownerのリフレッシュトークンのローテーションとアクセストークンの再発行
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.RefreshTokenReq: リフレッシュトークン
+//
+// return:
+//   - authDTO.AuthTokenRes: 署名済みのjwtと新しいリフレッシュトークン
+//   - error: error情報
+func (ah *authHandler) RefreshDogowner(c echo.Context, rtReq authDTO.RefreshTokenReq) (authDTO.AuthTokenRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	rt, wrErr := ah.getValidRefreshToken(c, rtReq.RefreshToken)
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	// dogrunmgのリフレッシュトークンは使用不可
+	if !rt.DogOwnerID.Valid {
+		wrErr := newInvalidRefreshTokenError()
+		logger.Errorf("Refresh token is not for dogowner: %v", wrErr)
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	// ログアウト済み(セッションが失効済み)の場合は再発行しない
+	session, wrErr := ah.getActiveSession(c, rt.FamilyID.String)
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	dogownerDetail := authDTO.UserAuthInfoDTO{
+		UserID: rt.DogOwnerID.Int64,
+		JwtID:  session.JwtID.String,
+		RoleID: core.DOGOWNER_ROLE,
+	}
+
+	return ah.rotateTokens(c, rt, dogownerDetail)
+}
+
+// RefreshDogrunmg: dogrunmgのリフレッシュトークンのローテーションとアクセストークンの再発行
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.RefreshTokenReq: リフレッシュトークン
+//
+// return:
+//   - authDTO.AuthTokenRes: 署名済みのjwtと新しいリフレッシュトークン
+//   - error: error情報
+func (ah *authHandler) RefreshDogrunmg(c echo.Context, rtReq authDTO.RefreshTokenReq) (authDTO.AuthTokenRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	rt, wrErr := ah.getValidRefreshToken(c, rtReq.RefreshToken)
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	// dogownerのリフレッシュトークンは使用不可
+	if !rt.DogrunmgID.Valid {
+		wrErr := newInvalidRefreshTokenError()
+		logger.Errorf("Refresh token is not for dogrunmg: %v", wrErr)
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	// ログアウト済み・ロール変更(セッションが失効済み)の場合は再発行しない
+	session, wrErr := ah.getActiveSession(c, rt.FamilyID.String)
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	// 無効化されたdogrunmgは再発行しない
+	adm, wrErr := ah.ar.GetAuthDogrunmg(c, rt.DogrunmgID.Int64)
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	if adm.IsDeactivated() {
+		wrErr := newInvalidRefreshTokenError()
+		logger.Errorf("Dogrunmg already deactivated: %v", wrErr)
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	// dogrunmgがadminかどうかの識別
+	roleID := core.DOGRUNMG_ROLE
+	if adm.IsAdmin.Valid && adm.IsAdmin.Bool {
+		roleID = core.DOGRUNMG_ADMIN_ROLE
+	}
+
+	dogrunmgDetail := authDTO.UserAuthInfoDTO{
+		UserID: rt.DogrunmgID.Int64,
+		JwtID:  session.JwtID.String,
+		RoleID: roleID,
+	}
+
+	return ah.rotateTokens(c, rt, dogrunmgDetail)
+}
+
+// rotateTokens: リフレッシュトークンのローテーションとアクセストークンの再発行
+// 同時に使用されてローテーションできなかった場合は、再利用とみなしてセッションごと失効させる
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - model.RefreshToken: 使用されたリフレッシュトークン
+//   - authDTO.UserAuthInfoDTO: jwtで使用する情報
+//
+// return:
+//   - authDTO.AuthTokenRes: 署名済みのjwtと新しいリフレッシュトークン
+//   - error: error情報
+func (ah *authHandler) rotateTokens(
+	c echo.Context,
+	rt model.RefreshToken,
+	uaDTO authDTO.UserAuthInfoDTO,
+) (authDTO.AuthTokenRes, error) {
+	// 同じファミリーでリフレッシュトークンを発行
+	refreshToken, newRT, wrErr := NewRefreshToken(c, uaDTO, rt.FamilyID.String)
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	rotated, wrErr := ah.ar.RotateRefreshToken(c, rt.RefreshTokenID.Int64, &newRT)
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	if !rotated {
+		return authDTO.AuthTokenRes{}, ah.revokeReusedFamily(c, rt)
+	}
+
+	return NewAuthTokenRes(c, uaDTO, refreshToken)
+}
+
+// getValidRefreshToken: 使用可能なリフレッシュトークンの取得
+// ローテーション済みのトークンが使用された場合は、漏洩したとみなしてファミリーごと失効させる
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: リフレッシュトークン
+//
+// return:
+//   - model.RefreshToken: リフレッシュトークン
+//   - error: error情報。使用できない場合はクライアントエラー
+func (ah *authHandler) getValidRefreshToken(c echo.Context, refreshToken string) (model.RefreshToken, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	rt, wrErr := ah.ar.GetRefreshTokenByHash(c, util.HashToken(refreshToken))
+
+	if wrErr != nil {
+		return model.RefreshToken{}, wrErr
+	}
+
+	if rt.IsEmpty() || rt.IsRevoked() || rt.IsExpired(time.Now()) {
+		wrErr := newInvalidRefreshTokenError()
+		logger.Errorf("Invalid refresh token: %v", wrErr)
+		return model.RefreshToken{}, wrErr
+	}
+
+	// ローテーション済みのトークンの再利用
+	if rt.IsRotated() {
+		return model.RefreshToken{}, ah.revokeReusedFamily(c, rt)
+	}
+
+	return rt, nil
+}
+
+// revokeReusedFamily: 再利用されたリフレッシュトークンのファミリーの失効
+// ファミリーから発行したアクセストークンも無効にするため、セッションごと失効させる
+// 他の端末のセッションはそのまま
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - model.RefreshToken: 再利用されたリフレッシュトークン
+//
+// return:
+//   - error: 再ログインを促すクライアントエラー。失効に失敗した場合はそのエラー
+func (ah *authHandler) revokeReusedFamily(c echo.Context, rt model.RefreshToken) error {
+	logger := log.GetLogger(c).Sugar()
+
+	logger.Warnf("Refresh token reused. family_id: %s", rt.FamilyID.String)
+
+	if wrErr := ah.ar.RevokeAuthSession(c, rt.FamilyID.String); wrErr != nil {
+		return wrErr
+	}
+
+	wrErr := wrErrors.NewWRError(
+		nil,
+		"リフレッシュトークンが再利用されたため、ログアウトしました。再度ログインしてください。",
+		wrErrors.NewAuthClientErrorEType(),
+	)
+	logger.Error(wrErr)
+	return wrErr
+}
+
+// NewRefreshToken: リフレッシュトークンの生成
+// クライアントには平文を返し、DBにはハッシュ値のみ保存する
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: 発行先のユーザー情報
+//   - string: トークンファミリーのID(セッションのjwt_id)
+//
+// return:
+//   - string: リフレッシュトークン(平文)
+//   - model.RefreshToken: DBに保存するリフレッシュトークン
+//   - error: error情報
+func NewRefreshToken(c echo.Context, uaDTO authDTO.UserAuthInfoDTO, familyID string) (string, model.RefreshToken, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	token, err := util.GenerateRandomToken(REFRESH_TOKEN_BYTES)
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"リフレッシュトークンの生成に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+		logger.Error(wrErr)
+		return "", model.RefreshToken{}, wrErr
+	}
+
+	rt := model.RefreshToken{
+		TokenHash: util.NewSqlNullString(util.HashToken(token)),
+		FamilyID:  util.NewSqlNullString(familyID),
+		ExpiresAt: util.NewSqlNullTime(time.Now().Add(RefreshTokenExpTime())),
+	}
+	if uaDTO.RoleID == core.DOGOWNER_ROLE {
+		rt.DogOwnerID = util.NewSqlNullInt64(uaDTO.UserID)
+	} else {
+		rt.DogrunmgID = util.NewSqlNullInt64(uaDTO.UserID)
+	}
+
+	return token, rt, nil
+}
+
+// NewAuthTokenRes: 署名済みのjwtを発行し、リフレッシュトークンとあわせてレスポンスにする
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: jwtで使用する情報
+//   - string: リフレッシュトークン(平文)
+//
+// return:
+//   - authDTO.AuthTokenRes: 署名済みのjwtとリフレッシュトークン
+//   - error: error情報
+func NewAuthTokenRes(c echo.Context, uaDTO authDTO.UserAuthInfoDTO, refreshToken string) (authDTO.AuthTokenRes, error) {
+	token, wrErr := GetSignedJwt(c, uaDTO)
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	return authDTO.AuthTokenRes{
+		AccessToken:  token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(AccessTokenExpTime().Seconds()),
+	}, nil
+}
+
+/*
+アクセストークンの有効期間
+*/
+func AccessTokenExpTime() time.Duration {
+	return time.Minute * time.Duration(configs.FetchConfigInt("jwt.access.exp.minute"))
+}
+
+/*
+リフレッシュトークンの有効期間
+*/
+func RefreshTokenExpTime() time.Duration {
+	return time.Hour * time.Duration(configs.FetchConfigInt("jwt.refresh.exp.hour"))
+}
+
+/*
+使用できないリフレッシュトークンのエラー
+*/
+func newInvalidRefreshTokenError() error {
+	return wrErrors.NewWRError(
+		nil,
+		"リフレッシュトークンが無効です。再度ログインしてください。",
+		wrErrors.NewAuthClientErrorEType(),
+	)
+}
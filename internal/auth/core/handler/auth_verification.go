@@ -0,0 +1,542 @@
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/configs"
+	"github.com/wanrun-develop/wanrun/internal/auth/adapters/mailer"
+	"github.com/wanrun-develop/wanrun/internal/auth/core"
+	authDTO "github.com/wanrun-develop/wanrun/internal/auth/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+)
+
+const (
+	EMAIL_VERIFICATION_AUDIENCE     = "wanrun-email-verification" // ログイン用のjwtと区別するためのaudience
+	EMAIL_VERIFICATION_MAIL_SUBJECT = "【WanRun】メールアドレスの確認"
+	PHONE_VERIFICATION_CODE_DIGITS  = 6 // SMSで送信する確認コードの桁数
+)
+
+// EmailVerificationClaims: メールアドレス確認用のトークンのクレーム
+// SubjectにクレデンシャルのIDを設定する
+type EmailVerificationClaims struct {
+	Role  int    `json:"role"`
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// GetVerificationStatus: ログインユーザーのメールアドレス・電話番号の確認状況の取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: ログインユーザーの情報
+//
+// return:
+//   - authDTO.VerificationStatusRes: 確認状況
+//   - error: error情報
+func (ah *authHandler) GetVerificationStatus(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) (authDTO.VerificationStatusRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	switch uaDTO.RoleID {
+	case core.DOGOWNER_ROLE:
+		credentials, wrErr := ah.ar.GetDogOwnerCredentialsByDogOwnerID(c, uaDTO.UserID)
+
+		if wrErr != nil {
+			return authDTO.VerificationStatusRes{}, wrErr
+		}
+
+		res := authDTO.VerificationStatusRes{}
+		for _, credential := range credentials {
+			if credential.Email.String != "" && (res.Email == "" || credential.EmailVerifiedAt.Valid) {
+				res.Email = credential.Email.String
+				res.EmailVerified = res.EmailVerified || credential.EmailVerifiedAt.Valid
+			}
+			if credential.PhoneNumber.String != "" && (res.PhoneNumber == "" || credential.PhoneVerifiedAt.Valid) {
+				res.PhoneNumber = credential.PhoneNumber.String
+				res.PhoneVerified = res.PhoneVerified || credential.PhoneVerifiedAt.Valid
+			}
+		}
+		res.Verified = res.EmailVerified || res.PhoneVerified
+
+		return res, nil
+
+	case core.DOGRUNMG_ROLE, core.DOGRUNMG_ADMIN_ROLE:
+		credential, wrErr := ah.ar.GetDogrunmgCredentialByDogrunmgID(c, uaDTO.UserID)
+
+		if wrErr != nil {
+			return authDTO.VerificationStatusRes{}, wrErr
+		}
+
+		return authDTO.VerificationStatusRes{
+			Email:         credential.Email.String,
+			EmailVerified: credential.EmailVerifiedAt.Valid,
+			Verified:      credential.EmailVerifiedAt.Valid,
+		}, nil
+	}
+
+	wrErr := wrErrors.NewWRError(nil, "確認状況を取得できないユーザーです。", wrErrors.NewAuthClientErrorEType())
+	logger.Errorf("Unsupported role for verification: %v", wrErr)
+	return authDTO.VerificationStatusRes{}, wrErr
+}
+
+// SendEmailVerification: ログインユーザーのメールアドレスに確認メールを送信
+// 確認画面のURLに署名付きのトークンを付与する
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: ログインユーザーの情報
+//
+// return:
+//   - error: error情報。メールアドレスが未登録・確認済みの場合はクライアントエラー
+func (ah *authHandler) SendEmailVerification(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) error {
+	logger := log.GetLogger(c).Sugar()
+
+	var credentialID int64
+	var email string
+
+	switch uaDTO.RoleID {
+	case core.DOGOWNER_ROLE:
+		credentials, wrErr := ah.ar.GetDogOwnerCredentialsByDogOwnerID(c, uaDTO.UserID)
+
+		if wrErr != nil {
+			return wrErr
+		}
+
+		for _, credential := range credentials {
+			if credential.Email.String == "" {
+				continue
+			}
+			if credential.EmailVerifiedAt.Valid {
+				return newVerificationClientError(c, uaDTO.RoleID, "メールアドレスは確認済みです。")
+			}
+			if credentialID == 0 {
+				credentialID, email = credential.CredentialID.Int64, credential.Email.String
+			}
+		}
+
+	case core.DOGRUNMG_ROLE, core.DOGRUNMG_ADMIN_ROLE:
+		credential, wrErr := ah.ar.GetDogrunmgCredentialByDogrunmgID(c, uaDTO.UserID)
+
+		if wrErr != nil {
+			return wrErr
+		}
+
+		if credential.EmailVerifiedAt.Valid {
+			return newVerificationClientError(c, uaDTO.RoleID, "メールアドレスは確認済みです。")
+		}
+		if credential.Email.String != "" {
+			credentialID, email = credential.CredentialID.Int64, credential.Email.String
+		}
+
+	default:
+		wrErr := wrErrors.NewWRError(nil, "メールアドレスを確認できないユーザーです。", wrErrors.NewAuthClientErrorEType())
+		logger.Errorf("Unsupported role for email verification: %v", wrErr)
+		return wrErr
+	}
+
+	if credentialID == 0 {
+		return newVerificationClientError(c, uaDTO.RoleID, "メールアドレスが登録されていません。")
+	}
+
+	return ah.sendEmailVerificationMail(c, uaDTO.RoleID, credentialID, email)
+}
+
+// ConfirmEmailVerification: 確認メールのトークンによるメールアドレスの確認
+// 未ログインの端末から開かれることもあるため、トークンのみで確認する
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.EmailVerificationConfirmReq: 確認メールのトークン
+//
+// return:
+//   - error: error情報。トークンが使用できない場合はクライアントエラー
+func (ah *authHandler) ConfirmEmailVerification(c echo.Context, evcReq authDTO.EmailVerificationConfirmReq) error {
+	logger := log.GetLogger(c).Sugar()
+
+	claims := EmailVerificationClaims{}
+	parsed, err := jwt.ParseWithClaims(evcReq.Token, &claims,
+		func(*jwt.Token) (interface{}, error) {
+			return emailVerificationSecretKey(), nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(EMAIL_VERIFICATION_AUDIENCE),
+	)
+
+	// 有効期限のないトークンは受け付けない
+	if err != nil || !parsed.Valid || claims.ExpiresAt == nil {
+		wrErr := newInvalidEmailVerificationTokenError(err)
+		logger.Errorf("Invalid email verification token: %v", wrErr)
+		return wrErr
+	}
+
+	credentialID, err := strconv.ParseInt(claims.Subject, 10, 64)
+
+	if err != nil {
+		wrErr := newInvalidEmailVerificationTokenError(err)
+		logger.Errorf("Invalid email verification subject: %v", wrErr)
+		return wrErr
+	}
+
+	var verified bool
+	var wrErr error
+
+	switch claims.Role {
+	case core.DOGOWNER_ROLE:
+		verified, wrErr = ah.ar.VerifyDogOwnerEmail(c, credentialID, claims.Email)
+	case core.DOGRUNMG_ROLE, core.DOGRUNMG_ADMIN_ROLE:
+		verified, wrErr = ah.ar.VerifyDogrunmgEmail(c, credentialID, claims.Email)
+	}
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	// 送信後にメールアドレスが変更された、またはクレデンシャルが存在しない
+	if !verified {
+		wrErr := newInvalidEmailVerificationTokenError(nil)
+		logger.Errorf("Email verification target not found: %v", wrErr)
+		return wrErr
+	}
+
+	logger.Infof("Email verified. role: %d, credential: %d", claims.Role, credentialID)
+
+	return nil
+}
+
+// SendPhoneVerification: ログインユーザー(dogowner)の電話番号に確認コードをSMSで送信
+// 再送信は一定時間空ける必要がある
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: ログインユーザーの情報
+//
+// return:
+//   - error: error情報。電話番号が未登録・確認済み、再送信の間隔が短い場合はクライアントエラー
+func (ah *authHandler) SendPhoneVerification(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) error {
+	logger := log.GetLogger(c).Sugar()
+
+	credential, wrErr := ah.getPhoneVerificationTarget(c, uaDTO)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	latest, wrErr := ah.ar.GetLatestPhoneVerificationCode(c, credential.CredentialID.Int64)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	cooldown := PhoneVerificationResendInterval()
+	if !latest.IsEmpty() && time.Since(latest.CreateAt.Time) < cooldown {
+		return newVerificationClientError(
+			c,
+			uaDTO.RoleID,
+			fmt.Sprintf("確認コードの再送信は%d秒以上空けてください。", int(cooldown.Seconds())),
+		)
+	}
+
+	code, err := generateVerificationCode(PHONE_VERIFICATION_CODE_DIGITS)
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(err, "確認コードの生成に失敗しました。", wrErrors.NewAuthServerErrorEType())
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	expTime := PhoneVerificationExpTime()
+	pvc := model.PhoneVerificationCode{
+		CredentialID: credential.CredentialID,
+		PhoneNumber:  credential.PhoneNumber,
+		CodeHash:     util.NewSqlNullString(util.HashToken(code)),
+		ExpiresAt:    util.NewSqlNullTime(time.Now().Add(expTime)),
+	}
+
+	if wrErr := ah.ar.CreatePhoneVerificationCode(c, &pvc); wrErr != nil {
+		return wrErr
+	}
+
+	message := fmt.Sprintf("【WanRun】確認コード: %s\n%d分以内に入力してください。", code, int(expTime.Minutes()))
+
+	return ah.s.Send(c, credential.PhoneNumber.String, message)
+}
+
+// ConfirmPhoneVerification: SMSで送信した確認コードによる電話番号の確認
+// 試行回数の上限を超えたコードは使用できない
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: ログインユーザーの情報
+//   - authDTO.PhoneVerificationConfirmReq: 確認コード
+//
+// return:
+//   - error: error情報。確認コードが違う・使用できない場合はクライアントエラー
+func (ah *authHandler) ConfirmPhoneVerification(
+	c echo.Context,
+	uaDTO authDTO.UserAuthInfoDTO,
+	pvcReq authDTO.PhoneVerificationConfirmReq,
+) error {
+	logger := log.GetLogger(c).Sugar()
+
+	credential, wrErr := ah.getPhoneVerificationTarget(c, uaDTO)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	pvc, wrErr := ah.ar.GetLatestPhoneVerificationCode(c, credential.CredentialID.Int64)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	if pvc.IsEmpty() || pvc.IsUsed() || pvc.IsExpired(time.Now()) || pvc.PhoneNumber != credential.PhoneNumber {
+		return newVerificationClientError(c, uaDTO.RoleID, "確認コードが無効です。再度確認コードを送信してください。")
+	}
+
+	// 照合の前に試行回数を消費する(同時に入力されても上限を超えて照合しない)
+	consumed, wrErr := ah.ar.ConsumePhoneVerificationAttempt(
+		c,
+		pvc.PhoneVerificationCodeID.Int64,
+		configs.FetchConfigInt("verification.phone.max.attempts"),
+	)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	if !consumed {
+		return newVerificationClientError(c, uaDTO.RoleID, "確認コードの入力回数の上限を超えました。再度確認コードを送信してください。")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(util.HashToken(pvcReq.Code)), []byte(pvc.CodeHash.String)) != 1 {
+		return newVerificationClientError(c, uaDTO.RoleID, "確認コードが違います。")
+	}
+
+	verified, wrErr := ah.ar.VerifyDogOwnerPhone(c, pvc)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	// 同時に使用されて使用済みになっていた
+	if !verified {
+		return newVerificationClientError(c, uaDTO.RoleID, "確認コードが無効です。再度確認コードを送信してください。")
+	}
+
+	logger.Infof("Phone verified. dogowner: %d, credential: %d", uaDTO.UserID, credential.CredentialID.Int64)
+
+	return nil
+}
+
+// SendSignUpVerification: サインアップ直後の確認の送信
+// dogownerは電話番号があればSMS、なければメールで送信し、dogrunmgはメールで送信する
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: 登録したユーザーの情報
+//
+// return:
+//   - error: error情報
+func (ah *authHandler) SendSignUpVerification(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) error {
+	if uaDTO.RoleID != core.DOGOWNER_ROLE {
+		return ah.SendEmailVerification(c, uaDTO)
+	}
+
+	credentials, wrErr := ah.ar.GetDogOwnerCredentialsByDogOwnerID(c, uaDTO.UserID)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	for _, credential := range credentials {
+		if credential.IsVerified() {
+			return nil
+		}
+	}
+
+	for _, credential := range credentials {
+		if credential.PhoneNumber.String != "" {
+			return ah.SendPhoneVerification(c, uaDTO)
+		}
+	}
+
+	return ah.SendEmailVerification(c, uaDTO)
+}
+
+// sendEmailVerificationMail: 確認用のトークンを署名し、確認画面のURLをメールで送信
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int: ユーザーのロール
+//   - int64: クレデンシャルのID
+//   - string: 送信先のEmail
+//
+// return:
+//   - error: error情報
+func (ah *authHandler) sendEmailVerificationMail(c echo.Context, role int, credentialID int64, email string) error {
+	logger := log.GetLogger(c).Sugar()
+
+	now := time.Now()
+	expTime := EmailVerificationExpTime()
+
+	claims := EmailVerificationClaims{
+		Role:  role,
+		Email: email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatInt(credentialID, 10),
+			Audience:  jwt.ClaimStrings{EMAIL_VERIFICATION_AUDIENCE},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expTime)),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(emailVerificationSecretKey())
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"メールアドレス確認用のトークンの生成に失敗しました。",
+			wrErrors.NewAuthServerErrorEType(),
+		)
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	verifyURL := fmt.Sprintf("%s?token=%s", configs.FetchConfigStr("verification.email.url"), url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"WanRunをご利用いただきありがとうございます。\n\n"+
+			"以下のURLから、%d時間以内にメールアドレスの確認を完了してください。\n%s\n\n"+
+			"このメールに心当たりがない場合は、破棄してください。\n",
+		int(expTime.Hours()),
+		verifyURL,
+	)
+
+	return ah.m.Send(c, mailer.Mail{
+		To:      email,
+		Subject: EMAIL_VERIFICATION_MAIL_SUBJECT,
+		Body:    body,
+	})
+}
+
+// getPhoneVerificationTarget: 電話番号の確認対象のクレデンシャルの取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - authDTO.UserAuthInfoDTO: ログインユーザーの情報
+//
+// return:
+//   - model.DogOwnerCredential: 電話番号が未確認のクレデンシャル
+//   - error: error情報。dogowner以外、電話番号が未登録・確認済みの場合はクライアントエラー
+func (ah *authHandler) getPhoneVerificationTarget(c echo.Context, uaDTO authDTO.UserAuthInfoDTO) (model.DogOwnerCredential, error) {
+	if uaDTO.RoleID != core.DOGOWNER_ROLE {
+		return model.DogOwnerCredential{}, newVerificationClientError(c, uaDTO.RoleID, "電話番号を確認できないユーザーです。")
+	}
+
+	credentials, wrErr := ah.ar.GetDogOwnerCredentialsByDogOwnerID(c, uaDTO.UserID)
+
+	if wrErr != nil {
+		return model.DogOwnerCredential{}, wrErr
+	}
+
+	for _, credential := range credentials {
+		if credential.PhoneNumber.String == "" {
+			continue
+		}
+		if credential.PhoneVerifiedAt.Valid {
+			return model.DogOwnerCredential{}, newVerificationClientError(c, uaDTO.RoleID, "電話番号は確認済みです。")
+		}
+		return credential, nil
+	}
+
+	return model.DogOwnerCredential{}, newVerificationClientError(c, uaDTO.RoleID, "電話番号が登録されていません。")
+}
+
+// generateVerificationCode: 指定桁数の数字の確認コードを生成
+//
+// args:
+//   - int: 桁数
+//
+// return:
+//   - string: 0埋めした確認コード
+//   - error: error情報
+func generateVerificationCode(digits int) (string, error) {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", digits, n.Int64()), nil
+}
+
+/*
+メールアドレス確認用のトークンの有効期間
+*/
+func EmailVerificationExpTime() time.Duration {
+	return time.Hour * time.Duration(configs.FetchConfigInt("verification.email.exp.hour"))
+}
+
+/*
+電話番号確認用の確認コードの有効期間
+*/
+func PhoneVerificationExpTime() time.Duration {
+	return time.Minute * time.Duration(configs.FetchConfigInt("verification.phone.exp.minute"))
+}
+
+/*
+電話番号確認用の確認コードの再送信の間隔
+*/
+func PhoneVerificationResendInterval() time.Duration {
+	return time.Second * time.Duration(configs.FetchConfigInt("verification.phone.resend.second"))
+}
+
+/*
+メールアドレス確認用のトークンの署名鍵
+未設定の場合はjwtの秘密鍵を使用する(audienceで区別する)
+*/
+func emailVerificationSecretKey() []byte {
+	if key := configs.FetchConfigStr("verification.secret.key"); key != "" {
+		return []byte(key)
+	}
+	return []byte(configs.FetchConfigStr("jwt.os.secret.key"))
+}
+
+/*
+確認処理のクライアントエラー
+認証エラー(401)にしないよう、ユーザーの種別ごとのクライアントエラーにする
+*/
+func newVerificationClientError(c echo.Context, role int, message string) error {
+	logger := log.GetLogger(c).Sugar()
+
+	eType := wrErrors.NewDogrunmgClientErrorEType()
+	if role == core.DOGOWNER_ROLE {
+		eType = wrErrors.NewDogOwnerClientErrorEType()
+	}
+
+	wrErr := wrErrors.NewWRError(nil, message, eType)
+	logger.Error(wrErr)
+	return wrErr
+}
+
+/*
+使用できないメールアドレス確認用のトークンのエラー
+*/
+func newInvalidEmailVerificationTokenError(err error) error {
+	return wrErrors.NewWRError(
+		err,
+		"メールアドレス確認のURLが無効です。再度確認メールを送信してください。",
+		wrErrors.NewAuthClientErrorEType(),
+	)
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -32,10 +33,20 @@ func NewAuthJwt(ar repository.IAuthRepository) IAuthJwt {
 // スキップ対象のパスを定義
 var skipPaths = []string{
 	"/auth/dogowner/token",
+	"/auth/dogowner/refresh",
+	"/auth/dogowner/google/token",
+	"/auth/dogowner/password/reset",
+	"/auth/dogowner/password/reset/confirm",
 	"/auth/dogrunmg/token",
+	"/auth/dogrunmg/refresh",
+	"/auth/dogrunmg/password/reset",
+	"/auth/dogrunmg/password/reset/confirm",
+	"/auth/verification/email/confirm",
 	"/dogowner/signUp",
+	"/dogrunmg/signUp",
 	"/org/contract",
 	"/health",
+	"/share/bookmark/:token",
 }
 
 // NewJwtValidationMiddleware: JWT検証用のミドルウェア設定を生成
@@ -75,6 +86,36 @@ func (aj *authJwt) NewJwtValidationMiddleware() echo.MiddlewareFunc {
 	)
 }
 
+// StreamTokenFromQuery: ストリーム系のパスに限り、クエリパラメータのトークンをAuthorizationヘッダーに移す
+// EventSource・WebSocketのクライアントはヘッダーを指定できないため
+// 検証自体はNewJwtValidationMiddlewareで行う。ルーティング前に実行するためe.Preで登録すること
+//
+// return:
+//   - echo.MiddlewareFunc: ミドルウェア
+func StreamTokenFromQuery() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if !strings.HasPrefix(req.URL.Path, core.STREAM_PATH_PREFIX) {
+				return next(c)
+			}
+
+			query := req.URL.Query()
+			token := query.Get(core.STREAM_TOKEN_QUERY_PARAM)
+			if token == "" {
+				return next(c)
+			}
+			if req.Header.Get(echo.HeaderAuthorization) == "" {
+				req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+			}
+			// ログ等にトークンが残らないようクエリから除く
+			query.Del(core.STREAM_TOKEN_QUERY_PARAM)
+			req.URL.RawQuery = query.Encode()
+			return next(c)
+		}
+	}
+}
+
 // extractAndValidateJwtClaims: contextからJWTのclaimsを取得と検証とバリデーション
 //
 // args:
@@ -131,15 +172,16 @@ func (aj *authJwt) extractAndValidateJwtClaims(c echo.Context) (*handler.Account
 		return nil, wrErr
 	}
 
-	// リクエストのJWT内に含まれる`jwt_id`が、DBの`jwt_id`と一致するかを検証
-	if wrErr := aj.jwtIDValid(c, claims); wrErr != nil {
+	// リクエストのJWT内に含まれる`jwt_id`のセッションが有効かを検証
+	if wrErr := aj.sessionValid(c, claims); wrErr != nil {
 		return nil, wrErr
 	}
 
 	return claims, nil
 }
 
-// JwtValid: リクエストのJWT内に含まれる`jwt_id`が、DBの`jwt_id`と一致するかを検証
+// sessionValid: リクエストのJWT内に含まれる`jwt_id`のセッションが有効で、ログインユーザーのものであるかを検証
+// 検証後、一定間隔でセッションの最終利用日時を更新する
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
@@ -147,9 +189,33 @@ func (aj *authJwt) extractAndValidateJwtClaims(c echo.Context) (*handler.Account
 //
 // return:
 //   - error: error情報
-func (aj *authJwt) jwtIDValid(c echo.Context, ac *handler.AccountClaims) error {
+func (aj *authJwt) sessionValid(c echo.Context, ac *handler.AccountClaims) error {
 	logger := log.GetLogger(c).Sugar()
 
+	// system(システムユーザーのトークンは運用で発行し、jwt_idは設定値で管理する)
+	if ac.Role == core.SYSTEM {
+		systemJwtID := configs.FetchConfigStr("jwt.system.id")
+		if systemJwtID == "" {
+			wrErr := wrErrs.NewWRError(
+				nil,
+				"システムユーザーのjwt_idが設定されていません。",
+				wrErrs.NewAuthClientErrorEType(),
+			)
+			logger.Error(wrErr)
+			return wrErr
+		}
+		if systemJwtID != ac.ID {
+			wrErr := wrErrs.NewWRError(
+				nil,
+				"jwt_idが一致しません。",
+				wrErrs.NewAuthClientErrorEType(),
+			)
+			logger.Error(wrErr)
+			return wrErr
+		}
+		return nil
+	}
+
 	// 共通処理: IDのパース
 	id, err := strconv.ParseInt(ac.UserID, 10, 64)
 	if err != nil {
@@ -162,36 +228,44 @@ func (aj *authJwt) jwtIDValid(c echo.Context, ac *handler.AccountClaims) error {
 		return wrErr
 	}
 
-	// Roleによる設定分岐
-	getJwtID := func(role int, id int64) (string, error) {
-		switch role {
-		// dogowner
-		case core.DOGOWNER_ROLE:
-			// dogownerのjwtID取得
-			return aj.ar.GetDogownerJwtID(c, id)
-		// dogrunmg
-		case core.DOGRUNMG_ROLE, core.DOGRUNMG_ADMIN_ROLE:
-			// dogrunmgのjwtID取得
-			return aj.ar.GetDogrunmgJwtID(c, id)
-		default:
-			return "", wrErrs.NewWRError(
-				nil,
-				"不明なユーザーRoleです。",
-				wrErrs.NewUnexpectedErrorEType(),
-			)
-		}
+	// セッションの取得
+	session, wrErr := aj.ar.GetAuthSessionByJwtID(c, ac.ID)
+
+	if wrErr != nil {
+		return wrErr
 	}
 
-	// JWT IDの取得
-	jwtID, wrErr := getJwtID(ac.Role, id)
+	now := time.Now()
+	if !session.IsActive(now) {
+		wrErr := wrErrs.NewWRError(
+			nil,
+			"セッションが無効です。再度ログインしてください。",
+			wrErrs.NewAuthClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return wrErr
+	}
 
-	if wrErr != nil {
+	// Roleによるセッションの所有者の確認
+	var owned bool
+	switch ac.Role {
+	// dogowner
+	case core.DOGOWNER_ROLE:
+		owned = session.DogOwnerID.Valid && session.DogOwnerID.Int64 == id
+	// dogrunmg
+	case core.DOGRUNMG_ROLE, core.DOGRUNMG_ADMIN_ROLE:
+		owned = session.DogrunmgID.Valid && session.DogrunmgID.Int64 == id
+	default:
+		wrErr := wrErrs.NewWRError(
+			nil,
+			"不明なユーザーRoleです。",
+			wrErrs.NewUnexpectedErrorEType(),
+		)
 		logger.Error(wrErr)
 		return wrErr
 	}
 
-	// JTIの一致確認
-	if jwtID != ac.ID {
+	if !owned {
 		wrErr := wrErrs.NewWRError(
 			nil,
 			"jwt_idが一致しません。",
@@ -201,5 +275,11 @@ func (aj *authJwt) jwtIDValid(c echo.Context, ac *handler.AccountClaims) error {
 		return wrErr
 	}
 
+	// 最終利用日時の更新(リクエストごとに更新しないよう間隔をあける)
+	if !session.LastSeenAt.Valid || now.Sub(session.LastSeenAt.Time) >= handler.SESSION_TOUCH_INTERVAL {
+		// 更新の失敗で認証は失敗させない(エラーはrepositoryでログ出力済み)
+		_ = aj.ar.TouchAuthSession(c, session.SessionID.Int64)
+	}
+
 	return nil
 }
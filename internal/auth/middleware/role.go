@@ -38,12 +38,26 @@ var DOG_MANAGE = []int{
 	core.DOGOWNER_ROLE,
 }
 
+// 犬参照(飼い主とドッグラン管理者)
+var DOG_REFER = []int{
+	core.DOGOWNER_ROLE,
+	core.DOGRUNMG_ADMIN_ROLE,
+	core.DOGRUNMG_ROLE,
+}
+
 // ドッグラン管理
 var DOGRUN_MANAGE = []int{
 	core.DOGRUNMG_ADMIN_ROLE,
 	core.DOGRUNMG_ROLE,
 }
 
+// ログインセッション管理(飼い主とドッグラン管理者)
+var SESSION_MANAGE = []int{
+	core.DOGOWNER_ROLE,
+	core.DOGRUNMG_ADMIN_ROLE,
+	core.DOGRUNMG_ROLE,
+}
+
 // ドッグラン特権管理
 var DOGRUN_SUPER_MANAGE = []int{
 	core.DOGRUNMG_ADMIN_ROLE,
@@ -68,16 +82,14 @@ func RoleAuthorization(allowedRoles []int) echo.MiddlewareFunc {
 			}
 
 			//システムユーザーはチェック対象外
-			for _, systemRole := range SYSTEM {
-				if userRole == systemRole {
-					return nil
-				}
+			if userRole == core.SYSTEM {
+				return next(c)
 			}
 
 			//引数の認可対象であるかチェック
 			for _, allowedRole := range allowedRoles {
 				if userRole == allowedRole {
-					return nil // 許可されたロールの場合、次へ進む
+					return next(c) // 許可されたロールの場合、次へ進む
 				}
 			}
 
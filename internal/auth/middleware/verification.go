@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/configs"
+	"github.com/wanrun-develop/wanrun/internal/auth/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/auth/core"
+	"github.com/wanrun-develop/wanrun/internal/wrcontext"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"golang.org/x/exp/slices"
+)
+
+type IVerificationAuthorization interface {
+	VerifiedAuthorization(verifiedRoles []int) echo.MiddlewareFunc
+}
+
+type verificationAuthorization struct {
+	ar repository.IAuthRepository
+}
+
+func NewVerificationAuthorization(ar repository.IAuthRepository) IVerificationAuthorization {
+	return &verificationAuthorization{ar}
+}
+
+// VerifiedAuthorization: 確認済みアカウントの認可
+// ロール認可の後に使用し、対象のロールのユーザーはメールアドレスか電話番号の確認済みであることを検証
+//
+// args:
+//   - []int:	確認済みであることを必須とするロール
+//
+// return:
+//   - echo.MiddlewareFunc:	ミドルウェア
+func (va *verificationAuthorization) VerifiedAuthorization(verifiedRoles []int) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			logger := log.GetLogger(c).Sugar()
+
+			if !configs.FetchConfigBool("verification.required") {
+				return next(c)
+			}
+
+			userRole, err := wrcontext.GetLoginUserRole(c) // Contextからユーザーのロールを取得
+			if err != nil {
+				err = errors.NewWRError(err, "ユーザーの確認状況の認可で失敗しました。", errors.NewAuthServerErrorEType())
+				return err
+			}
+
+			//システムユーザー、対象外のロールはチェック対象外
+			if userRole == core.SYSTEM || !slices.Contains(verifiedRoles, userRole) {
+				return next(c)
+			}
+
+			userID, err := wrcontext.GetLoginUserID(c)
+			if err != nil {
+				return err
+			}
+
+			var verified bool
+			switch userRole {
+			case core.DOGOWNER_ROLE:
+				verified, err = va.ar.IsDogOwnerVerified(c, userID)
+			case core.DOGRUNMG_ROLE, core.DOGRUNMG_ADMIN_ROLE:
+				verified, err = va.ar.IsDogrunmgVerified(c, userID)
+			}
+			if err != nil {
+				return err
+			}
+
+			if !verified {
+				// ログイン切れ(401)と区別できるよう、ユーザーの種別ごとのクライアントエラーにする
+				eType := errors.NewDogrunmgClientErrorEType()
+				if userRole == core.DOGOWNER_ROLE {
+					eType = errors.NewDogOwnerClientErrorEType()
+				}
+				wrErr := errors.NewWRError(
+					nil,
+					"メールアドレスか電話番号の確認が完了していないため、ご利用できない機能です。",
+					eType,
+				)
+				logger.Errorf("Unverified user. role: %d, user: %d, err: %v", userRole, userID, wrErr)
+				return wrErr
+			}
+
+			return next(c)
+		}
+	}
+}
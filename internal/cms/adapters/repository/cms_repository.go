@@ -12,6 +12,7 @@ type ICmsRepository interface {
 	CreateS3FileInfo(c echo.Context, s3FileInfo model.S3FileInfo) error
 	GetS3FileInfoByFileID(c echo.Context, fileID string) ([]model.S3FileInfo, error)
 	DeleteS3FileInfo(c echo.Context, s3FileInfo model.S3FileInfo) error
+	ExistsFileReference(c echo.Context, fileID string) (bool, error)
 }
 
 type cmsRepository struct {
@@ -107,3 +108,38 @@ func (cr *cmsRepository) DeleteS3FileInfo(c echo.Context, s3Info model.S3FileInf
 
 	return nil
 }
+
+// ExistsFileReference: ファイルがギャラリー画像か予防接種証明書で使用中か
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: fileID
+//
+// return:
+//   - bool: 使用中か
+//   - error: error情報
+func (cr *cmsRepository) ExistsFileReference(c echo.Context, fileID string) (bool, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	for _, m := range []any{&model.DogrunImage{}, &model.InjectionCertification{}} {
+		var count int64
+		if err := cr.db.Model(m).
+			Where("file_id = ?", fileID).
+			Limit(1).
+			Count(&count).Error; err != nil {
+			wrErr := wrErrors.NewWRError(
+				err,
+				"DBからのデータ取得に失敗しました。",
+				wrErrors.NewCmsServerErrorEType(),
+			)
+			logger.Errorf("DB search failure: %v", wrErr)
+
+			return false, wrErr
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
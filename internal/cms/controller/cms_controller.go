@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/auth/core"
 	"github.com/wanrun-develop/wanrun/internal/cms/core/dto"
 	"github.com/wanrun-develop/wanrun/internal/cms/core/handler"
 	"github.com/wanrun-develop/wanrun/internal/wrcontext"
@@ -29,8 +30,15 @@ func NewCmsController(ch handler.ICmsHandler) ICmsController {
 func (cc *cmsController) UploadFile(c echo.Context) error {
 	logger := log.GetLogger(c).Sugar()
 
-	// dogOwnerIDの取得
-	dogOwnerID, wrErr := wrcontext.GetLoginUserID(c)
+	// ログインユーザーIDの取得
+	userID, wrErr := wrcontext.GetLoginUserID(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	// アップロードしたユーザーはロールによってdogownerかdogrun_managerとして登録する
+	role, wrErr := wrcontext.GetLoginUserRole(c)
 
 	if wrErr != nil {
 		return wrErr
@@ -73,10 +81,23 @@ func (cc *cmsController) UploadFile(c echo.Context) error {
 	baseName := strings.TrimSuffix(fileName, filepath.Ext(fileName))
 
 	fuq := dto.FileUploadReq{
-		FileName:   baseName,
-		Extension:  ext,
-		Src:        src,
-		DogOwnerID: dogOwnerID,
+		FileName:  baseName,
+		Extension: ext,
+		Src:       src,
+	}
+	switch role {
+	case core.DOGOWNER_ROLE:
+		fuq.DogOwnerID = userID
+	case core.DOGRUNMG_ROLE, core.DOGRUNMG_ADMIN_ROLE:
+		fuq.DogrunManagerID = userID
+	default:
+		wrErr := errors.NewWRError(
+			nil,
+			"ファイルをアップロードできないユーザーです。",
+			errors.NewCmsClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return wrErr
 	}
 
 	// FileUploadのハンドラー
@@ -110,6 +131,35 @@ func (cc *cmsController) DeleteFile(c echo.Context) error {
 		return wrErr
 	}
 
+	// ログインユーザーIDの取得
+	userID, wrErr := wrcontext.GetLoginUserID(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	// アップロードしたユーザーのみ削除できるよう、ロールによってdogownerかdogrun_managerとして指定する
+	role, wrErr := wrcontext.GetLoginUserRole(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	switch role {
+	case core.DOGOWNER_ROLE:
+		fdReq.DogOwnerID = userID
+	case core.DOGRUNMG_ROLE, core.DOGRUNMG_ADMIN_ROLE:
+		fdReq.DogrunManagerID = userID
+	default:
+		wrErr := errors.NewWRError(
+			nil,
+			"ファイルを削除できないユーザーです。",
+			errors.NewCmsClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return wrErr
+	}
+
 	// S3データ削除とS3fileデータ情報をDBから削除
 	if wrErr := cc.ch.HandleFileDelete(c, fdReq); wrErr != nil {
 		return wrErr
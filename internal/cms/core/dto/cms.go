@@ -7,6 +7,8 @@ type FileUploadReq struct {
 	Extension  string         // ファイルの拡張子 (例: ".png", ".txt")
 	Src        multipart.File // ファイルの内容
 	DogOwnerID int64
+	// dogrun_managerがアップロードする場合はDogOwnerIDの代わりに指定
+	DogrunManagerID int64
 }
 
 type FileUploadRes struct {
@@ -14,5 +16,8 @@ type FileUploadRes struct {
 }
 
 type FileDeleteReq struct {
-	FileID string `json:"fileId" validate:"required"`
+	FileID     string `json:"fileId" validate:"required"`
+	DogOwnerID int64  `json:"-"`
+	// dogrun_managerが削除する場合はDogOwnerIDの代わりに指定
+	DogrunManagerID int64 `json:"-"`
 }
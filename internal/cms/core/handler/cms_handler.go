@@ -24,6 +24,7 @@ const (
 type ICmsHandler interface {
 	HandleFileUpload(c echo.Context, fuq dto.FileUploadReq) (dto.FileUploadRes, error)
 	HandleFileDelete(c echo.Context, fdReq dto.FileDeleteReq) error
+	DeleteUnusedFile(c echo.Context, fileID string) error
 }
 
 type cmsHandler struct {
@@ -66,10 +67,11 @@ func (ch *cmsHandler) HandleFileUpload(c echo.Context, fuq dto.FileUploadReq) (d
 	}
 
 	s3FI := model.S3FileInfo{
-		FileID:      wrUtil.NewSqlNullString(fileID),
-		FileSize:    wrUtil.NewSqlNullInt64(fileSize),
-		S3ObjectKey: wrUtil.NewSqlNullString(s3ObjectKey),
-		DogOwnerID:  wrUtil.NewSqlNullInt64(fuq.DogOwnerID),
+		FileID:          wrUtil.NewSqlNullString(fileID),
+		FileSize:        wrUtil.NewSqlNullInt64(fileSize),
+		S3ObjectKey:     wrUtil.NewSqlNullString(s3ObjectKey),
+		DogOwnerID:      wrUtil.NewSqlNullInt64(fuq.DogOwnerID),
+		DogrunManagerID: wrUtil.NewSqlNullInt64(fuq.DogrunManagerID),
 	}
 
 	// S3FileInfoの登録
@@ -85,6 +87,7 @@ func (ch *cmsHandler) HandleFileUpload(c echo.Context, fuq dto.FileUploadReq) (d
 }
 
 // HandleFileDelete: S3へのファイル削除と対象のDBレコード削除
+// ログインユーザーがアップロードしたファイルのみ削除できる
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
@@ -95,13 +98,64 @@ func (ch *cmsHandler) HandleFileUpload(c echo.Context, fuq dto.FileUploadReq) (d
 func (ch *cmsHandler) HandleFileDelete(c echo.Context, fdReq dto.FileDeleteReq) error {
 	logger := log.GetLogger(c).Sugar()
 
-	// 対象のS3のfile情報があるのか確認
-	s3Files, wrErr := ch.cr.GetS3FileInfoByFileID(c, fdReq.FileID)
+	s3File, wrErr := ch.getS3File(c, fdReq.FileID)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	// アップロードしたユーザー以外は削除できない
+	if !isUploader(s3File, fdReq) {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			"対象のファイルを削除する権限がありません",
+			wrErrors.NewCmsClientErrorEType(),
+		)
+		logger.Errorf("Not the uploader of file %s: %v", fdReq.FileID, wrErr)
+		return wrErr
+	}
+
+	return ch.deleteFile(c, s3File)
+}
+
+// DeleteUnusedFile: 使用されなくなったファイルの削除
+// 他のドメインで参照を削除した後に使用し、アップロードしたユーザーのチェックは呼び出し元で行う
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: fileID
+//
+// return:
+//   - error: error情報
+func (ch *cmsHandler) DeleteUnusedFile(c echo.Context, fileID string) error {
+	s3File, wrErr := ch.getS3File(c, fileID)
 
 	if wrErr != nil {
 		return wrErr
 	}
 
+	return ch.deleteFile(c, s3File)
+}
+
+// getS3File: 対象のS3のfile情報の取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: fileID
+//
+// return:
+//   - model.S3FileInfo: S3ファイル情報
+//   - error: error情報
+func (ch *cmsHandler) getS3File(c echo.Context, fileID string) (model.S3FileInfo, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	// 対象のS3のfile情報があるのか確認
+	s3Files, wrErr := ch.cr.GetS3FileInfoByFileID(c, fileID)
+
+	if wrErr != nil {
+		return model.S3FileInfo{}, wrErr
+	}
+
 	// 対象のS3File情報がいない場合
 	if len(s3Files) == 0 {
 		wrErr := wrErrors.NewWRError(
@@ -111,7 +165,7 @@ func (ch *cmsHandler) HandleFileDelete(c echo.Context, fdReq dto.FileDeleteReq)
 		)
 
 		logger.Errorf("s3File not found: %v", wrErr)
-		return wrErr
+		return model.S3FileInfo{}, wrErr
 	}
 
 	// 対象のFileIDが重複することがないので複数いる場合は、データの不整合が起きている(基本的に起きない)
@@ -122,24 +176,68 @@ func (ch *cmsHandler) HandleFileDelete(c echo.Context, fdReq dto.FileDeleteReq)
 			wrErrors.NewCmsServerErrorEType(),
 		)
 		logger.Errorf("Multiple records found: %v", wrErr)
+		return model.S3FileInfo{}, wrErr
+	}
+
+	return s3Files[0], nil
+}
+
+// deleteFile: 使用中でないことを確認し、S3のオブジェクトと対象のS3file情報を削除
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - model.S3FileInfo: S3ファイル情報
+//
+// return:
+//   - error: error情報
+func (ch *cmsHandler) deleteFile(c echo.Context, s3File model.S3FileInfo) error {
+	logger := log.GetLogger(c).Sugar()
+
+	// ギャラリー画像や予防接種証明書で使用中のファイルは削除できない
+	referenced, wrErr := ch.cr.ExistsFileReference(c, s3File.FileID.String)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	if referenced {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			"使用中のファイルは削除できません",
+			wrErrors.NewCmsClientErrorEType(),
+		)
+		logger.Errorf("File %s is still referenced: %v", s3File.FileID.String, wrErr)
 		return wrErr
 	}
 
 	// 対象のオブジェクトの削除
-	if wrErr := ch.cs3.DeleteObject(c, s3Files[0].S3ObjectKey.String); wrErr != nil {
+	if wrErr := ch.cs3.DeleteObject(c, s3File.S3ObjectKey.String); wrErr != nil {
 		return wrErr
 	}
 
 	logger.Info("Success s3 object delete!!!")
 
 	// 対象のS3file情報をDBから削除
-	if wrErr := ch.cr.DeleteS3FileInfo(c, s3Files[0]); wrErr != nil {
+	if wrErr := ch.cr.DeleteS3FileInfo(c, s3File); wrErr != nil {
 		return wrErr
 	}
 
 	return nil
 }
 
+/*
+削除を要求したユーザーがファイルをアップロードしたユーザーか
+*/
+func isUploader(s3File model.S3FileInfo, fdReq dto.FileDeleteReq) bool {
+	if fdReq.DogOwnerID != 0 {
+		return s3File.DogOwnerID.Valid && s3File.DogOwnerID.Int64 == fdReq.DogOwnerID
+	}
+	if fdReq.DogrunManagerID != 0 {
+		return s3File.DogrunManagerID.Valid && s3File.DogrunManagerID.Int64 == fdReq.DogrunManagerID
+	}
+	return false
+}
+
 // generateS3ObjectKey: S3ObjectKeyの生成
 //
 // args:
@@ -0,0 +1,67 @@
+package facade
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/cms/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/cms/core/handler"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+type ICmsFacade interface {
+	GetS3FileInfoByFileID(echo.Context, string) (model.S3FileInfo, error)
+	DeleteFile(echo.Context, string) error
+}
+
+type cmsFacade struct {
+	ch handler.ICmsHandler
+	cr repository.ICmsRepository
+}
+
+func NewCmsFacade(ch handler.ICmsHandler, cr repository.ICmsRepository) ICmsFacade {
+	return &cmsFacade{ch, cr}
+}
+
+// GetS3FileInfoByFileID: FileIDを元にアップロード済みのS3File情報を取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - string:	fileID
+//
+// return:
+//   - model.S3FileInfo:	S3ファイル情報
+//   - error:	エラー
+func (f *cmsFacade) GetS3FileInfoByFileID(c echo.Context, fileID string) (model.S3FileInfo, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	s3Files, wrErr := f.cr.GetS3FileInfoByFileID(c, fileID)
+	if wrErr != nil {
+		return model.S3FileInfo{}, wrErr
+	}
+
+	if len(s3Files) != 1 {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			"対象のS3File情報が存在しません",
+			wrErrors.NewCmsClientErrorEType(),
+		)
+		logger.Errorf("s3File not found or duplicated: %s", fileID)
+		return model.S3FileInfo{}, wrErr
+	}
+
+	return s3Files[0], nil
+}
+
+// DeleteFile: S3のファイルと対象のS3File情報を削除
+// 呼び出し元でファイルの参照を削除した後に使用する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - string:	fileID
+//
+// return:
+//   - error:	エラー
+func (f *cmsFacade) DeleteFile(c echo.Context, fileID string) error {
+	return f.ch.DeleteUnusedFile(c, fileID)
+}
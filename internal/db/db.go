@@ -15,12 +15,7 @@ func NewDB() (*gorm.DB, error) {
 	config := configs.DbInfo()
 	fmt.Printf("DB info: %+v\n", *config)
 
-	postgresUrl := fmt.Sprintf("postgres://%s:%s@%s:%s/%s",
-		config.PostgresUser(),
-		config.PostgresPassword(),
-		config.PostgresHost(),
-		config.PostgresPort(),
-		config.PostgresDB())
+	postgresUrl := PostgresURL()
 
 	// logレベルの取得
 	logLevel := getLoggerLevel()
@@ -36,6 +31,20 @@ func NewDB() (*gorm.DB, error) {
 	return db, nil
 }
 
+/*
+configのDB情報から接続URLを生成
+gorm以外でpostgresに直接接続する場合(LISTEN/NOTIFYなど)にも使用する
+*/
+func PostgresURL() string {
+	config := configs.DbInfo()
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s",
+		config.PostgresUser(),
+		config.PostgresPassword(),
+		config.PostgresHost(),
+		config.PostgresPort(),
+		config.PostgresDB())
+}
+
 func CloseDB(db *gorm.DB) error {
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 type IDogRepository interface {
 	GetAllDogs(echo.Context) ([]model.Dog, error)
 	GetDogByID(echo.Context, int64) (model.Dog, error)
+	GetDogByIDs(echo.Context, []int64) ([]model.Dog, error)
 	GetDogByDogOwnerID(echo.Context, int64) ([]model.Dog, error)
 	GetDogTypeMst(echo.Context) ([]model.DogTypeMst, error)
 	CreateDog(echo.Context, model.Dog) (model.Dog, error)
@@ -58,6 +59,27 @@ func (dr *dogRepository) GetDogByID(c echo.Context, dogID int64) (model.Dog, err
 	return dog, nil
 }
 
+// GetDogByIDs: DBへ複数のDogIDでdogsのセレクト
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []int64:	dogIDs
+//
+// return:
+//   - []model.Dog:	dogデータ
+//   - error:	エラー
+func (dr *dogRepository) GetDogByIDs(c echo.Context, dogIDs []int64) ([]model.Dog, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogs := []model.Dog{}
+	if err := dr.db.Where("dog_id in ?", dogIDs).Find(&dogs).Error; err != nil {
+		logger.Error(err)
+		err = errors.NewWRError(err, "dogのselectで失敗しました。", errors.NewDogServerErrorEType())
+		return []model.Dog{}, err
+	}
+	return dogs, nil
+}
+
 // GetDogByID: DBへDogOwnerIDでdogsのセレクト。dogTypeもロードする
 //
 // args:
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"github.com/labstack/echo/v4"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+)
+
+type IInjectionCertificationRepository interface {
+	GetCertificationByID(echo.Context, int64) (model.InjectionCertification, error)
+	GetCertificationsByDogID(echo.Context, int64) ([]model.InjectionCertification, error)
+	GetCertificationsByDogIDs(echo.Context, []int64) ([]model.InjectionCertification, error)
+	CreateCertification(echo.Context, model.InjectionCertification) (model.InjectionCertification, error)
+	UpdateCertification(echo.Context, model.InjectionCertification) (model.InjectionCertification, error)
+	DeleteCertification(echo.Context, int64) error
+	ExistsDogrunVisit(echo.Context, int64, []int64) (bool, error)
+}
+
+type injectionCertificationRepository struct {
+	db *gorm.DB
+}
+
+func NewInjectionCertificationRepository(db *gorm.DB) IInjectionCertificationRepository {
+	return &injectionCertificationRepository{db}
+}
+
+// GetCertificationByID: 予防接種証明書をIDで取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	injectionCertificationID
+//
+// return:
+//   - model.InjectionCertification:	予防接種証明書(存在しない場合は空)
+//   - error:	エラー
+func (r *injectionCertificationRepository) GetCertificationByID(c echo.Context, id int64) (model.InjectionCertification, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	certification := model.InjectionCertification{}
+	if err := r.db.Where("injection_certification_id = ?", id).Find(&certification).Error; err != nil {
+		logger.Error(err)
+		err = errors.NewWRError(err, "injection_certificationsのselectで失敗しました。", errors.NewDogServerErrorEType())
+		return model.InjectionCertification{}, err
+	}
+	return certification, nil
+}
+
+// GetCertificationsByDogID: dogの予防接種証明書一覧を取得
+// ワクチン種別ごとに有効期限の新しい順
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogID
+//
+// return:
+//   - []model.InjectionCertification:	予防接種証明書一覧
+//   - error:	エラー
+func (r *injectionCertificationRepository) GetCertificationsByDogID(c echo.Context, dogID int64) ([]model.InjectionCertification, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	certifications := []model.InjectionCertification{}
+	if err := r.db.Where("dog_id = ?", dogID).
+		Order("type").
+		Order("valid_until DESC NULLS LAST").
+		Order("injection_certification_id").
+		Find(&certifications).Error; err != nil {
+		logger.Error(err)
+		err = errors.NewWRError(err, "injection_certificationsのselectで失敗しました。", errors.NewDogServerErrorEType())
+		return []model.InjectionCertification{}, err
+	}
+	return certifications, nil
+}
+
+// GetCertificationsByDogIDs: 複数dogの予防接種証明書を取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []int64:	dogIDs
+//
+// return:
+//   - []model.InjectionCertification:	予防接種証明書一覧
+//   - error:	エラー
+func (r *injectionCertificationRepository) GetCertificationsByDogIDs(c echo.Context, dogIDs []int64) ([]model.InjectionCertification, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	certifications := []model.InjectionCertification{}
+	if err := r.db.Where("dog_id in ?", dogIDs).
+		Order("dog_id").
+		Order("type").
+		Order("valid_until DESC NULLS LAST").
+		Find(&certifications).Error; err != nil {
+		logger.Error(err)
+		err = errors.NewWRError(err, "injection_certificationsのselectで失敗しました。", errors.NewDogServerErrorEType())
+		return []model.InjectionCertification{}, err
+	}
+	return certifications, nil
+}
+
+// CreateCertification: 予防接種証明書のinsert
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - model.InjectionCertification:	登録する予防接種証明書
+//
+// return:
+//   - model.InjectionCertification:	登録された予防接種証明書
+//   - error:	エラー
+func (r *injectionCertificationRepository) CreateCertification(c echo.Context, certification model.InjectionCertification) (model.InjectionCertification, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := r.db.Omit("Dog").Create(&certification).Error; err != nil {
+		logger.Error(err)
+		err = errors.NewWRError(err, "injection_certificationsのinsert処理で失敗しました。", errors.NewDogServerErrorEType())
+		return model.InjectionCertification{}, err
+	}
+	return certification, nil
+}
+
+// UpdateCertification: 予防接種証明書のupdate
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - model.InjectionCertification:	更新する予防接種証明書
+//
+// return:
+//   - model.InjectionCertification:	更新された予防接種証明書
+//   - error:	エラー
+func (r *injectionCertificationRepository) UpdateCertification(c echo.Context, certification model.InjectionCertification) (model.InjectionCertification, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := r.db.Omit("Dog").Save(&certification).Error; err != nil {
+		logger.Error(err)
+		err = errors.NewWRError(err, "injection_certificationsのupdateで失敗しました。", errors.NewDogServerErrorEType())
+		return model.InjectionCertification{}, err
+	}
+	return certification, nil
+}
+
+// DeleteCertification: 予防接種証明書のdelete
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	injectionCertificationID
+//
+// return:
+//   - error:	エラー
+func (r *injectionCertificationRepository) DeleteCertification(c echo.Context, id int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := r.db.Where("injection_certification_id = ?", id).Delete(&model.InjectionCertification{}).Error; err != nil {
+		logger.Error(err)
+		err = errors.NewWRError(err, "injection_certificationsのdeleteで失敗しました。", errors.NewDogServerErrorEType())
+		return err
+	}
+	return nil
+}
+
+// ExistsDogrunVisit: dogが指定のドッグランのいずれかを利用(チェックイン)したことがあるか
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogID
+//   - []int64:	dogrunIDs
+//
+// return:
+//   - bool:	利用したことがあるか
+//   - error:	エラー
+func (r *injectionCertificationRepository) ExistsDogrunVisit(c echo.Context, dogID int64, dogrunIDs []int64) (bool, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	if len(dogrunIDs) == 0 {
+		return false, nil
+	}
+
+	var count int64
+	if err := r.db.Model(&model.DogrunVisit{}).
+		Where("dog_id = ?", dogID).
+		Where("dogrun_id in ?", dogrunIDs).
+		Limit(1).
+		Count(&count).Error; err != nil {
+		logger.Error(err)
+		err = errors.NewWRError(err, "dogrun_visitsのselectで失敗しました。", errors.NewDogServerErrorEType())
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/dog/core/dto"
+	"github.com/wanrun-develop/wanrun/internal/dog/core/handler"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+type IInjectionCertificationController interface {
+	GetCertifications(c echo.Context) error
+	CreateCertification(c echo.Context) error
+	ReplaceCertification(c echo.Context) error
+	DeleteCertification(c echo.Context) error
+	VerifyCertification(c echo.Context) error
+}
+
+type injectionCertificationController struct {
+	h handler.IInjectionCertificationHandler
+}
+
+func NewInjectionCertificationController(h handler.IInjectionCertificationHandler) IInjectionCertificationController {
+	return &injectionCertificationController{h}
+}
+
+// GetCertifications: dogの予防接種証明書一覧を取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (icc *injectionCertificationController) GetCertifications(c echo.Context) error {
+	dogID, err := parseNaturalParam(c, "dogID")
+	if err != nil {
+		return err
+	}
+
+	resList, err := icc.h.GetCertifications(c, dogID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resList)
+}
+
+// CreateCertification: 予防接種証明書の登録
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (icc *injectionCertificationController) CreateCertification(c echo.Context) error {
+	dogID, err := parseNaturalParam(c, "dogID")
+	if err != nil {
+		return err
+	}
+
+	req, err := bindInjectionCertificationSaveReq(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := icc.h.CreateCertification(c, dogID, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]int64{
+		"injectionCertificationId": id,
+	})
+}
+
+// ReplaceCertification: 予防接種証明書の差し替え
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (icc *injectionCertificationController) ReplaceCertification(c echo.Context) error {
+	id, err := parseNaturalParam(c, "certificationId")
+	if err != nil {
+		return err
+	}
+
+	req, err := bindInjectionCertificationSaveReq(c)
+	if err != nil {
+		return err
+	}
+
+	id, err = icc.h.ReplaceCertification(c, id, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]int64{
+		"injectionCertificationId": id,
+	})
+}
+
+// DeleteCertification: 予防接種証明書の削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (icc *injectionCertificationController) DeleteCertification(c echo.Context) error {
+	id, err := parseNaturalParam(c, "certificationId")
+	if err != nil {
+		return err
+	}
+
+	if err := icc.h.DeleteCertification(c, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// VerifyCertification: dogrun管理者による予防接種証明書の確認
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (icc *injectionCertificationController) VerifyCertification(c echo.Context) error {
+	id, err := parseNaturalParam(c, "certificationId")
+	if err != nil {
+		return err
+	}
+
+	if err := icc.h.VerifyCertification(c, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// parseNaturalParam: パスパラメータを自然数として取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - string:	パラメータ名
+//
+// return:
+//   - int64:	パラメータ値
+//   - error:	エラー
+func parseNaturalParam(c echo.Context, name string) (int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	value, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || value <= 0 {
+		err = errors.NewWRError(err, errors.M_REQUEST_PARAM_MUST_BE_NATURAL, errors.NewDogClientErrorEType())
+		logger.Error(err)
+		return 0, err
+	}
+	return value, nil
+}
+
+// bindInjectionCertificationSaveReq: 予防接種証明書の保存リクエストのバインドとバリデーション
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - dto.InjectionCertificationSaveReq:	リクエスト内容
+//   - error:	エラー
+func bindInjectionCertificationSaveReq(c echo.Context) (dto.InjectionCertificationSaveReq, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	var req dto.InjectionCertificationSaveReq
+	if err := c.Bind(&req); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_IS_INVALID, errors.NewDogClientErrorEType())
+		logger.Error(err)
+		return dto.InjectionCertificationSaveReq{}, err
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_VALIDATION_FAILED, errors.NewDogClientErrorEType())
+		logger.Error(err)
+		return dto.InjectionCertificationSaveReq{}, err
+	}
+	return req, nil
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"github.com/wanrun-develop/wanrun/common"
+)
+
+const VACCINE_DATE_FORMAT = "2006-01-02" // 接種日・有効期限の日付フォーマット
+
+// 予防接種証明書の登録・差し替え用
+type InjectionCertificationSaveReq struct {
+	Type       int64  `json:"type" validate:"required,oneof=1 2"`                 // 1:狂犬病, 2:混合ワクチン
+	FileID     string `json:"fileId" validate:"required,max=64"`                  // cmsでアップロードしたファイルのID
+	ValidFrom  string `json:"validFrom" validate:"required,datetime=2006-01-02"`  // yyyy-MM-dd
+	ValidUntil string `json:"validUntil" validate:"required,datetime=2006-01-02"` // yyyy-MM-dd
+}
+
+// 予防接種証明書レスポンス
+type InjectionCertificationRes struct {
+	InjectionCertificationID int64          `json:"injectionCertificationId"`
+	DogID                    int64          `json:"dogId"`
+	Type                     int64          `json:"type"`
+	FileID                   string         `json:"fileId"`
+	ValidFrom                string         `json:"validFrom,omitempty"`
+	ValidUntil               string         `json:"validUntil,omitempty"`
+	IsValid                  bool           `json:"isValid"`
+	IsVerified               bool           `json:"isVerified"`
+	VerifiedBy               int64          `json:"verifiedBy,omitempty"`
+	VerifiedAt               *common.WRTime `json:"verifiedAt,omitempty"`
+	CreateAt                 common.WRTime  `json:"createAt"`
+	UpdateAt                 common.WRTime  `json:"updateAt"`
+}
@@ -0,0 +1,482 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/common"
+	"github.com/wanrun-develop/wanrun/internal/auth/core"
+	cmsF "github.com/wanrun-develop/wanrun/internal/cms/facade"
+	"github.com/wanrun-develop/wanrun/internal/dog/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/dog/core/dto"
+	dogrunF "github.com/wanrun-develop/wanrun/internal/dogrun/facade"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/internal/wrcontext"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+)
+
+type IInjectionCertificationHandler interface {
+	GetCertifications(echo.Context, int64) ([]dto.InjectionCertificationRes, error)
+	CreateCertification(echo.Context, int64, dto.InjectionCertificationSaveReq) (int64, error)
+	ReplaceCertification(echo.Context, int64, dto.InjectionCertificationSaveReq) (int64, error)
+	DeleteCertification(echo.Context, int64) error
+	VerifyCertification(echo.Context, int64) error
+}
+
+type injectionCertificationHandler struct {
+	dr  repository.IDogRepository
+	icr repository.IInjectionCertificationRepository
+	cf  cmsF.ICmsFacade
+	drf dogrunF.IDogrunFacade
+}
+
+func NewInjectionCertificationHandler(
+	dr repository.IDogRepository,
+	icr repository.IInjectionCertificationRepository,
+	cf cmsF.ICmsFacade,
+	drf dogrunF.IDogrunFacade,
+) IInjectionCertificationHandler {
+	return &injectionCertificationHandler{dr, icr, cf, drf}
+}
+
+// GetCertifications: dogの予防接種証明書一覧を取得
+// dogownerは自分のdogのみ参照可能。dogrun管理者は管理するドッグランを利用したdogのみ参照可能
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogID
+//
+// return:
+//   - []dto.InjectionCertificationRes:	予防接種証明書一覧
+//   - error:	エラー
+func (h *injectionCertificationHandler) GetCertifications(c echo.Context, dogID int64) ([]dto.InjectionCertificationRes, error) {
+	role, err := wrcontext.GetLoginUserRole(c)
+	if err != nil {
+		return []dto.InjectionCertificationRes{}, err
+	}
+
+	if role == core.DOGOWNER_ROLE {
+		if _, err := h.getOwnedDog(c, dogID); err != nil {
+			return []dto.InjectionCertificationRes{}, err
+		}
+	} else {
+		if _, err := h.getDog(c, dogID); err != nil {
+			return []dto.InjectionCertificationRes{}, err
+		}
+		if err := h.checkManagedDogrunVisit(c, dogID); err != nil {
+			return []dto.InjectionCertificationRes{}, err
+		}
+	}
+
+	certifications, err := h.icr.GetCertificationsByDogID(c, dogID)
+	if err != nil {
+		return []dto.InjectionCertificationRes{}, err
+	}
+
+	now := time.Now()
+	resList := []dto.InjectionCertificationRes{}
+	for _, ic := range certifications {
+		resList = append(resList, convertToInjectionCertificationRes(ic, now))
+	}
+	return resList, nil
+}
+
+// CreateCertification: 予防接種証明書の登録
+// ファイルはcmsで事前にアップロード済みであること
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogID
+//   - dto.InjectionCertificationSaveReq:	リクエスト内容
+//
+// return:
+//   - int64:	登録したinjectionCertificationID
+//   - error:	エラー
+func (h *injectionCertificationHandler) CreateCertification(c echo.Context, dogID int64, req dto.InjectionCertificationSaveReq) (int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	if _, err := h.getOwnedDog(c, dogID); err != nil {
+		return 0, err
+	}
+
+	validFrom, validUntil, err := parseValidPeriod(c, req)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := h.checkFileOwner(c, req.FileID); err != nil {
+		return 0, err
+	}
+
+	certification := model.InjectionCertification{
+		DogID:      util.NewSqlNullInt64(dogID),
+		Type:       util.NewSqlNullInt64(req.Type),
+		FileID:     util.NewSqlNullString(req.FileID),
+		ValidFrom:  util.NewSqlNullTime(validFrom),
+		ValidUntil: util.NewSqlNullTime(validUntil),
+	}
+
+	certification, err = h.icr.CreateCertification(c, certification)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.Infof("dog %d の予防接種証明書 %d を登録しました。", dogID, certification.InjectionCertificationID.Int64)
+	return certification.InjectionCertificationID.Int64, nil
+}
+
+// ReplaceCertification: 予防接種証明書の差し替え
+// 差し替え後は再度dogrun管理者の確認が必要になるため、確認状態をリセットする
+// ファイルが変わった場合は古いファイルを削除する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	injectionCertificationID
+//   - dto.InjectionCertificationSaveReq:	リクエスト内容
+//
+// return:
+//   - int64:	更新したinjectionCertificationID
+//   - error:	エラー
+func (h *injectionCertificationHandler) ReplaceCertification(c echo.Context, id int64, req dto.InjectionCertificationSaveReq) (int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	certification, err := h.getOwnedCertification(c, id)
+	if err != nil {
+		return 0, err
+	}
+
+	validFrom, validUntil, err := parseValidPeriod(c, req)
+	if err != nil {
+		return 0, err
+	}
+
+	oldFileID := certification.FileID.String
+	if req.FileID != oldFileID {
+		if err := h.checkFileOwner(c, req.FileID); err != nil {
+			return 0, err
+		}
+	}
+
+	certification.Type = util.NewSqlNullInt64(req.Type)
+	certification.FileID = util.NewSqlNullString(req.FileID)
+	certification.ValidFrom = util.NewSqlNullTime(validFrom)
+	certification.ValidUntil = util.NewSqlNullTime(validUntil)
+	certification.VerifiedBy = util.NewSqlNullInt64(0)
+	certification.VerifiedAt = util.NewSqlNullTime(time.Time{})
+
+	if certification, err = h.icr.UpdateCertification(c, certification); err != nil {
+		return 0, err
+	}
+
+	if req.FileID != oldFileID {
+		h.deleteFileQuietly(c, oldFileID)
+	}
+
+	logger.Infof("予防接種証明書 %d を差し替えました。", id)
+	return certification.InjectionCertificationID.Int64, nil
+}
+
+// DeleteCertification: 予防接種証明書とそのファイルの削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	injectionCertificationID
+//
+// return:
+//   - error:	エラー
+func (h *injectionCertificationHandler) DeleteCertification(c echo.Context, id int64) error {
+	certification, err := h.getOwnedCertification(c, id)
+	if err != nil {
+		return err
+	}
+
+	if err := h.icr.DeleteCertification(c, id); err != nil {
+		return err
+	}
+
+	h.deleteFileQuietly(c, certification.FileID.String)
+	return nil
+}
+
+// VerifyCertification: dogrun管理者による予防接種証明書の確認
+// 管理するドッグランを利用したdogの証明書のみ確認可能で、有効期限切れの証明書は確認できない
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	injectionCertificationID
+//
+// return:
+//   - error:	エラー
+func (h *injectionCertificationHandler) VerifyCertification(c echo.Context, id int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunmgID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return err
+	}
+
+	certification, err := h.getCertification(c, id)
+	if err != nil {
+		return err
+	}
+
+	if err := h.checkManagedDogrunVisit(c, certification.DogID.Int64); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if !certification.IsValidAt(now) {
+		err := errors.NewWRError(nil, "有効期限外の予防接種証明書は確認できません。", errors.NewDogClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	certification.VerifiedBy = util.NewSqlNullInt64(dogrunmgID)
+	certification.VerifiedAt = util.NewSqlNullTime(now)
+
+	if _, err := h.icr.UpdateCertification(c, certification); err != nil {
+		return err
+	}
+
+	logger.Infof("dogrunmg %d が予防接種証明書 %d を確認しました。", dogrunmgID, id)
+	return nil
+}
+
+// checkManagedDogrunVisit: ログインdogrun管理者の管理するドッグランをdogが利用したことがあるかのチェック
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogID
+//
+// return:
+//   - error:	エラー
+func (h *injectionCertificationHandler) checkManagedDogrunVisit(c echo.Context, dogID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunmgID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return err
+	}
+
+	managedIDs, err := h.drf.GetManagedDogrunIDs(c, dogrunmgID)
+	if err != nil {
+		return err
+	}
+
+	visited, err := h.icr.ExistsDogrunVisit(c, dogID, managedIDs)
+	if err != nil {
+		return err
+	}
+	if !visited {
+		err := errors.NewWRError(nil, "管理しているドッグランを利用したdogの予防接種証明書のみ参照・確認できます。", errors.NewDogClientErrorEType())
+		logger.Errorf("dogrunmg %d による管理外のdog %d の予防接種証明書へのアクセス: %v", dogrunmgID, dogID, err)
+		return err
+	}
+	return nil
+}
+
+// getDog: dogの存在チェック
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogID
+//
+// return:
+//   - model.Dog:	dog
+//   - error:	エラー
+func (h *injectionCertificationHandler) getDog(c echo.Context, dogID int64) (model.Dog, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dog, err := h.dr.GetDogByID(c, dogID)
+	if err != nil {
+		return model.Dog{}, err
+	}
+	if dog.IsEmpty() {
+		err = errors.NewWRError(nil, "指定されたdogは存在しません。", errors.NewDogClientErrorEType())
+		logger.Error("不正なdog idの指定", err)
+		return model.Dog{}, err
+	}
+	return dog, nil
+}
+
+// getOwnedDog: dogの存在とログインdogownerのdogであるかのチェック
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogID
+//
+// return:
+//   - model.Dog:	dog
+//   - error:	エラー
+func (h *injectionCertificationHandler) getOwnedDog(c echo.Context, dogID int64) (model.Dog, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogOwnerID, err := wrcontext.GetLoginDogownerID(c)
+	if err != nil {
+		return model.Dog{}, err
+	}
+
+	dog, err := h.getDog(c, dogID)
+	if err != nil {
+		return model.Dog{}, err
+	}
+	if dog.DogOwnerID.Int64 != dogOwnerID {
+		err = errors.NewWRError(nil, "指定されたdogはあなたのペットではありません。", errors.NewDogClientErrorEType())
+		logger.Error(err)
+		return model.Dog{}, err
+	}
+	return dog, nil
+}
+
+// getCertification: 予防接種証明書の存在チェック
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	injectionCertificationID
+//
+// return:
+//   - model.InjectionCertification:	予防接種証明書
+//   - error:	エラー
+func (h *injectionCertificationHandler) getCertification(c echo.Context, id int64) (model.InjectionCertification, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	certification, err := h.icr.GetCertificationByID(c, id)
+	if err != nil {
+		return model.InjectionCertification{}, err
+	}
+	if certification.IsEmpty() {
+		err = errors.NewWRError(nil, "指定された予防接種証明書は存在しません。", errors.NewDogClientErrorEType())
+		logger.Error(err)
+		return model.InjectionCertification{}, err
+	}
+	return certification, nil
+}
+
+// getOwnedCertification: 予防接種証明書の存在とログインdogownerのdogのものであるかのチェック
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	injectionCertificationID
+//
+// return:
+//   - model.InjectionCertification:	予防接種証明書
+//   - error:	エラー
+func (h *injectionCertificationHandler) getOwnedCertification(c echo.Context, id int64) (model.InjectionCertification, error) {
+	certification, err := h.getCertification(c, id)
+	if err != nil {
+		return model.InjectionCertification{}, err
+	}
+	if _, err := h.getOwnedDog(c, certification.DogID.Int64); err != nil {
+		return model.InjectionCertification{}, err
+	}
+	return certification, nil
+}
+
+// checkFileOwner: cmsにアップロード済みのファイルがログインユーザーのものであるかのチェック
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - string:	fileID
+//
+// return:
+//   - error:	エラー
+func (h *injectionCertificationHandler) checkFileOwner(c echo.Context, fileID string) error {
+	logger := log.GetLogger(c).Sugar()
+
+	userID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return err
+	}
+
+	s3File, err := h.cf.GetS3FileInfoByFileID(c, fileID)
+	if err != nil {
+		return err
+	}
+	if s3File.DogOwnerID.Int64 != userID {
+		err = errors.NewWRError(nil, "指定されたファイルは利用できません。", errors.NewDogClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// deleteFileQuietly: 不要になったファイルの削除
+// 証明書の更新自体は完了しているため、削除の失敗はログ出力のみとする
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - string:	fileID
+func (h *injectionCertificationHandler) deleteFileQuietly(c echo.Context, fileID string) {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := h.cf.DeleteFile(c, fileID); err != nil {
+		logger.Warnf("予防接種証明書のファイル %s の削除に失敗しました: %v", fileID, err)
+	}
+}
+
+// parseValidPeriod: 有効期間のパースと前後関係のチェック
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.InjectionCertificationSaveReq:	リクエスト内容
+//
+// return:
+//   - time.Time:	有効期間の開始日
+//   - time.Time:	有効期限
+//   - error:	エラー
+func parseValidPeriod(c echo.Context, req dto.InjectionCertificationSaveReq) (time.Time, time.Time, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	validFrom, err := time.Parse(dto.VACCINE_DATE_FORMAT, req.ValidFrom)
+	if err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_VALIDATION_FAILED, errors.NewDogClientErrorEType())
+		logger.Error(err)
+		return time.Time{}, time.Time{}, err
+	}
+	validUntil, err := time.Parse(dto.VACCINE_DATE_FORMAT, req.ValidUntil)
+	if err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_VALIDATION_FAILED, errors.NewDogClientErrorEType())
+		logger.Error(err)
+		return time.Time{}, time.Time{}, err
+	}
+	if validUntil.Before(validFrom) {
+		err = errors.NewWRError(nil, "有効期限は接種日以降の日付を指定してください。", errors.NewDogClientErrorEType())
+		logger.Error(err)
+		return time.Time{}, time.Time{}, err
+	}
+	return validFrom, validUntil, nil
+}
+
+// convertToInjectionCertificationRes: 予防接種証明書をレスポンスに変換
+//
+// args:
+//   - model.InjectionCertification:	予防接種証明書
+//   - time.Time:	有効判定の基準日時
+//
+// return:
+//   - dto.InjectionCertificationRes:	レスポンス
+func convertToInjectionCertificationRes(ic model.InjectionCertification, now time.Time) dto.InjectionCertificationRes {
+	res := dto.InjectionCertificationRes{
+		InjectionCertificationID: ic.InjectionCertificationID.Int64,
+		DogID:                    ic.DogID.Int64,
+		Type:                     ic.Type.Int64,
+		FileID:                   ic.FileID.String,
+		IsValid:                  ic.IsValidAt(now),
+		IsVerified:               ic.IsVerified(),
+		VerifiedBy:               ic.VerifiedBy.Int64,
+		CreateAt:                 util.ConvertToWRTime(ic.CreateAt),
+		UpdateAt:                 util.ConvertToWRTime(ic.UpdateAt),
+	}
+	if ic.ValidFrom.Valid {
+		res.ValidFrom = ic.ValidFrom.Time.Format(dto.VACCINE_DATE_FORMAT)
+	}
+	if ic.ValidUntil.Valid {
+		res.ValidUntil = ic.ValidUntil.Time.Format(dto.VACCINE_DATE_FORMAT)
+	}
+	if ic.VerifiedAt.Valid {
+		res.VerifiedAt = &common.WRTime{Time: ic.VerifiedAt.Time}
+	}
+	return res
+}
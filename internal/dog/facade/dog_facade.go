@@ -5,6 +5,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/wanrun-develop/wanrun/internal/dog/adapters/repository"
+	model "github.com/wanrun-develop/wanrun/internal/models"
 	"github.com/wanrun-develop/wanrun/internal/wrcontext"
 	"github.com/wanrun-develop/wanrun/pkg/errors"
 	"github.com/wanrun-develop/wanrun/pkg/log"
@@ -12,14 +13,17 @@ import (
 
 type IDogFacade interface {
 	CheckDogownerValid(echo.Context, []int64) error
+	GetDogsByIDs(echo.Context, []int64) ([]model.Dog, error)
+	GetInjectionCertificationsByDogIDs(echo.Context, []int64) (map[int64][]model.InjectionCertification, error)
 }
 
 type dogFacade struct {
-	dr repository.IDogRepository
+	dr  repository.IDogRepository
+	icr repository.IInjectionCertificationRepository
 }
 
-func NewDogFacade(drr repository.IDogRepository) IDogFacade {
-	return &dogFacade{drr}
+func NewDogFacade(drr repository.IDogRepository, icr repository.IInjectionCertificationRepository) IDogFacade {
+	return &dogFacade{drr, icr}
 }
 
 // CheckDogownerValid: dogのdogownerが正しいかチェック
@@ -60,3 +64,38 @@ func (f dogFacade) CheckDogownerValid(c echo.Context, paramDogIDs []int64) error
 
 	return nil
 }
+
+// GetDogsByIDs: 複数のdogを取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []int64:	dogIDs
+//
+// return:
+//   - []model.Dog:	dog一覧
+//   - error:	エラー
+func (f dogFacade) GetDogsByIDs(c echo.Context, dogIDs []int64) ([]model.Dog, error) {
+	return f.dr.GetDogByIDs(c, dogIDs)
+}
+
+// GetInjectionCertificationsByDogIDs: 複数dogの予防接種証明書をdogIDごとに取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []int64:	dogIDs
+//
+// return:
+//   - map[int64][]model.InjectionCertification:	dogIDごとの予防接種証明書
+//   - error:	エラー
+func (f dogFacade) GetInjectionCertificationsByDogIDs(c echo.Context, dogIDs []int64) (map[int64][]model.InjectionCertification, error) {
+	certifications, err := f.icr.GetCertificationsByDogIDs(c, dogIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	certificationMap := make(map[int64][]model.InjectionCertification)
+	for _, ic := range certifications {
+		certificationMap[ic.DogID.Int64] = append(certificationMap[ic.DogID.Int64], ic)
+	}
+	return certificationMap, nil
+}
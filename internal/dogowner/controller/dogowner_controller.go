@@ -68,13 +68,11 @@ func (doc *dogOwnerController) DogOwnerSignUp(c echo.Context) error {
 	}
 
 	// dogOwnerのSignUp
-	token, wrErr := doc.doh.DogOwnerSignUp(c, doReq)
+	res, wrErr := doc.doh.DogOwnerSignUp(c, doReq)
 
 	if wrErr != nil {
 		return wrErr
 	}
 
-	return c.JSON(http.StatusCreated, map[string]string{
-		"accessToken": token,
-	})
+	return c.JSON(http.StatusCreated, res)
 }
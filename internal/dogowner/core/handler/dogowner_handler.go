@@ -5,6 +5,7 @@ import (
 	authRepository "github.com/wanrun-develop/wanrun/internal/auth/adapters/repository"
 	"github.com/wanrun-develop/wanrun/internal/auth/core"
 	authDTO "github.com/wanrun-develop/wanrun/internal/auth/core/dto"
+	authFacade "github.com/wanrun-develop/wanrun/internal/auth/core/facade"
 	authHandler "github.com/wanrun-develop/wanrun/internal/auth/core/handler"
 	dogOwnerRepository "github.com/wanrun-develop/wanrun/internal/dogowner/adapters/repository"
 	doDTO "github.com/wanrun-develop/wanrun/internal/dogowner/core/dto"
@@ -18,7 +19,7 @@ import (
 )
 
 type IDogOwnerHandler interface {
-	DogOwnerSignUp(c echo.Context, doReq doDTO.DogOwnerReq) (string, error)
+	DogOwnerSignUp(c echo.Context, doReq doDTO.DogOwnerReq) (authDTO.AuthTokenRes, error)
 }
 
 type dogOwnerHandler struct {
@@ -27,6 +28,7 @@ type dogOwnerHandler struct {
 	asr  authRepository.IAuthScopeRepository
 	dor  dogOwnerRepository.IDogOwnerRepository
 	ar   authRepository.IAuthRepository
+	af   authFacade.IAuthFacade
 }
 
 func NewDogOwnerHandler(
@@ -35,6 +37,7 @@ func NewDogOwnerHandler(
 	asr authRepository.IAuthScopeRepository,
 	dor dogOwnerRepository.IDogOwnerRepository,
 	ar authRepository.IAuthRepository,
+	af authFacade.IAuthFacade,
 ) IDogOwnerHandler {
 	return &dogOwnerHandler{
 		dosr: dosr,
@@ -42,19 +45,20 @@ func NewDogOwnerHandler(
 		asr:  asr,
 		dor:  dor,
 		ar:   ar,
+		af:   af,
 	}
 }
 
-// DogOwnerSignUp: dogOwnerの登録し、検証済みのJWTを返す
+// DogOwnerSignUp: dogOwnerの登録し、検証済みのJWTとリフレッシュトークンを返す
 //
 // args:
 //   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用されます。
 //   - doDTO.DogOwnerReq: dogOwnerに対するリクエスト情報
 //
 // return:
-//   - string
+//   - authDTO.AuthTokenRes: 署名済みのjwtとリフレッシュトークン
 //   - error: error情報
-func (doh *dogOwnerHandler) DogOwnerSignUp(c echo.Context, doReq doDTO.DogOwnerReq) (string, error) {
+func (doh *dogOwnerHandler) DogOwnerSignUp(c echo.Context, doReq doDTO.DogOwnerReq) (authDTO.AuthTokenRes, error) {
 	logger := log.GetLogger(c).Sugar()
 
 	// パスワードのハッシュ化
@@ -67,20 +71,13 @@ func (doh *dogOwnerHandler) DogOwnerSignUp(c echo.Context, doReq doDTO.DogOwnerR
 			wrErrors.NewDogOwnerClientErrorEType(),
 		)
 		logger.Error(wrErr)
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// EmailとPhoneNumberのバリデーション
 	if wrErr := validateEmailOrPhoneNumber(doReq); wrErr != nil {
 		logger.Error(wrErr)
-		return "", wrErr
-	}
-
-	// JWT IDの生成
-	jwtID, wrErr := authHandler.GenerateJwtID(c)
-
-	if wrErr != nil {
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// requestからDogOwnerの構造体に詰め替え
@@ -90,7 +87,6 @@ func (doh *dogOwnerHandler) DogOwnerSignUp(c echo.Context, doReq doDTO.DogOwnerR
 		Password:    wrUtil.NewSqlNullString(string(hash)),
 		GrantType:   wrUtil.NewSqlNullString(model.PASSWORD_GRANT_TYPE), // Password認証
 		AuthDogOwner: model.AuthDogOwner{
-			JwtID: wrUtil.NewSqlNullString(jwtID),
 			DogOwner: model.DogOwner{
 				Name: wrUtil.NewSqlNullString(doReq.DogOwnerName),
 			},
@@ -101,14 +97,18 @@ func (doh *dogOwnerHandler) DogOwnerSignUp(c echo.Context, doReq doDTO.DogOwnerR
 
 	ctx := c.Request().Context()
 
+	// 発行したリフレッシュトークン(平文)とセッション
+	var refreshToken string
+	var session model.AuthSession
+
 	// Emailの重複チェック
 	if wrErr := doh.ar.CheckDuplicate(c, model.EmailField, dogOwnerCredential.Email); wrErr != nil {
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// PhoneNumberの重複チェック
 	if wrErr := doh.ar.CheckDuplicate(c, model.PhoneNumberField, dogOwnerCredential.PhoneNumber); wrErr != nil {
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// dogOwnerの作成する1トランザクション
@@ -129,12 +129,32 @@ func (doh *dogOwnerHandler) DogOwnerSignUp(c echo.Context, doReq doDTO.DogOwnerR
 			return wrErr
 		}
 
+		// ログインセッションとリフレッシュトークンを作成
+		token, as, rt, wrErr := authHandler.NewLoginSession(c, authDTO.UserAuthInfoDTO{
+			UserID: dogOwnerCredential.AuthDogOwner.DogOwnerID.Int64,
+			RoleID: core.DOGOWNER_ROLE,
+		}, "")
+
+		if wrErr != nil {
+			return wrErr
+		}
+
+		if wrErr := doh.asr.CreateAuthSession(tx, c, &as); wrErr != nil {
+			return wrErr
+		}
+
+		if wrErr := doh.asr.CreateRefreshToken(tx, c, &rt); wrErr != nil {
+			return wrErr
+		}
+		refreshToken = token
+		session = as
+
 		// 正常に完了
 		return nil
 
 	}); err != nil {
 		logger.Error("Transaction failed:", err)
-		return "", err
+		return authDTO.AuthTokenRes{}, err
 	}
 
 	// 正常に終了
@@ -143,20 +163,17 @@ func (doh *dogOwnerHandler) DogOwnerSignUp(c echo.Context, doReq doDTO.DogOwnerR
 	// 作成したDogOwnerの情報をdto詰め替え
 	dogOwnerDetail := authDTO.UserAuthInfoDTO{
 		UserID: dogOwnerCredential.AuthDogOwner.DogOwnerID.Int64,
-		JwtID:  dogOwnerCredential.AuthDogOwner.JwtID.String,
+		JwtID:  session.JwtID.String,
 		RoleID: core.DOGOWNER_ROLE,
 	}
 
 	logger.Infof("dogOwnerDetail: %v", dogOwnerDetail)
 
-	// 署名済みのjwt token取得
-	token, wrErr := authHandler.GetSignedJwt(c, dogOwnerDetail)
-
-	if wrErr != nil {
-		return "", wrErr
-	}
+	// メールアドレス・電話番号の確認を送信(失敗してもサインアップは完了させる)
+	doh.af.SendSignUpVerification(c, dogOwnerDetail)
 
-	return token, nil
+	// 署名済みのjwt token取得
+	return authHandler.NewAuthTokenRes(c, dogOwnerDetail, refreshToken)
 }
 
 // validateEmailOrPhoneNumber: EmailかPhoneNumberの識別バリデーション。パスワード認証は、EmailかPhoneNumberで登録するため
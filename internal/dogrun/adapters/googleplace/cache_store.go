@@ -0,0 +1,117 @@
+package googleplace
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wanrun-develop/wanrun/configs"
+	"gorm.io/gorm"
+)
+
+// キャッシュのバックエンド
+const (
+	CACHE_BACKEND_LRU      string = "lru"      //インプロセスのLRU
+	CACHE_BACKEND_POSTGRES string = "postgres" //Postgres(インスタンス間で共有)
+)
+
+// google place apiのレスポンスのキャッシュストア
+type ICacheStore interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+/*
+設定値のバックエンドのキャッシュストアの生成
+未対応のバックエンドが指定された場合はエラーとする
+*/
+func NewCacheStore(db *gorm.DB) (ICacheStore, error) {
+	switch backend := configs.FetchConfigStr("google.place.cache.backend"); backend {
+	case CACHE_BACKEND_LRU:
+		return NewLRUCacheStore(configs.FetchConfigInt("google.place.cache.lru.size")), nil
+	case CACHE_BACKEND_POSTGRES:
+		return NewPostgresCacheStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown google.place.cache.backend: %q", backend)
+	}
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+type lruCacheStore struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+/*
+インプロセスのLRUキャッシュストアの生成
+*/
+func NewLRUCacheStore(capacity int) ICacheStore {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &lruCacheStore{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+/*
+キャッシュの取得
+有効期限切れの場合は削除し、ミスとする
+*/
+func (s *lruCacheStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		s.removeElement(elem)
+		return nil, false
+	}
+	s.ll.MoveToFront(elem)
+	return entry.value, true
+}
+
+/*
+キャッシュの保存
+容量を超えた場合は最も使われていないものから削除する
+*/
+func (s *lruCacheStore) Set(key string, value []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+	if elem, ok := s.items[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		s.ll.MoveToFront(elem)
+		return
+	}
+
+	s.items[key] = s.ll.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for s.ll.Len() > s.capacity {
+		s.removeElement(s.ll.Back())
+	}
+}
+
+func (s *lruCacheStore) removeElement(elem *list.Element) {
+	s.ll.Remove(elem)
+	delete(s.items, elem.Value.(*lruEntry).key)
+}
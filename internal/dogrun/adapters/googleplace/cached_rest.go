@@ -0,0 +1,250 @@
+package googleplace
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/configs"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"go.uber.org/zap"
+)
+
+// キャッシュキーのエンドポイント識別子
+const (
+	CACHE_ENDPOINT_PLACE_INFO    string = "placeInfo"
+	CACHE_ENDPOINT_SEARCH_NEARBY string = "searchNearby"
+	CACHE_ENDPOINT_SEARCH_TEXT   string = "searchText"
+	CACHE_ENDPOINT_PHOTO         string = "photo"
+)
+
+// キャッシュキーに使う座標の桁数(小数点以下6桁 ≒ 0.1m)
+const cacheCoordinatePrecision = 1e6
+
+// エンドポイントごとのキャッシュの有効期限
+type CacheTTL struct {
+	PlaceInfo    time.Duration
+	SearchNearby time.Duration
+	SearchText   time.Duration
+	Photo        time.Duration
+}
+
+/*
+設定値からキャッシュの有効期限を生成
+*/
+func NewCacheTTL() CacheTTL {
+	return CacheTTL{
+		PlaceInfo:    time.Duration(configs.FetchConfigInt("google.place.cache.ttl.placeInfo")) * time.Second,
+		SearchNearby: time.Duration(configs.FetchConfigInt("google.place.cache.ttl.searchNearby")) * time.Second,
+		SearchText:   time.Duration(configs.FetchConfigInt("google.place.cache.ttl.searchText")) * time.Second,
+		Photo:        time.Duration(configs.FetchConfigInt("google.place.cache.ttl.photo")) * time.Second,
+	}
+}
+
+// エンドポイントごとのヒット/ミス数
+type CacheMetrics struct {
+	Hits   map[string]int64 `json:"hits"`
+	Misses map[string]int64 `json:"misses"`
+}
+
+type cacheCounter struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type ICachedRest interface {
+	IRest
+	Metrics() CacheMetrics
+	ReportMetrics(context.Context, time.Duration)
+}
+
+// IRestのレスポンスをキャッシュするラッパー
+type cachedRest struct {
+	rest     IRest
+	store    ICacheStore
+	ttl      CacheTTL
+	counters map[string]*cacheCounter
+}
+
+func NewCachedRest(rest IRest, store ICacheStore, ttl CacheTTL) ICachedRest {
+	counters := map[string]*cacheCounter{}
+	for _, endpoint := range []string{
+		CACHE_ENDPOINT_PLACE_INFO,
+		CACHE_ENDPOINT_SEARCH_NEARBY,
+		CACHE_ENDPOINT_SEARCH_TEXT,
+		CACHE_ENDPOINT_PHOTO,
+	} {
+		counters[endpoint] = &cacheCounter{}
+	}
+	return &cachedRest{
+		rest:     rest,
+		store:    store,
+		ttl:      ttl,
+		counters: counters,
+	}
+}
+
+/*
+GET
+google place details apiの実行(キャッシュあり)
+*/
+func (r *cachedRest) GETPlaceInfo(c echo.Context, placeId string, field IFieldMask) ([]byte, error) {
+	key := buildCacheKey(CACHE_ENDPOINT_PLACE_INFO, field.getValue(), placeId)
+	return r.fetch(c, CACHE_ENDPOINT_PLACE_INFO, key, r.ttl.PlaceInfo, func() ([]byte, error) {
+		return r.rest.GETPlaceInfo(c, placeId, field)
+	})
+}
+
+/*
+POST
+google place search nearby apiの実行(キャッシュあり)
+*/
+func (r *cachedRest) POSTSearchNearby(c echo.Context, payload SearchNearbyPayLoad, field IFieldMask) ([]byte, error) {
+	normalized, err := normalizeSearchNearbyPayload(c, payload)
+	if err != nil {
+		return nil, err
+	}
+	key := buildCacheKey(CACHE_ENDPOINT_SEARCH_NEARBY, field.getValueWPlaces(), normalized)
+	return r.fetch(c, CACHE_ENDPOINT_SEARCH_NEARBY, key, r.ttl.SearchNearby, func() ([]byte, error) {
+		return r.rest.POSTSearchNearby(c, payload, field)
+	})
+}
+
+/*
+POST
+google place search text apiの実行(キャッシュあり)
+*/
+func (r *cachedRest) POSTSearchText(c echo.Context, payload SearchTextPayLoad, field IFieldMask) ([]byte, error) {
+	normalized, err := normalizeSearchTextPayload(c, payload)
+	if err != nil {
+		return nil, err
+	}
+	key := buildCacheKey(CACHE_ENDPOINT_SEARCH_TEXT, field.getValueWPlacesAndNextPageToken(), normalized)
+	return r.fetch(c, CACHE_ENDPOINT_SEARCH_TEXT, key, r.ttl.SearchText, func() ([]byte, error) {
+		return r.rest.POSTSearchText(c, payload, field)
+	})
+}
+
+/*
+GET
+google place photo media apiの実行(キャッシュあり)
+*/
+func (r *cachedRest) GETPhotoByName(c echo.Context, name, widthPx, heightPx string) ([]byte, error) {
+	key := buildCacheKey(CACHE_ENDPOINT_PHOTO, "", name, widthPx, heightPx)
+	return r.fetch(c, CACHE_ENDPOINT_PHOTO, key, r.ttl.Photo, func() ([]byte, error) {
+		return r.rest.GETPhotoByName(c, name, widthPx, heightPx)
+	})
+}
+
+/*
+エンドポイントごとのヒット/ミス数の取得
+*/
+func (r *cachedRest) Metrics() CacheMetrics {
+	metrics := CacheMetrics{
+		Hits:   map[string]int64{},
+		Misses: map[string]int64{},
+	}
+	for endpoint, counter := range r.counters {
+		metrics.Hits[endpoint] = counter.hits.Load()
+		metrics.Misses[endpoint] = counter.misses.Load()
+	}
+	return metrics
+}
+
+/*
+一定間隔ごとにエンドポイントごとのヒット/ミス数をログに出力
+コンテキストがキャンセルされるまでブロックするため、goroutineで実行すること
+*/
+func (r *cachedRest) ReportMetrics(ctx context.Context, interval time.Duration) {
+	logger := log.GetBaseLogger()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			metrics := r.Metrics()
+			logger.Info("google place api cache metrics",
+				zap.Any("hits", metrics.Hits), zap.Any("misses", metrics.Misses))
+		}
+	}
+}
+
+/*
+キャッシュの取得。ミスの場合はapiを実行し、成功したレスポンスのみキャッシュする
+*/
+func (r *cachedRest) fetch(c echo.Context, endpoint, key string, ttl time.Duration, call func() ([]byte, error)) ([]byte, error) {
+	logger := log.GetLogger(c)
+	counter := r.counters[endpoint]
+
+	if body, ok := r.store.Get(key); ok {
+		hits := counter.hits.Add(1)
+		logger.Debug("google place api cache hit",
+			zap.String("endpoint", endpoint), zap.Int64("hits", hits), zap.Int64("misses", counter.misses.Load()))
+		return body, nil
+	}
+	misses := counter.misses.Add(1)
+	logger.Debug("google place api cache miss",
+		zap.String("endpoint", endpoint), zap.Int64("hits", counter.hits.Load()), zap.Int64("misses", misses))
+
+	body, err := call()
+	if err != nil {
+		return nil, err
+	}
+	r.store.Set(key, body, ttl)
+	return body, nil
+}
+
+/*
+エンドポイント、field mask、リクエスト内容からキャッシュキーを生成
+*/
+func buildCacheKey(endpoint, fieldMask string, parts ...string) string {
+	hash := sha256.Sum256([]byte(fieldMask + "\n" + strings.Join(parts, "\n")))
+	return endpoint + ":" + hex.EncodeToString(hash[:])
+}
+
+/*
+search nearbyのpayloadの正規化
+座標の桁を丸め、JSON化する
+*/
+func normalizeSearchNearbyPayload(c echo.Context, payload SearchNearbyPayLoad) (string, error) {
+	payload.LocationRestriction.Circle.Center = roundPointer(payload.LocationRestriction.Circle.Center)
+	return marshalPayload(c, payload)
+}
+
+/*
+search textのpayloadの正規化
+座標の桁を丸め、JSON化する
+*/
+func normalizeSearchTextPayload(c echo.Context, payload SearchTextPayLoad) (string, error) {
+	payload.TextQuery = strings.TrimSpace(payload.TextQuery)
+	payload.LocationRestriction.Rectangle.Low = roundPointer(payload.LocationRestriction.Rectangle.Low)
+	payload.LocationRestriction.Rectangle.High = roundPointer(payload.LocationRestriction.Rectangle.High)
+	return marshalPayload(c, payload)
+}
+
+func roundPointer(p pointer) pointer {
+	return pointer{
+		Latitude:  math.Round(p.Latitude*cacheCoordinatePrecision) / cacheCoordinatePrecision,
+		Longitude: math.Round(p.Longitude*cacheCoordinatePrecision) / cacheCoordinatePrecision,
+	}
+}
+
+func marshalPayload(c echo.Context, payload any) (string, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		err = errors.NewWRError(err, "payloadのJSON変換に失敗しました", errors.NewDogrunServerErrorEType())
+		log.GetLogger(c).Sugar().Error(err)
+		return "", err
+	}
+	return string(jsonData), nil
+}
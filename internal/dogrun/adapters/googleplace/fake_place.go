@@ -0,0 +1,166 @@
+package googleplace
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/wanrun-develop/wanrun/pkg/util"
+)
+
+// オフライン用のgoogle placeのフィクスチャ
+// フィクスチャファイルはsearch textのレスポンスと同じ形式({"places": [...]})
+type FakePlaces struct {
+	places   []BaseResource
+	photoUri string
+}
+
+/*
+フィクスチャファイルの読み込み
+*/
+func LoadFakePlaces(path string, photoUri string) (*FakePlaces, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("google placeのフィクスチャの読み込みに失敗しました: %w", err)
+	}
+
+	fixture := SearchTextBaseResource{}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("google placeのフィクスチャのJSON変換に失敗しました: %w", err)
+	}
+	return &FakePlaces{
+		places:   fixture.Places,
+		photoUri: photoUri,
+	}, nil
+}
+
+/*
+place idでの場所の取得
+*/
+func (f *FakePlaces) PlaceInfo(placeId string) (BaseResource, bool) {
+	for _, place := range f.places {
+		if place.ID == placeId {
+			return place, true
+		}
+	}
+	return BaseResource{}, false
+}
+
+/*
+長方形の位置制限での検索
+pageSizeとpageTokenでのページングを行う
+*/
+func (f *FakePlaces) SearchText(payload SearchTextPayLoad) (SearchTextBaseResource, error) {
+	offset, err := decodeFakePageToken(payload.PageToken)
+	if err != nil {
+		return SearchTextBaseResource{}, err
+	}
+
+	matched := []BaseResource{}
+	for _, place := range f.places {
+		if inRectangle(payload.LocationRestriction.Rectangle, place.Location) {
+			matched = append(matched, place)
+		}
+	}
+
+	pageSize := payload.PageSize
+	if pageSize <= 0 || pageSize > 20 {
+		pageSize = 20
+	}
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	end := offset + pageSize
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	res := SearchTextBaseResource{Places: matched[offset:end]}
+	if end < len(matched) {
+		token := encodeFakePageToken(end)
+		res.NextPageToken = &token
+	}
+	return res, nil
+}
+
+/*
+円の位置制限での検索
+中心からの距離の近い順にmaxResultCount件まで返す
+*/
+func (f *FakePlaces) SearchNearby(payload SearchNearbyPayLoad) []BaseResource {
+	circle := payload.LocationRestriction.Circle
+
+	matched := []BaseResource{}
+	for _, place := range f.places {
+		if distanceMeter(circle.Center, place.Location) <= circle.Radius {
+			matched = append(matched, place)
+		}
+	}
+	if payload.RankPreference == RANKPREFERENCE_DISTANCE {
+		sortByDistance(matched, circle.Center)
+	}
+	if payload.MaxResultCount > 0 && len(matched) > payload.MaxResultCount {
+		matched = matched[:payload.MaxResultCount]
+	}
+	return matched
+}
+
+/*
+写真の取得
+フィクスチャでは設定値の画像URIを返す
+*/
+func (f *FakePlaces) Photo(name string) PhotoMediaResource {
+	return PhotoMediaResource{
+		Name:     name,
+		PhotoUri: f.photoUri,
+	}
+}
+
+/*
+長方形内(境界を含む)にあるかの判定
+経度は日付変更線をまたぐ場合も考慮する
+*/
+func inRectangle(r rectangle, l Location) bool {
+	if l.Latitude < r.Low.Latitude || l.Latitude > r.High.Latitude {
+		return false
+	}
+	if r.Low.Longitude <= r.High.Longitude {
+		return r.Low.Longitude <= l.Longitude && l.Longitude <= r.High.Longitude
+	}
+	return l.Longitude >= r.Low.Longitude || l.Longitude <= r.High.Longitude
+}
+
+/*
+中心からの距離(m)
+*/
+func distanceMeter(p pointer, l Location) float64 {
+	return util.CalcDistanceMeter(p.Latitude, p.Longitude, l.Latitude, l.Longitude)
+}
+
+func sortByDistance(places []BaseResource, center pointer) {
+	sort.SliceStable(places, func(i, j int) bool {
+		return distanceMeter(center, places[i].Location) < distanceMeter(center, places[j].Location)
+	})
+}
+
+func encodeFakePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeFakePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("pageTokenが不正です: %w", err)
+	}
+	offset, err := strconv.Atoi(string(decoded))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("pageTokenが不正です: %s", token)
+	}
+	return offset, nil
+}
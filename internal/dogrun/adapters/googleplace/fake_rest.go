@@ -0,0 +1,76 @@
+package googleplace
+
+import (
+	"encoding/json"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+// フィクスチャを使うオフライン用のIRest
+type fakeRest struct {
+	places *FakePlaces
+}
+
+func NewFakeRest(places *FakePlaces) IRest {
+	return &fakeRest{places}
+}
+
+/*
+GET
+フィクスチャからの詳細情報の取得
+*/
+func (r *fakeRest) GETPlaceInfo(c echo.Context, placeId string, field IFieldMask) ([]byte, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	place, ok := r.places.PlaceInfo(placeId)
+	if !ok {
+		err := errors.NewWRError(nil, "Google API リクエスト失敗", errors.NewDogrunServerErrorEType())
+		logger.Errorf("fake google place api: placeId\"%s\"はフィクスチャに存在しません", placeId)
+		return nil, err
+	}
+	return marshalFakeResponse(c, place)
+}
+
+/*
+GET
+フィクスチャからの写真の取得
+*/
+func (r *fakeRest) GETPhotoByName(c echo.Context, name, widthPx, heightPx string) ([]byte, error) {
+	return marshalFakeResponse(c, r.places.Photo(name))
+}
+
+/*
+POST
+フィクスチャでのsearch nearby
+*/
+func (r *fakeRest) POSTSearchNearby(c echo.Context, payload SearchNearbyPayLoad, field IFieldMask) ([]byte, error) {
+	return marshalFakeResponse(c, SearchTextBaseResource{Places: r.places.SearchNearby(payload)})
+}
+
+/*
+POST
+フィクスチャでのsearch text
+*/
+func (r *fakeRest) POSTSearchText(c echo.Context, payload SearchTextPayLoad, field IFieldMask) ([]byte, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	res, err := r.places.SearchText(payload)
+	if err != nil {
+		err = errors.NewWRError(err, "Google API リクエスト失敗", errors.NewDogrunServerErrorEType())
+		logger.Error(err)
+		return nil, err
+	}
+	return marshalFakeResponse(c, res)
+}
+
+func marshalFakeResponse(c echo.Context, res any) ([]byte, error) {
+	body, err := json.Marshal(res)
+	if err != nil {
+		err = errors.NewWRError(err, "レスポンスのJSON変換に失敗しました", errors.NewDogrunServerErrorEType())
+		log.GetLogger(c).Sugar().Error(err)
+		return nil, err
+	}
+	return body, nil
+}
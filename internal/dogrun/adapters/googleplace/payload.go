@@ -66,9 +66,9 @@ func ConvertReqToSearchNearbyPayload(req dto.SearchAroundCircleCondition) Search
 
 	return SearchNearbyPayLoad{
 		IncludedTypes:       []string{"dog_park"},
-		MaxResultCount:      10,
+		MaxResultCount:      20,
 		LocationRestriction: locationRestrictionCircle{Circle: circle},
-		RankPreference:      "POPULARITY",
+		RankPreference:      RANKPREFERENCE_DISTANCE,
 	}
 }
 
@@ -0,0 +1,93 @@
+package googleplace
+
+import (
+	"sync"
+	"time"
+
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 有効期限切れのキャッシュの削除間隔
+const POSTGRES_CACHE_PURGE_INTERVAL = 10 * time.Minute
+
+type postgresCacheStore struct {
+	db         *gorm.DB
+	mu         sync.Mutex
+	lastPurged time.Time
+}
+
+/*
+Postgresのキャッシュストアの生成
+複数インスタンスでキャッシュを共有できる
+*/
+func NewPostgresCacheStore(db *gorm.DB) ICacheStore {
+	return &postgresCacheStore{db: db}
+}
+
+/*
+キャッシュの取得
+有効期限切れ・取得に失敗した場合はミスとする
+*/
+func (s *postgresCacheStore) Get(key string) ([]byte, bool) {
+	var cache model.GooglePlaceCache
+	result := s.db.
+		Where("cache_key = ? AND expires_at > ?", key, time.Now()).
+		Limit(1).
+		Find(&cache)
+	if result.Error != nil {
+		log.GetBaseLogger().Sugar().Errorf("Failed to get google place cache: %v", result.Error)
+		return nil, false
+	}
+	if result.RowsAffected == 0 {
+		return nil, false
+	}
+	return cache.Value, true
+}
+
+/*
+キャッシュの保存
+保存に失敗してもapiのレスポンスは返せるため、ログのみ出力する
+*/
+func (s *postgresCacheStore) Set(key string, value []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	logger := log.GetBaseLogger().Sugar()
+	cache := model.GooglePlaceCache{
+		CacheKey:  key,
+		Value:     value,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cache_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "expires_at", "upd_at"}),
+	}).Create(&cache).Error; err != nil {
+		logger.Errorf("Failed to set google place cache: %v", err)
+	}
+
+	s.purgeExpired()
+}
+
+/*
+有効期限切れのキャッシュの削除
+読まれなくなったキャッシュが残り続けないよう、削除間隔ごとに保存時にまとめて削除する
+*/
+func (s *postgresCacheStore) purgeExpired() {
+	now := time.Now()
+
+	s.mu.Lock()
+	if now.Sub(s.lastPurged) < POSTGRES_CACHE_PURGE_INTERVAL {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPurged = now
+	s.mu.Unlock()
+
+	if err := s.db.Where("expires_at <= ?", now).Delete(&model.GooglePlaceCache{}).Error; err != nil {
+		log.GetBaseLogger().Sugar().Errorf("Failed to purge google place cache: %v", err)
+	}
+}
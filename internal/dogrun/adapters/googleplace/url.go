@@ -1,9 +1,10 @@
 package googleplace
 
+import "github.com/wanrun-develop/wanrun/configs"
+
 const (
 	// MAP_BASE    string = "https://maps.googleapis.com/maps/api/place"
-	PLACES_BASE        string = "https://places.googleapis.com/v1"
-	PLACES_BASE_PLACES string = PLACES_BASE + "/" + PLACES
+	PLACES_BASE string = "https://places.googleapis.com/v1"
 )
 
 const (
@@ -13,18 +14,29 @@ const (
 	MEDIA         string = "media"
 )
 
+/*
+places apiのベースURL
+設定値がある場合(フェイクサーバーなど)はそちらを使う
+*/
+func placesBase() string {
+	if base := configs.FetchConfigStr("google.place.base.url"); base != "" {
+		return base
+	}
+	return PLACES_BASE
+}
+
 func urlPlacesWPlaceId(placeeId string) string {
-	return PLACES_BASE_PLACES + "/" + placeeId
+	return placesBase() + "/" + PLACES + "/" + placeeId
 }
 
 func urlPlacesWSearchNearBy() string {
-	return PLACES_BASE_PLACES + ":" + SEARCH_NEARBY
+	return placesBase() + "/" + PLACES + ":" + SEARCH_NEARBY
 }
 
 func urlPlacesWSearchText() string {
-	return PLACES_BASE_PLACES + ":" + SEARCH_TEXT
+	return placesBase() + "/" + PLACES + ":" + SEARCH_TEXT
 }
 
 func urlPlacesPhotoWName(name string) string {
-	return PLACES_BASE + "/" + name + "/" + MEDIA
+	return placesBase() + "/" + name + "/" + MEDIA
 }
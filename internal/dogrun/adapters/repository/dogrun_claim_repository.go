@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"github.com/labstack/echo/v4"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+)
+
+type IDogrunClaimRepository interface {
+	GetClaimByID(echo.Context, int64) (model.DogrunClaim, error)
+	GetPendingClaimByDogrunID(echo.Context, int64) (model.DogrunClaim, error)
+	FindClaimsByOrganizationID(echo.Context, int64) ([]model.DogrunClaim, error)
+	FindClaimsByStatus(echo.Context, int64) ([]model.DogrunClaim, error)
+}
+
+type dogrunClaimRepository struct {
+	db *gorm.DB
+}
+
+func NewDogrunClaimRepository(db *gorm.DB) IDogrunClaimRepository {
+	return &dogrunClaimRepository{db}
+}
+
+// GetClaimByID: クレームIDで、ステータス履歴を含めたクレームの取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunClaimID
+//
+// return:
+//   - model.DogrunClaim:	検索結果。存在しない場合は空
+//   - error:	エラー
+func (dcr *dogrunClaimRepository) GetClaimByID(c echo.Context, claimID int64) (model.DogrunClaim, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	claim := model.DogrunClaim{}
+	if err := dcr.db.Preload("Dogrun").
+		Preload("Histories", func(db *gorm.DB) *gorm.DB {
+			return db.Order("dogrun_claim_history_id")
+		}).
+		Where("dogrun_claim_id = ?", claimID).
+		Find(&claim).Error; err != nil {
+		logger.Error(err)
+		return model.DogrunClaim{}, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return claim, nil
+}
+
+// GetPendingClaimByDogrunID: ドッグランに対する申請中のクレームの取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//
+// return:
+//   - model.DogrunClaim:	検索結果。存在しない場合は空
+//   - error:	エラー
+func (dcr *dogrunClaimRepository) GetPendingClaimByDogrunID(c echo.Context, dogrunID int64) (model.DogrunClaim, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	claim := model.DogrunClaim{}
+	if err := dcr.db.Where("dogrun_id = ? AND status = ?", dogrunID, model.DOGRUN_CLAIM_STATUS_PENDING).
+		Find(&claim).Error; err != nil {
+		logger.Error(err)
+		return model.DogrunClaim{}, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return claim, nil
+}
+
+// FindClaimsByOrganizationID: organizationが申請したクレームの一覧取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	organizationID
+//
+// return:
+//   - []model.DogrunClaim:	検索結果
+//   - error:	エラー
+func (dcr *dogrunClaimRepository) FindClaimsByOrganizationID(c echo.Context, orgID int64) ([]model.DogrunClaim, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	claims := []model.DogrunClaim{}
+	if err := dcr.db.Preload("Dogrun").
+		Preload("Histories", func(db *gorm.DB) *gorm.DB {
+			return db.Order("dogrun_claim_history_id")
+		}).
+		Where("organization_id = ?", orgID).
+		Order("dogrun_claim_id DESC").
+		Find(&claims).Error; err != nil {
+		logger.Error(err)
+		return nil, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return claims, nil
+}
+
+// FindClaimsByStatus: ステータスでクレームの一覧取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	ステータス
+//
+// return:
+//   - []model.DogrunClaim:	検索結果
+//   - error:	エラー
+func (dcr *dogrunClaimRepository) FindClaimsByStatus(c echo.Context, status int64) ([]model.DogrunClaim, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	claims := []model.DogrunClaim{}
+	if err := dcr.db.Preload("Dogrun").
+		Preload("Histories", func(db *gorm.DB) *gorm.DB {
+			return db.Order("dogrun_claim_history_id")
+		}).
+		Where("status = ?", status).
+		Order("dogrun_claim_id").
+		Find(&claims).Error; err != nil {
+		logger.Error(err)
+		return nil, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return claims, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"github.com/labstack/echo/v4"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+)
+
+type IDogrunClaimScopeRepository interface {
+	CreateClaim(tx *gorm.DB, c echo.Context, claim *model.DogrunClaim) error
+	UpdateClaimStatus(tx *gorm.DB, c echo.Context, claimID int64, from int64, to int64) error
+	CreateClaimHistory(tx *gorm.DB, c echo.Context, history *model.DogrunClaimHistory) error
+}
+
+type dogrunClaimScopeRepository struct {
+}
+
+func NewDogrunClaimScopeRepository() IDogrunClaimScopeRepository {
+	return &dogrunClaimScopeRepository{}
+}
+
+// CreateClaim: クレームの作成
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - *model.DogrunClaim:	クレーム情報
+//
+// return:
+//   - error:	エラー
+func (dcsr *dogrunClaimScopeRepository) CreateClaim(tx *gorm.DB, c echo.Context, claim *model.DogrunClaim) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := tx.Omit("Dogrun", "Histories").Create(claim).Error; err != nil {
+		logger.Error("Failed to create dogrun claim: ", err)
+		return errors.NewWRError(err, "クレームの作成に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	return nil
+}
+
+// UpdateClaimStatus: クレームのステータス更新
+// 更新前のステータスが一致する場合のみ更新し、一致しない場合(審査済み)はエラーとする
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunClaimID
+//   - int64:	更新前のステータス
+//   - int64:	更新後のステータス
+//
+// return:
+//   - error:	エラー
+func (dcsr *dogrunClaimScopeRepository) UpdateClaimStatus(tx *gorm.DB, c echo.Context, claimID int64, from int64, to int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	result := tx.Model(&model.DogrunClaim{}).
+		Where("dogrun_claim_id = ? AND status = ?", claimID, from).
+		Update("status", to)
+
+	if result.Error != nil {
+		logger.Error("Failed to update dogrun claim: ", result.Error)
+		return errors.NewWRError(result.Error, "クレームの更新に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	if result.RowsAffected == 0 {
+		err := errors.NewWRError(nil, "クレームは既に審査済みです。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// CreateClaimHistory: クレームのステータス履歴の作成
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - *model.DogrunClaimHistory:	ステータス履歴
+//
+// return:
+//   - error:	エラー
+func (dcsr *dogrunClaimScopeRepository) CreateClaimHistory(tx *gorm.DB, c echo.Context, history *model.DogrunClaimHistory) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := tx.Create(history).Error; err != nil {
+		logger.Error("Failed to create dogrun claim history: ", err)
+		return errors.NewWRError(err, "クレーム履歴の作成に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	return nil
+}
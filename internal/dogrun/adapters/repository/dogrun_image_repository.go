@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/labstack/echo/v4"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+)
+
+type IDogrunImageRepository interface {
+	GetDogrunImageByID(echo.Context, int64) (model.DogrunImage, error)
+	FindDogrunImagesByStatus(echo.Context, int64, int64) ([]model.DogrunImage, error)
+	FindApprovedDogrunImages(echo.Context, []int64) ([]model.DogrunImage, error)
+	FindDogrunImagesByDogOwnerID(echo.Context, int64) ([]model.DogrunImage, error)
+	ExistsDogrunImageByFileID(echo.Context, string) (bool, error)
+	UpdateDogrunImageCaption(echo.Context, int64, sql.NullString) error
+	DeleteDogrunImage(echo.Context, int64) error
+}
+
+type dogrunImageRepository struct {
+	db *gorm.DB
+}
+
+func NewDogrunImageRepository(db *gorm.DB) IDogrunImageRepository {
+	return &dogrunImageRepository{db}
+}
+
+// GetDogrunImageByID: ギャラリー画像の取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunImageID
+//
+// return:
+//   - model.DogrunImage:	検索結果。存在しない場合は空
+//   - error:	エラー
+func (dir *dogrunImageRepository) GetDogrunImageByID(c echo.Context, imageID int64) (model.DogrunImage, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	image := model.DogrunImage{}
+	if err := dir.db.
+		Where("dogrun_image_id = ?", imageID).
+		Find(&image).Error; err != nil {
+		logger.Error(err)
+		return model.DogrunImage{}, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return image, nil
+}
+
+// FindDogrunImagesByStatus: ドッグランのステータスごとのギャラリー画像の取得
+// 公開中は表示順、それ以外はアップロード順
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - int64:	ステータス
+//
+// return:
+//   - []model.DogrunImage:	検索結果
+//   - error:	エラー
+func (dir *dogrunImageRepository) FindDogrunImagesByStatus(c echo.Context, dogrunID int64, status int64) ([]model.DogrunImage, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	query := dir.db.
+		Where("dogrun_id = ?", dogrunID).
+		Where("status = ?", status)
+	if status == model.DOGRUN_IMAGE_STATUS_APPROVED {
+		query = orderGalleryImages(query)
+	} else {
+		query = query.Order("upload_at, dogrun_image_id")
+	}
+
+	images := []model.DogrunImage{}
+	if err := query.Find(&images).Error; err != nil {
+		logger.Error(err)
+		return nil, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return images, nil
+}
+
+// FindApprovedDogrunImages: 複数ドッグランの公開中のギャラリー画像を表示順で取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []int64:	dogrunIDs
+//
+// return:
+//   - []model.DogrunImage:	検索結果
+//   - error:	エラー
+func (dir *dogrunImageRepository) FindApprovedDogrunImages(c echo.Context, dogrunIDs []int64) ([]model.DogrunImage, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	images := []model.DogrunImage{}
+	if len(dogrunIDs) == 0 {
+		return images, nil
+	}
+	if err := orderGalleryImages(dir.db.
+		Where("dogrun_id IN ?", dogrunIDs).
+		Where("status = ?", model.DOGRUN_IMAGE_STATUS_APPROVED)).
+		Find(&images).Error; err != nil {
+		logger.Error(err)
+		return nil, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return images, nil
+}
+
+// FindDogrunImagesByDogOwnerID: dogownerが投稿したギャラリー画像を新しい順で取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogownerID
+//
+// return:
+//   - []model.DogrunImage:	検索結果
+//   - error:	エラー
+func (dir *dogrunImageRepository) FindDogrunImagesByDogOwnerID(c echo.Context, dogownerID int64) ([]model.DogrunImage, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	images := []model.DogrunImage{}
+	if err := dir.db.
+		Where("dog_owner_id = ?", dogownerID).
+		Order("upload_at DESC, dogrun_image_id DESC").
+		Find(&images).Error; err != nil {
+		logger.Error(err)
+		return nil, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return images, nil
+}
+
+// ExistsDogrunImageByFileID: cmsのファイルがギャラリー画像に使用済みか
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - string:	fileID
+//
+// return:
+//   - bool:	使用済みか
+//   - error:	エラー
+func (dir *dogrunImageRepository) ExistsDogrunImageByFileID(c echo.Context, fileID string) (bool, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	var count int64
+	if err := dir.db.Model(&model.DogrunImage{}).
+		Where("file_id = ?", fileID).
+		Count(&count).Error; err != nil {
+		logger.Error(err)
+		return false, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return count > 0, nil
+}
+
+// UpdateDogrunImageCaption: ギャラリー画像のキャプションの更新
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunImageID
+//   - sql.NullString:	キャプション
+//
+// return:
+//   - error:	エラー
+func (dir *dogrunImageRepository) UpdateDogrunImageCaption(c echo.Context, imageID int64, caption sql.NullString) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := dir.db.Model(&model.DogrunImage{}).
+		Where("dogrun_image_id = ?", imageID).
+		Update("caption", caption).Error; err != nil {
+		logger.Error(err)
+		return errors.NewWRError(err, "ギャラリー画像の更新に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	return nil
+}
+
+// DeleteDogrunImage: ギャラリー画像の削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunImageID
+//
+// return:
+//   - error:	エラー
+func (dir *dogrunImageRepository) DeleteDogrunImage(c echo.Context, imageID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := dir.db.
+		Where("dogrun_image_id = ?", imageID).
+		Delete(&model.DogrunImage{}).Error; err != nil {
+		logger.Error(err)
+		return errors.NewWRError(err, "ギャラリー画像の削除に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	return nil
+}
+
+/*
+公開中のギャラリー画像の表示順
+*/
+func orderGalleryImages(db *gorm.DB) *gorm.DB {
+	return db.Order("dogrun_id, sort_order NULLS LAST, upload_at, dogrun_image_id")
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"github.com/labstack/echo/v4"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+)
+
+type IDogrunImageScopeRepository interface {
+	GetNextSortOrder(tx *gorm.DB, c echo.Context, dogrunID int64) (int64, error)
+	CreateDogrunImage(tx *gorm.DB, c echo.Context, image *model.DogrunImage) error
+	UpdateDogrunImageReview(tx *gorm.DB, c echo.Context, image *model.DogrunImage) error
+	UpdateDogrunImageSortOrders(tx *gorm.DB, c echo.Context, dogrunID int64, imageIDs []int64) error
+}
+
+type dogrunImageScopeRepository struct {
+}
+
+func NewDogrunImageScopeRepository() IDogrunImageScopeRepository {
+	return &dogrunImageScopeRepository{}
+}
+
+// GetNextSortOrder: 公開中のギャラリー画像の末尾の表示順
+// 同時に追加・承認された場合に表示順が重複しないよう、ドッグランの行をロックする
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//
+// return:
+//   - int64:	末尾の次の表示順
+//   - error:	エラー
+func (disr *dogrunImageScopeRepository) GetNextSortOrder(tx *gorm.DB, c echo.Context, dogrunID int64) (int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := tx.Exec("SELECT 1 FROM dogruns WHERE dogrun_id = ? FOR UPDATE", dogrunID).Error; err != nil {
+		logger.Error("Failed to lock dogrun: ", err)
+		return 0, errors.NewWRError(err, "ドッグランのロックに失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+
+	var maxSortOrder int64
+	if err := tx.Model(&model.DogrunImage{}).
+		Select("COALESCE(MAX(sort_order), 0)").
+		Where("dogrun_id = ?", dogrunID).
+		Where("status = ?", model.DOGRUN_IMAGE_STATUS_APPROVED).
+		Scan(&maxSortOrder).Error; err != nil {
+		logger.Error("Failed to get max sort order: ", err)
+		return 0, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return maxSortOrder + 1, nil
+}
+
+// CreateDogrunImage: ギャラリー画像の登録
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - *model.DogrunImage:	画像情報。発行されたIDがセットされる
+//
+// return:
+//   - error:	エラー
+func (disr *dogrunImageScopeRepository) CreateDogrunImage(tx *gorm.DB, c echo.Context, image *model.DogrunImage) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := tx.Omit("Dogrun").Create(image).Error; err != nil {
+		logger.Error("Failed to create dogrun image: ", err)
+		return errors.NewWRError(err, "ギャラリー画像の登録に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	return nil
+}
+
+// UpdateDogrunImageReview: 投稿された画像の審査結果の更新
+// 審査待ちの場合のみ更新し、審査済みの場合はエラーとする
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - *model.DogrunImage:	審査結果(ステータス、表示順、審査者、審査日時、却下理由)
+//
+// return:
+//   - error:	エラー
+func (disr *dogrunImageScopeRepository) UpdateDogrunImageReview(tx *gorm.DB, c echo.Context, image *model.DogrunImage) error {
+	logger := log.GetLogger(c).Sugar()
+
+	result := tx.Model(&model.DogrunImage{}).
+		Where("dogrun_image_id = ? AND status = ?", image.DogrunImageID.Int64, model.DOGRUN_IMAGE_STATUS_PENDING).
+		Select("status", "sort_order", "reviewed_by", "reviewed_at", "reject_reason", "upd_at").
+		Updates(image)
+
+	if result.Error != nil {
+		logger.Error("Failed to update dogrun image: ", result.Error)
+		return errors.NewWRError(result.Error, "ギャラリー画像の更新に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	if result.RowsAffected == 0 {
+		err := errors.NewWRError(nil, "画像は既に審査済みです。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateDogrunImageSortOrders: 公開中のギャラリー画像の表示順を、指定したIDの順に振り直す
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - []int64:	表示順に並べたdogrunImageIDs
+//
+// return:
+//   - error:	エラー
+func (disr *dogrunImageScopeRepository) UpdateDogrunImageSortOrders(tx *gorm.DB, c echo.Context, dogrunID int64, imageIDs []int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	for i, imageID := range imageIDs {
+		if err := tx.Model(&model.DogrunImage{}).
+			Where("dogrun_image_id = ? AND dogrun_id = ?", imageID, dogrunID).
+			Update("sort_order", i+1).Error; err != nil {
+			logger.Error("Failed to update dogrun image sort order: ", err)
+			return errors.NewWRError(err, "ギャラリー画像の並び替えに失敗しました。", errors.NewDogrunServerErrorEType())
+		}
+	}
+	return nil
+}
@@ -8,14 +8,20 @@ import (
 	"github.com/wanrun-develop/wanrun/pkg/log"
 	"github.com/wanrun-develop/wanrun/pkg/util"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IDogrunRepository interface {
 	GetDogrunByPlaceID(echo.Context, string) (model.Dogrun, error)
 	GetDogrunByID(string) (model.Dogrun, error)
+	FindDogrunByID(echo.Context, int64) (model.Dogrun, error)
 	FindDogrunByIDs([]int64) ([]model.Dogrun, error)
+	GetDogrunsByIDs(echo.Context, []int64) ([]model.Dogrun, error)
+	FindDogrunIDsByManagerID(echo.Context, int64) ([]int64, error)
 	GetDogrunByRectanglePointerOrPlaceId(echo.Context, dto.SearchAroundRectangleCondition, []string) ([]model.Dogrun, error)
-	GetDogrunByRectanglePointerAndDogrunTags(echo.Context, dto.SearchAroundRectangleCondition) ([]model.Dogrun, error)
+	GetDogrunByCirclePointerOrPlaceId(echo.Context, dto.SearchAroundCircleCondition, []string) ([]model.Dogrun, error)
+	GetDogrunsInBox(echo.Context, float64, float64, float64, float64, []string) ([]model.Dogrun, error)
+	GetDogrunsInRadius(echo.Context, float64, float64, float64, []string) ([]model.Dogrun, error)
 	GetTagMst(echo.Context) ([]model.TagMst, error)
 	RegistDogrunPlaceId(echo.Context, string) (int64, error)
 }
@@ -56,6 +62,29 @@ func (drr *dogrunRepository) GetDogrunByID(id string) (model.Dogrun, error) {
 	return dogrun, nil
 }
 
+// FindDogrunByID: DogrunIDで、関連情報を含めたドッグランの取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//
+// return:
+//   - model.Dogrun:	検索結果。存在しない場合は空
+//   - error:	エラー
+func (drr *dogrunRepository) FindDogrunByID(c echo.Context, id int64) (model.Dogrun, error) {
+	logger := log.GetLogger(c).Sugar()
+	dogrun := model.Dogrun{}
+	if err := drr.db.Preload("DogrunTags").
+		Preload("RegularBusinessHours").
+		Preload("SpecialBusinessHours").
+		Where("dogrun_id = ?", id).
+		Find(&dogrun).Error; err != nil {
+		logger.Error(err)
+		return model.Dogrun{}, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return dogrun, nil
+}
+
 // FindDogrunByIDs: 複数IDのドッグラン検索
 //
 // args:
@@ -73,7 +102,56 @@ func (drr *dogrunRepository) FindDogrunByIDs(ids []int64) ([]model.Dogrun, error
 	return dogruns, nil
 }
 
+// GetDogrunsByIDs: 複数IDで、関連情報を含めたドッグランの取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []int64:	dogrunIDs
+//
+// return:
+//   - []model.Dogrun:	検索結果(順不同)
+//   - error:	エラー
+func (drr *dogrunRepository) GetDogrunsByIDs(c echo.Context, ids []int64) ([]model.Dogrun, error) {
+	logger := log.GetLogger(c).Sugar()
+	dogruns := []model.Dogrun{}
+	if len(ids) == 0 {
+		return dogruns, nil
+	}
+	if err := drr.db.Preload("DogrunTags").
+		Preload("RegularBusinessHours").
+		Preload("SpecialBusinessHours").
+		Where("dogrun_id IN ?", ids).
+		Find(&dogruns).Error; err != nil {
+		logger.Error(err)
+		return nil, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return dogruns, nil
+}
+
+// FindDogrunIDsByManagerID: dogrun管理者が管理するドッグランIDの取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunmgID
+//
+// return:
+//   - []int64:	dogrunIDs
+//   - error:	エラー
+func (drr *dogrunRepository) FindDogrunIDsByManagerID(c echo.Context, dogrunmgID int64) ([]int64, error) {
+	logger := log.GetLogger(c).Sugar()
+	dogrunIDs := []int64{}
+	if err := drr.db.Model(&model.Dogrun{}).
+		Where("dogrun_manager_id = ?", dogrunmgID).
+		Order("dogrun_id").
+		Pluck("dogrun_id", &dogrunIDs).Error; err != nil {
+		logger.Error(err)
+		return nil, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return dogrunIDs, nil
+}
+
 // GetDogrunByRectanglePointerOrPlaceId: 条件の範囲内 または 指定のPlaceIDのdogrunを取得
+// 長方形の中心からの距離をDistanceにセットする
 //
 // args:
 //   - echo.Context:	コンテキスト
@@ -84,15 +162,51 @@ func (drr *dogrunRepository) FindDogrunByIDs(ids []int64) ([]model.Dogrun, error
 //   - []model.Dogrun:	ドッグランの検索結果
 //   - error:	エラー
 func (drr *dogrunRepository) GetDogrunByRectanglePointerOrPlaceId(c echo.Context, condition dto.SearchAroundRectangleCondition, placeIDs []string) ([]model.Dogrun, error) {
+	sw, ne := condition.Target.Southwest, condition.Target.Northeast
+	return drr.GetDogrunsInBox(c, sw.Latitude, sw.Longitude, ne.Latitude, ne.Longitude, placeIDs)
+}
+
+// GetDogrunByCirclePointerOrPlaceId: 条件の円の範囲内 または 指定のPlaceIDのdogrunを取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.SearchAroundCircleCondition:	条件
+//   - []string:	placeIDs
+//
+// return:
+//   - []model.Dogrun:	ドッグランの検索結果(中心からの距離順)
+//   - error:	エラー
+func (drr *dogrunRepository) GetDogrunByCirclePointerOrPlaceId(c echo.Context, condition dto.SearchAroundCircleCondition, placeIDs []string) ([]model.Dogrun, error) {
+	return drr.GetDogrunsInRadius(c, condition.Center.Latitude, condition.Center.Longitude, float64(condition.Target.Radius), placeIDs)
+}
+
+// GetDogrunsInBox: 長方形(南西, 北東)の範囲内 または 指定のPlaceIDのdogrunを取得
+// 日付変更線をまたぐ長方形(南西の経度 > 北東の経度)にも対応する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - float64:	南西の緯度
+//   - float64:	南西の経度
+//   - float64:	北東の緯度
+//   - float64:	北東の経度
+//   - []string:	placeIDs
+//
+// return:
+//   - []model.Dogrun:	ドッグランの検索結果(長方形の中心からの距離付き)
+//   - error:	エラー
+func (drr *dogrunRepository) GetDogrunsInBox(c echo.Context, swLat, swLng, neLat, neLng float64, placeIDs []string) ([]model.Dogrun, error) {
 	logger := log.GetLogger(c).Sugar()
+
+	boxQuery, boxArgs := boxCondition(swLat, swLng, neLat, neLng)
+	centerLat, centerLng := boxCenter(swLat, swLng, neLat, neLng)
+
 	dogruns := []model.Dogrun{}
-	if err := drr.db.Preload("DogrunTags").
+	if err := drr.db.Scopes(selectWithDistance(centerLat, centerLng)).
+		Preload("DogrunTags").
 		Preload("RegularBusinessHours").
 		Preload("SpecialBusinessHours").
-		Where("(longitude BETWEEN ? AND ?) AND (latitude BETWEEN ? AND ?)",
-			condition.Target.Southwest.Longitude, condition.Target.Northeast.Longitude,
-			condition.Target.Southwest.Latitude, condition.Target.Northeast.Latitude).
-		Or("place_id IN ?", placeIDs).
+		Where(boxQuery, boxArgs...).
+		Or("dogruns.place_id IN ?", placeIDs).
 		Find(&dogruns).Error; err != nil {
 		logger.Error(err)
 		return nil, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
@@ -100,27 +214,29 @@ func (drr *dogrunRepository) GetDogrunByRectanglePointerOrPlaceId(c echo.Context
 	return dogruns, nil
 }
 
-// GetDogrunByRectanglePointerAndDogrunTags: 条件の範囲内 かつ ドッグランタグのdogrunを取得
+// GetDogrunsInRadius: 中心から半径(m)内 または 指定のPlaceIDのdogrunを、中心からの距離順に取得
 //
 // args:
 //   - echo.Context:	コンテキスト
-//   - to.SearchAroundRectangleCondition:	条件
+//   - float64:	中心の緯度
+//   - float64:	中心の経度
+//   - float64:	半径(m)
+//   - []string:	placeIDs
 //
 // return:
-//   - []model.Dogrun:	ドッグランの検索結果
+//   - []model.Dogrun:	ドッグランの検索結果(中心からの距離付き)
 //   - error:	エラー
-func (drr *dogrunRepository) GetDogrunByRectanglePointerAndDogrunTags(c echo.Context, condition dto.SearchAroundRectangleCondition) ([]model.Dogrun, error) {
+func (drr *dogrunRepository) GetDogrunsInRadius(c echo.Context, lat, lng, radius float64, placeIDs []string) ([]model.Dogrun, error) {
 	logger := log.GetLogger(c).Sugar()
+
 	dogruns := []model.Dogrun{}
-	if err := drr.db.Joins("LEFT OUTER JOIN dogrun_tags on dogruns.dogrun_id = dogrun_tags.dogrun_id").
-		Where("(longitude BETWEEN ? AND ?) AND (latitude BETWEEN ? AND ?)",
-			condition.Target.Southwest.Longitude, condition.Target.Northeast.Longitude,
-			condition.Target.Southwest.Latitude, condition.Target.Northeast.Latitude).
-		Where("dogrun_tags.tag_id IN ?", condition.IncludeDogrunTags).
-		Group("dogruns.dogrun_id"). // dogruns の重複を排除
+	if err := drr.db.Scopes(selectWithDistance(lat, lng)).
 		Preload("DogrunTags").
 		Preload("RegularBusinessHours").
 		Preload("SpecialBusinessHours").
+		Where("ST_DWithin(dogruns.geog, "+geogPointSQL+", ?)", lng, lat, radius).
+		Or("dogruns.place_id IN ?", placeIDs).
+		Order("distance NULLS LAST").
 		Find(&dogruns).Error; err != nil {
 		logger.Error(err)
 		return nil, errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
@@ -128,6 +244,46 @@ func (drr *dogrunRepository) GetDogrunByRectanglePointerAndDogrunTags(c echo.Con
 	return dogruns, nil
 }
 
+// 経度, 緯度のプレースホルダからgeographyの点を作るSQL
+const geogPointSQL = "ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography"
+
+/*
+基準点からの距離(m)をdistanceとしてselectするscope
+*/
+func selectWithDistance(lat, lng float64) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Select("dogruns.*, ST_Distance(dogruns.geog, "+geogPointSQL+") AS distance", lng, lat)
+	}
+}
+
+/*
+長方形の範囲内の条件
+日付変更線をまたぐ場合は、東西2つの長方形に分割する
+*/
+func boxCondition(swLat, swLng, neLat, neLng float64) (string, []interface{}) {
+	envelope := "ST_Intersects(dogruns.geog::geometry, ST_MakeEnvelope(?, ?, ?, ?, 4326))"
+	if swLng <= neLng {
+		return envelope, []interface{}{swLng, swLat, neLng, neLat}
+	}
+	return "(" + envelope + " OR " + envelope + ")",
+		[]interface{}{swLng, swLat, 180.0, neLat, -180.0, swLat, neLng, neLat}
+}
+
+/*
+長方形の中心点(緯度, 経度)
+*/
+func boxCenter(swLat, swLng, neLat, neLng float64) (float64, float64) {
+	if swLng > neLng {
+		// 日付変更線をまたぐ場合
+		lng := (swLng + neLng + 360) / 2
+		if lng > 180 {
+			lng -= 360
+		}
+		return (swLat + neLat) / 2, lng
+	}
+	return (swLat + neLat) / 2, (swLng + neLng) / 2
+}
+
 // GetDogrunTagMst: tag_mstの全件select
 //
 // args:
@@ -149,6 +305,7 @@ func (drr *dogrunRepository) GetTagMst(c echo.Context) ([]model.TagMst, error) {
 }
 
 // RegistDogrunPlaceId: placeIdをDBへ保存する
+// 既に保存済みのplaceIdの場合は、そのドッグランのPKを返す
 //
 // args:
 //   - echo.Context: c
@@ -161,7 +318,8 @@ func (drr *dogrunRepository) RegistDogrunPlaceId(c echo.Context, placeId string)
 	logger := log.GetLogger(c).Sugar()
 	dogrun := model.Dogrun{PlaceId: util.NewSqlNullString(placeId)}
 
-	if err := drr.db.Create(&dogrun).Error; err != nil {
+	// 同時に作成された場合は作成済みのドッグランのPKを返す
+	if err := drr.db.Clauses(placeIdUpsertClause()).Create(&dogrun).Error; err != nil {
 		logger.Error(err)
 		err := errors.NewWRError(err, "placeIdのDB保存に失敗", errors.NewDogrunServerErrorEType())
 		return 0, err
@@ -170,3 +328,14 @@ func (drr *dogrunRepository) RegistDogrunPlaceId(c echo.Context, placeId string)
 	//主キー返す
 	return dogrun.DogrunID.Int64, nil
 }
+
+/*
+placeIdの一意制約(idx_dogruns_place_id)に違反した場合に、作成済みのドッグランを返すためのupsert句
+*/
+func placeIdUpsertClause() clause.OnConflict {
+	return clause.OnConflict{
+		Columns:     []clause.Column{{Name: "place_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "place_id IS NOT NULL"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"place_id"}),
+	}
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"github.com/labstack/echo/v4"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+)
+
+type IDogrunScopeRepository interface {
+	UpdateDogrunProfile(tx *gorm.DB, c echo.Context, d *model.Dogrun) error
+	ReplaceDogrunTags(tx *gorm.DB, c echo.Context, dogrunID int64, tags []model.DogrunTag) error
+	ReplaceRegularBusinessHours(tx *gorm.DB, c echo.Context, dogrunID int64, hours []model.RegularBusinessHour) error
+	ReplaceSpecialBusinessHours(tx *gorm.DB, c echo.Context, dogrunID int64, hours []model.SpecialBusinessHour) error
+	LinkDogrunManager(tx *gorm.DB, c echo.Context, dogrunID int64, dogrunmgID int64) error
+	CreateDogrunPlaceId(tx *gorm.DB, c echo.Context, d *model.Dogrun) error
+}
+
+type dogrunScopeRepository struct {
+}
+
+func NewDogrunScopeRepository() IDogrunScopeRepository {
+	return &dogrunScopeRepository{}
+}
+
+// UpdateDogrunProfile: ドッグランの基本情報の更新
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - *model.Dogrun:	更新内容
+//
+// return:
+//   - error:	エラー
+func (drsr *dogrunScopeRepository) UpdateDogrunProfile(tx *gorm.DB, c echo.Context, d *model.Dogrun) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := tx.Model(&model.Dogrun{}).
+		Where("dogrun_id = ?", d.DogrunID.Int64).
+		Select("name", "address", "postcode", "latitude", "longitude", "description", "upd_at").
+		Updates(d).Error; err != nil {
+		logger.Error("Failed to update dogrun: ", err)
+		return errors.NewWRError(err, "ドッグラン情報の更新に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	return nil
+}
+
+// ReplaceDogrunTags: ドッグランタグの全置き換え
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - []model.DogrunTag:	置き換え後のタグ
+//
+// return:
+//   - error:	エラー
+func (drsr *dogrunScopeRepository) ReplaceDogrunTags(tx *gorm.DB, c echo.Context, dogrunID int64, tags []model.DogrunTag) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := tx.Where("dogrun_id = ?", dogrunID).Delete(&model.DogrunTag{}).Error; err != nil {
+		logger.Error("Failed to delete dogrun tags: ", err)
+		return errors.NewWRError(err, "ドッグランタグの削除に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	if err := tx.Create(&tags).Error; err != nil {
+		logger.Error("Failed to create dogrun tags: ", err)
+		return errors.NewWRError(err, "ドッグランタグの登録に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	return nil
+}
+
+// ReplaceRegularBusinessHours: 通常営業時間の全置き換え
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - []model.RegularBusinessHour:	置き換え後の通常営業時間
+//
+// return:
+//   - error:	エラー
+func (drsr *dogrunScopeRepository) ReplaceRegularBusinessHours(tx *gorm.DB, c echo.Context, dogrunID int64, hours []model.RegularBusinessHour) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := tx.Where("dogrun_id = ?", dogrunID).Delete(&model.RegularBusinessHour{}).Error; err != nil {
+		logger.Error("Failed to delete regular business hours: ", err)
+		return errors.NewWRError(err, "通常営業時間の削除に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	if len(hours) == 0 {
+		return nil
+	}
+	if err := tx.Create(&hours).Error; err != nil {
+		logger.Error("Failed to create regular business hours: ", err)
+		return errors.NewWRError(err, "通常営業時間の登録に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	return nil
+}
+
+// ReplaceSpecialBusinessHours: 特別営業時間の全置き換え
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - []model.SpecialBusinessHour:	置き換え後の特別営業時間
+//
+// return:
+//   - error:	エラー
+func (drsr *dogrunScopeRepository) ReplaceSpecialBusinessHours(tx *gorm.DB, c echo.Context, dogrunID int64, hours []model.SpecialBusinessHour) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := tx.Where("dogrun_id = ?", dogrunID).Delete(&model.SpecialBusinessHour{}).Error; err != nil {
+		logger.Error("Failed to delete special business hours: ", err)
+		return errors.NewWRError(err, "特別営業時間の削除に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	if len(hours) == 0 {
+		return nil
+	}
+	if err := tx.Create(&hours).Error; err != nil {
+		logger.Error("Failed to create special business hours: ", err)
+		return errors.NewWRError(err, "特別営業時間の登録に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	return nil
+}
+
+// LinkDogrunManager: ドッグランに管理者を紐付け、管理対象にする
+// 既に管理者が紐付いている場合はエラーとする
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - int64:	dogrunmgID
+//
+// return:
+//   - error:	エラー
+func (drsr *dogrunScopeRepository) LinkDogrunManager(tx *gorm.DB, c echo.Context, dogrunID int64, dogrunmgID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	result := tx.Model(&model.Dogrun{}).
+		Where("dogrun_id = ? AND dogrun_manager_id IS NULL", dogrunID).
+		Updates(map[string]interface{}{
+			"dogrun_manager_id": dogrunmgID,
+			"is_managed":        true,
+		})
+
+	if result.Error != nil {
+		logger.Error("Failed to link dogrun manager: ", result.Error)
+		return errors.NewWRError(result.Error, "ドッグランの管理者の設定に失敗しました。", errors.NewDogrunServerErrorEType())
+	}
+	if result.RowsAffected == 0 {
+		err := errors.NewWRError(nil, "ドッグランには既に管理者が設定されています。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// CreateDogrunPlaceId: placeIdのみのドッグランの作成
+// 同時に作成された場合は、作成済みのドッグランのdogrunIDを設定する
+//
+// args:
+//   - *gorm.DB:	トランザクションを張っているtx情報
+//   - echo.Context:	コンテキスト
+//   - *model.Dogrun:	placeIdを設定したドッグラン。作成後にdogrunIDが設定される
+//
+// return:
+//   - error:	エラー
+func (drsr *dogrunScopeRepository) CreateDogrunPlaceId(tx *gorm.DB, c echo.Context, d *model.Dogrun) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := tx.Clauses(placeIdUpsertClause()).Create(d).Error; err != nil {
+		logger.Error(err)
+		return errors.NewWRError(err, "placeIdのDB保存に失敗", errors.NewDogrunServerErrorEType())
+	}
+	return nil
+}
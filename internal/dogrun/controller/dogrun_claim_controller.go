@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/dto"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/handler"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+type IDogrunClaimController interface {
+	SubmitClaim(echo.Context) error
+	GetOrgClaims(echo.Context) error
+	GetPendingClaims(echo.Context) error
+	ApproveClaim(echo.Context) error
+	RejectClaim(echo.Context) error
+}
+
+type dogrunClaimController struct {
+	h handler.IDogrunClaimHandler
+}
+
+func NewDogrunClaimController(h handler.IDogrunClaimHandler) IDogrunClaimController {
+	return &dogrunClaimController{h}
+}
+
+// SubmitClaim: ドッグランの管理者としてのクレーム申請
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dcc *dogrunClaimController) SubmitClaim(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	reqBody := dto.DogrunClaimReq{}
+	if err := c.Bind(&reqBody); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_IS_INVALID, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	if err := validator.New().Struct(reqBody); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_VALIDATION_FAILED, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	claimID, err := dcc.h.SubmitClaim(c, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, map[string]int64{
+		"dogrunClaimId": claimID,
+	})
+}
+
+// GetOrgClaims: ログインユーザーのorganizationが申請したクレームの一覧取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dcc *dogrunClaimController) GetOrgClaims(c echo.Context) error {
+	claims, err := dcc.h.GetOrgClaims(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, claims)
+}
+
+// GetPendingClaims: 審査待ちのクレームの一覧取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dcc *dogrunClaimController) GetPendingClaims(c echo.Context) error {
+	claims, err := dcc.h.GetPendingClaims(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, claims)
+}
+
+// ApproveClaim: クレームの承認
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dcc *dogrunClaimController) ApproveClaim(c echo.Context) error {
+	claimID, reqBody, err := parseClaimReviewReq(c)
+	if err != nil {
+		return err
+	}
+	if err := dcc.h.ApproveClaim(c, claimID, reqBody); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RejectClaim: クレームの却下
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dcc *dogrunClaimController) RejectClaim(c echo.Context) error {
+	claimID, reqBody, err := parseClaimReviewReq(c)
+	if err != nil {
+		return err
+	}
+	if err := dcc.h.RejectClaim(c, claimID, reqBody); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+/*
+審査リクエストのパスパラメータとリクエストボディの取得とバリデーション
+*/
+func parseClaimReviewReq(c echo.Context) (int64, dto.DogrunClaimReviewReq, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	claimID, err := strconv.ParseInt(c.Param("claimId"), 10, 64)
+	if err != nil || claimID <= 0 {
+		err = errors.NewWRError(err, errors.M_REQUEST_PARAM_MUST_BE_NATURAL, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return 0, dto.DogrunClaimReviewReq{}, err
+	}
+
+	reqBody := dto.DogrunClaimReviewReq{}
+	if err := c.Bind(&reqBody); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_IS_INVALID, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return 0, dto.DogrunClaimReviewReq{}, err
+	}
+	if err := validator.New().Struct(reqBody); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_VALIDATION_FAILED, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return 0, dto.DogrunClaimReviewReq{}, err
+	}
+	return claimID, reqBody, nil
+}
@@ -15,17 +15,26 @@ import (
 type IDogrunController interface {
 	GetDogrunDetail(echo.Context) error
 	GetDogrun(echo.Context) error
+	GetDogrunOccupancy(echo.Context) error
+	GetDogrunOccupancies(echo.Context) error
 	GetDogrunTagMst(echo.Context) error
 	SearchAroundDogruns(echo.Context) error
+	SearchNearbyDogruns(echo.Context) error
 	GetDogrunPhoto(echo.Context) error
+	UpdateDogrunProfile(echo.Context) error
+	UpdateDogrunTags(echo.Context) error
+	UpdateDogrunBusinessHours(echo.Context) error
+	GetBookmarkedDogruns(echo.Context) error
+	GetSharedBookmarkCollection(echo.Context) error
 }
 
 type dogrunController struct {
-	h handler.IDogrunHandler
+	h  handler.IDogrunHandler
+	mh handler.IDogrunManageHandler
 }
 
-func NewDogrunController(h handler.IDogrunHandler) IDogrunController {
-	return &dogrunController{h}
+func NewDogrunController(h handler.IDogrunHandler, mh handler.IDogrunManageHandler) IDogrunController {
+	return &dogrunController{h, mh}
 }
 
 // ドッグラン詳細情報の取得
@@ -49,6 +58,91 @@ func (dc *dogrunController) GetDogrun(c echo.Context) error {
 	return nil
 }
 
+// GetDogrunOccupancy: ドッグランの現在の混雑状況を取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dc *dogrunController) GetDogrunOccupancy(c echo.Context) error {
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	occupancy, err := dc.h.GetDogrunOccupancy(c, dogrunID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, occupancy)
+}
+
+// GetDogrunOccupancies: 複数ドッグランの現在の混雑状況を一括取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dc *dogrunController) GetDogrunOccupancies(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	var req dto.OccupancyReq
+	if err := c.Bind(&req); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_IS_INVALID, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	if err := validator.New().Struct(req); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_VALIDATION_FAILED, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	occupancies, err := dc.h.GetDogrunOccupancies(c, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, occupancies)
+}
+
+// SearchNearbyDogruns: 指定内（円）のドッグランを中心からの距離順で検索
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dc *dogrunController) SearchNearbyDogruns(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+	//リクエストボディをバインド
+	var condition dto.SearchAroundCircleCondition
+	if err := c.Bind(&condition); err != nil {
+		err = errors.NewWRError(err, "検索条件が不正です", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	// バリデータのインスタンス作成
+	validate := validator.New()
+	// カスタムバリデーションルールの登録
+	_ = validate.RegisterValidation("latitude", dto.VLatitude)
+	_ = validate.RegisterValidation("longitude", dto.VLongitude)
+
+	//リクエストボディのバリデーション
+	if err := validate.Struct(condition); err != nil {
+		err = errors.NewWRError(err, "検索条件のバリデーションに違反しています", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	resDogruns, err := dc.h.SearchNearbyDogruns(c, condition)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resDogruns)
+}
+
 // GetDogrunTagMst: DogrunTagMstのマスターデータの取得
 //
 // args:
@@ -90,24 +184,12 @@ func (dc *dogrunController) SearchAroundDogruns(c echo.Context) error {
 		return err
 	}
 
-	if len(condition.IncludeDogrunTags) == 0 {
-		//ドッグランタグ検索条件がない場合は、google検索を基準とする
-		if resDogruns, err := dc.h.SearchAroundDogruns(c, condition); err != nil {
-			return err
-		} else {
-			return c.JSON(http.StatusOK, resDogruns)
-
-		}
-	} else {
-		//ドッグランタグ検索条件がある場合は、DB検索を基準とする
-		if resDogruns, err := dc.h.SearchAroundAndTagDogruns(c, condition); err != nil {
-			return err
-		} else {
-			return c.JSON(http.StatusOK, resDogruns)
-
-		}
+	//google検索を基準とし、DB情報と統合して絞り込む
+	resDogruns, err := dc.h.SearchAroundDogruns(c, condition)
+	if err != nil {
+		return err
 	}
-
+	return c.JSON(http.StatusOK, resDogruns)
 }
 
 // ドッグランの画像nameよりsrcUrlの取得
@@ -142,6 +224,215 @@ func (dc *dogrunController) GetDogrunPhoto(c echo.Context) error {
 	})
 }
 
+// UpdateDogrunProfile: ドッグランの基本情報の更新(ドッグラン管理者のみ)
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dc *dogrunController) UpdateDogrunProfile(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	//リクエストボディをバインド
+	var reqBody dto.DogrunProfileUpdateReq
+	if err := c.Bind(&reqBody); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_IS_INVALID, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	// バリデータのインスタンス作成
+	validate := validator.New()
+	// カスタムバリデーションルールの登録
+	_ = validate.RegisterValidation("latitude", dto.VLatitude)
+	_ = validate.RegisterValidation("longitude", dto.VLongitude)
+
+	//リクエストボディのバリデーション
+	if err := validate.Struct(reqBody); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_VALIDATION_FAILED, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	if err := dc.mh.UpdateDogrunProfile(c, dogrunID, reqBody); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]int64{
+		"dogrunId": dogrunID,
+	})
+}
+
+// UpdateDogrunTags: ドッグランタグの更新(ドッグラン管理者のみ)
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dc *dogrunController) UpdateDogrunTags(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	//リクエストボディをバインド
+	var reqBody dto.DogrunTagUpdateReq
+	if err := c.Bind(&reqBody); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_IS_INVALID, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	// バリデータのインスタンス作成
+	validate := validator.New()
+
+	//リクエストボディのバリデーション
+	if err := validate.Struct(reqBody); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_VALIDATION_FAILED, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	if err := dc.mh.UpdateDogrunTags(c, dogrunID, reqBody); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]int64{
+		"dogrunId": dogrunID,
+	})
+}
+
+// UpdateDogrunBusinessHours: ドッグランの営業時間の更新(ドッグラン管理者のみ)
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dc *dogrunController) UpdateDogrunBusinessHours(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	//リクエストボディをバインド
+	var reqBody dto.DogrunBusinessHourUpdateReq
+	if err := c.Bind(&reqBody); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_IS_INVALID, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	// バリデータのインスタンス作成
+	validate := validator.New()
+	// カスタムバリデーションルールの登録
+	_ = validate.RegisterValidation("businessTime", dto.VBusinessTime)
+
+	//リクエストボディのバリデーション
+	if err := validate.Struct(reqBody); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_VALIDATION_FAILED, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	if err := dc.mh.UpdateDogrunBusinessHours(c, dogrunID, reqBody); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]int64{
+		"dogrunId": dogrunID,
+	})
+}
+
+// ブックマーク済みドッグラン一覧の取得
+func (dc *dogrunController) GetBookmarkedDogruns(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	var req dto.BookmarkedDogrunsReq
+	if err := c.Bind(&req); err != nil {
+		err = errors.NewWRError(err, "ブックマーク一覧の条件が不正です", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	if err := validateBookmarkListCondition(c, req); err != nil {
+		return err
+	}
+
+	res, err := dc.h.GetBookmarkedDogruns(c, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// 共有リンクからコレクションの取得(ログイン不要)
+func (dc *dogrunController) GetSharedBookmarkCollection(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	token := c.Param("token")
+	if token == "" || len(token) > 256 {
+		err := errors.NewWRError(nil, "共有リンクが無効です。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	var req dto.SharedBookmarkCollectionReq
+	if err := c.Bind(&req); err != nil {
+		err = errors.NewWRError(err, "ブックマーク一覧の条件が不正です", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	if err := validateBookmarkListCondition(c, req); err != nil {
+		return err
+	}
+
+	res, err := dc.h.GetSharedBookmarkCollection(c, token, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+/*
+ブックマーク一覧の条件のバリデーション
+*/
+func validateBookmarkListCondition(c echo.Context, req any) error {
+	logger := log.GetLogger(c).Sugar()
+
+	// バリデータのインスタンス作成
+	validate := validator.New()
+	// カスタムバリデーションルールの登録
+	_ = validate.RegisterValidation("latitude", dto.VLatitude)
+	_ = validate.RegisterValidation("longitude", dto.VLongitude)
+
+	if err := validate.Struct(req); err != nil {
+		err = errors.NewWRError(err, "ブックマーク一覧の条件のバリデーションに違反しています", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+/*
+パスパラメータのdogrunIDの取得とバリデーション
+*/
+func parseDogrunIDParam(c echo.Context) (int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || dogrunID <= 0 {
+		err = errors.NewWRError(err, errors.M_REQUEST_PARAM_MUST_BE_NATURAL, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return 0, err
+	}
+	return dogrunID, nil
+}
+
 /*
 リクエストのクエリパラメータのpxのバリデーション
 */
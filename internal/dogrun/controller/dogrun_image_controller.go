@@ -0,0 +1,318 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/dto"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/handler"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+type IDogrunImageController interface {
+	GetDogrunImages(echo.Context) error
+	GetManagedDogrunImages(echo.Context) error
+	AddDogrunImage(echo.Context) error
+	UpdateDogrunImageCaption(echo.Context) error
+	ReorderDogrunImages(echo.Context) error
+	DeleteDogrunImage(echo.Context) error
+	ApproveDogrunImage(echo.Context) error
+	RejectDogrunImage(echo.Context) error
+	SubmitDogrunImage(echo.Context) error
+	GetSubmittedDogrunImages(echo.Context) error
+	DeleteSubmittedDogrunImage(echo.Context) error
+}
+
+type dogrunImageController struct {
+	h handler.IDogrunImageHandler
+}
+
+func NewDogrunImageController(h handler.IDogrunImageHandler) IDogrunImageController {
+	return &dogrunImageController{h}
+}
+
+// GetDogrunImages: ドッグランの公開中のギャラリー画像の取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dic *dogrunImageController) GetDogrunImages(c echo.Context) error {
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	images, err := dic.h.GetDogrunImages(c, dogrunID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, images)
+}
+
+// GetManagedDogrunImages: 管理しているドッグランのギャラリー画像のステータスごとの取得(ドッグラン管理者のみ)
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dic *dogrunImageController) GetManagedDogrunImages(c echo.Context) error {
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+	req := dto.DogrunImageStatusReq{}
+	if err := bindAndValidateImageReq(c, &req); err != nil {
+		return err
+	}
+
+	images, err := dic.h.GetManagedDogrunImages(c, dogrunID, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, images)
+}
+
+// AddDogrunImage: ギャラリー画像の追加(ドッグラン管理者のみ)
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dic *dogrunImageController) AddDogrunImage(c echo.Context) error {
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+	reqBody := dto.DogrunImageReq{}
+	if err := bindAndValidateImageReq(c, &reqBody); err != nil {
+		return err
+	}
+
+	image, err := dic.h.AddDogrunImage(c, dogrunID, reqBody)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, image)
+}
+
+// UpdateDogrunImageCaption: ギャラリー画像のキャプションの更新(ドッグラン管理者のみ)
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dic *dogrunImageController) UpdateDogrunImageCaption(c echo.Context) error {
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+	imageID, err := parseDogrunImageIDParam(c)
+	if err != nil {
+		return err
+	}
+	reqBody := dto.DogrunImageCaptionReq{}
+	if err := bindAndValidateImageReq(c, &reqBody); err != nil {
+		return err
+	}
+
+	if err := dic.h.UpdateDogrunImageCaption(c, dogrunID, imageID, reqBody); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ReorderDogrunImages: ギャラリー画像の並び替え(ドッグラン管理者のみ)
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dic *dogrunImageController) ReorderDogrunImages(c echo.Context) error {
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+	reqBody := dto.DogrunImageOrderReq{}
+	if err := bindAndValidateImageReq(c, &reqBody); err != nil {
+		return err
+	}
+
+	if err := dic.h.ReorderDogrunImages(c, dogrunID, reqBody); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteDogrunImage: ギャラリー画像の削除(ドッグラン管理者のみ)
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dic *dogrunImageController) DeleteDogrunImage(c echo.Context) error {
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+	imageID, err := parseDogrunImageIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := dic.h.DeleteDogrunImage(c, dogrunID, imageID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ApproveDogrunImage: 投稿された画像の承認(ドッグラン管理者のみ)
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dic *dogrunImageController) ApproveDogrunImage(c echo.Context) error {
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+	imageID, err := parseDogrunImageIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := dic.h.ApproveDogrunImage(c, dogrunID, imageID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RejectDogrunImage: 投稿された画像の却下(ドッグラン管理者のみ)
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dic *dogrunImageController) RejectDogrunImage(c echo.Context) error {
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+	imageID, err := parseDogrunImageIDParam(c)
+	if err != nil {
+		return err
+	}
+	reqBody := dto.DogrunImageRejectReq{}
+	if err := bindAndValidateImageReq(c, &reqBody); err != nil {
+		return err
+	}
+
+	if err := dic.h.RejectDogrunImage(c, dogrunID, imageID, reqBody); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SubmitDogrunImage: dogownerによるドッグランの写真の投稿
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dic *dogrunImageController) SubmitDogrunImage(c echo.Context) error {
+	dogrunID, err := parseDogrunIDParam(c)
+	if err != nil {
+		return err
+	}
+	reqBody := dto.DogrunImageReq{}
+	if err := bindAndValidateImageReq(c, &reqBody); err != nil {
+		return err
+	}
+
+	image, err := dic.h.SubmitDogrunImage(c, dogrunID, reqBody)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, image)
+}
+
+// GetSubmittedDogrunImages: ログインしているdogownerが投稿した写真の取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dic *dogrunImageController) GetSubmittedDogrunImages(c echo.Context) error {
+	images, err := dic.h.GetSubmittedDogrunImages(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, images)
+}
+
+// DeleteSubmittedDogrunImage: ログインしているdogownerが投稿した写真の削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dic *dogrunImageController) DeleteSubmittedDogrunImage(c echo.Context) error {
+	imageID, err := parseDogrunImageIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := dic.h.DeleteSubmittedDogrunImage(c, imageID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+/*
+パスパラメータのdogrunImageIDの取得とバリデーション
+*/
+func parseDogrunImageIDParam(c echo.Context) (int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	imageID, err := strconv.ParseInt(c.Param("imageId"), 10, 64)
+	if err != nil || imageID <= 0 {
+		err = errors.NewWRError(err, errors.M_REQUEST_PARAM_MUST_BE_NATURAL, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return 0, err
+	}
+	return imageID, nil
+}
+
+/*
+ギャラリー画像のリクエストのバインドとバリデーション
+*/
+func bindAndValidateImageReq(c echo.Context, req any) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := c.Bind(req); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_IS_INVALID, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	if err := validator.New().Struct(req); err != nil {
+		err = errors.NewWRError(err, errors.M_REQUEST_BODY_VALIDATION_FAILED, errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	return nil
+}
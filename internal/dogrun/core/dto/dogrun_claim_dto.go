@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+)
+
+// ドッグランのクレーム申請
+// dogrunIdかplaceIdのどちらかを指定する
+type DogrunClaimReq struct {
+	DogrunID    int64  `json:"dogrunId" validate:"required_without=PlaceID,omitempty,min=1"`
+	PlaceID     string `json:"placeId" validate:"required_without=DogrunID,omitempty,max=255"`
+	Evidence    string `json:"evidence" validate:"required,max=2000"`
+	EvidenceURL string `json:"evidenceUrl" validate:"omitempty,url,max=2048"`
+}
+
+// クレームの審査
+type DogrunClaimReviewReq struct {
+	Comment string `json:"comment" validate:"max=2000"`
+}
+
+// クレーム情報
+type DogrunClaimRes struct {
+	DogrunClaimID  int64                   `json:"dogrunClaimId"`
+	DogrunID       int64                   `json:"dogrunId"`
+	PlaceId        string                  `json:"placeId,omitempty"`
+	DogrunName     string                  `json:"dogrunName,omitempty"`
+	DogrunmgID     int64                   `json:"dogrunmgId"`
+	OrganizationID int64                   `json:"organizationId"`
+	Evidence       string                  `json:"evidence"`
+	EvidenceURL    string                  `json:"evidenceUrl,omitempty"`
+	Status         int64                   `json:"status"`
+	Histories      []DogrunClaimHistoryRes `json:"histories"`
+	CreateAt       *time.Time              `json:"createAt,omitempty"`
+	UpdateAt       *time.Time              `json:"updateAt,omitempty"`
+}
+
+// クレームのステータス履歴
+type DogrunClaimHistoryRes struct {
+	Status        int64      `json:"status"`
+	ChangedBy     int64      `json:"changedBy,omitempty"`
+	ChangedByRole int64      `json:"changedByRole"`
+	Comment       string     `json:"comment,omitempty"`
+	CreateAt      *time.Time `json:"createAt,omitempty"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+)
+
+// ギャラリー画像の登録・投稿
+// fileIdはcmsでアップロードしたファイルのID
+type DogrunImageReq struct {
+	FileID  string `json:"fileId" validate:"required,max=64"`
+	Caption string `json:"caption" validate:"max=200"`
+}
+
+// ギャラリー画像のキャプションの更新
+type DogrunImageCaptionReq struct {
+	Caption string `json:"caption" validate:"max=200"`
+}
+
+// ギャラリー画像の並び替え
+// 公開中の画像のIDをすべて、表示順に指定する
+type DogrunImageOrderReq struct {
+	DogrunImageIDs []int64 `json:"dogrunImageIds" validate:"required,min=1,dive,min=1"`
+}
+
+// 投稿された画像の却下
+type DogrunImageRejectReq struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// 管理者向けのギャラリー画像一覧のクエリパラメータ
+type DogrunImageStatusReq struct {
+	Status int64 `query:"status" validate:"omitempty,oneof=1 2 3"` // 1:審査待ち, 2:公開, 3:却下。未指定の場合は審査待ち
+}
+
+// ギャラリー画像
+type DogrunImageRes struct {
+	DogrunImageID   int64      `json:"dogrunImageId"`
+	DogrunID        int64      `json:"dogrunId"`
+	Image           string     `json:"image"` // s3のオブジェクトキー(cms以前の画像はURL)
+	Caption         string     `json:"caption,omitempty"`
+	SortOrder       int64      `json:"sortOrder,omitempty"` // 公開中の画像のみ
+	Status          int64      `json:"status"`
+	DogOwnerID      int64      `json:"dogOwnerId,omitempty"`      // 投稿したdogowner
+	DogrunManagerID int64      `json:"dogrunManagerId,omitempty"` // アップロードしたdogrun_manager
+	RejectReason    string     `json:"rejectReason,omitempty"`
+	UploadAt        *time.Time `json:"uploadAt,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewedAt,omitempty"`
+}
@@ -29,7 +29,112 @@ type centerTarget struct {
 */
 type SearchAroundRectangleCondition struct {
 	Target            rectangleTarget `json:"target" validate:"required"`
-	IncludeDogrunTags []int64         `json:"includeDogrunTags" validate:"min=0,max=100"`
+	IncludeDogrunTags []int64         `json:"includeDogrunTags" validate:"min=0,max=100"` // filter.includeTagsと同じ(互換用)
+	Filter            SearchFilter    `json:"filter"`
+	Sort              SearchSort      `json:"sort"`
+	PageSize          int             `json:"pageSize" validate:"omitempty,gte=1,lte=100"` // 未指定の場合は全件
+	Cursor            string          `json:"cursor" validate:"omitempty,max=256"`         // 前回レスポンスのnextCursor
+}
+
+// タグ条件の一致方法
+const (
+	TAG_MATCH_ANY = "any" // いずれかのタグを含む
+	TAG_MATCH_ALL = "all" // 全てのタグを含む
+)
+
+/*
+検索結果の絞り込み条件
+google, DBのどちらから取得した結果にも同じく適用する
+*/
+type SearchFilter struct {
+	IncludeTags []int64 `json:"includeTags" validate:"max=100"`
+	TagMatch    string  `json:"tagMatch" validate:"omitempty,oneof=any all"` // 未指定の場合はany
+	ExcludeTags []int64 `json:"excludeTags" validate:"max=100"`
+	OpenNow     bool    `json:"openNow"`                                                        // 現在営業中のみ
+	OpenAt      string  `json:"openAt" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"` // 指定日時(RFC3339)に営業中のみ
+	MinRating   float32 `json:"minRating" validate:"gte=0,lte=5"`                               // google評価の下限
+	Name        string  `json:"name" validate:"max=256"`                                        // 名前の部分一致
+	ManagedOnly bool    `json:"managedOnly"`                                                    // 管理者がいるドッグランのみ
+}
+
+/*
+絞り込み条件の取得
+互換用のincludeDogrunTagsはincludeTagsに統合する
+*/
+func (c SearchAroundRectangleCondition) ResolveFilter() SearchFilter {
+	filter := c.Filter
+	if len(c.IncludeDogrunTags) > 0 {
+		filter.IncludeTags = append(append([]int64{}, filter.IncludeTags...), c.IncludeDogrunTags...)
+	}
+	return filter
+}
+
+// 検索結果の並び替えキー
+const (
+	SORT_KEY_DISTANCE      = "distance"     // 基準点からの距離
+	SORT_KEY_RATING        = "rating"       // google評価
+	SORT_KEY_RATING_COUNT  = "ratingCount"  // google評価数
+	SORT_KEY_NAME          = "name"         // 名前
+	SORT_KEY_OPEN_NOW      = "openNow"      // 営業中を先頭(同じ場合は距離順)
+	SORT_KEY_WANRUN_RATING = "wanrunRating" // WanRunのレビューの平均評価(同じ場合はレビュー数順)
+)
+
+// 検索結果の並び順
+const (
+	SORT_ORDER_ASC  = "asc"
+	SORT_ORDER_DESC = "desc"
+)
+
+/*
+検索結果の並び替え条件
+未指定の場合は、検索範囲の中心からの距離順
+*/
+type SearchSort struct {
+	Key       string   `json:"key" validate:"omitempty,oneof=distance rating ratingCount name openNow wanrunRating"`
+	Order     string   `json:"order" validate:"omitempty,oneof=asc desc"` // 未指定の場合はキーごとの既定順
+	Reference *pointer `json:"reference" validate:"omitempty"`            // 距離の基準点。未指定の場合は検索範囲の中心
+}
+
+// ブックマーク一覧の並び替えキー
+// 検索結果の並び替えキーに加えて指定できる
+const (
+	BOOKMARK_SORT_KEY_SAVED_AT = "savedAt" // ブックマーク登録日時(コレクション指定時は追加日時)
+)
+
+/*
+ブックマーク済みドッグラン一覧のクエリパラメータ
+*/
+type BookmarkedDogrunsReq struct {
+	CollectionID int64 `query:"collectionId" validate:"omitempty,min=1"` // 指定した場合はコレクション内のドッグランのみ
+	BookmarkListCondition
+}
+
+/*
+共有されたコレクションのクエリパラメータ
+並び替えは登録日時順のみ。ページサイズ未指定の場合は既定の件数でページングする
+*/
+type SharedBookmarkCollectionReq struct {
+	BookmarkListCondition
+}
+
+/*
+ブックマーク一覧の並び替えとページング
+距離は基準点(latitude, longitude)を指定した場合のみセットする
+*/
+type BookmarkListCondition struct {
+	Sort      string  `query:"sort" validate:"omitempty,oneof=savedAt distance rating ratingCount name openNow wanrunRating"` // 未指定の場合はsavedAt
+	Order     string  `query:"order" validate:"omitempty,oneof=asc desc"`                                                     // 未指定の場合、登録日時・評価・評価数は降順、それ以外は昇順
+	Latitude  float64 `query:"latitude" validate:"required_if=Sort distance,required_with=Longitude,omitempty,latitude"`
+	Longitude float64 `query:"longitude" validate:"required_if=Sort distance,required_with=Latitude,omitempty,longitude"`
+	PageSize  int     `query:"pageSize" validate:"omitempty,gte=1,lte=100"` // 未指定の場合は全件
+	Cursor    string  `query:"cursor" validate:"omitempty,max=256"`         // 前回レスポンスのnextCursor
+}
+
+/*
+距離の基準点が指定されているか
+*/
+func (c BookmarkListCondition) HasReference() bool {
+	return c.Latitude != 0 || c.Longitude != 0
 }
 
 /*
@@ -39,3 +144,63 @@ type rectangleTarget struct {
 	Southwest pointer `json:"southwest" validate:"required"`
 	Northeast pointer `json:"northeast" validate:"required"`
 }
+
+/*
+ドッグラン基本情報更新のリクエストボディ
+*/
+type DogrunProfileUpdateReq struct {
+	Name        string  `json:"name" validate:"required,max=256"`
+	Address     string  `json:"address" validate:"required,max=256"`
+	PostCode    string  `json:"postcode" validate:"max=8"`
+	Description string  `json:"description"`
+	Location    pointer `json:"location" validate:"required"`
+}
+
+/*
+ドッグランタグ更新のリクエストボディ
+指定されたタグで全置き換えする
+*/
+type DogrunTagUpdateReq struct {
+	DogrunTags []int64 `json:"dogrunTagIds" validate:"max=100,dive,gt=0"`
+}
+
+/*
+ドッグラン営業時間更新のリクエストボディ
+通常営業時間、特別営業時間ともに指定された内容で全置き換えする
+*/
+type DogrunBusinessHourUpdateReq struct {
+	Regular []RegularBusinessHourReq `json:"regular" validate:"max=7,dive"`
+	Special []SpecialBusinessHourReq `json:"special" validate:"max=366,dive"`
+}
+
+/*
+通常営業時間(曜日ごと)
+*/
+type RegularBusinessHourReq struct {
+	Day *int `json:"day" validate:"required,gte=0,lte=6"` // 曜日（0: 日曜日, 1: 月曜日,...）
+	BusinessTimeReq
+}
+
+/*
+特別営業時間(日付ごと)
+*/
+type SpecialBusinessHourReq struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02"` // yyyy-MM-dd
+	BusinessTimeReq
+}
+
+/*
+営業時間
+時刻はHH:mm:ss形式。isAllDay、isHolidayがtrueの場合は時刻を無視する
+*/
+type BusinessTimeReq struct {
+	OpenTime  string `json:"openTime" validate:"omitempty,businessTime"`
+	CloseTime string `json:"closeTime" validate:"omitempty,businessTime"`
+	IsAllDay  bool   `json:"isAllDay"`
+	IsHoliday bool   `json:"isHoliday"`
+}
+
+// 混雑状況の一括取得用
+type OccupancyReq struct {
+	DogrunIDs []int64 `json:"dogrunIds" validate:"required,min=1,max=100,dive,min=1"`
+}
@@ -6,21 +6,26 @@ import (
 
 // ドッグラン詳細画面での表示情報
 type DogrunDetail struct {
-	DogrunID        int64        `json:"dogrunId,omitempty"`
-	DogrunManagerID int64        `json:"dogrunManagerId,omitempty"`
-	PlaceId         string       `json:"placeId,omitempty"`
-	Name            string       `json:"name"`
-	Address         Address      `json:"address"`
-	Location        Location     `json:"location"`
-	BusinessStatus  string       `json:"businessStatus,omitempty"`
-	NowOpen         bool         `json:"nowOpen"`
-	BusinessHour    BusinessHour `json:"businessHour"`
-	Description     string       `json:"description,omitempty"`
-	GoogleRating    float32      `json:"googleRating,omitempty"`
-	UserRatingCount int          `json:"userRatingCount,omitempty"`
-	DogrunTags      []int64      `json:"dogrunTagId,omitempty"`
-	CreateAt        *time.Time   `json:"createAt,omitempty"`
-	UpdateAt        *time.Time   `json:"updateAt,omitempty"`
+	DogrunID            int64            `json:"dogrunId,omitempty"`
+	DogrunManagerID     int64            `json:"dogrunManagerId,omitempty"`
+	PlaceId             string           `json:"placeId,omitempty"`
+	Name                string           `json:"name"`
+	Address             Address          `json:"address"`
+	Location            Location         `json:"location"`
+	BusinessStatus      string           `json:"businessStatus,omitempty"`
+	NowOpen             bool             `json:"nowOpen"`
+	BusinessHour        BusinessHour     `json:"businessHour"`
+	Description         string           `json:"description,omitempty"`
+	GoogleRating        float32          `json:"googleRating,omitempty"`
+	UserRatingCount     int              `json:"userRatingCount,omitempty"`
+	WanrunRating        float64          `json:"wanrunRating,omitempty"`        // WanRunのレビューの平均評価
+	WanrunRatingCount   int64            `json:"wanrunRatingCount,omitempty"`   // WanRunのレビュー数
+	WanrunAspectRatings *AspectRatingRes `json:"wanrunAspectRatings,omitempty"` // WanRunのレビューの観点別の平均評価
+	DogrunTags          []int64          `json:"dogrunTagId,omitempty"`
+	Photos              []PhotoInfo      `json:"photos,omitempty"`
+	Occupancy           *OccupancyRes    `json:"occupancy,omitempty"` // 現在の混雑状況
+	CreateAt            *time.Time       `json:"createAt,omitempty"`
+	UpdateAt            *time.Time       `json:"updateAt,omitempty"`
 }
 
 // ドッグラン一覧での表示情報
@@ -36,10 +41,66 @@ type DogrunLists struct {
 	Description       string          `json:"description,omitempty"`
 	GoogleRating      float32         `json:"googleRating,omitempty"`
 	UserRatingCount   int             `json:"userRatingCount,omitempty"`
+	WanrunRating      float64         `json:"wanrunRating,omitempty"`      // WanRunのレビューの平均評価
+	WanrunRatingCount int64           `json:"wanrunRatingCount,omitempty"` // WanRunのレビュー数
 	DogrunTags        []int64         `json:"dogrunTagId,omitempty"`
 	Photos            []PhotoInfo     `json:"photos,omitempty"`
 	IsBookmarked      bool            `json:"isBookmarked"`
 	IsManaged         bool            `json:"isManaged"`
+	Distance          *float64        `json:"distance,omitempty"`  // 検索の基準点からの距離(m)
+	Occupancy         *OccupancyRes   `json:"occupancy,omitempty"` // 現在の混雑状況
+}
+
+// ドッグラン検索結果(ページング)
+type DogrunSearchRes struct {
+	Dogruns    []DogrunLists `json:"dogruns"`
+	TotalCount int           `json:"totalCount"`
+	NextCursor string        `json:"nextCursor,omitempty"` // 次のページがない場合は空
+}
+
+// ブックマーク済みドッグラン
+type BookmarkedDogrun struct {
+	DogrunLists
+	SavedAt time.Time `json:"savedAt"`        // ブックマーク登録日時(コレクション内の場合は追加日時)
+	Note    string    `json:"note,omitempty"` // コレクション内のメモ
+}
+
+// ブックマーク済みドッグラン一覧(ページング)
+type BookmarkedDogrunsRes struct {
+	Dogruns    []BookmarkedDogrun `json:"dogruns"`
+	TotalCount int                `json:"totalCount"`
+	NextCursor string             `json:"nextCursor,omitempty"` // 次のページがない場合は空
+}
+
+// 共有リンクから閲覧するコレクション
+type SharedBookmarkCollectionRes struct {
+	Name string `json:"name"`
+	Note string `json:"note,omitempty"`
+	BookmarkedDogrunsRes
+}
+
+// ドッグランの混雑状況(滞在中のdog数)
+type OccupancyRes struct {
+	DogrunID int64            `json:"dogrunId"`
+	Total    int64            `json:"total"`
+	Sizes    OccupancySizeRes `json:"sizes"`
+	AsOf     time.Time        `json:"asOf"`
+}
+
+// サイズ区分別の滞在中のdog数
+type OccupancySizeRes struct {
+	Small   int64 `json:"small"`
+	Medium  int64 `json:"medium"`
+	Large   int64 `json:"large"`
+	Unknown int64 `json:"unknown"` // 体重未登録
+}
+
+// ドッグランのレビューの観点別の平均評価
+// 評価がない観点は省略
+type AspectRatingRes struct {
+	Cleanliness *float64 `json:"cleanliness,omitempty"` // 清潔さ
+	Size        *float64 `json:"size,omitempty"`        // 広さ
+	Crowding    *float64 `json:"crowding,omitempty"`    // 混雑具合(空いているほど高い)
 }
 
 // 営業日情報
@@ -72,10 +133,21 @@ type SpecialBusinessHour struct {
 	DayBusinessTime
 }
 
+// 写真の提供元
+const (
+	PHOTO_SOURCE_WANRUN = "wanrun" // WanRunのギャラリー画像(photoKeyはS3のオブジェクトキー)
+	PHOTO_SOURCE_GOOGLE = "google" // Google Place APIの写真(photoKeyはphotoのname)
+)
+
+// ドッグランの写真
+// WanRunのギャラリー画像を表示順で先頭に、Googleの写真をその後ろに並べる
 type PhotoInfo struct {
-	PhotoKey string `json:"photoKey"`
-	WidthPx  uint   `json:"widthPx"`
-	HeightPx uint   `json:"heightPx"`
+	PhotoKey      string `json:"photoKey"`
+	WidthPx       uint   `json:"widthPx,omitempty"`
+	HeightPx      uint   `json:"heightPx,omitempty"`
+	Source        string `json:"source"`
+	DogrunImageID int64  `json:"dogrunImageId,omitempty"` // WanRunのギャラリー画像のみ
+	Caption       string `json:"caption,omitempty"`       // WanRunのギャラリー画像のみ
 }
 
 // 軽度・緯度情報
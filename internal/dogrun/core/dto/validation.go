@@ -1,6 +1,15 @@
 package dto
 
-import "github.com/go-playground/validator/v10"
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	BUSINESS_TIME_FORMAT = "15:04:05"   // 営業時間の時刻フォーマット
+	SPECIAL_DATE_FORMAT  = "2006-01-02" // 特別営業日の日付フォーマット
+)
 
 // 経度カスタムバリデーション
 func VLatitude(fl validator.FieldLevel) bool {
@@ -13,3 +22,9 @@ func VLongitude(fl validator.FieldLevel) bool {
 	lon := fl.Field().Float()
 	return lon >= -180 && lon <= 180
 }
+
+// 営業時間(HH:mm:ss)カスタムバリデーション
+func VBusinessTime(fl validator.FieldLevel) bool {
+	_, err := time.Parse(BUSINESS_TIME_FORMAT, fl.Field().String())
+	return err == nil
+}
@@ -0,0 +1,387 @@
+package handler
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/adapters/googleplace"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+)
+
+const (
+	BOOKMARK_PLACE_FETCH_CONCURRENCY = 5  // ブックマーク一覧でgoogleのplace情報を並列で取得する数
+	SHARED_BOOKMARK_PAGE_SIZE        = 20 // 共有リンクのページサイズ未指定時の件数
+)
+
+// ブックマーク一覧の1件(ドッグランの解決前)
+type bookmarkEntry struct {
+	dogrunID int64
+	savedAt  time.Time
+	note     string
+}
+
+// GetBookmarkedDogruns: ログインユーザーのブックマーク済みドッグランを、検索と同じ情報で返す
+// コレクションIDを指定した場合は、コレクション内のドッグランのみ返す
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.BookmarkedDogrunsReq:	条件
+//
+// return:
+//   - dto.BookmarkedDogrunsRes:	ブックマーク済みドッグラン(ページング)
+//   - error:	エラー
+func (h *dogrunHandler) GetBookmarkedDogruns(c echo.Context, req dto.BookmarkedDogrunsReq) (dto.BookmarkedDogrunsRes, error) {
+	logger := log.GetLogger(c).Sugar()
+	logger.Debugw("ブックマーク一覧の条件", "req", req)
+
+	entries := []bookmarkEntry{}
+	if req.CollectionID != 0 {
+		collection, err := h.bf.GetUserBookmarkCollection(c, req.CollectionID)
+		if err != nil {
+			return dto.BookmarkedDogrunsRes{}, err
+		}
+		entries = toCollectionEntries(collection)
+	} else {
+		bookmarks, err := h.bf.GetUserBookmarks(c)
+		if err != nil {
+			return dto.BookmarkedDogrunsRes{}, err
+		}
+		for _, bookmark := range bookmarks {
+			entries = append(entries, bookmarkEntry{
+				dogrunID: bookmark.DogrunID.Int64,
+				savedAt:  bookmark.SavedAt.Time,
+			})
+		}
+	}
+
+	res, err := h.listBookmarkedDogruns(c, req.BookmarkListCondition, entries)
+	if err != nil {
+		return dto.BookmarkedDogrunsRes{}, err
+	}
+
+	//ログインユーザーのブックマークのみなので、全てブックマーク済み
+	for i := range res.Dogruns {
+		res.Dogruns[i].IsBookmarked = true
+	}
+	logger.Infof("ブックマーク件数:%d, レスポンス件数:%d", res.TotalCount, len(res.Dogruns))
+	return res, nil
+}
+
+// GetSharedBookmarkCollection: 共有リンクのトークンで、コレクション内のドッグランを検索と同じ情報で返す
+// ログインなしで閲覧できるため、閲覧者のブックマーク済みフラグはセットしない
+// google情報の取得をページ内に限定するため、登録日時順のみでページングする
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - string:	共有リンクのトークン
+//   - dto.SharedBookmarkCollectionReq:	条件
+//
+// return:
+//   - dto.SharedBookmarkCollectionRes:	コレクションとドッグラン(ページング)
+//   - error:	エラー
+func (h *dogrunHandler) GetSharedBookmarkCollection(c echo.Context, token string, req dto.SharedBookmarkCollectionReq) (dto.SharedBookmarkCollectionRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	if req.Sort != "" && req.Sort != dto.BOOKMARK_SORT_KEY_SAVED_AT {
+		err := errors.NewWRError(nil, "共有リンクは登録日時順のみ並び替えできます。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return dto.SharedBookmarkCollectionRes{}, err
+	}
+	if req.PageSize == 0 {
+		req.PageSize = SHARED_BOOKMARK_PAGE_SIZE
+	}
+
+	collection, err := h.bf.GetSharedBookmarkCollection(c, token)
+	if err != nil {
+		return dto.SharedBookmarkCollectionRes{}, err
+	}
+
+	res, err := h.listBookmarkedDogruns(c, req.BookmarkListCondition, toCollectionEntries(collection))
+	if err != nil {
+		return dto.SharedBookmarkCollectionRes{}, err
+	}
+
+	return dto.SharedBookmarkCollectionRes{
+		Name:                 collection.Name.String,
+		Note:                 collection.Note.String,
+		BookmarkedDogrunsRes: res,
+	}, nil
+}
+
+// listBookmarkedDogruns: ブックマークを並び替えてページングし、ドッグラン情報を解決する
+// 登録日時順の場合は、ページングしてからそのページのみ解決する
+// それ以外はgoogleの情報が必要なため、全件を解決してから並び替える
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.BookmarkListCondition:	並び替えとページングの条件
+//   - []bookmarkEntry:	ブックマーク
+//
+// return:
+//   - dto.BookmarkedDogrunsRes:	ブックマーク済みドッグラン(ページング)
+//   - error:	エラー
+func (h *dogrunHandler) listBookmarkedDogruns(c echo.Context, condition dto.BookmarkListCondition, entries []bookmarkEntry) (dto.BookmarkedDogrunsRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	offset, err := util.DecodeOffsetCursor(condition.Cursor)
+	if err != nil {
+		err = errors.NewWRError(err, "カーソルが不正です", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return dto.BookmarkedDogrunsRes{}, err
+	}
+
+	key := condition.Sort
+	if key == "" {
+		key = dto.BOOKMARK_SORT_KEY_SAVED_AT
+	}
+	desc := isBookmarkSortDesc(key, condition.Order)
+
+	var dogruns []dto.BookmarkedDogrun
+	var nextCursor string
+	if key == dto.BOOKMARK_SORT_KEY_SAVED_AT {
+		sort.SliceStable(entries, func(i, j int) bool {
+			a, b := entries[i], entries[j]
+			if !a.savedAt.Equal(b.savedAt) {
+				if desc {
+					return a.savedAt.After(b.savedAt)
+				}
+				return a.savedAt.Before(b.savedAt)
+			}
+			return a.dogrunID < b.dogrunID
+		})
+		var page []bookmarkEntry
+		page, nextCursor = paginateSlice(entries, offset, condition.PageSize)
+		if dogruns, err = h.resolveBookmarkedDogruns(c, page); err != nil {
+			return dto.BookmarkedDogrunsRes{}, err
+		}
+		setBookmarkDistances(condition, dogruns)
+	} else {
+		all, err := h.resolveBookmarkedDogruns(c, entries)
+		if err != nil {
+			return dto.BookmarkedDogrunsRes{}, err
+		}
+		setBookmarkDistances(condition, all)
+		sortBookmarkedDogruns(key, desc, all)
+		dogruns, nextCursor = paginateSlice(all, offset, condition.PageSize)
+	}
+
+	//混雑状況の付与
+	if err := h.setBookmarkOccupancies(c, dogruns); err != nil {
+		return dto.BookmarkedDogrunsRes{}, err
+	}
+
+	return dto.BookmarkedDogrunsRes{
+		Dogruns:    dogruns,
+		TotalCount: len(entries),
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// resolveBookmarkedDogruns: ブックマークのドッグランを、google情報とDB情報から検索と同じ情報に解決する
+// google情報の取得に失敗した場合は、DB情報のみで解決する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []bookmarkEntry:	ブックマーク
+//
+// return:
+//   - []dto.BookmarkedDogrun:	ブックマーク済みドッグラン(ブックマークの順)
+//   - error:	エラー
+func (h *dogrunHandler) resolveBookmarkedDogruns(c echo.Context, entries []bookmarkEntry) ([]dto.BookmarkedDogrun, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunIDs := []int64{}
+	for _, entry := range entries {
+		dogrunIDs = append(dogrunIDs, entry.dogrunID)
+	}
+	dogrunsD, err := h.drr.GetDogrunsByIDs(c, dogrunIDs)
+	if err != nil {
+		return nil, err
+	}
+	dogrunDMap := util.ConvertSliceToMap(dogrunsD, func(d model.Dogrun) int64 { return d.DogrunID.Int64 })
+
+	dogrunGMap := h.getPlaceInfos(c, dogrunsD)
+
+	resolvedEntries := []bookmarkEntry{}
+	dogrunLists := []dto.DogrunLists{}
+	for _, entry := range entries {
+		dogrunD, exist := dogrunDMap[entry.dogrunID]
+		if !exist {
+			logger.Warnf("ブックマーク済みのドッグランが存在しません. dogrunID: %d", entry.dogrunID)
+			continue
+		}
+
+		var dogrunList dto.DogrunLists
+		if dogrunG, exist := dogrunGMap[dogrunD.PlaceId.String]; exist {
+			dogrunList = resolveDogrunList(dogrunG, dogrunD)
+		} else {
+			dogrunList = resolveDogrunListByOnlyDB(dogrunD)
+			dogrunList.PlaceId = dogrunD.PlaceId.String
+		}
+		resolvedEntries = append(resolvedEntries, entry)
+		dogrunLists = append(dogrunLists, dogrunList)
+	}
+
+	//WanRunの評価の付与(並び替えに使用するため解決時にセット)
+	if err := h.setWanrunRatings(c, dogrunLists); err != nil {
+		return nil, err
+	}
+
+	//ギャラリー画像のマージ
+	if err := h.setDogrunImages(c, dogrunLists); err != nil {
+		return nil, err
+	}
+
+	dogruns := []dto.BookmarkedDogrun{}
+	for i, entry := range resolvedEntries {
+		dogruns = append(dogruns, dto.BookmarkedDogrun{
+			DogrunLists: dogrunLists[i],
+			SavedAt:     entry.savedAt,
+			Note:        entry.note,
+		})
+	}
+	return dogruns, nil
+}
+
+// getPlaceInfos: placeIdがあるドッグランのgoogleのplace情報を並列で取得する
+// 取得に失敗したplaceは結果に含めない
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []model.Dogrun:	ドッグラン
+//
+// return:
+//   - map[string]googleplace.BaseResource:	placeIdごとのplace情報
+func (h *dogrunHandler) getPlaceInfos(c echo.Context, dogrunsD []model.Dogrun) map[string]googleplace.BaseResource {
+	logger := log.GetLogger(c).Sugar()
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, BOOKMARK_PLACE_FETCH_CONCURRENCY)
+	)
+	dogrunGMap := make(map[string]googleplace.BaseResource)
+	for _, dogrunD := range dogrunsD {
+		if !dogrunD.PlaceId.Valid || dogrunD.PlaceId.String == "" {
+			continue
+		}
+		placeID := dogrunD.PlaceId.String
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			dogrunG, err := h.getPlaceInfo(c, placeID)
+			if err != nil {
+				logger.Warnf("place情報の取得に失敗したため、DB情報のみで表示します. placeID: %s, err: %v", placeID, err)
+				return
+			}
+			mu.Lock()
+			dogrunGMap[placeID] = dogrunG
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return dogrunGMap
+}
+
+// setBookmarkOccupancies: ブックマーク済みドッグランに混雑状況をセットする
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []dto.BookmarkedDogrun:	dogruns
+//
+// return:
+//   - error:	エラー
+func (h *dogrunHandler) setBookmarkOccupancies(c echo.Context, dogruns []dto.BookmarkedDogrun) error {
+	dogrunLists := make([]dto.DogrunLists, len(dogruns))
+	for i := range dogruns {
+		dogrunLists[i] = dogruns[i].DogrunLists
+	}
+	if err := h.setOccupancies(c, dogrunLists); err != nil {
+		return err
+	}
+	for i := range dogruns {
+		dogruns[i].Occupancy = dogrunLists[i].Occupancy
+	}
+	return nil
+}
+
+/*
+コレクション内のドッグランをブックマーク一覧の形式に変換
+登録日時はコレクションへの追加日時
+*/
+func toCollectionEntries(collection model.BookmarkCollection) []bookmarkEntry {
+	entries := []bookmarkEntry{}
+	for _, item := range collection.Items {
+		entries = append(entries, bookmarkEntry{
+			dogrunID: item.DogrunID.Int64,
+			savedAt:  item.AddedAt.Time,
+			note:     item.Note.String,
+		})
+	}
+	return entries
+}
+
+/*
+基準点が指定されている場合、基準点からの距離をセットする
+*/
+func setBookmarkDistances(condition dto.BookmarkListCondition, dogruns []dto.BookmarkedDogrun) {
+	if !condition.HasReference() {
+		return
+	}
+	for i := range dogruns {
+		distance := math.Round(util.CalcDistanceMeter(
+			condition.Latitude, condition.Longitude,
+			dogruns[i].Location.Latitude, dogruns[i].Location.Longitude,
+		))
+		dogruns[i].Distance = &distance
+	}
+}
+
+// sortBookmarkedDogruns: ブックマーク済みドッグランを条件のキーで並び替える
+// キーが同じ場合は、登録日時の新しい順, dogrunIDの順で並べ、結果を一意にする
+//
+// args:
+//   - string:	並び替えキー(登録日時以外)
+//   - bool:	降順か
+//   - []dto.BookmarkedDogrun:	距離をセット済みのドッグラン
+//
+// return:
+func sortBookmarkedDogruns(key string, desc bool, dogruns []dto.BookmarkedDogrun) {
+	sort.SliceStable(dogruns, func(i, j int) bool {
+		a, b := dogruns[i], dogruns[j]
+		if cmp := compareBySortKey(key, a.DogrunLists, b.DogrunLists); cmp != 0 {
+			if desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		// タイブレーク
+		if !a.SavedAt.Equal(b.SavedAt) {
+			return a.SavedAt.After(b.SavedAt)
+		}
+		return a.DogrunID < b.DogrunID
+	})
+}
+
+/*
+ブックマーク一覧の並び順が降順か
+未指定の場合、登録日時・評価・評価数は降順、それ以外は昇順
+*/
+func isBookmarkSortDesc(key, order string) bool {
+	if order == "" && key == dto.BOOKMARK_SORT_KEY_SAVED_AT {
+		return true
+	}
+	return isSortDesc(key, order)
+}
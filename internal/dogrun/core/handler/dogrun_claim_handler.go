@@ -0,0 +1,384 @@
+package handler
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/dto"
+	dogrunmgFacade "github.com/wanrun-develop/wanrun/internal/dogrunmg/core/facade"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/internal/transaction"
+	"github.com/wanrun-develop/wanrun/internal/wrcontext"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+	"gorm.io/gorm"
+)
+
+type IDogrunClaimHandler interface {
+	SubmitClaim(echo.Context, dto.DogrunClaimReq) (int64, error)
+	GetOrgClaims(echo.Context) ([]dto.DogrunClaimRes, error)
+	GetPendingClaims(echo.Context) ([]dto.DogrunClaimRes, error)
+	ApproveClaim(echo.Context, int64, dto.DogrunClaimReviewReq) error
+	RejectClaim(echo.Context, int64, dto.DogrunClaimReviewReq) error
+}
+
+type dogrunClaimHandler struct {
+	drr  repository.IDogrunRepository
+	drsr repository.IDogrunScopeRepository
+	dcr  repository.IDogrunClaimRepository
+	dcsr repository.IDogrunClaimScopeRepository
+	tm   transaction.ITransactionManager
+	dmf  dogrunmgFacade.IDogrunmgFacade
+}
+
+func NewDogrunClaimHandler(
+	drr repository.IDogrunRepository,
+	drsr repository.IDogrunScopeRepository,
+	dcr repository.IDogrunClaimRepository,
+	dcsr repository.IDogrunClaimScopeRepository,
+	tm transaction.ITransactionManager,
+	dmf dogrunmgFacade.IDogrunmgFacade,
+) IDogrunClaimHandler {
+	return &dogrunClaimHandler{
+		drr:  drr,
+		drsr: drsr,
+		dcr:  dcr,
+		dcsr: dcsr,
+		tm:   tm,
+		dmf:  dmf,
+	}
+}
+
+// SubmitClaim: ドッグランの管理者としてのクレーム申請
+// placeIdが指定され、DBに存在しない場合はクレームと同じトランザクションでドッグランのレコードを作成する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.DogrunClaimReq:	リクエスト内容
+//
+// return:
+//   - int64:	作成したdogrunClaimID
+//   - error:	エラー
+func (h *dogrunClaimHandler) SubmitClaim(c echo.Context, reqBody dto.DogrunClaimReq) (int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dmID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return 0, err
+	}
+	role, err := wrcontext.GetLoginUserRole(c)
+	if err != nil {
+		return 0, err
+	}
+	orgID, err := h.dmf.GetOrganizationID(c, dmID)
+	if err != nil {
+		return 0, err
+	}
+
+	dogrun, err := h.resolveClaimTargetDogrun(c, reqBody)
+	if err != nil {
+		return 0, err
+	}
+	if dogrun.DogrunManagerID.Valid {
+		err := errors.NewWRError(nil, "ドッグランには既に管理者が設定されています。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return 0, err
+	}
+
+	if dogrun.DogrunID.Valid {
+		pending, err := h.dcr.GetPendingClaimByDogrunID(c, dogrun.DogrunID.Int64)
+		if err != nil {
+			return 0, err
+		}
+		if !pending.IsEmpty() {
+			err := errors.NewWRError(nil, "ドッグランには審査中のクレームが存在します。", errors.NewDogrunClientErrorEType())
+			logger.Error(err)
+			return 0, err
+		}
+	}
+
+	claim := model.DogrunClaim{
+		DogrunID:       dogrun.DogrunID,
+		DogrunmgID:     util.NewSqlNullInt64(dmID),
+		OrganizationID: util.NewSqlNullInt64(orgID),
+		Evidence:       util.NewSqlNullString(reqBody.Evidence),
+		EvidenceURL:    util.NewSqlNullString(reqBody.EvidenceURL),
+		Status:         util.NewSqlNullInt64(model.DOGRUN_CLAIM_STATUS_PENDING),
+	}
+
+	ctx := c.Request().Context()
+	if err := h.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
+		// クレームの登録に失敗した場合にドッグランだけが残らないよう、同じトランザクションで作成する
+		if !dogrun.DogrunID.Valid {
+			logger.Infof("placeId\"%s\"がDBに存在しないため、レコードを作成", dogrun.PlaceId.String)
+			if err := h.drsr.CreateDogrunPlaceId(tx, c, &dogrun); err != nil {
+				return err
+			}
+			claim.DogrunID = dogrun.DogrunID
+		}
+		if err := h.dcsr.CreateClaim(tx, c, &claim); err != nil {
+			return err
+		}
+		history := newDogrunClaimHistory(claim.DogrunClaimID.Int64, model.DOGRUN_CLAIM_STATUS_PENDING, dmID, true, role, "")
+		return h.dcsr.CreateClaimHistory(tx, c, &history)
+	}); err != nil {
+		logger.Error("Transaction failed:", err)
+		return 0, err
+	}
+
+	logger.Infof("dogrun %d へのクレーム %d を申請", dogrun.DogrunID.Int64, claim.DogrunClaimID.Int64)
+	return claim.DogrunClaimID.Int64, nil
+}
+
+// GetOrgClaims: ログインユーザーのorganizationが申請したクレームの一覧取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - []dto.DogrunClaimRes:	クレーム一覧
+//   - error:	エラー
+func (h *dogrunClaimHandler) GetOrgClaims(c echo.Context) ([]dto.DogrunClaimRes, error) {
+	dmID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return nil, err
+	}
+	orgID, err := h.dmf.GetOrganizationID(c, dmID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := h.dcr.FindClaimsByOrganizationID(c, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return resolveDogrunClaims(claims), nil
+}
+
+// GetPendingClaims: 審査待ちのクレームの一覧取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - []dto.DogrunClaimRes:	クレーム一覧
+//   - error:	エラー
+func (h *dogrunClaimHandler) GetPendingClaims(c echo.Context) ([]dto.DogrunClaimRes, error) {
+	claims, err := h.dcr.FindClaimsByStatus(c, model.DOGRUN_CLAIM_STATUS_PENDING)
+	if err != nil {
+		return nil, err
+	}
+	return resolveDogrunClaims(claims), nil
+}
+
+// ApproveClaim: クレームの承認
+// 承認時にドッグランへ申請者を管理者として紐付け、管理対象にする
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunClaimID
+//   - dto.DogrunClaimReviewReq:	リクエスト内容
+//
+// return:
+//   - error:	エラー
+func (h *dogrunClaimHandler) ApproveClaim(c echo.Context, claimID int64, reqBody dto.DogrunClaimReviewReq) error {
+	logger := log.GetLogger(c).Sugar()
+
+	claim, err := h.findPendingClaim(c, claimID)
+	if err != nil {
+		return err
+	}
+	history, err := newReviewHistory(c, claimID, model.DOGRUN_CLAIM_STATUS_APPROVED, reqBody.Comment)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
+		if err := h.dcsr.UpdateClaimStatus(tx, c, claimID, model.DOGRUN_CLAIM_STATUS_PENDING, model.DOGRUN_CLAIM_STATUS_APPROVED); err != nil {
+			return err
+		}
+		if err := h.drsr.LinkDogrunManager(tx, c, claim.DogrunID.Int64, claim.DogrunmgID.Int64); err != nil {
+			return err
+		}
+		return h.dcsr.CreateClaimHistory(tx, c, &history)
+	}); err != nil {
+		logger.Error("Transaction failed:", err)
+		return err
+	}
+
+	logger.Infof("クレーム %d を承認。dogrun %d の管理者を %d に設定", claimID, claim.DogrunID.Int64, claim.DogrunmgID.Int64)
+	return nil
+}
+
+// RejectClaim: クレームの却下
+// 却下理由のコメントは必須
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunClaimID
+//   - dto.DogrunClaimReviewReq:	リクエスト内容
+//
+// return:
+//   - error:	エラー
+func (h *dogrunClaimHandler) RejectClaim(c echo.Context, claimID int64, reqBody dto.DogrunClaimReviewReq) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if reqBody.Comment == "" {
+		err := errors.NewWRError(nil, "却下理由を入力してください。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	if _, err := h.findPendingClaim(c, claimID); err != nil {
+		return err
+	}
+	history, err := newReviewHistory(c, claimID, model.DOGRUN_CLAIM_STATUS_REJECTED, reqBody.Comment)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
+		if err := h.dcsr.UpdateClaimStatus(tx, c, claimID, model.DOGRUN_CLAIM_STATUS_PENDING, model.DOGRUN_CLAIM_STATUS_REJECTED); err != nil {
+			return err
+		}
+		return h.dcsr.CreateClaimHistory(tx, c, &history)
+	}); err != nil {
+		logger.Error("Transaction failed:", err)
+		return err
+	}
+
+	logger.Infof("クレーム %d を却下", claimID)
+	return nil
+}
+
+// resolveClaimTargetDogrun: クレーム対象のドッグランの取得
+// placeIdが指定され、DBに存在しない場合はplaceIdのみの未登録のドッグラン(dogrunIDなし)を返す
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.DogrunClaimReq:	リクエスト内容
+//
+// return:
+//   - model.Dogrun:	対象のドッグラン。未登録の場合はdogrunIDが空
+//   - error:	エラー
+func (h *dogrunClaimHandler) resolveClaimTargetDogrun(c echo.Context, reqBody dto.DogrunClaimReq) (model.Dogrun, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	if reqBody.DogrunID != 0 {
+		dogrun, err := h.drr.FindDogrunByID(c, reqBody.DogrunID)
+		if err != nil {
+			return model.Dogrun{}, err
+		}
+		if dogrun.IsEmpty() {
+			err := errors.NewWRError(nil, "指定されたドッグランは存在しません。", errors.NewDogrunClientErrorEType())
+			logger.Error(err)
+			return model.Dogrun{}, err
+		}
+		return dogrun, nil
+	}
+
+	dogrun, err := h.drr.GetDogrunByPlaceID(c, reqBody.PlaceID)
+	if err != nil {
+		return model.Dogrun{}, err
+	}
+	if !dogrun.IsEmpty() {
+		return dogrun, nil
+	}
+
+	return model.Dogrun{PlaceId: util.NewSqlNullString(reqBody.PlaceID)}, nil
+}
+
+// findPendingClaim: 審査待ちのクレームの取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunClaimID
+//
+// return:
+//   - model.DogrunClaim:	クレーム
+//   - error:	エラー
+func (h *dogrunClaimHandler) findPendingClaim(c echo.Context, claimID int64) (model.DogrunClaim, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	claim, err := h.dcr.GetClaimByID(c, claimID)
+	if err != nil {
+		return model.DogrunClaim{}, err
+	}
+	if claim.IsEmpty() {
+		err := errors.NewWRError(nil, "指定されたクレームは存在しません。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return model.DogrunClaim{}, err
+	}
+	if !claim.IsPending() {
+		err := errors.NewWRError(nil, "クレームは既に審査済みです。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return model.DogrunClaim{}, err
+	}
+	return claim, nil
+}
+
+/*
+審査者のステータス履歴の作成
+システムユーザーのIDは数値とは限らないため、数値で無い場合は変更者を空とする
+*/
+func newReviewHistory(c echo.Context, claimID int64, status int64, comment string) (model.DogrunClaimHistory, error) {
+	claims, err := wrcontext.GetVerifiedClaims(c)
+	if err != nil {
+		return model.DogrunClaimHistory{}, err
+	}
+	reviewerID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	return newDogrunClaimHistory(claimID, status, reviewerID, err == nil, claims.Role, comment), nil
+}
+
+/*
+ステータス履歴のモデル作成
+SYSTEMロールは0のため、ロールは常に有効値として扱う
+*/
+func newDogrunClaimHistory(claimID int64, status int64, changedBy int64, hasChangedBy bool, role int, comment string) model.DogrunClaimHistory {
+	return model.DogrunClaimHistory{
+		DogrunClaimID: util.NewSqlNullInt64(claimID),
+		Status:        util.NewSqlNullInt64(status),
+		ChangedBy:     sql.NullInt64{Int64: changedBy, Valid: hasChangedBy},
+		ChangedByRole: sql.NullInt64{Int64: int64(role), Valid: true},
+		Comment:       util.NewSqlNullString(comment),
+	}
+}
+
+/*
+クレームのレスポンスへの変換
+*/
+func resolveDogrunClaims(claims []model.DogrunClaim) []dto.DogrunClaimRes {
+	res := make([]dto.DogrunClaimRes, 0, len(claims))
+	for _, claim := range claims {
+		histories := make([]dto.DogrunClaimHistoryRes, 0, len(claim.Histories))
+		for _, history := range claim.Histories {
+			histories = append(histories, dto.DogrunClaimHistoryRes{
+				Status:        history.Status.Int64,
+				ChangedBy:     history.ChangedBy.Int64,
+				ChangedByRole: history.ChangedByRole.Int64,
+				Comment:       history.Comment.String,
+				CreateAt:      &history.CreateAt.Time,
+			})
+		}
+		res = append(res, dto.DogrunClaimRes{
+			DogrunClaimID:  claim.DogrunClaimID.Int64,
+			DogrunID:       claim.DogrunID.Int64,
+			PlaceId:        claim.Dogrun.PlaceId.String,
+			DogrunName:     claim.Dogrun.Name.String,
+			DogrunmgID:     claim.DogrunmgID.Int64,
+			OrganizationID: claim.OrganizationID.Int64,
+			Evidence:       claim.Evidence.String,
+			EvidenceURL:    claim.EvidenceURL.String,
+			Status:         claim.Status.Int64,
+			Histories:      histories,
+			CreateAt:       &claim.CreateAt.Time,
+			UpdateAt:       &claim.UpdateAt.Time,
+		})
+	}
+	return res
+}
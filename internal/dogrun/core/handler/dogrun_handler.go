@@ -3,7 +3,9 @@ package handler
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"slices"
+	"sort"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -25,20 +27,27 @@ type IDogrunHandler interface {
 	GetDogrunDetail(echo.Context, string) (dto.DogrunDetail, error)
 	GetDogrunByID(string)
 	GetDogrunTagMst(echo.Context) ([]dto.TagMstRes, error)
-	SearchAroundDogruns(echo.Context, dto.SearchAroundRectangleCondition) ([]dto.DogrunLists, error)
-	SearchAroundAndTagDogruns(echo.Context, dto.SearchAroundRectangleCondition) ([]dto.DogrunLists, error)
+	SearchAroundDogruns(echo.Context, dto.SearchAroundRectangleCondition) (dto.DogrunSearchRes, error)
+	SearchNearbyDogruns(echo.Context, dto.SearchAroundCircleCondition) ([]dto.DogrunLists, error)
 	getBookmarkedDogrunIDs(echo.Context, chan<- []int64)
 	GetDogrunPhotoSrc(echo.Context, string, string, string) (string, error)
+	GetDogrunOccupancy(echo.Context, int64) (dto.OccupancyRes, error)
+	GetDogrunOccupancies(echo.Context, dto.OccupancyReq) ([]dto.OccupancyRes, error)
+	GetBookmarkedDogruns(echo.Context, dto.BookmarkedDogrunsReq) (dto.BookmarkedDogrunsRes, error)
+	GetSharedBookmarkCollection(echo.Context, string, dto.SharedBookmarkCollectionReq) (dto.SharedBookmarkCollectionRes, error)
 }
 
 type dogrunHandler struct {
 	rest googleplace.IRest
 	drr  repository.IDogrunRepository
 	bf   facade.IBookmarkFacade
+	vf   facade.IVisitFacade
+	rf   facade.IReviewFacade
+	dir  repository.IDogrunImageRepository
 }
 
-func NewDogrunHandler(rest googleplace.IRest, drr repository.IDogrunRepository, bf facade.IBookmarkFacade) IDogrunHandler {
-	return &dogrunHandler{rest, drr, bf}
+func NewDogrunHandler(rest googleplace.IRest, drr repository.IDogrunRepository, bf facade.IBookmarkFacade, vf facade.IVisitFacade, rf facade.IReviewFacade, dir repository.IDogrunImageRepository) IDogrunHandler {
+	return &dogrunHandler{rest, drr, bf, vf, rf, dir}
 }
 
 // GetDogRunDetailByPlaceId: placeIdでgoogle検索して返す
@@ -51,13 +60,57 @@ func NewDogrunHandler(rest googleplace.IRest, drr repository.IDogrunRepository,
 //   - dto.DogrunDetail:	詳細DTO
 //   - error:	エラー
 func (h *dogrunHandler) GetDogrunDetail(c echo.Context, placeID string) (dto.DogrunDetail, error) {
+	//place情報の取得
+	dogrunG, err := h.getPlaceInfo(c, placeID)
+	if err != nil {
+		return dto.DogrunDetail{}, err
+	}
+
+	//dbから取得
+	dogrunD, err := h.drr.GetDogrunByPlaceID(c, placeID)
+	if err != nil {
+		return dto.DogrunDetail{}, err
+	}
+
+	//情報選定
+	resDogDetail := resolveDogrunDetail(dogrunG, dogrunD)
+
+	//混雑状況とWanRunの評価、ギャラリー画像はDBに登録済みのドッグランのみ
+	if !dogrunD.IsEmpty() {
+		occupancies, err := h.getOccupancies(c, []int64{dogrunD.DogrunID.Int64})
+		if err != nil {
+			return dto.DogrunDetail{}, err
+		}
+		resDogDetail.Occupancy = occupancies[dogrunD.DogrunID.Int64]
+
+		if err := h.setWanrunRatingDetail(c, &resDogDetail); err != nil {
+			return dto.DogrunDetail{}, err
+		}
+
+		if err := h.setDogrunImageDetail(c, &resDogDetail); err != nil {
+			return dto.DogrunDetail{}, err
+		}
+	}
+	return resDogDetail, nil
+}
+
+// getPlaceInfo: placeIdでgoogleのplace情報を取得する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - string:	placeID
+//
+// return:
+//   - googleplace.BaseResource:	place情報
+//   - error:	エラー。placeIdのデータが存在しない場合はクライアントエラー
+func (h *dogrunHandler) getPlaceInfo(c echo.Context, placeID string) (googleplace.BaseResource, error) {
 	logger := log.GetLogger(c).Sugar()
 	//base情報のFieldを使用
 	var baseFiled googleplace.IFieldMask = googleplace.BaseField{}
 	//place情報の取得
 	resG, err := h.rest.GETPlaceInfo(c, placeID, baseFiled)
 	if err != nil {
-		return dto.DogrunDetail{}, err
+		return googleplace.BaseResource{}, err
 	}
 	logger.Info("Google Place APIによって、ドッグラン情報の取得成功")
 
@@ -67,22 +120,13 @@ func (h *dogrunHandler) GetDogrunDetail(c echo.Context, placeID string) (dto.Dog
 	if err != nil {
 		err = errors.NewWRError(nil, "google apiレスポンスの変換に失敗しました。", errors.NewDogrunServerErrorEType())
 		logger.Error(err)
-		return dto.DogrunDetail{}, err
+		return googleplace.BaseResource{}, err
 	}
 
 	if dogrunG.ID == "" {
-		return dto.DogrunDetail{}, errors.NewWRError(nil, "指定されたPlaceIdのデータが存在しません。", errors.NewDogrunClientErrorEType())
-	}
-
-	//dbから取得
-	dogrunD, err := h.drr.GetDogrunByPlaceID(c, placeID)
-	if err != nil {
-		return dto.DogrunDetail{}, err
+		return googleplace.BaseResource{}, errors.NewWRError(nil, "指定されたPlaceIdのデータが存在しません。", errors.NewDogrunClientErrorEType())
 	}
-
-	//情報選定
-	resDogDetail := resolveDogrunDetail(dogrunG, dogrunD)
-	return resDogDetail, nil
+	return dogrunG, nil
 }
 
 func (h *dogrunHandler) GetDogrunByID(id string) {
@@ -118,6 +162,7 @@ func (h *dogrunHandler) GetDogrunTagMst(c echo.Context) ([]dto.TagMstRes, error)
 }
 
 // SearchAroundDogruns: 指定内（長方形）のドッグランをgoogle検索して、DB情報と照合して返す
+// 絞り込み条件は、google, DBのどちらの結果にも統合後に適用する
 //
 // args:
 //   - echo.Context:	コンテキスト
@@ -126,14 +171,21 @@ func (h *dogrunHandler) GetDogrunTagMst(c echo.Context) ([]dto.TagMstRes, error)
 // return:
 //   - []dto.DogrunLists:	リストDTO
 //   - error:	エラー
-func (h *dogrunHandler) SearchAroundDogruns(c echo.Context, condition dto.SearchAroundRectangleCondition) ([]dto.DogrunLists, error) {
+func (h *dogrunHandler) SearchAroundDogruns(c echo.Context, condition dto.SearchAroundRectangleCondition) (dto.DogrunSearchRes, error) {
 	logger := log.GetLogger(c).Sugar()
 	logger.Debugw("検索条件", "condition", condition)
 
 	payload := googleplace.ConvertReqToSearchTextPayload(condition)
 
+	//絞り込み条件
+	matcher, err := newSearchFilterMatcher(c, condition.ResolveFilter())
+	if err != nil {
+		return dto.DogrunSearchRes{}, err
+	}
+
 	//ブックマーク済みを並列で取得
-	bookmarkedDogrunIDsCH := make(chan []int64)
+	//途中でエラーを返し受信しない場合もgoroutineが終了できるよう、バッファを持たせる
+	bookmarkedDogrunIDsCH := make(chan []int64, 1)
 	go h.getBookmarkedDogrunIDs(c, bookmarkedDogrunIDsCH)
 
 	//base情報のFieldを使用
@@ -142,7 +194,7 @@ func (h *dogrunHandler) SearchAroundDogruns(c echo.Context, condition dto.Search
 	//place情報の取得
 	dogrunsG, err := h.searchTextUpToSpecifiedTimes(c, payload, baseFiled)
 	if err != nil {
-		return nil, err
+		return dto.DogrunSearchRes{}, err
 	}
 	logger.Infof("googleレスポンスplace数:%d", len(dogrunsG))
 	dogrunGPlaceIDs := []string{}
@@ -153,7 +205,7 @@ func (h *dogrunHandler) SearchAroundDogruns(c echo.Context, condition dto.Search
 	//DBにある指定場所内のドッグランを取得
 	dogrunsD, err := h.drr.GetDogrunByRectanglePointerOrPlaceId(c, condition, dogrunGPlaceIDs)
 	if err != nil {
-		return nil, err
+		return dto.DogrunSearchRes{}, err
 	}
 	logger.Infof("DBから取得数:%d", len(dogrunsD))
 
@@ -161,47 +213,85 @@ func (h *dogrunHandler) SearchAroundDogruns(c echo.Context, condition dto.Search
 	dogrunsD = excludeInsufficientDogrunInfo(c, dogrunsD)
 
 	//検索結果からレスポンスを作成
-	dogrunLists, err := h.integrateDogrunInfos(dogrunsG, dogrunsD)
-	logger.Infof("レスポンス件数:%d", len(dogrunLists))
+	dogrunLists, err := h.integrateDogrunInfos(dogrunsG, dogrunsD, matcher)
+	logger.Infof("検索件数:%d", len(dogrunLists))
 	if err != nil {
-		return nil, err
+		return dto.DogrunSearchRes{}, err
 	}
 
 	//dogrunIDがないデータのメンテしてセット
 	if err = h.GenerateSetDogrunIDs(c, dogrunLists); err != nil {
-		return nil, err
+		return dto.DogrunSearchRes{}, err
 	}
 
+	//WanRunの評価の付与(並び替えに使用するため全件)
+	if err = h.setWanrunRatings(c, dogrunLists); err != nil {
+		return dto.DogrunSearchRes{}, err
+	}
+
+	//並び替えとページング
+	sortDogrunLists(condition, dogrunLists)
+	res, err := paginateDogrunLists(c, condition, dogrunLists)
+	if err != nil {
+		return dto.DogrunSearchRes{}, err
+	}
+	logger.Infof("レスポンス件数:%d", len(res.Dogruns))
+
 	//ブックマーク済みdogrunにフラグ付与
-	dogrunLists, err = setIsBookmarked(c, dogrunLists, bookmarkedDogrunIDsCH)
+	res.Dogruns, err = setIsBookmarked(c, res.Dogruns, bookmarkedDogrunIDsCH)
 	if err != nil {
-		return nil, err
+		return dto.DogrunSearchRes{}, err
 	}
 
-	return dogrunLists, nil
+	//混雑状況の付与
+	if err = h.setOccupancies(c, res.Dogruns); err != nil {
+		return dto.DogrunSearchRes{}, err
+	}
+
+	//ギャラリー画像のマージ
+	if err = h.setDogrunImages(c, res.Dogruns); err != nil {
+		return dto.DogrunSearchRes{}, err
+	}
+
+	return res, nil
 }
 
-// SearchAroundAndTagDogruns: 指定内（長方形）とドッグランタグ条件でDB検索して、返す
-// google検索は行わない
+// SearchNearbyDogruns: 指定内（円）のドッグランをgoogle検索して、DB情報と照合して中心からの距離順で返す
 //
 // args:
 //   - echo.Context:	コンテキスト
-//   - dto.SearchAroundRectangleCondition:	条件
+//   - dto.SearchAroundCircleCondition:	条件
 //
 // return:
 //   - []dto.DogrunLists:	リストDTO
 //   - error:	エラー
-func (h *dogrunHandler) SearchAroundAndTagDogruns(c echo.Context, condition dto.SearchAroundRectangleCondition) ([]dto.DogrunLists, error) {
-
+func (h *dogrunHandler) SearchNearbyDogruns(c echo.Context, condition dto.SearchAroundCircleCondition) ([]dto.DogrunLists, error) {
 	logger := log.GetLogger(c).Sugar()
 	logger.Debugw("検索条件", "condition", condition)
 
+	payload := googleplace.ConvertReqToSearchNearbyPayload(condition)
+
 	//ブックマーク済みを並列で取得
-	bookmarkedDogrunIDsCH := make(chan []int64)
+	//途中でエラーを返し受信しない場合もgoroutineが終了できるよう、バッファを持たせる
+	bookmarkedDogrunIDsCH := make(chan []int64, 1)
 	go h.getBookmarkedDogrunIDs(c, bookmarkedDogrunIDsCH)
 
+	//base情報のFieldを使用
+	var baseFiled googleplace.IFieldMask = googleplace.BaseField{}
+
+	//place情報の取得
+	dogrunsG, err := h.searchNearby(c, payload, baseFiled)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("googleレスポンスplace数:%d", len(dogrunsG))
+	dogrunGPlaceIDs := []string{}
+	for _, dogrunG := range dogrunsG {
+		dogrunGPlaceIDs = append(dogrunGPlaceIDs, dogrunG.ID)
+	}
+
 	//DBにある指定場所内のドッグランを取得
-	dogrunsD, err := h.drr.GetDogrunByRectanglePointerAndDogrunTags(c, condition)
+	dogrunsD, err := h.drr.GetDogrunByCirclePointerOrPlaceId(c, condition, dogrunGPlaceIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -210,19 +300,42 @@ func (h *dogrunHandler) SearchAroundAndTagDogruns(c echo.Context, condition dto.
 	//ドッグラン情報の過不足フィルター
 	dogrunsD = excludeInsufficientDogrunInfo(c, dogrunsD)
 
-	dogrunLists := []dto.DogrunLists{}
-
-	for _, dogrun := range dogrunsD {
-		dogrunLists = append(dogrunLists, resolveDogrunListByOnlyDB(dogrun))
+	//検索結果からレスポンスを作成
+	dogrunLists, err := h.integrateDogrunInfos(dogrunsG, dogrunsD, searchFilterMatcher{})
+	if err != nil {
+		return nil, err
 	}
+
+	//円の外のドッグランを除外し、距離順に並べる
+	dogrunLists = filterAndSortByDistance(condition, dogrunLists)
 	logger.Infof("レスポンス件数:%d", len(dogrunLists))
 
+	//dogrunIDがないデータのメンテしてセット
+	if err = h.GenerateSetDogrunIDs(c, dogrunLists); err != nil {
+		return nil, err
+	}
+
 	//ブックマーク済みdogrunにフラグ付与
 	dogrunLists, err = setIsBookmarked(c, dogrunLists, bookmarkedDogrunIDsCH)
 	if err != nil {
 		return nil, err
 	}
 
+	//混雑状況の付与
+	if err = h.setOccupancies(c, dogrunLists); err != nil {
+		return nil, err
+	}
+
+	//WanRunの評価の付与
+	if err = h.setWanrunRatings(c, dogrunLists); err != nil {
+		return nil, err
+	}
+
+	//ギャラリー画像のマージ
+	if err = h.setDogrunImages(c, dogrunLists); err != nil {
+		return nil, err
+	}
+
 	return dogrunLists, nil
 }
 
@@ -331,6 +444,7 @@ func resolveDogrunDetail(dogrunG googleplace.BaseResource, dogrunD model.Dogrun)
 		GoogleRating:    dogrunG.Rating,
 		UserRatingCount: dogrunG.UserRatingCount,
 		DogrunTags:      resolveDogrunTagInfo(dogrunD), // ドッグランタグ情報
+		Photos:          resolvePlacePhotos(dogrunG),
 		CreateAt:        &dogrunD.CreateAt.Time,
 		UpdateAt:        &dogrunD.UpdateAt.Time,
 	}
@@ -359,6 +473,7 @@ func resolveDogrunDetailByOnlyGoogle(dogrunG googleplace.BaseResource) dto.Dogru
 		Description:     dogrunG.Summary.Text,
 		GoogleRating:    dogrunG.Rating,
 		UserRatingCount: dogrunG.UserRatingCount,
+		Photos:          resolvePlacePhotos(dogrunG),
 	}
 }
 
@@ -439,16 +554,27 @@ func resolveDogrunAddress(dogrunG googleplace.BaseResource, dogrunD model.Dogrun
 営業時間から、現在が営業中かを判定
 */
 func resolveNowOpening(dogrunG googleplace.BaseResource, dogrunD model.Dogrun) bool {
-
 	if dogrunD.IsEmpty() && dogrunG.IsNotEmpty() { //DB情報がない時
 		return dogrunG.OpeningHours.OpenNow
 	}
+	return resolveOpeningAt(dogrunG, dogrunD, time.Now())
+}
 
-	now := time.Now()
+/*
+営業時間から、指定日時に営業中かを判定
+DB情報がない場合は、googleの営業時間から判定する
+*/
+func resolveOpeningAt(dogrunG googleplace.BaseResource, dogrunD model.Dogrun, at time.Time) bool {
 
-	//DBより、今日の曜日の通常営業時間情報を取得
-	regularBusinessHour := dogrunD.FetchTargetRegularBusinessHour(int(now.Weekday()))
-	todaySpecialBusinesshour := dogrunD.FetchTargetDateSpecialBusinessHour(now)
+	if dogrunD.IsEmpty() && dogrunG.IsNotEmpty() { //DB情報がない時
+		return resolveGoogleOpeningAt(dogrunG.OpeningHours, at)
+	}
+
+	//DBより、指定日の曜日の通常営業時間情報を取得
+	regularBusinessHour := dogrunD.FetchTargetRegularBusinessHour(int(at.Weekday()))
+	//特別営業日はdate型(UTCの0時)で保持しているため、日付のみで比較する
+	targetDate := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	todaySpecialBusinesshour := dogrunD.FetchTargetDateSpecialBusinessHour(targetDate)
 
 	var nowOpen bool
 
@@ -462,7 +588,7 @@ func resolveNowOpening(dogrunG googleplace.BaseResource, dogrunD model.Dogrun) b
 			//その他は開始時間/終了時間より判定
 			openTimeStr := todaySpecialBusinesshour.OpenTime.String
 			closeTimeStr := todaySpecialBusinesshour.CloseTime.String
-			nowOpen = DetermineIsOpen(now, util.ParseStrToTime(openTimeStr), util.ParseStrToTime(closeTimeStr))
+			nowOpen = DetermineIsOpen(at, util.ParseStrToTime(openTimeStr), util.ParseStrToTime(closeTimeStr))
 		}
 	} else if regularBusinessHour.IsValid() {
 		if regularBusinessHour.IsClosed.Bool {
@@ -476,17 +602,41 @@ func resolveNowOpening(dogrunG googleplace.BaseResource, dogrunD model.Dogrun) b
 			//通常営業時間より判定
 			openTimeStr := regularBusinessHour.OpenTime.String
 			closeTimeStr := regularBusinessHour.CloseTime.String
-			nowOpen = DetermineIsOpen(now, util.ParseStrToTime(openTimeStr), util.ParseStrToTime(closeTimeStr))
+			nowOpen = DetermineIsOpen(at, util.ParseStrToTime(openTimeStr), util.ParseStrToTime(closeTimeStr))
 		}
 	}
 
 	return nowOpen
 }
 
+/*
+googleの営業時間(periods)から、指定日時に営業中かを判定
+週の分単位に変換して比較し、日をまたぐ・週をまたぐ営業時間にも対応する
+closeがない(開始と終了が同じ)periodは24時間営業とする
+*/
+func resolveGoogleOpeningAt(openingHours googleplace.OpeningHours, at time.Time) bool {
+	const minutesOfWeek = 7 * 24 * 60
+	toMinutes := func(p googleplace.OpeningHoursPeriodInfo) int {
+		return p.Day*24*60 + p.Hour*60 + p.Minute
+	}
+	target := int(at.Weekday())*24*60 + at.Hour()*60 + at.Minute()
+
+	for _, period := range openingHours.Periods {
+		open, close := toMinutes(period.Open), toMinutes(period.Close)
+		if close <= open {
+			close += minutesOfWeek
+		}
+		if (open <= target && target < close) || (open <= target+minutesOfWeek && target+minutesOfWeek < close) {
+			return true
+		}
+	}
+	return false
+}
+
 func DetermineIsOpen(now, openTime, closeTime time.Time) bool {
-	// 時間部分だけを取り出しては、他は統一
-	openTime = time.Date(now.Year(), now.Month(), now.Day(), openTime.Hour(), openTime.Minute(), 00, 0, time.UTC)
-	closeTime = time.Date(now.Year(), now.Month(), now.Day(), closeTime.Hour(), closeTime.Minute(), 00, 0, time.UTC)
+	// 時間部分だけを取り出しては、他は統一(判定日時と同じタイムゾーンで比較する)
+	openTime = time.Date(now.Year(), now.Month(), now.Day(), openTime.Hour(), openTime.Minute(), 00, 0, now.Location())
+	closeTime = time.Date(now.Year(), now.Month(), now.Day(), closeTime.Hour(), closeTime.Minute(), 00, 0, now.Location())
 	// 終了時間が開始時間よりも前の場合、終了時間を次の日に設定
 	if closeTime.Before(openTime) {
 		closeTime = closeTime.Add(24 * time.Hour)
@@ -634,53 +784,113 @@ func (h *dogrunHandler) searchTextUpToSpecifiedTimes(c echo.Context, payload goo
 }
 
 /*
-検索結果をもとに、レスポンス用のDTOを作成
-placeIdで、両方にあるデータと、DBにのみあるデータ等で分けて、それぞれ統合する
+searchNearbyを実行する
+searchNearbyはページングがないため、1回のみ実行する
 */
-func (h *dogrunHandler) integrateDogrunInfos(dogrunsG []googleplace.BaseResource, dogrunsD []model.Dogrun) ([]dto.DogrunLists, error) {
-	//google情報からplaceIdをkeyにmapにまとめる
-	dogrunsGWithPlaceID := make(map[string]googleplace.BaseResource, len(dogrunsG))
-	for _, dogrunG := range dogrunsG {
-		dogrunsGWithPlaceID[dogrunG.ID] = dogrunG
+func (h *dogrunHandler) searchNearby(c echo.Context, payload googleplace.SearchNearbyPayLoad, fields googleplace.IFieldMask) ([]googleplace.BaseResource, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	res, err := h.rest.POSTSearchNearby(c, payload, fields)
+	if err != nil {
+		return nil, err
 	}
+	// JSONデータを構造体にデコード
+	searchNearbyRes := &googleplace.SearchTextBaseResource{}
+	if err = json.Unmarshal(res, searchNearbyRes); err != nil {
+		err = errors.NewWRError(nil, "google apiレスポンスの変換に失敗しました。", errors.NewDogrunServerErrorEType())
+		logger.Error(err)
+		return nil, err
+	}
+	logger.Infow("レスポンス", "response", searchNearbyRes)
+
+	return searchNearbyRes.Places, nil
+}
 
+/*
+中心からの距離をセットし、半径外のものを除外して距離の近い順に並べる
+距離が同じ場合は、dogrunID, placeIdの順で並べる
+*/
+func filterAndSortByDistance(condition dto.SearchAroundCircleCondition, dogrunLists []dto.DogrunLists) []dto.DogrunLists {
+	radius := float64(condition.Target.Radius)
+
+	filtered := []dto.DogrunLists{}
+	for _, dogrun := range dogrunLists {
+		distance := math.Round(util.CalcDistanceMeter(
+			condition.Center.Latitude, condition.Center.Longitude,
+			dogrun.Location.Latitude, dogrun.Location.Longitude,
+		))
+		if distance > radius {
+			continue
+		}
+		dogrun.Distance = &distance
+		filtered = append(filtered, dogrun)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if *filtered[i].Distance != *filtered[j].Distance {
+			return *filtered[i].Distance < *filtered[j].Distance
+		}
+		if filtered[i].DogrunID != filtered[j].DogrunID {
+			return filtered[i].DogrunID < filtered[j].DogrunID
+		}
+		return filtered[i].PlaceId < filtered[j].PlaceId
+	})
+	return filtered
+}
+
+/*
+検索結果をもとに、レスポンス用のDTOを作成
+placeIdで、両方にあるデータと、DBにのみあるデータ等で分けて、それぞれ統合する
+統合後のデータに絞り込み条件を適用する
+*/
+func (h *dogrunHandler) integrateDogrunInfos(dogrunsG []googleplace.BaseResource, dogrunsD []model.Dogrun, matcher searchFilterMatcher) ([]dto.DogrunLists, error) {
 	//DB情報からplaceIdがあるデータのみ、mapにまとめる
 	dogrunsDWithPlaceID := make(map[string]model.Dogrun)
-	//placeIdがないもののみでまとめる
-	var dogrunDWithoutPlaceIdS []model.Dogrun
 	for _, dogrunD := range dogrunsD {
 		if dogrunD.PlaceId.Valid {
 			dogrunsDWithPlaceID[dogrunD.PlaceId.String] = dogrunD
-		} else {
-			dogrunDWithoutPlaceIdS = append(dogrunDWithoutPlaceIdS, dogrunD)
 		}
 	}
 
 	dogrunLists := []dto.DogrunLists{}
 
-	//両方にplaceIdがある情報をDTOにつめる
-	for placeId, dogrunGValue := range dogrunsGWithPlaceID {
-		dogrunDValue, existDogrunD := dogrunsDWithPlaceID[placeId]
+	//google情報をレスポンス順に、DBにもある場合は統合してDTOにつめる
+	//map順に依存しないよう、スライスの順で処理する
+	mergedPlaceIDs := make(map[string]struct{}, len(dogrunsG))
+	for _, dogrunGValue := range dogrunsG {
+		if _, merged := mergedPlaceIDs[dogrunGValue.ID]; merged {
+			//google側の重複は除外
+			continue
+		}
+		mergedPlaceIDs[dogrunGValue.ID] = struct{}{}
+
+		var dogrunList dto.DogrunLists
+		dogrunDValue, existDogrunD := dogrunsDWithPlaceID[dogrunGValue.ID]
 		if existDogrunD {
 			//DBにもある場合、両方からデータの選別してセット
-			dogrunLists = append(dogrunLists, resolveDogrunList(dogrunGValue, dogrunDValue))
-			//DogrunsDから削除
-			delete(dogrunsDWithPlaceID, placeId)
+			dogrunList = resolveDogrunList(dogrunGValue, dogrunDValue)
 		} else {
 			//google側にしかない場合
 			//レスポンス整形してセット
-			dogrunLists = append(dogrunLists, resolveDogrunListByOnlyGoogle(dogrunGValue))
+			dogrunList = resolveDogrunListByOnlyGoogle(dogrunGValue)
+		}
+		if matcher.match(dogrunList, dogrunGValue, dogrunDValue) {
+			dogrunLists = append(dogrunLists, dogrunList)
 		}
 	}
 
-	//placeIdはあるが、google側にないものをDTOにつめる
-	for _, dogrunDValue := range dogrunsDWithPlaceID {
-		dogrunLists = append(dogrunLists, resolveDogrunListByOnlyDB(dogrunDValue))
-	}
-
-	//placeIdがないDBのみのデータをDTOにつめる
-	for _, dogrunDValue := range dogrunDWithoutPlaceIdS {
-		dogrunLists = append(dogrunLists, resolveDogrunListByOnlyDB(dogrunDValue))
+	//google側にないDB情報(placeIdなしを含む)をDB取得順にDTOにつめる
+	for _, dogrunDValue := range dogrunsD {
+		if dogrunDValue.PlaceId.Valid {
+			if _, merged := mergedPlaceIDs[dogrunDValue.PlaceId.String]; merged {
+				continue
+			}
+			mergedPlaceIDs[dogrunDValue.PlaceId.String] = struct{}{}
+		}
+		dogrunList := resolveDogrunListByOnlyDB(dogrunDValue)
+		if matcher.match(dogrunList, googleplace.BaseResource{}, dogrunDValue) {
+			dogrunLists = append(dogrunLists, dogrunList)
+		}
 	}
 
 	return dogrunLists, nil
@@ -708,7 +918,7 @@ func resolveDogrunList(dogrunG googleplace.BaseResource, dogrunD model.Dogrun) d
 		UserRatingCount:   dogrunG.UserRatingCount,
 		Photos:            resolvePlacePhotos(dogrunG),
 		DogrunTags:        resolveDogrunTagInfo(dogrunD), // ドッグランタグ情報
-		IsManaged:         true,
+		IsManaged:         dogrunD.IsManaged.Bool,
 	}
 
 }
@@ -756,7 +966,7 @@ func resolveDogrunListByOnlyDB(dogrunD model.Dogrun) dto.DogrunLists {
 		ToadyBusinessHour: resolveTodayBusinessHour(emptyDogrunG, dogrunD),
 		Description:       dogrunD.Description.String,
 		DogrunTags:        resolveDogrunTagInfo(dogrunD), // ドッグランタグ情報
-		IsManaged:         true,
+		IsManaged:         dogrunD.IsManaged.Bool,
 	}
 
 }
@@ -811,6 +1021,7 @@ func resolvePlacePhotos(dogrunG googleplace.BaseResource) []dto.PhotoInfo {
 			PhotoKey: photo.Name,
 			HeightPx: photo.HeightPx,
 			WidthPx:  photo.WidthPx,
+			Source:   dto.PHOTO_SOURCE_GOOGLE,
 		}
 		photos = append(photos, photoInfo)
 	}
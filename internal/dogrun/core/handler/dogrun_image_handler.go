@@ -0,0 +1,589 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	cmsFacade "github.com/wanrun-develop/wanrun/internal/cms/facade"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/internal/transaction"
+	"github.com/wanrun-develop/wanrun/internal/wrcontext"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+	"gorm.io/gorm"
+)
+
+type IDogrunImageHandler interface {
+	GetDogrunImages(echo.Context, int64) ([]dto.DogrunImageRes, error)
+	GetManagedDogrunImages(echo.Context, int64, dto.DogrunImageStatusReq) ([]dto.DogrunImageRes, error)
+	AddDogrunImage(echo.Context, int64, dto.DogrunImageReq) (dto.DogrunImageRes, error)
+	UpdateDogrunImageCaption(echo.Context, int64, int64, dto.DogrunImageCaptionReq) error
+	ReorderDogrunImages(echo.Context, int64, dto.DogrunImageOrderReq) error
+	DeleteDogrunImage(echo.Context, int64, int64) error
+	ApproveDogrunImage(echo.Context, int64, int64) error
+	RejectDogrunImage(echo.Context, int64, int64, dto.DogrunImageRejectReq) error
+	SubmitDogrunImage(echo.Context, int64, dto.DogrunImageReq) (dto.DogrunImageRes, error)
+	GetSubmittedDogrunImages(echo.Context) ([]dto.DogrunImageRes, error)
+	DeleteSubmittedDogrunImage(echo.Context, int64) error
+}
+
+type dogrunImageHandler struct {
+	drr  repository.IDogrunRepository
+	dir  repository.IDogrunImageRepository
+	disr repository.IDogrunImageScopeRepository
+	tm   transaction.ITransactionManager
+	cf   cmsFacade.ICmsFacade
+}
+
+func NewDogrunImageHandler(
+	drr repository.IDogrunRepository,
+	dir repository.IDogrunImageRepository,
+	disr repository.IDogrunImageScopeRepository,
+	tm transaction.ITransactionManager,
+	cf cmsFacade.ICmsFacade,
+) IDogrunImageHandler {
+	return &dogrunImageHandler{
+		drr:  drr,
+		dir:  dir,
+		disr: disr,
+		tm:   tm,
+		cf:   cf,
+	}
+}
+
+// GetDogrunImages: ドッグランの公開中のギャラリー画像を表示順で取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//
+// return:
+//   - []dto.DogrunImageRes:	ギャラリー画像
+//   - error:	エラー
+func (h *dogrunImageHandler) GetDogrunImages(c echo.Context, dogrunID int64) ([]dto.DogrunImageRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrun, err := h.drr.FindDogrunByID(c, dogrunID)
+	if err != nil {
+		return nil, err
+	}
+	if dogrun.IsEmpty() {
+		err := errors.NewWRError(nil, "指定されたドッグランは存在しません。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return nil, err
+	}
+
+	images, err := h.dir.FindDogrunImagesByStatus(c, dogrunID, model.DOGRUN_IMAGE_STATUS_APPROVED)
+	if err != nil {
+		return nil, err
+	}
+	return convertToDogrunImageResList(images), nil
+}
+
+// GetManagedDogrunImages: 管理しているドッグランのギャラリー画像をステータスごとに取得
+// ステータスの指定がない場合は審査待ちの投稿を返す
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - dto.DogrunImageStatusReq:	リクエスト内容
+//
+// return:
+//   - []dto.DogrunImageRes:	ギャラリー画像
+//   - error:	エラー
+func (h *dogrunImageHandler) GetManagedDogrunImages(c echo.Context, dogrunID int64, req dto.DogrunImageStatusReq) ([]dto.DogrunImageRes, error) {
+	if _, err := findManagedDogrun(c, h.drr, dogrunID); err != nil {
+		return nil, err
+	}
+
+	status := req.Status
+	if status == 0 {
+		status = model.DOGRUN_IMAGE_STATUS_PENDING
+	}
+	images, err := h.dir.FindDogrunImagesByStatus(c, dogrunID, status)
+	if err != nil {
+		return nil, err
+	}
+	return convertToDogrunImageResList(images), nil
+}
+
+// AddDogrunImage: 管理しているドッグランのギャラリーに画像を追加
+// 管理者の画像は審査なしで公開し、表示順の末尾に追加する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - dto.DogrunImageReq:	リクエスト内容
+//
+// return:
+//   - dto.DogrunImageRes:	追加した画像
+//   - error:	エラー
+func (h *dogrunImageHandler) AddDogrunImage(c echo.Context, dogrunID int64, reqBody dto.DogrunImageReq) (dto.DogrunImageRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrun, err := findManagedDogrun(c, h.drr, dogrunID)
+	if err != nil {
+		return dto.DogrunImageRes{}, err
+	}
+	s3File, err := h.getUsableFile(c, reqBody.FileID, func(s3File model.S3FileInfo) bool {
+		return s3File.DogrunManagerID.Valid && s3File.DogrunManagerID.Int64 == dogrun.DogrunManagerID.Int64
+	})
+	if err != nil {
+		return dto.DogrunImageRes{}, err
+	}
+
+	image := model.DogrunImage{
+		DogrunID:        util.NewSqlNullInt64(dogrunID),
+		Image:           s3File.S3ObjectKey,
+		FileID:          s3File.FileID,
+		Caption:         util.NewSqlNullString(reqBody.Caption),
+		Status:          util.NewSqlNullInt64(model.DOGRUN_IMAGE_STATUS_APPROVED),
+		DogrunManagerID: dogrun.DogrunManagerID,
+	}
+
+	ctx := c.Request().Context()
+	if err := h.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
+		sortOrder, err := h.disr.GetNextSortOrder(tx, c, dogrunID)
+		if err != nil {
+			return err
+		}
+		image.SortOrder = util.NewSqlNullInt64(sortOrder)
+		return h.disr.CreateDogrunImage(tx, c, &image)
+	}); err != nil {
+		logger.Error("Transaction failed:", err)
+		return dto.DogrunImageRes{}, err
+	}
+
+	logger.Infof("dogrun %d にギャラリー画像 %d を追加", dogrunID, image.DogrunImageID.Int64)
+	return convertToDogrunImageRes(image), nil
+}
+
+// UpdateDogrunImageCaption: 管理しているドッグランのギャラリー画像のキャプションを更新
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - int64:	dogrunImageID
+//   - dto.DogrunImageCaptionReq:	リクエスト内容
+//
+// return:
+//   - error:	エラー
+func (h *dogrunImageHandler) UpdateDogrunImageCaption(c echo.Context, dogrunID int64, imageID int64, reqBody dto.DogrunImageCaptionReq) error {
+	if _, err := findManagedDogrun(c, h.drr, dogrunID); err != nil {
+		return err
+	}
+	if _, err := h.getDogrunImage(c, dogrunID, imageID); err != nil {
+		return err
+	}
+	return h.dir.UpdateDogrunImageCaption(c, imageID, util.NewSqlNullString(reqBody.Caption))
+}
+
+// ReorderDogrunImages: 管理しているドッグランの公開中のギャラリー画像を並び替え
+// 公開中の画像をすべて過不足なく指定する必要がある
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - dto.DogrunImageOrderReq:	リクエスト内容
+//
+// return:
+//   - error:	エラー
+func (h *dogrunImageHandler) ReorderDogrunImages(c echo.Context, dogrunID int64, reqBody dto.DogrunImageOrderReq) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if _, err := findManagedDogrun(c, h.drr, dogrunID); err != nil {
+		return err
+	}
+
+	images, err := h.dir.FindDogrunImagesByStatus(c, dogrunID, model.DOGRUN_IMAGE_STATUS_APPROVED)
+	if err != nil {
+		return err
+	}
+	approved := map[int64]bool{}
+	for _, image := range images {
+		approved[image.DogrunImageID.Int64] = true
+	}
+	specified := map[int64]bool{}
+	for _, imageID := range reqBody.DogrunImageIDs {
+		if !approved[imageID] || specified[imageID] {
+			err := errors.NewWRError(nil, "公開中のギャラリー画像のIDを重複なく指定してください。", errors.NewDogrunClientErrorEType())
+			logger.Error(err)
+			return err
+		}
+		specified[imageID] = true
+	}
+	if len(specified) != len(approved) {
+		err := errors.NewWRError(nil, "公開中のギャラリー画像をすべて指定してください。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
+		return h.disr.UpdateDogrunImageSortOrders(tx, c, dogrunID, reqBody.DogrunImageIDs)
+	}); err != nil {
+		logger.Error("Transaction failed:", err)
+		return err
+	}
+
+	logger.Infof("dogrun %d のギャラリー画像を並び替え", dogrunID)
+	return nil
+}
+
+// DeleteDogrunImage: 管理しているドッグランのギャラリー画像を削除
+// 投稿された画像も含めて削除でき、cmsのファイルもあわせて削除する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - int64:	dogrunImageID
+//
+// return:
+//   - error:	エラー
+func (h *dogrunImageHandler) DeleteDogrunImage(c echo.Context, dogrunID int64, imageID int64) error {
+	if _, err := findManagedDogrun(c, h.drr, dogrunID); err != nil {
+		return err
+	}
+	image, err := h.getDogrunImage(c, dogrunID, imageID)
+	if err != nil {
+		return err
+	}
+	return h.deleteDogrunImage(c, image)
+}
+
+// ApproveDogrunImage: 投稿された画像を承認して公開する
+// 表示順の末尾に追加する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - int64:	dogrunImageID
+//
+// return:
+//   - error:	エラー
+func (h *dogrunImageHandler) ApproveDogrunImage(c echo.Context, dogrunID int64, imageID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrun, image, err := h.getPendingDogrunImage(c, dogrunID, imageID)
+	if err != nil {
+		return err
+	}
+
+	image.Status = util.NewSqlNullInt64(model.DOGRUN_IMAGE_STATUS_APPROVED)
+	image.ReviewedBy = dogrun.DogrunManagerID
+	image.ReviewedAt = util.NewSqlNullTime(time.Now())
+
+	ctx := c.Request().Context()
+	if err := h.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
+		sortOrder, err := h.disr.GetNextSortOrder(tx, c, dogrunID)
+		if err != nil {
+			return err
+		}
+		image.SortOrder = util.NewSqlNullInt64(sortOrder)
+		return h.disr.UpdateDogrunImageReview(tx, c, &image)
+	}); err != nil {
+		logger.Error("Transaction failed:", err)
+		return err
+	}
+
+	logger.Infof("dogrun %d の投稿画像 %d を承認", dogrunID, imageID)
+	return nil
+}
+
+// RejectDogrunImage: 投稿された画像を却下する
+// 却下した画像は投稿者が理由を確認できるよう残す
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - int64:	dogrunImageID
+//   - dto.DogrunImageRejectReq:	リクエスト内容
+//
+// return:
+//   - error:	エラー
+func (h *dogrunImageHandler) RejectDogrunImage(c echo.Context, dogrunID int64, imageID int64, reqBody dto.DogrunImageRejectReq) error {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrun, image, err := h.getPendingDogrunImage(c, dogrunID, imageID)
+	if err != nil {
+		return err
+	}
+
+	image.Status = util.NewSqlNullInt64(model.DOGRUN_IMAGE_STATUS_REJECTED)
+	image.ReviewedBy = dogrun.DogrunManagerID
+	image.ReviewedAt = util.NewSqlNullTime(time.Now())
+	image.RejectReason = util.NewSqlNullString(reqBody.Reason)
+
+	ctx := c.Request().Context()
+	if err := h.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
+		return h.disr.UpdateDogrunImageReview(tx, c, &image)
+	}); err != nil {
+		logger.Error("Transaction failed:", err)
+		return err
+	}
+
+	logger.Infof("dogrun %d の投稿画像 %d を却下", dogrunID, imageID)
+	return nil
+}
+
+// SubmitDogrunImage: dogownerによるドッグランの写真の投稿
+// 管理者のいるドッグランのみ投稿でき、管理者が承認するまで公開しない
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - dto.DogrunImageReq:	リクエスト内容
+//
+// return:
+//   - dto.DogrunImageRes:	投稿した画像
+//   - error:	エラー
+func (h *dogrunImageHandler) SubmitDogrunImage(c echo.Context, dogrunID int64, reqBody dto.DogrunImageReq) (dto.DogrunImageRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogownerID, err := wrcontext.GetLoginDogownerID(c)
+	if err != nil {
+		return dto.DogrunImageRes{}, err
+	}
+
+	dogrun, err := h.drr.FindDogrunByID(c, dogrunID)
+	if err != nil {
+		return dto.DogrunImageRes{}, err
+	}
+	if dogrun.IsEmpty() {
+		err := errors.NewWRError(nil, "指定されたドッグランは存在しません。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return dto.DogrunImageRes{}, err
+	}
+	if !dogrun.DogrunManagerID.Valid {
+		err := errors.NewWRError(nil, "管理者のいないドッグランには写真を投稿できません。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return dto.DogrunImageRes{}, err
+	}
+
+	s3File, err := h.getUsableFile(c, reqBody.FileID, func(s3File model.S3FileInfo) bool {
+		return s3File.DogOwnerID.Valid && s3File.DogOwnerID.Int64 == dogownerID
+	})
+	if err != nil {
+		return dto.DogrunImageRes{}, err
+	}
+
+	image := model.DogrunImage{
+		DogrunID:   util.NewSqlNullInt64(dogrunID),
+		Image:      s3File.S3ObjectKey,
+		FileID:     s3File.FileID,
+		Caption:    util.NewSqlNullString(reqBody.Caption),
+		Status:     util.NewSqlNullInt64(model.DOGRUN_IMAGE_STATUS_PENDING),
+		DogOwnerID: util.NewSqlNullInt64(dogownerID),
+	}
+
+	ctx := c.Request().Context()
+	if err := h.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
+		return h.disr.CreateDogrunImage(tx, c, &image)
+	}); err != nil {
+		logger.Error("Transaction failed:", err)
+		return dto.DogrunImageRes{}, err
+	}
+
+	logger.Infof("dogowner %d が dogrun %d に写真 %d を投稿", dogownerID, dogrunID, image.DogrunImageID.Int64)
+	return convertToDogrunImageRes(image), nil
+}
+
+// GetSubmittedDogrunImages: ログインしているdogownerが投稿した写真を審査状況とあわせて取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - []dto.DogrunImageRes:	投稿した写真
+//   - error:	エラー
+func (h *dogrunImageHandler) GetSubmittedDogrunImages(c echo.Context) ([]dto.DogrunImageRes, error) {
+	dogownerID, err := wrcontext.GetLoginDogownerID(c)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := h.dir.FindDogrunImagesByDogOwnerID(c, dogownerID)
+	if err != nil {
+		return nil, err
+	}
+	return convertToDogrunImageResList(images), nil
+}
+
+// DeleteSubmittedDogrunImage: ログインしているdogownerが投稿した写真を削除
+// 公開中の写真も削除でき、cmsのファイルもあわせて削除する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunImageID
+//
+// return:
+//   - error:	エラー
+func (h *dogrunImageHandler) DeleteSubmittedDogrunImage(c echo.Context, imageID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	dogownerID, err := wrcontext.GetLoginDogownerID(c)
+	if err != nil {
+		return err
+	}
+
+	image, err := h.dir.GetDogrunImageByID(c, imageID)
+	if err != nil {
+		return err
+	}
+	if image.IsEmpty() || image.DogOwnerID.Int64 != dogownerID {
+		err := errors.NewWRError(nil, "指定された写真は存在しません。", errors.NewDogrunClientErrorEType())
+		logger.Errorf("dogowner %d による投稿外の写真 %d の削除: %v", dogownerID, imageID, err)
+		return err
+	}
+	return h.deleteDogrunImage(c, image)
+}
+
+// getUsableFile: ギャラリー画像に使用できるcmsのファイルの取得
+// ログインユーザーがアップロードした、未使用のファイルのみ使用できる
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - string:	fileID
+//   - func(model.S3FileInfo) bool:	ログインユーザーがアップロードしたファイルか
+//
+// return:
+//   - model.S3FileInfo:	ファイル情報
+//   - error:	エラー
+func (h *dogrunImageHandler) getUsableFile(c echo.Context, fileID string, isUploader func(model.S3FileInfo) bool) (model.S3FileInfo, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	s3File, err := h.cf.GetS3FileInfoByFileID(c, fileID)
+	if err != nil {
+		return model.S3FileInfo{}, err
+	}
+	if !isUploader(s3File) {
+		err := errors.NewWRError(nil, "指定されたファイルは利用できません。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return model.S3FileInfo{}, err
+	}
+
+	used, err := h.dir.ExistsDogrunImageByFileID(c, fileID)
+	if err != nil {
+		return model.S3FileInfo{}, err
+	}
+	if used {
+		err := errors.NewWRError(nil, "指定されたファイルは既に使用されています。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return model.S3FileInfo{}, err
+	}
+	return s3File, nil
+}
+
+// getDogrunImage: ドッグランのギャラリー画像の取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - int64:	dogrunImageID
+//
+// return:
+//   - model.DogrunImage:	ギャラリー画像
+//   - error:	エラー。ドッグランの画像でない場合はクライアントエラー
+func (h *dogrunImageHandler) getDogrunImage(c echo.Context, dogrunID int64, imageID int64) (model.DogrunImage, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	image, err := h.dir.GetDogrunImageByID(c, imageID)
+	if err != nil {
+		return model.DogrunImage{}, err
+	}
+	if image.IsEmpty() || image.DogrunID.Int64 != dogrunID {
+		err := errors.NewWRError(nil, "指定された画像は存在しません。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return model.DogrunImage{}, err
+	}
+	return image, nil
+}
+
+// getPendingDogrunImage: 管理しているドッグランの審査待ちの画像の取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - int64:	dogrunImageID
+//
+// return:
+//   - model.Dogrun:	ドッグラン情報
+//   - model.DogrunImage:	審査待ちの画像
+//   - error:	エラー
+func (h *dogrunImageHandler) getPendingDogrunImage(c echo.Context, dogrunID int64, imageID int64) (model.Dogrun, model.DogrunImage, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrun, err := findManagedDogrun(c, h.drr, dogrunID)
+	if err != nil {
+		return model.Dogrun{}, model.DogrunImage{}, err
+	}
+	image, err := h.getDogrunImage(c, dogrunID, imageID)
+	if err != nil {
+		return model.Dogrun{}, model.DogrunImage{}, err
+	}
+	if !image.IsPending() {
+		err := errors.NewWRError(nil, "画像は既に審査済みです。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return model.Dogrun{}, model.DogrunImage{}, err
+	}
+	return dogrun, image, nil
+}
+
+// deleteDogrunImage: ギャラリー画像とcmsのファイルの削除
+// 画像の削除自体は完了しているため、ファイルの削除の失敗はログ出力のみとする
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - model.DogrunImage:	削除する画像
+//
+// return:
+//   - error:	エラー
+func (h *dogrunImageHandler) deleteDogrunImage(c echo.Context, image model.DogrunImage) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := h.dir.DeleteDogrunImage(c, image.DogrunImageID.Int64); err != nil {
+		return err
+	}
+	if image.FileID.Valid {
+		if err := h.cf.DeleteFile(c, image.FileID.String); err != nil {
+			logger.Warnf("ギャラリー画像のファイル %s の削除に失敗しました: %v", image.FileID.String, err)
+		}
+	}
+	logger.Infof("dogrun %d のギャラリー画像 %d を削除", image.DogrunID.Int64, image.DogrunImageID.Int64)
+	return nil
+}
+
+/*
+ギャラリー画像をレスポンスに変換
+*/
+func convertToDogrunImageRes(image model.DogrunImage) dto.DogrunImageRes {
+	res := dto.DogrunImageRes{
+		DogrunImageID:   image.DogrunImageID.Int64,
+		DogrunID:        image.DogrunID.Int64,
+		Image:           image.Image.String,
+		Caption:         image.Caption.String,
+		SortOrder:       image.SortOrder.Int64,
+		Status:          image.Status.Int64,
+		DogOwnerID:      image.DogOwnerID.Int64,
+		DogrunManagerID: image.DogrunManagerID.Int64,
+		RejectReason:    image.RejectReason.String,
+	}
+	if image.UploadAt.Valid {
+		res.UploadAt = &image.UploadAt.Time
+	}
+	if image.ReviewedAt.Valid {
+		res.ReviewedAt = &image.ReviewedAt.Time
+	}
+	return res
+}
+
+func convertToDogrunImageResList(images []model.DogrunImage) []dto.DogrunImageRes {
+	resList := []dto.DogrunImageRes{}
+	for _, image := range images {
+		resList = append(resList, convertToDogrunImageRes(image))
+	}
+	return resList
+}
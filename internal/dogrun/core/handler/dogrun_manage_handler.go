@@ -0,0 +1,334 @@
+package handler
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/internal/transaction"
+	"github.com/wanrun-develop/wanrun/internal/wrcontext"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+	"gorm.io/gorm"
+)
+
+type IDogrunManageHandler interface {
+	UpdateDogrunProfile(echo.Context, int64, dto.DogrunProfileUpdateReq) error
+	UpdateDogrunTags(echo.Context, int64, dto.DogrunTagUpdateReq) error
+	UpdateDogrunBusinessHours(echo.Context, int64, dto.DogrunBusinessHourUpdateReq) error
+}
+
+type dogrunManageHandler struct {
+	drr  repository.IDogrunRepository
+	drsr repository.IDogrunScopeRepository
+	tm   transaction.ITransactionManager
+}
+
+func NewDogrunManageHandler(
+	drr repository.IDogrunRepository,
+	drsr repository.IDogrunScopeRepository,
+	tm transaction.ITransactionManager,
+) IDogrunManageHandler {
+	return &dogrunManageHandler{
+		drr:  drr,
+		drsr: drsr,
+		tm:   tm,
+	}
+}
+
+// UpdateDogrunProfile: ドッグランの基本情報(名前、住所、説明、位置情報)の更新
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - dto.DogrunProfileUpdateReq:	リクエスト内容
+//
+// return:
+//   - error:	エラー
+func (h *dogrunManageHandler) UpdateDogrunProfile(c echo.Context, dogrunID int64, reqBody dto.DogrunProfileUpdateReq) error {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrun, err := findManagedDogrun(c, h.drr, dogrunID)
+	if err != nil {
+		return err
+	}
+
+	//更新値をつめる
+	dogrun.Name = util.NewSqlNullString(reqBody.Name)
+	dogrun.Address = util.NewSqlNullString(reqBody.Address)
+	dogrun.PostCode = util.NewSqlNullString(reqBody.PostCode)
+	dogrun.Description = util.NewSqlNullString(reqBody.Description)
+	dogrun.Latitude.Float64, dogrun.Latitude.Valid = reqBody.Location.Latitude, true
+	dogrun.Longitude.Float64, dogrun.Longitude.Valid = reqBody.Location.Longitude, true
+	dogrun.UpdateAt = util.NewSqlNullTime(time.Now())
+
+	ctx := c.Request().Context()
+	if err := h.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
+		return h.drsr.UpdateDogrunProfile(tx, c, &dogrun)
+	}); err != nil {
+		logger.Error("Transaction failed:", err)
+		return err
+	}
+
+	logger.Infof("dogrun %d の基本情報を更新", dogrunID)
+	return nil
+}
+
+// UpdateDogrunTags: ドッグランタグの更新
+// 指定されたタグで全置き換えする
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - dto.DogrunTagUpdateReq:	リクエスト内容
+//
+// return:
+//   - error:	エラー
+func (h *dogrunManageHandler) UpdateDogrunTags(c echo.Context, dogrunID int64, reqBody dto.DogrunTagUpdateReq) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if _, err := findManagedDogrun(c, h.drr, dogrunID); err != nil {
+		return err
+	}
+
+	//tag_mstに存在するタグかチェック
+	tagMst, err := h.drr.GetTagMst(c)
+	if err != nil {
+		return err
+	}
+	tagMstMap := util.ConvertSliceToMap(tagMst, func(t model.TagMst) int64 {
+		return t.TagID.Int64
+	})
+
+	tags := []model.DogrunTag{}
+	appended := map[int64]bool{}
+	for _, tagID := range reqBody.DogrunTags {
+		if _, ok := tagMstMap[tagID]; !ok {
+			err := errors.NewWRError(nil, fmt.Sprintf("タグID:%dは存在しません。", tagID), errors.NewDogrunClientErrorEType())
+			logger.Error(err)
+			return err
+		}
+		//重複指定は1件にまとめる
+		if appended[tagID] {
+			continue
+		}
+		appended[tagID] = true
+		tags = append(tags, model.DogrunTag{
+			DogrunID: util.NewSqlNullInt64(dogrunID),
+			TagID:    util.NewSqlNullInt64(tagID),
+		})
+	}
+
+	ctx := c.Request().Context()
+	if err := h.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
+		return h.drsr.ReplaceDogrunTags(tx, c, dogrunID, tags)
+	}); err != nil {
+		logger.Error("Transaction failed:", err)
+		return err
+	}
+
+	logger.Infof("dogrun %d のタグを更新", dogrunID)
+	return nil
+}
+
+// UpdateDogrunBusinessHours: ドッグランの通常営業時間・特別営業時間の更新
+// 指定された内容で全置き換えする
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - dto.DogrunBusinessHourUpdateReq:	リクエスト内容
+//
+// return:
+//   - error:	エラー
+func (h *dogrunManageHandler) UpdateDogrunBusinessHours(c echo.Context, dogrunID int64, reqBody dto.DogrunBusinessHourUpdateReq) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if _, err := findManagedDogrun(c, h.drr, dogrunID); err != nil {
+		return err
+	}
+
+	//営業時間の重複・範囲チェック
+	if err := validateRegularBusinessHours(reqBody.Regular); err != nil {
+		logger.Error(err)
+		return err
+	}
+	if err := validateSpecialBusinessHours(reqBody.Special); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	regularHours := []model.RegularBusinessHour{}
+	for _, r := range reqBody.Regular {
+		openTime, closeTime := resolveReqBusinessTime(r.BusinessTimeReq)
+		regularHours = append(regularHours, model.RegularBusinessHour{
+			DogrunID:  util.NewSqlNullInt64(dogrunID),
+			Day:       sql.NullInt64{Int64: int64(*r.Day), Valid: true}, // 日曜日(0)も有効値として扱う
+			OpenTime:  openTime,
+			CloseTime: closeTime,
+			IsAllDay:  util.NewSqlNullBool(r.IsAllDay),
+			IsClosed:  util.NewSqlNullBool(r.IsHoliday),
+		})
+	}
+
+	specialHours := []model.SpecialBusinessHour{}
+	for _, s := range reqBody.Special {
+		date, _ := time.Parse(dto.SPECIAL_DATE_FORMAT, s.Date) //バリデーション済み
+		openTime, closeTime := resolveReqBusinessTime(s.BusinessTimeReq)
+		specialHours = append(specialHours, model.SpecialBusinessHour{
+			DogrunID:  util.NewSqlNullInt64(dogrunID),
+			Date:      util.NewSqlNullTime(date),
+			OpenTime:  openTime,
+			CloseTime: closeTime,
+			IsAllDay:  util.NewSqlNullBool(s.IsAllDay),
+			IsClosed:  util.NewSqlNullBool(s.IsHoliday),
+		})
+	}
+
+	ctx := c.Request().Context()
+	if err := h.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
+		if err := h.drsr.ReplaceRegularBusinessHours(tx, c, dogrunID, regularHours); err != nil {
+			return err
+		}
+		return h.drsr.ReplaceSpecialBusinessHours(tx, c, dogrunID, specialHours)
+	}); err != nil {
+		logger.Error("Transaction failed:", err)
+		return err
+	}
+
+	logger.Infof("dogrun %d の営業時間を更新", dogrunID)
+	return nil
+}
+
+// findManagedDogrun: ログインユーザーが管理しているドッグランの取得
+// ドッグランの存在チェックと、dogrun_manager_idがログインユーザーと一致するかのチェックを行う
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - repository.IDogrunRepository:	ドッグランのリポジトリ
+//   - int64:	dogrunID
+//
+// return:
+//   - model.Dogrun:	ドッグラン情報
+//   - error:	エラー
+func findManagedDogrun(c echo.Context, drr repository.IDogrunRepository, dogrunID int64) (model.Dogrun, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunmgID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return model.Dogrun{}, err
+	}
+
+	dogrun, err := drr.FindDogrunByID(c, dogrunID)
+	if err != nil {
+		return model.Dogrun{}, err
+	}
+	if dogrun.IsEmpty() {
+		err := errors.NewWRError(nil, "指定されたドッグランは存在しません。", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return model.Dogrun{}, err
+	}
+	if !dogrun.DogrunManagerID.Valid || dogrun.DogrunManagerID.Int64 != dogrunmgID {
+		err := errors.NewWRError(nil, "指定されたドッグランの管理権限がありません。", errors.NewDogrunClientErrorEType())
+		logger.Errorf("dogrunmg %d による管理外のdogrun %d への更新リクエスト: %v", dogrunmgID, dogrunID, err)
+		return model.Dogrun{}, err
+	}
+	return dogrun, nil
+}
+
+/*
+通常営業時間のバリデーション
+曜日の重複と、深夜営業(閉店時間が開店時間より前)の場合の翌日営業時間との重複をチェックする
+*/
+func validateRegularBusinessHours(regulars []dto.RegularBusinessHourReq) error {
+	dayMap := map[int]dto.BusinessTimeReq{}
+	for _, r := range regulars {
+		day := *r.Day
+		if _, ok := dayMap[day]; ok {
+			return errors.NewWRError(nil, fmt.Sprintf("曜日:%dの営業時間が重複して指定されています。", day), errors.NewDogrunClientErrorEType())
+		}
+		if err := validateBusinessTime(r.BusinessTimeReq); err != nil {
+			return errors.NewWRError(err, fmt.Sprintf("曜日:%dの営業時間が不正です。%s", day, err.Error()), errors.NewDogrunClientErrorEType())
+		}
+		dayMap[day] = r.BusinessTimeReq
+	}
+
+	for day, bt := range dayMap {
+		if !isOvernightBusinessTime(bt) {
+			continue
+		}
+		nextDay := (day + 1) % 7
+		next, ok := dayMap[nextDay]
+		if !ok || next.IsHoliday {
+			continue
+		}
+		if next.IsAllDay || util.ParseStrToTime(bt.CloseTime).After(util.ParseStrToTime(next.OpenTime)) {
+			return errors.NewWRError(nil, fmt.Sprintf("曜日:%dの深夜営業が曜日:%dの営業時間と重複しています。", day, nextDay), errors.NewDogrunClientErrorEType())
+		}
+	}
+	return nil
+}
+
+/*
+特別営業時間のバリデーション
+日付の重複と各営業時間の妥当性をチェックする
+*/
+func validateSpecialBusinessHours(specials []dto.SpecialBusinessHourReq) error {
+	dateMap := map[string]bool{}
+	for _, s := range specials {
+		if dateMap[s.Date] {
+			return errors.NewWRError(nil, fmt.Sprintf("日付:%sの特別営業時間が重複して指定されています。", s.Date), errors.NewDogrunClientErrorEType())
+		}
+		if err := validateBusinessTime(s.BusinessTimeReq); err != nil {
+			return errors.NewWRError(err, fmt.Sprintf("日付:%sの特別営業時間が不正です。%s", s.Date, err.Error()), errors.NewDogrunClientErrorEType())
+		}
+		dateMap[s.Date] = true
+	}
+	return nil
+}
+
+/*
+1日分の営業時間の妥当性チェック
+24時間営業と定休日の同時指定不可。通常営業の場合は開店・閉店時間が必須で、同時刻は不可
+*/
+func validateBusinessTime(bt dto.BusinessTimeReq) error {
+	if bt.IsAllDay && bt.IsHoliday {
+		return fmt.Errorf("24時間営業と定休日は同時に指定できません。")
+	}
+	if bt.IsAllDay || bt.IsHoliday {
+		return nil
+	}
+	if bt.OpenTime == "" || bt.CloseTime == "" {
+		return fmt.Errorf("開店時間と閉店時間は必須です。")
+	}
+	if bt.OpenTime == bt.CloseTime {
+		return fmt.Errorf("開店時間と閉店時間が同じです。24時間営業の場合はisAllDayを指定してください。")
+	}
+	return nil
+}
+
+/*
+深夜営業(閉店時間が開店時間より前)かの判定
+*/
+func isOvernightBusinessTime(bt dto.BusinessTimeReq) bool {
+	if bt.IsAllDay || bt.IsHoliday {
+		return false
+	}
+	return util.ParseStrToTime(bt.CloseTime).Before(util.ParseStrToTime(bt.OpenTime))
+}
+
+/*
+リクエストの営業時間から、DBに保存する開店・閉店時間を返す
+24時間営業、定休日の場合は時刻を保存しない
+*/
+func resolveReqBusinessTime(bt dto.BusinessTimeReq) (sql.NullString, sql.NullString) {
+	if bt.IsAllDay || bt.IsHoliday {
+		return sql.NullString{}, sql.NullString{}
+	}
+	return util.NewSqlNullString(bt.OpenTime), util.NewSqlNullString(bt.CloseTime)
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+// GetDogrunOccupancy: ドッグランの現在の混雑状況を取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//
+// return:
+//   - dto.OccupancyRes:	混雑状況
+//   - error:	エラー
+func (h *dogrunHandler) GetDogrunOccupancy(c echo.Context, dogrunID int64) (dto.OccupancyRes, error) {
+	resList, err := h.GetDogrunOccupancies(c, dto.OccupancyReq{DogrunIDs: []int64{dogrunID}})
+	if err != nil {
+		return dto.OccupancyRes{}, err
+	}
+	return resList[0], nil
+}
+
+// GetDogrunOccupancies: 複数ドッグランの現在の混雑状況を一括取得
+// 検索結果の一覧表示用
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.OccupancyReq:	リクエスト
+//
+// return:
+//   - []dto.OccupancyRes:	リクエストのdogrunIDs順の混雑状況
+//   - error:	エラー
+func (h *dogrunHandler) GetDogrunOccupancies(c echo.Context, req dto.OccupancyReq) ([]dto.OccupancyRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	//dogrun存在チェック
+	dogruns, err := h.drr.FindDogrunByIDs(req.DogrunIDs)
+	if err != nil {
+		err = errors.NewWRError(err, "DBからのデータ取得に失敗", errors.NewDogrunServerErrorEType())
+		logger.Error(err)
+		return nil, err
+	}
+	existDogrunIDs := make(map[int64]struct{})
+	for _, dogrun := range dogruns {
+		existDogrunIDs[dogrun.DogrunID.Int64] = struct{}{}
+	}
+	for _, dogrunID := range req.DogrunIDs {
+		if _, exists := existDogrunIDs[dogrunID]; !exists {
+			err = errors.NewWRError(nil, fmt.Sprintf("指定されたドッグランID:%dが存在しません", dogrunID), errors.NewDogrunClientErrorEType())
+			logger.Error("不正なdogrun idの指定", err)
+			return nil, err
+		}
+	}
+
+	occupancies, err := h.getOccupancies(c, req.DogrunIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resList := []dto.OccupancyRes{}
+	for _, dogrunID := range req.DogrunIDs {
+		resList = append(resList, *occupancies[dogrunID])
+	}
+	return resList, nil
+}
+
+// setOccupancies: ドッグラン一覧に混雑状況をセットする
+// dogrunIDが発行済みのドッグランのみ対象
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []dto.DogrunLists:	dogruns
+//
+// return:
+//   - error:	エラー
+func (h *dogrunHandler) setOccupancies(c echo.Context, dogrunLists []dto.DogrunLists) error {
+	dogrunIDs := []int64{}
+	for _, dogrunList := range dogrunLists {
+		if dogrunList.DogrunID != 0 {
+			dogrunIDs = append(dogrunIDs, dogrunList.DogrunID)
+		}
+	}
+
+	occupancies, err := h.getOccupancies(c, dogrunIDs)
+	if err != nil {
+		return err
+	}
+	for i := range dogrunLists {
+		dogrunLists[i].Occupancy = occupancies[dogrunLists[i].DogrunID]
+	}
+	return nil
+}
+
+// getOccupancies: 滞在中のdog数を集計してレスポンスに変換
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []int64:	dogrunIDs
+//
+// return:
+//   - map[int64]*dto.OccupancyRes:	dogrunIDごとの混雑状況
+//   - error:	エラー
+func (h *dogrunHandler) getOccupancies(c echo.Context, dogrunIDs []int64) (map[int64]*dto.OccupancyRes, error) {
+	occupancies, err := h.vf.GetOccupancies(c, dogrunIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	asOf := time.Now()
+	resMap := make(map[int64]*dto.OccupancyRes)
+	for dogrunID, occupancy := range occupancies {
+		resMap[dogrunID] = convertToOccupancyRes(occupancy, asOf)
+	}
+	return resMap, nil
+}
+
+// convertToOccupancyRes: 集計結果をレスポンスに変換
+//
+// args:
+//   - model.DogrunOccupancy:	集計結果
+//   - time.Time:	集計日時
+//
+// return:
+//   - *dto.OccupancyRes:	混雑状況
+func convertToOccupancyRes(occupancy model.DogrunOccupancy, asOf time.Time) *dto.OccupancyRes {
+	return &dto.OccupancyRes{
+		DogrunID: occupancy.DogrunID,
+		Total:    occupancy.Total,
+		Sizes: dto.OccupancySizeRes{
+			Small:   occupancy.Small,
+			Medium:  occupancy.Medium,
+			Large:   occupancy.Large,
+			Unknown: occupancy.Unknown,
+		},
+		AsOf: asOf,
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+)
+
+// setDogrunImages: ドッグラン一覧の写真にWanRunのギャラリー画像をマージする
+// ギャラリー画像を表示順で先頭に、Googleの写真をその後ろに並べる
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []dto.DogrunLists:	dogruns
+//
+// return:
+//   - error:	エラー
+func (h *dogrunHandler) setDogrunImages(c echo.Context, dogrunLists []dto.DogrunLists) error {
+	dogrunIDs := []int64{}
+	for _, dogrunList := range dogrunLists {
+		if dogrunList.DogrunID != 0 {
+			dogrunIDs = append(dogrunIDs, dogrunList.DogrunID)
+		}
+	}
+
+	images, err := h.dir.FindApprovedDogrunImages(c, dogrunIDs)
+	if err != nil {
+		return err
+	}
+	imagesMap := map[int64][]model.DogrunImage{}
+	for _, image := range images {
+		imagesMap[image.DogrunID.Int64] = append(imagesMap[image.DogrunID.Int64], image)
+	}
+	for i := range dogrunLists {
+		dogrunLists[i].Photos = mergeDogrunPhotos(imagesMap[dogrunLists[i].DogrunID], dogrunLists[i].Photos)
+	}
+	return nil
+}
+
+// setDogrunImageDetail: ドッグラン詳細の写真にWanRunのギャラリー画像をマージする
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - *dto.DogrunDetail:	ドッグラン詳細
+//
+// return:
+//   - error:	エラー
+func (h *dogrunHandler) setDogrunImageDetail(c echo.Context, detail *dto.DogrunDetail) error {
+	images, err := h.dir.FindApprovedDogrunImages(c, []int64{detail.DogrunID})
+	if err != nil {
+		return err
+	}
+	detail.Photos = mergeDogrunPhotos(images, detail.Photos)
+	return nil
+}
+
+/*
+ギャラリー画像(表示順に並んでいる前提)を先頭に、Googleの写真を後ろに並べる
+*/
+func mergeDogrunPhotos(images []model.DogrunImage, googlePhotos []dto.PhotoInfo) []dto.PhotoInfo {
+	if len(images) == 0 {
+		return googlePhotos
+	}
+	photos := make([]dto.PhotoInfo, 0, len(images)+len(googlePhotos))
+	for _, image := range images {
+		photos = append(photos, dto.PhotoInfo{
+			PhotoKey:      image.Image.String,
+			Source:        dto.PHOTO_SOURCE_WANRUN,
+			DogrunImageID: image.DogrunImageID.Int64,
+			Caption:       image.Caption.String,
+		})
+	}
+	return append(photos, googlePhotos...)
+}
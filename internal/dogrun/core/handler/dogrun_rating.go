@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"database/sql"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+)
+
+// setWanrunRatings: ドッグラン一覧にWanRunのレビューの評価をセットする
+// dogrunIDが発行済みのドッグランのみ対象
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []dto.DogrunLists:	dogruns
+//
+// return:
+//   - error:	エラー
+func (h *dogrunHandler) setWanrunRatings(c echo.Context, dogrunLists []dto.DogrunLists) error {
+	dogrunIDs := []int64{}
+	for _, dogrunList := range dogrunLists {
+		if dogrunList.DogrunID != 0 {
+			dogrunIDs = append(dogrunIDs, dogrunList.DogrunID)
+		}
+	}
+
+	summaries, err := h.rf.GetRatingSummaries(c, dogrunIDs)
+	if err != nil {
+		return err
+	}
+	for i := range dogrunLists {
+		summary, exist := summaries[dogrunLists[i].DogrunID]
+		if !exist {
+			continue
+		}
+		dogrunLists[i].WanrunRating = summary.AvgRating
+		dogrunLists[i].WanrunRatingCount = summary.ReviewCount
+	}
+	return nil
+}
+
+// setWanrunRatingDetail: ドッグラン詳細にWanRunのレビューの評価をセットする
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - *dto.DogrunDetail:	ドッグラン詳細
+//
+// return:
+//   - error:	エラー
+func (h *dogrunHandler) setWanrunRatingDetail(c echo.Context, detail *dto.DogrunDetail) error {
+	summaries, err := h.rf.GetRatingSummaries(c, []int64{detail.DogrunID})
+	if err != nil {
+		return err
+	}
+	summary, exist := summaries[detail.DogrunID]
+	if !exist {
+		return nil
+	}
+	detail.WanrunRating = summary.AvgRating
+	detail.WanrunRatingCount = summary.ReviewCount
+	detail.WanrunAspectRatings = convertToAspectRatingRes(summary)
+	return nil
+}
+
+/*
+観点別の平均評価をレスポンスに変換。いずれの観点も評価がない場合はnil
+*/
+func convertToAspectRatingRes(summary model.DogrunRatingSummary) *dto.AspectRatingRes {
+	if !summary.AvgCleanliness.Valid && !summary.AvgSize.Valid && !summary.AvgCrowding.Valid {
+		return nil
+	}
+	return &dto.AspectRatingRes{
+		Cleanliness: nullFloat64Ptr(summary.AvgCleanliness),
+		Size:        nullFloat64Ptr(summary.AvgSize),
+		Crowding:    nullFloat64Ptr(summary.AvgCrowding),
+	}
+}
+
+func nullFloat64Ptr(v sql.NullFloat64) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Float64
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/adapters/googleplace"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+// 検索結果の絞り込み
+type searchFilterMatcher struct {
+	filter dto.SearchFilter
+	openAt *time.Time
+}
+
+// newSearchFilterMatcher: 絞り込み条件から判定用の構造体を生成
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.SearchFilter:	絞り込み条件
+//
+// return:
+//   - searchFilterMatcher:	絞り込み判定
+//   - error:	エラー
+func newSearchFilterMatcher(c echo.Context, filter dto.SearchFilter) (searchFilterMatcher, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	matcher := searchFilterMatcher{filter: filter}
+	if filter.OpenAt != "" {
+		openAt, err := time.Parse(time.RFC3339, filter.OpenAt)
+		if err != nil {
+			err = errors.NewWRError(err, "営業日時の形式が不正です", errors.NewDogrunClientErrorEType())
+			logger.Error(err)
+			return searchFilterMatcher{}, err
+		}
+		// 営業時間はローカル時刻で管理しているため合わせる
+		openAt = openAt.In(time.Local)
+		matcher.openAt = &openAt
+	}
+	matcher.filter.Name = strings.ToLower(strings.TrimSpace(filter.Name))
+	return matcher, nil
+}
+
+/*
+google情報, DB情報から作成したドッグランが絞り込み条件に一致するか
+*/
+func (m searchFilterMatcher) match(dogrun dto.DogrunLists, dogrunG googleplace.BaseResource, dogrunD model.Dogrun) bool {
+	f := m.filter
+
+	if len(f.IncludeTags) > 0 {
+		if f.TagMatch == dto.TAG_MATCH_ALL {
+			for _, tagID := range f.IncludeTags {
+				if !slices.Contains(dogrun.DogrunTags, tagID) {
+					return false
+				}
+			}
+		} else if !slices.ContainsFunc(f.IncludeTags, func(tagID int64) bool {
+			return slices.Contains(dogrun.DogrunTags, tagID)
+		}) {
+			return false
+		}
+	}
+	if slices.ContainsFunc(f.ExcludeTags, func(tagID int64) bool {
+		return slices.Contains(dogrun.DogrunTags, tagID)
+	}) {
+		return false
+	}
+	if f.OpenNow && !dogrun.NowOpen {
+		return false
+	}
+	if m.openAt != nil && !resolveOpeningAt(dogrunG, dogrunD, *m.openAt) {
+		return false
+	}
+	if f.MinRating > 0 && dogrun.GoogleRating < f.MinRating {
+		return false
+	}
+	if f.Name != "" && !strings.Contains(strings.ToLower(dogrun.Name), f.Name) {
+		return false
+	}
+	if f.ManagedOnly && !dogrun.IsManaged {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"math"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/dogrun/core/dto"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+)
+
+// sortDogrunLists: 検索結果に基準点からの距離をセットし、条件のキーで並び替える
+// キーが同じ場合は、距離, dogrunID, placeIdの順で並べ、結果を一意にする
+//
+// args:
+//   - dto.SearchAroundRectangleCondition:	検索条件
+//   - []dto.DogrunLists:	検索結果
+//
+// return:
+func sortDogrunLists(condition dto.SearchAroundRectangleCondition, dogrunLists []dto.DogrunLists) {
+	refLat, refLng := resolveSortReference(condition)
+	for i := range dogrunLists {
+		distance := math.Round(util.CalcDistanceMeter(
+			refLat, refLng,
+			dogrunLists[i].Location.Latitude, dogrunLists[i].Location.Longitude,
+		))
+		dogrunLists[i].Distance = &distance
+	}
+
+	key := condition.Sort.Key
+	if key == "" {
+		key = dto.SORT_KEY_DISTANCE
+	}
+	desc := isSortDesc(key, condition.Sort.Order)
+
+	sort.SliceStable(dogrunLists, func(i, j int) bool {
+		a, b := dogrunLists[i], dogrunLists[j]
+		if cmp := compareBySortKey(key, a, b); cmp != 0 {
+			if desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		// タイブレーク
+		if *a.Distance != *b.Distance {
+			return *a.Distance < *b.Distance
+		}
+		if a.DogrunID != b.DogrunID {
+			return a.DogrunID < b.DogrunID
+		}
+		return a.PlaceId < b.PlaceId
+	})
+}
+
+// paginateDogrunLists: カーソルとページサイズで検索結果を切り出す
+// ページサイズの指定がない場合は全件を返す
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.SearchAroundRectangleCondition:	検索条件
+//   - []dto.DogrunLists:	並び替え済みの検索結果
+//
+// return:
+//   - dto.DogrunSearchRes:	ページングした検索結果
+//   - error:	エラー
+func paginateDogrunLists(c echo.Context, condition dto.SearchAroundRectangleCondition, dogrunLists []dto.DogrunLists) (dto.DogrunSearchRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	offset, err := util.DecodeOffsetCursor(condition.Cursor)
+	if err != nil {
+		err = errors.NewWRError(err, "カーソルが不正です", errors.NewDogrunClientErrorEType())
+		logger.Error(err)
+		return dto.DogrunSearchRes{}, err
+	}
+
+	page, nextCursor := paginateSlice(dogrunLists, offset, condition.PageSize)
+	return dto.DogrunSearchRes{
+		Dogruns:    page,
+		TotalCount: len(dogrunLists),
+		NextCursor: nextCursor,
+	}, nil
+}
+
+/*
+並び替えキーでの比較
+a < b の場合は負, a > b の場合は正を返す
+*/
+func compareBySortKey(key string, a, b dto.DogrunLists) int {
+	switch key {
+	case dto.SORT_KEY_RATING:
+		return compareFloat(float64(a.GoogleRating), float64(b.GoogleRating))
+	case dto.SORT_KEY_RATING_COUNT:
+		return a.UserRatingCount - b.UserRatingCount
+	case dto.SORT_KEY_WANRUN_RATING:
+		if cmp := compareFloat(a.WanrunRating, b.WanrunRating); cmp != 0 {
+			return cmp
+		}
+		return compareFloat(float64(a.WanrunRatingCount), float64(b.WanrunRatingCount))
+	case dto.SORT_KEY_NAME:
+		switch {
+		case a.Name < b.Name:
+			return -1
+		case a.Name > b.Name:
+			return 1
+		}
+		return 0
+	case dto.SORT_KEY_OPEN_NOW:
+		// 営業中を小さい(先頭)とする
+		switch {
+		case a.NowOpen == b.NowOpen:
+			return 0
+		case a.NowOpen:
+			return -1
+		}
+		return 1
+	default:
+		return compareFloat(*a.Distance, *b.Distance)
+	}
+}
+
+/*
+並び順が降順か
+未指定の場合、評価・評価数は降順、それ以外は昇順
+*/
+func isSortDesc(key, order string) bool {
+	if order != "" {
+		return order == dto.SORT_ORDER_DESC
+	}
+	return key == dto.SORT_KEY_RATING || key == dto.SORT_KEY_RATING_COUNT || key == dto.SORT_KEY_WANRUN_RATING
+}
+
+/*
+距離の基準点(緯度, 経度)
+未指定の場合は検索範囲(長方形)の中心
+*/
+func resolveSortReference(condition dto.SearchAroundRectangleCondition) (float64, float64) {
+	if condition.Sort.Reference != nil {
+		return condition.Sort.Reference.Latitude, condition.Sort.Reference.Longitude
+	}
+	sw, ne := condition.Target.Southwest, condition.Target.Northeast
+	lng := (sw.Longitude + ne.Longitude) / 2
+	if sw.Longitude > ne.Longitude {
+		// 日付変更線をまたぐ場合
+		lng = (sw.Longitude + ne.Longitude + 360) / 2
+		if lng > 180 {
+			lng -= 360
+		}
+	}
+	return (sw.Latitude + ne.Latitude) / 2, lng
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+/*
+オフセットとページサイズでスライスを切り出し、次のページのカーソルを返す
+ページサイズが0の場合は全件を返す
+*/
+func paginateSlice[T any](items []T, offset, pageSize int) ([]T, string) {
+	total := len(items)
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if pageSize > 0 && offset+pageSize < total {
+		end = offset + pageSize
+	}
+
+	nextCursor := ""
+	if end < total {
+		nextCursor = util.EncodeOffsetCursor(end)
+	}
+	return items[offset:end], nextCursor
+}
@@ -5,12 +5,16 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/wanrun-develop/wanrun/internal/dogrun/adapters/repository"
+	model "github.com/wanrun-develop/wanrun/internal/models"
 	"github.com/wanrun-develop/wanrun/pkg/errors"
 	"github.com/wanrun-develop/wanrun/pkg/log"
 )
 
 type IDogrunFacade interface {
 	CheckDogrunExistByIDs(echo.Context, []int64) error
+	GetDogrun(echo.Context, int64) (model.Dogrun, error)
+	GetDogrunTagIDs(echo.Context, int64) ([]int64, error)
+	GetManagedDogrunIDs(echo.Context, int64) ([]int64, error)
 }
 
 type dogrunFacade struct {
@@ -56,3 +60,62 @@ func (h *dogrunFacade) CheckDogrunExistByIDs(c echo.Context, dogrunIDs []int64)
 	}
 	return nil
 }
+
+// GetDogrun: ドッグランの取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//
+// return:
+//   - model.Dogrun:	ドッグラン(タグ・営業時間を含む)
+//   - error:	エラー。存在しない場合もエラー
+func (h *dogrunFacade) GetDogrun(c echo.Context, dogrunID int64) (model.Dogrun, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrun, err := h.drr.FindDogrunByID(c, dogrunID)
+	if err != nil {
+		return model.Dogrun{}, err
+	}
+	if dogrun.IsEmpty() {
+		err = errors.NewWRError(nil, fmt.Sprintf("指定されたドッグランID:%dが存在しません", dogrunID), errors.NewDogrunClientErrorEType())
+		logger.Error("不正なdogrun idの指定", err)
+		return model.Dogrun{}, err
+	}
+	return dogrun, nil
+}
+
+// GetDogrunTagIDs: ドッグランに設定されているタグIDの取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//
+// return:
+//   - []int64:	タグIDs
+//   - error:	エラー
+func (h *dogrunFacade) GetDogrunTagIDs(c echo.Context, dogrunID int64) ([]int64, error) {
+	dogrun, err := h.GetDogrun(c, dogrunID)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIDs := []int64{}
+	for _, tag := range dogrun.DogrunTags {
+		tagIDs = append(tagIDs, tag.TagID.Int64)
+	}
+	return tagIDs, nil
+}
+
+// GetManagedDogrunIDs: dogrun管理者が管理するドッグランIDの取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunmgID
+//
+// return:
+//   - []int64:	dogrunIDs
+//   - error:	エラー
+func (h *dogrunFacade) GetManagedDogrunIDs(c echo.Context, dogrunmgID int64) ([]int64, error) {
+	return h.drr.FindDogrunIDsByManagerID(c, dogrunmgID)
+}
@@ -1,10 +1,22 @@
 package repository
 
 import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
 	"gorm.io/gorm"
 )
 
 type IDogrunmgRepository interface {
+	GetDogrunmgByID(c echo.Context, dmID int64) (model.Dogrunmg, error)
+	GetStaffsByOrganizationID(c echo.Context, orgID int64) ([]model.DogrunmgCredential, error)
+	CreateInvitation(c echo.Context, inv *model.DogrunmgInvitation) error
+	GetInvitationByTokenHash(c echo.Context, tokenHash string) (model.DogrunmgInvitation, error)
+	DeactivateDogrunmg(c echo.Context, dmID int64) error
+	UpdateDogrunmgAdmin(c echo.Context, dmID int64, isAdmin bool) error
 }
 
 type dogrunmgRepository struct {
@@ -18,3 +30,201 @@ func NewDogrunmgRepository(
 		db: db,
 	}
 }
+
+// GetDogrunmgByID: dogrunmgの取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: dogrunmgのID
+//
+// return:
+//   - model.Dogrunmg: dogrunmgの情報。存在しない場合は空
+//   - error: error情報
+func (dmr *dogrunmgRepository) GetDogrunmgByID(c echo.Context, dmID int64) (model.Dogrunmg, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunmg := model.Dogrunmg{}
+	if err := dmr.db.Where("dogrun_manager_id = ?", dmID).Find(&dogrunmg).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewDogrunmgServerErrorEType(),
+		)
+		logger.Errorf("Failed to get dogrunmg: %v", wrErr)
+		return model.Dogrunmg{}, wrErr
+	}
+	return dogrunmg, nil
+}
+
+// GetStaffsByOrganizationID: organizationに所属するdogrunmgのクレデンシャル一覧の取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: organizationのID
+//
+// return:
+//   - []model.DogrunmgCredential: dogrunmgのクレデンシャル情報(AuthDogrunmg, Dogrunmgを含む)
+//   - error: error情報
+func (dmr *dogrunmgRepository) GetStaffsByOrganizationID(c echo.Context, orgID int64) ([]model.DogrunmgCredential, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	var results []model.DogrunmgCredential
+	if err := dmr.db.Model(&model.DogrunmgCredential{}).
+		Preload("AuthDogrunmg").
+		Preload("AuthDogrunmg.Dogrunmg").
+		Joins("JOIN auth_dogrun_managers ON auth_dogrun_managers.auth_dogrun_manager_id = dogrun_manager_credentials.auth_dogrun_manager_id").
+		Joins("JOIN dogrun_managers ON dogrun_managers.dogrun_manager_id = auth_dogrun_managers.dogrun_manager_id").
+		Where("dogrun_managers.organization_id = ?", orgID).
+		Order("dogrun_managers.dogrun_manager_id").
+		Find(&results).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewDogrunmgServerErrorEType(),
+		)
+		logger.Errorf("Failed to get staffs: %v", wrErr)
+		return nil, wrErr
+	}
+	return results, nil
+}
+
+// CreateInvitation: スタッフ招待の登録
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - *model.DogrunmgInvitation: 招待情報
+//
+// return:
+//   - error: error情報
+func (dmr *dogrunmgRepository) CreateInvitation(c echo.Context, inv *model.DogrunmgInvitation) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := dmr.db.Create(inv).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"スタッフ招待の登録に失敗しました。",
+			wrErrors.NewDogrunmgServerErrorEType(),
+		)
+		logger.Errorf("Failed to create invitation: %v", wrErr)
+		return wrErr
+	}
+	return nil
+}
+
+// GetInvitationByTokenHash: 招待トークンのハッシュ値から招待情報を取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - string: 招待トークンのハッシュ値
+//
+// return:
+//   - model.DogrunmgInvitation: 招待情報。存在しない場合は空
+//   - error: error情報
+func (dmr *dogrunmgRepository) GetInvitationByTokenHash(c echo.Context, tokenHash string) (model.DogrunmgInvitation, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	invitation := model.DogrunmgInvitation{}
+	if err := dmr.db.Where("token_hash = ?", tokenHash).Find(&invitation).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"DBからのデータ取得に失敗しました。",
+			wrErrors.NewDogrunmgServerErrorEType(),
+		)
+		logger.Errorf("Failed to get invitation: %v", wrErr)
+		return model.DogrunmgInvitation{}, wrErr
+	}
+	return invitation, nil
+}
+
+// DeactivateDogrunmg: dogrunmgの無効化
+// セッションも失効させ、発行済みのトークンを無効にする
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: dogrunmgのID
+//
+// return:
+//   - error: error情報
+func (dmr *dogrunmgRepository) DeactivateDogrunmg(c echo.Context, dmID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := dmr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.AuthDogrunmg{}).
+			Where("dogrun_manager_id = ?", dmID).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+		return revokeDogrunmgSessions(tx, dmID)
+	}); err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"スタッフの無効化に失敗しました。",
+			wrErrors.NewDogrunmgServerErrorEType(),
+		)
+		logger.Errorf("Failed to deactivate dogrunmg: %v", wrErr)
+		return wrErr
+	}
+
+	// 更新日時の更新
+	if err := dmr.db.Model(&model.Dogrunmg{}).
+		Where("dogrun_manager_id = ?", dmID).
+		Update("upd_at", time.Now()).Error; err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"スタッフの無効化に失敗しました。",
+			wrErrors.NewDogrunmgServerErrorEType(),
+		)
+		logger.Errorf("Failed to update dogrunmg: %v", wrErr)
+		return wrErr
+	}
+	return nil
+}
+
+// UpdateDogrunmgAdmin: dogrunmgのadmin権限の変更
+// ロールはjwtに含まれるため、セッションを失効させて再ログインさせる
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: dogrunmgのID
+//   - bool: adminにするか
+//
+// return:
+//   - error: error情報
+func (dmr *dogrunmgRepository) UpdateDogrunmgAdmin(c echo.Context, dmID int64, isAdmin bool) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := dmr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.AuthDogrunmg{}).
+			Where("dogrun_manager_id = ?", dmID).
+			Update("is_admin", isAdmin).Error; err != nil {
+			return err
+		}
+		return revokeDogrunmgSessions(tx, dmID)
+	}); err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"スタッフの権限変更に失敗しました。",
+			wrErrors.NewDogrunmgServerErrorEType(),
+		)
+		logger.Errorf("Failed to update dogrunmg admin: %v", wrErr)
+		return wrErr
+	}
+	return nil
+}
+
+/*
+dogrunmgの未失効のセッションとリフレッシュトークンを失効
+*/
+func revokeDogrunmgSessions(tx *gorm.DB, dmID int64) error {
+	now := time.Now()
+	if err := tx.Model(&model.AuthSession{}).
+		Where("dogrun_manager_id = ?", dmID).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.RefreshToken{}).
+		Where("dogrun_manager_id = ?", dmID).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", now).Error
+}
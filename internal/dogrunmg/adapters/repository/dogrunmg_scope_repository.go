@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	model "github.com/wanrun-develop/wanrun/internal/models"
@@ -12,6 +13,7 @@ import (
 
 type IDogrunmgScopeRepository interface {
 	CreateDogrunmg(tx *gorm.DB, c echo.Context, adm *model.Dogrunmg) (sql.NullInt64, error)
+	UseInvitation(tx *gorm.DB, c echo.Context, invitationID int64) error
 }
 
 type dogrunmgScopeRepository struct {
@@ -52,3 +54,46 @@ func (dmsr *dogrunmgScopeRepository) CreateDogrunmg(
 
 	return dm.DogrunmgID, nil
 }
+
+// UseInvitation: 招待を使用済みにする
+// 未使用の招待のみ更新し、更新できなかった場合(使用済み)はエラーとする
+//
+// args:
+//   - *gorm.DB: トランザクションを張っているtx情報
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: 招待ID
+//
+// return:
+//   - error: error情報
+func (dmsr *dogrunmgScopeRepository) UseInvitation(
+	tx *gorm.DB,
+	c echo.Context,
+	invitationID int64,
+) error {
+	logger := log.GetLogger(c).Sugar()
+
+	result := tx.Model(&model.DogrunmgInvitation{}).
+		Where("invitation_id = ? AND used_at IS NULL", invitationID).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		logger.Error("Failed to use invitation: ", result.Error)
+		return wrErrors.NewWRError(
+			result.Error,
+			"招待の更新に失敗しました。",
+			wrErrors.NewDogrunmgServerErrorEType(),
+		)
+	}
+
+	if result.RowsAffected == 0 {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			"この招待は既に使用されています。",
+			wrErrors.NewDogrunmgClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	return nil
+}
@@ -1,12 +1,23 @@
 package controller
 
 import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/dogrunmg/core/dto"
 	dogrunmgHandler "github.com/wanrun-develop/wanrun/internal/dogrunmg/core/handler"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
 )
 
 type IDogrunmgController interface {
 	DogrunmgSignUp(c echo.Context) error
+	InviteDogrunmg(c echo.Context) error
+	GetStaffs(c echo.Context) error
+	DeactivateStaff(c echo.Context) error
+	UpdateStaffRole(c echo.Context) error
 }
 
 type dogrunmgController struct {
@@ -21,13 +32,195 @@ func NewDogrunmgController(
 	}
 }
 
-// / DogrunmgSignUp: dogrunmanagerの登録処理
+// DogrunmgSignUp: 招待トークンを使ったdogrunmanagerの登録処理
 //
 // args:
-//   - echo.Context:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
 //
 // return:
 //   - error: error情報
 func (dmc *dogrunmgController) DogrunmgSignUp(c echo.Context) error {
-	return nil
+	logger := log.GetLogger(c).Sugar()
+
+	dmReq := dto.DogrunmgReq{}
+
+	if err := c.Bind(&dmReq); err != nil {
+		wrErr := errors.NewWRError(
+			err,
+			"入力項目に不正があります。",
+			errors.NewDogrunmgClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	// バリデータのインスタンス作成
+	validate := validator.New()
+
+	//リクエストボディのバリデーション
+	if err := validate.Struct(&dmReq); err != nil {
+		err = errors.NewWRError(
+			err,
+			"必須の項目に不正があります。",
+			errors.NewDogrunmgClientErrorEType(),
+		)
+		logger.Error(err)
+		return err
+	}
+
+	// dogrunmgのSignUp
+	res, wrErr := dmc.dm.DogrunmgSignUp(c, dmReq)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// InviteDogrunmg: organizationへのスタッフ招待
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//
+// return:
+//   - error: error情報
+func (dmc *dogrunmgController) InviteDogrunmg(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	inviteReq := dto.DogrunmgInviteReq{}
+
+	if err := c.Bind(&inviteReq); err != nil {
+		wrErr := errors.NewWRError(
+			err,
+			"入力項目に不正があります。",
+			errors.NewDogrunmgClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	// バリデータのインスタンス作成
+	validate := validator.New()
+
+	//リクエストボディのバリデーション
+	if err := validate.Struct(&inviteReq); err != nil {
+		err = errors.NewWRError(
+			err,
+			"必須の項目に不正があります。",
+			errors.NewDogrunmgClientErrorEType(),
+		)
+		logger.Error(err)
+		return err
+	}
+
+	inviteRes, wrErr := dmc.dm.InviteDogrunmg(c, inviteReq)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	return c.JSON(http.StatusCreated, inviteRes)
+}
+
+// GetStaffs: 同じorganizationのスタッフ一覧の取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//
+// return:
+//   - error: error情報
+func (dmc *dogrunmgController) GetStaffs(c echo.Context) error {
+	staffs, wrErr := dmc.dm.GetStaffs(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	return c.JSON(http.StatusOK, staffs)
+}
+
+// DeactivateStaff: スタッフの無効化
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//
+// return:
+//   - error: error情報
+func (dmc *dogrunmgController) DeactivateStaff(c echo.Context) error {
+	dmID, wrErr := parseDogrunmgIDParam(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	if wrErr := dmc.dm.DeactivateStaff(c, dmID); wrErr != nil {
+		return wrErr
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// UpdateStaffRole: スタッフのadmin権限の付与・剥奪
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//
+// return:
+//   - error: error情報
+func (dmc *dogrunmgController) UpdateStaffRole(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	dmID, wrErr := parseDogrunmgIDParam(c)
+
+	if wrErr != nil {
+		return wrErr
+	}
+
+	roleReq := dto.DogrunmgRoleReq{}
+
+	if err := c.Bind(&roleReq); err != nil {
+		wrErr := errors.NewWRError(
+			err,
+			"入力項目に不正があります。",
+			errors.NewDogrunmgClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return wrErr
+	}
+
+	// バリデータのインスタンス作成
+	validate := validator.New()
+
+	//リクエストボディのバリデーション
+	if err := validate.Struct(&roleReq); err != nil {
+		err = errors.NewWRError(
+			err,
+			"必須の項目に不正があります。",
+			errors.NewDogrunmgClientErrorEType(),
+		)
+		logger.Error(err)
+		return err
+	}
+
+	if wrErr := dmc.dm.UpdateStaffRole(c, dmID, roleReq); wrErr != nil {
+		return wrErr
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+/*
+パスパラメータのdogrunmgIDの取得とバリデーション
+*/
+func parseDogrunmgIDParam(c echo.Context) (int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dmID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || dmID <= 0 {
+		wrErr := errors.NewWRError(err, errors.M_REQUEST_PARAM_MUST_BE_NATURAL, errors.NewDogrunmgClientErrorEType())
+		logger.Error(wrErr)
+		return 0, wrErr
+	}
+	return dmID, nil
 }
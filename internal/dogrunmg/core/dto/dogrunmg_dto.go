@@ -1,8 +1,36 @@
 package dto
 
+import "github.com/wanrun-develop/wanrun/common"
+
+// 招待トークンを使ったdogrunmgの登録用
 type DogrunmgReq struct {
-	Password     string `json:"password" validate:"required"`
-	DogrunmgName string `json:"dogrunmgName" validate:"required"`
-	Email        string `json:"email"`
-	PhoneNumber  string `json:"phoneNumber"`
+	InvitationToken string `json:"invitationToken" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	DogrunmgName    string `json:"dogrunmgName" validate:"required,max=128"`
+}
+
+// スタッフ招待リクエスト
+type DogrunmgInviteReq struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+// スタッフ招待レスポンス
+type DogrunmgInviteRes struct {
+	Email     string        `json:"email"`
+	ExpiresAt common.WRTime `json:"expiresAt"`
+}
+
+// スタッフの権限変更リクエスト
+type DogrunmgRoleReq struct {
+	IsAdmin *bool `json:"isAdmin" validate:"required"`
+}
+
+// スタッフ一覧レスポンス
+type DogrunmgStaffRes struct {
+	DogrunmgID int64         `json:"dogrunmgId"`
+	Name       string        `json:"name"`
+	Email      string        `json:"email"`
+	IsAdmin    bool          `json:"isAdmin"`
+	IsActive   bool          `json:"isActive"`
+	CreateAt   common.WRTime `json:"createAt"`
 }
@@ -1,11 +1,53 @@
 package facade
 
+import (
+	"github.com/labstack/echo/v4"
+	dogrunmgRepository "github.com/wanrun-develop/wanrun/internal/dogrunmg/adapters/repository"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
 type IDogrunmgFacade interface {
+	GetOrganizationID(c echo.Context, dmID int64) (int64, error)
 }
 
 type dogrunmgFacade struct {
+	dmr dogrunmgRepository.IDogrunmgRepository
+}
+
+func NewDogrunmgFacade(dmr dogrunmgRepository.IDogrunmgRepository) IDogrunmgFacade {
+	return &dogrunmgFacade{
+		dmr: dmr,
+	}
 }
 
-func NewDogrunmgFacade() IDogrunmgFacade {
-	return &dogrunmgFacade{}
+// GetOrganizationID: dogrunmgが所属するorganizationのIDの取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: dogrunmgのID
+//
+// return:
+//   - int64: organizationのID
+//   - error: error情報
+func (dmf *dogrunmgFacade) GetOrganizationID(c echo.Context, dmID int64) (int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunmg, wrErr := dmf.dmr.GetDogrunmgByID(c, dmID)
+
+	if wrErr != nil {
+		return 0, wrErr
+	}
+
+	if dogrunmg.IsEmpty() {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			"指定されたdogrunmgは存在しません。",
+			wrErrors.NewDogrunmgClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return 0, wrErr
+	}
+
+	return dogrunmg.OrganizationID.Int64, nil
 }
@@ -1,21 +1,455 @@
 package handler
 
 import (
+	"fmt"
+	"net/url"
+	"time"
+
 	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/common"
+	"github.com/wanrun-develop/wanrun/configs"
+	"github.com/wanrun-develop/wanrun/internal/auth/adapters/mailer"
+	authRepository "github.com/wanrun-develop/wanrun/internal/auth/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/auth/core"
+	authDTO "github.com/wanrun-develop/wanrun/internal/auth/core/dto"
+	authFacade "github.com/wanrun-develop/wanrun/internal/auth/core/facade"
+	authHandler "github.com/wanrun-develop/wanrun/internal/auth/core/handler"
+	dogrunmgRepository "github.com/wanrun-develop/wanrun/internal/dogrunmg/adapters/repository"
 	"github.com/wanrun-develop/wanrun/internal/dogrunmg/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/internal/transaction"
+	"github.com/wanrun-develop/wanrun/internal/wrcontext"
+	wrErrors "github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	wrUtil "github.com/wanrun-develop/wanrun/pkg/util"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	INVITATION_TOKEN_BYTES  = 32 // 招待トークンのバイト数
+	INVITATION_MAIL_SUBJECT = "【WanRun】ドッグラン管理者への招待"
 )
 
 type IDogrunmgHandler interface {
-	DogrunmgSignUp(c echo.Context, doReq dto.DogrunmgReq) (string, error)
+	DogrunmgSignUp(c echo.Context, dmReq dto.DogrunmgReq) (authDTO.AuthTokenRes, error)
+	InviteDogrunmg(c echo.Context, inviteReq dto.DogrunmgInviteReq) (dto.DogrunmgInviteRes, error)
+	GetStaffs(c echo.Context) ([]dto.DogrunmgStaffRes, error)
+	DeactivateStaff(c echo.Context, dmID int64) error
+	UpdateStaffRole(c echo.Context, dmID int64, roleReq dto.DogrunmgRoleReq) error
 }
 
 type dogrunmgHandler struct {
+	dmr  dogrunmgRepository.IDogrunmgRepository
+	dmsr dogrunmgRepository.IDogrunmgScopeRepository
+	asr  authRepository.IAuthScopeRepository
+	tm   transaction.ITransactionManager
+	af   authFacade.IAuthFacade
+	m    mailer.IMailer
+}
+
+func NewDogrunmgHandler(
+	dmr dogrunmgRepository.IDogrunmgRepository,
+	dmsr dogrunmgRepository.IDogrunmgScopeRepository,
+	asr authRepository.IAuthScopeRepository,
+	tm transaction.ITransactionManager,
+	af authFacade.IAuthFacade,
+	m mailer.IMailer,
+) IDogrunmgHandler {
+	return &dogrunmgHandler{
+		dmr:  dmr,
+		dmsr: dmsr,
+		asr:  asr,
+		tm:   tm,
+		af:   af,
+		m:    m,
+	}
 }
 
-func NewDogOwnerHandler() IDogrunmgHandler {
-	return &dogrunmgHandler{}
+// DogrunmgSignUp: 招待トークンを使ったdogrunmgの登録
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - dto.DogrunmgReq: 登録内容
+//
+// return:
+//   - authDTO.AuthTokenRes: 署名済みのjwtとリフレッシュトークン
+//   - error: error情報
+func (dmh *dogrunmgHandler) DogrunmgSignUp(c echo.Context, dmReq dto.DogrunmgReq) (authDTO.AuthTokenRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	// 招待の有効性チェック
+	invitation, wrErr := dmh.dmr.GetInvitationByTokenHash(c, wrUtil.HashToken(dmReq.InvitationToken))
+
+	if wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	if invitation.IsEmpty() || invitation.IsUsed() || invitation.IsExpired(time.Now()) {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			"招待が無効です。使用済みか有効期限が切れています。",
+			wrErrors.NewDogrunmgClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	// 招待後に同じEmailで登録されていないか
+	if wrErr := dmh.af.OrgEmailValidate(c, invitation.Email.String); wrErr != nil {
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	// パスワードのハッシュ化
+	hash, err := bcrypt.GenerateFromPassword([]byte(dmReq.Password), bcrypt.DefaultCost) // 一旦costをデフォルト値
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"パスワードに不正な文字列が入っています。",
+			wrErrors.NewDogrunmgClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return authDTO.AuthTokenRes{}, wrErr
+	}
+
+	// requestからdogrunmgの構造体に詰め替え
+	dogrunmgInfo := model.DogrunmgCredential{
+		Email:    invitation.Email,
+		Password: wrUtil.NewSqlNullString(string(hash)),
+		AuthDogrunmg: model.AuthDogrunmg{
+			IsAdmin: wrUtil.NewSqlNullBool(false), // 招待されたスタッフは一般
+			Dogrunmg: model.Dogrunmg{
+				Name:           wrUtil.NewSqlNullString(dmReq.DogrunmgName),
+				OrganizationID: invitation.OrganizationID,
+			},
+		},
+	}
+
+	ctx := c.Request().Context()
+
+	// 発行したリフレッシュトークン(平文)とセッション
+	var refreshToken string
+	var session model.AuthSession
+
+	// dogrunmgの作成トランザクション
+	if err := dmh.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
+
+		// 招待を使用済みにする(同時に使用された場合はここで弾く)
+		if wrErr := dmh.dmsr.UseInvitation(tx, c, invitation.InvitationID.Int64); wrErr != nil {
+			return wrErr
+		}
+
+		// dogrunmgの作成
+		dmID, wrErr := dmh.dmsr.CreateDogrunmg(tx, c, &dogrunmgInfo.AuthDogrunmg.Dogrunmg)
+
+		if wrErr != nil {
+			return wrErr
+		}
+
+		// Dogrunmgが作成された後、そのIDをauthDogrunmgに設定
+		dogrunmgInfo.AuthDogrunmg.DogrunmgID = dmID
+
+		// AuthDogrunmgの作成
+		admID, wrErr := dmh.asr.CreateAuthDogrunmg(tx, c, &dogrunmgInfo.AuthDogrunmg)
+
+		if wrErr != nil {
+			return wrErr
+		}
+
+		// AuthDogrunmgが作成された後、そのIDをdogrunmgCredentialに設定
+		dogrunmgInfo.AuthDogrunmgID = admID
+
+		// DogrunmgのCredentialsの作成
+		if wrErr := dmh.asr.CreateDogrunmgCredential(tx, c, &dogrunmgInfo); wrErr != nil {
+			return wrErr
+		}
+
+		// ログインセッションとリフレッシュトークンを作成
+		token, as, rt, wrErr := authHandler.NewLoginSession(c, authDTO.UserAuthInfoDTO{
+			UserID: dmID.Int64,
+			RoleID: core.DOGRUNMG_ROLE,
+		}, "")
+
+		if wrErr != nil {
+			return wrErr
+		}
+
+		if wrErr := dmh.asr.CreateAuthSession(tx, c, &as); wrErr != nil {
+			return wrErr
+		}
+
+		if wrErr := dmh.asr.CreateRefreshToken(tx, c, &rt); wrErr != nil {
+			return wrErr
+		}
+		refreshToken = token
+		session = as
+
+		// 正常に完了
+		return nil
+
+	}); err != nil {
+		logger.Error("Transaction failed:", err)
+		return authDTO.AuthTokenRes{}, err
+	}
+
+	// 作成したdogrunmgの情報をdto詰め替え
+	dogrunmgDetail := authDTO.UserAuthInfoDTO{
+		UserID: dogrunmgInfo.AuthDogrunmg.DogrunmgID.Int64,
+		JwtID:  session.JwtID.String,
+		RoleID: core.DOGRUNMG_ROLE,
+	}
+
+	logger.Infof("dogrunmgDetail: %v", dogrunmgDetail)
+
+	// メールアドレスの確認を送信(失敗してもサインアップは完了させる)
+	dmh.af.SendSignUpVerification(c, dogrunmgDetail)
+
+	// 署名済みのjwt token取得
+	return authHandler.NewAuthTokenRes(c, dogrunmgDetail, refreshToken)
 }
 
-func (dmh *dogrunmgHandler) DogrunmgSignUp(c echo.Context, doReq dto.DogrunmgReq) (string, error) {
-	return "", nil
+// InviteDogrunmg: organizationへのスタッフ招待
+// 一度だけ使用できる有効期限付きの招待トークンを発行し、登録画面のURLを招待先にメールで送信する
+// トークンはメールでのみ送信し、DBにはハッシュ値のみ保存する
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - dto.DogrunmgInviteReq: 招待内容
+//
+// return:
+//   - dto.DogrunmgInviteRes: 招待情報
+//   - error: error情報
+func (dmh *dogrunmgHandler) InviteDogrunmg(c echo.Context, inviteReq dto.DogrunmgInviteReq) (dto.DogrunmgInviteRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	admin, wrErr := dmh.getLoginDogrunmg(c)
+
+	if wrErr != nil {
+		return dto.DogrunmgInviteRes{}, wrErr
+	}
+
+	// 登録済みのEmailでないか
+	if wrErr := dmh.af.OrgEmailValidate(c, inviteReq.Email); wrErr != nil {
+		return dto.DogrunmgInviteRes{}, wrErr
+	}
+
+	// 招待トークンの生成
+	token, err := wrUtil.GenerateRandomToken(INVITATION_TOKEN_BYTES)
+
+	if err != nil {
+		wrErr := wrErrors.NewWRError(
+			err,
+			"招待トークンの生成に失敗しました。",
+			wrErrors.NewDogrunmgServerErrorEType(),
+		)
+		logger.Error(wrErr)
+		return dto.DogrunmgInviteRes{}, wrErr
+	}
+
+	expiresAt := time.Now().Add(time.Duration(configs.FetchConfigInt("dogrunmg.invitation.exp.hour")) * time.Hour)
+
+	// トークンはハッシュ化して保存
+	invitation := model.DogrunmgInvitation{
+		OrganizationID: admin.OrganizationID,
+		InvitedBy:      admin.DogrunmgID,
+		Email:          wrUtil.NewSqlNullString(inviteReq.Email),
+		TokenHash:      wrUtil.NewSqlNullString(wrUtil.HashToken(token)),
+		ExpiresAt:      wrUtil.NewSqlNullTime(expiresAt),
+	}
+
+	if wrErr := dmh.dmr.CreateInvitation(c, &invitation); wrErr != nil {
+		return dto.DogrunmgInviteRes{}, wrErr
+	}
+
+	signUpURL := fmt.Sprintf("%s?token=%s", configs.FetchConfigStr("dogrunmg.invitation.url"), url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"WanRunのドッグラン管理者として招待されました。\n\n"+
+			"以下のURLから、%sまでに登録してください。\n%s\n\n"+
+			"このメールに心当たりがない場合は、破棄してください。\n",
+		expiresAt.Format("2006/01/02 15:04"),
+		signUpURL,
+	)
+
+	if wrErr := dmh.m.Send(c, mailer.Mail{
+		To:      inviteReq.Email,
+		Subject: INVITATION_MAIL_SUBJECT,
+		Body:    body,
+	}); wrErr != nil {
+		return dto.DogrunmgInviteRes{}, wrErr
+	}
+
+	logger.Infof("dogrunmg %d が %s をorganization %d に招待", admin.DogrunmgID.Int64, inviteReq.Email, admin.OrganizationID.Int64)
+
+	return dto.DogrunmgInviteRes{
+		Email:     inviteReq.Email,
+		ExpiresAt: common.WRTime{Time: expiresAt},
+	}, nil
+}
+
+// GetStaffs: ログインユーザーと同じorganizationのスタッフ一覧の取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//
+// return:
+//   - []dto.DogrunmgStaffRes: スタッフ一覧
+//   - error: error情報
+func (dmh *dogrunmgHandler) GetStaffs(c echo.Context) ([]dto.DogrunmgStaffRes, error) {
+	admin, wrErr := dmh.getLoginDogrunmg(c)
+
+	if wrErr != nil {
+		return nil, wrErr
+	}
+
+	staffs, wrErr := dmh.dmr.GetStaffsByOrganizationID(c, admin.OrganizationID.Int64)
+
+	if wrErr != nil {
+		return nil, wrErr
+	}
+
+	staffsRes := []dto.DogrunmgStaffRes{}
+	for _, s := range staffs {
+		staffsRes = append(staffsRes, dto.DogrunmgStaffRes{
+			DogrunmgID: s.AuthDogrunmg.DogrunmgID.Int64,
+			Name:       s.AuthDogrunmg.Dogrunmg.Name.String,
+			Email:      s.Email.String,
+			IsAdmin:    s.AuthDogrunmg.IsAdmin.Bool,
+			IsActive:   !s.AuthDogrunmg.IsDeactivated(),
+			CreateAt:   common.WRTime{Time: s.AuthDogrunmg.Dogrunmg.CreateAt.Time},
+		})
+	}
+
+	return staffsRes, nil
+}
+
+// DeactivateStaff: スタッフの無効化
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: 対象のdogrunmgのID
+//
+// return:
+//   - error: error情報
+func (dmh *dogrunmgHandler) DeactivateStaff(c echo.Context, dmID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if _, wrErr := dmh.getStaffInSameOrg(c, dmID); wrErr != nil {
+		return wrErr
+	}
+
+	if wrErr := dmh.dmr.DeactivateDogrunmg(c, dmID); wrErr != nil {
+		return wrErr
+	}
+
+	logger.Infof("dogrunmg %d を無効化", dmID)
+	return nil
+}
+
+// UpdateStaffRole: スタッフのadmin権限の付与・剥奪
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: 対象のdogrunmgのID
+//   - dto.DogrunmgRoleReq: 変更内容
+//
+// return:
+//   - error: error情報
+func (dmh *dogrunmgHandler) UpdateStaffRole(c echo.Context, dmID int64, roleReq dto.DogrunmgRoleReq) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if _, wrErr := dmh.getStaffInSameOrg(c, dmID); wrErr != nil {
+		return wrErr
+	}
+
+	if wrErr := dmh.dmr.UpdateDogrunmgAdmin(c, dmID, *roleReq.IsAdmin); wrErr != nil {
+		return wrErr
+	}
+
+	logger.Infof("dogrunmg %d のadmin権限を %t に変更", dmID, *roleReq.IsAdmin)
+	return nil
+}
+
+// getLoginDogrunmg: ログインしているdogrunmgの取得
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//
+// return:
+//   - model.Dogrunmg: dogrunmgの情報
+//   - error: error情報
+func (dmh *dogrunmgHandler) getLoginDogrunmg(c echo.Context) (model.Dogrunmg, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	loginID, wrErr := wrcontext.GetLoginUserID(c)
+
+	if wrErr != nil {
+		return model.Dogrunmg{}, wrErr
+	}
+
+	dogrunmg, wrErr := dmh.dmr.GetDogrunmgByID(c, loginID)
+
+	if wrErr != nil {
+		return model.Dogrunmg{}, wrErr
+	}
+
+	if dogrunmg.IsEmpty() {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			"ログインユーザーが存在しません。",
+			wrErrors.NewAuthClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return model.Dogrunmg{}, wrErr
+	}
+
+	return dogrunmg, nil
+}
+
+// getStaffInSameOrg: ログインユーザーと同じorganizationのスタッフの取得
+// 自分自身の変更はorganizationにadminがいなくなる可能性があるため不可
+//
+// args:
+//   - echo.Context: Echoのコンテキスト。リクエストやレスポンスにアクセスするために使用
+//   - int64: 対象のdogrunmgのID
+//
+// return:
+//   - model.Dogrunmg: 対象のdogrunmgの情報
+//   - error: error情報
+func (dmh *dogrunmgHandler) getStaffInSameOrg(c echo.Context, dmID int64) (model.Dogrunmg, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	admin, wrErr := dmh.getLoginDogrunmg(c)
+
+	if wrErr != nil {
+		return model.Dogrunmg{}, wrErr
+	}
+
+	if admin.DogrunmgID.Int64 == dmID {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			"自分自身の権限は変更できません。",
+			wrErrors.NewDogrunmgClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return model.Dogrunmg{}, wrErr
+	}
+
+	staff, wrErr := dmh.dmr.GetDogrunmgByID(c, dmID)
+
+	if wrErr != nil {
+		return model.Dogrunmg{}, wrErr
+	}
+
+	if staff.IsEmpty() || staff.OrganizationID.Int64 != admin.OrganizationID.Int64 {
+		wrErr := wrErrors.NewWRError(
+			nil,
+			"指定されたスタッフは存在しません。",
+			wrErrors.NewDogrunmgClientErrorEType(),
+		)
+		logger.Error(wrErr)
+		return model.Dogrunmg{}, wrErr
+	}
+
+	return staff, nil
 }
@@ -0,0 +1,54 @@
+package eventhub
+
+import (
+	"context"
+	"sync"
+)
+
+// IEventBroker: イベントのファンアウトを担うブローカー
+// 発行されたイベントを、購読している全インスタンスのdeliverに届ける
+type IEventBroker interface {
+	Start(deliver func([]byte)) error
+	Publish(context.Context, []byte) error
+	Close() error
+}
+
+type localBroker struct {
+	mu      sync.RWMutex
+	deliver func([]byte)
+}
+
+// NewLocalBroker: プロセス内で完結するブローカーの生成
+// 単一インスタンスで稼働する場合に使用する
+//
+// return:
+//   - IEventBroker:	ブローカー
+func NewLocalBroker() IEventBroker {
+	return &localBroker{}
+}
+
+// Start: 受信先の登録
+func (b *localBroker) Start(deliver func([]byte)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deliver = deliver
+	return nil
+}
+
+// Publish: 受信先へそのまま配信
+func (b *localBroker) Publish(_ context.Context, payload []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.deliver != nil {
+		b.deliver(payload)
+	}
+	return nil
+}
+
+// Close: 受信先の解除
+func (b *localBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deliver = nil
+	return nil
+}
@@ -0,0 +1,37 @@
+package eventhub
+
+import (
+	"time"
+)
+
+// イベント種別
+const (
+	EVENT_TYPE_CHECKIN   = "checkin"   // チェックイン
+	EVENT_TYPE_CHECKOUT  = "checkout"  // チェックアウト
+	EVENT_TYPE_OCCUPANCY = "occupancy" // 混雑状況の変化
+)
+
+// DogrunEvent: ドッグランごとに配信するイベント
+type DogrunEvent struct {
+	Type       string          `json:"type"`
+	DogrunID   int64           `json:"dogrun_id"`
+	DogIDs     []int64         `json:"dog_ids,omitempty"`   // checkin・checkoutのみ
+	System     bool            `json:"system,omitempty"`    // 自動チェックアウトなどシステムによるものか
+	Occupancy  *EventOccupancy `json:"occupancy,omitempty"` // occupancyのみ
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// EventOccupancy: 滞在中のdog数(サイズ区分別)
+type EventOccupancy struct {
+	Total   int64 `json:"total"`
+	Small   int64 `json:"small"`
+	Medium  int64 `json:"medium"`
+	Large   int64 `json:"large"`
+	Unknown int64 `json:"unknown"`
+}
+
+// isDogActivity: dogの入退場のイベントであるか
+// dogIDを含むため、閲覧できる購読者を限定する
+func (e DogrunEvent) isDogActivity() bool {
+	return e.Type == EVENT_TYPE_CHECKIN || e.Type == EVENT_TYPE_CHECKOUT
+}
@@ -0,0 +1,152 @@
+package eventhub
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+// IEventHub: ドッグランのイベントの配信ハブ
+// 購読者の管理はプロセス内で行い、インスタンス間の配信はIEventBrokerに任せる
+type IEventHub interface {
+	Publish(context.Context, DogrunEvent) error
+	Subscribe(SubscribeFilter) *Subscription
+	Close() error
+}
+
+// SubscribeFilter: 購読するイベントの条件
+type SubscribeFilter struct {
+	DogrunIDs     []int64 // 購読するドッグラン
+	OccupancyOnly bool    // trueの場合はdogの入退場イベントを受け取らない
+}
+
+// Subscription: イベントの購読
+type Subscription struct {
+	Events <-chan DogrunEvent
+
+	events        chan DogrunEvent
+	dogrunIDs     map[int64]struct{}
+	occupancyOnly bool
+	hub           *eventHub
+	closeOnce     sync.Once
+}
+
+// Close: 購読の終了
+func (s *Subscription) Close() {
+	s.closeOnce.Do(func() {
+		s.hub.unsubscribe(s)
+	})
+}
+
+// accepts: 購読条件に一致するイベントか
+func (s *Subscription) accepts(event DogrunEvent) bool {
+	if _, ok := s.dogrunIDs[event.DogrunID]; !ok {
+		return false
+	}
+	return !(s.occupancyOnly && event.isDogActivity())
+}
+
+type eventHub struct {
+	broker        IEventBroker
+	bufferSize    int
+	mu            sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+}
+
+// NewEventHub: イベントハブの生成と、ブローカーからの受信開始
+//
+// args:
+//   - IEventBroker:	インスタンス間の配信を担うブローカー
+//   - int:	購読者ごとのバッファサイズ
+//
+// return:
+//   - IEventHub:	イベントハブ
+//   - error:	エラー
+func NewEventHub(broker IEventBroker, bufferSize int) (IEventHub, error) {
+	h := &eventHub{
+		broker:        broker,
+		bufferSize:    bufferSize,
+		subscriptions: make(map[*Subscription]struct{}),
+	}
+	if err := broker.Start(h.deliver); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Publish: イベントの発行
+func (h *eventHub) Publish(ctx context.Context, event DogrunEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return h.broker.Publish(ctx, payload)
+}
+
+// Subscribe: イベントの購読開始
+func (h *eventHub) Subscribe(filter SubscribeFilter) *Subscription {
+	events := make(chan DogrunEvent, h.bufferSize)
+	s := &Subscription{
+		Events:        events,
+		events:        events,
+		dogrunIDs:     make(map[int64]struct{}),
+		occupancyOnly: filter.OccupancyOnly,
+		hub:           h,
+	}
+	for _, dogrunID := range filter.DogrunIDs {
+		s.dogrunIDs[dogrunID] = struct{}{}
+	}
+
+	h.mu.Lock()
+	h.subscriptions[s] = struct{}{}
+	h.mu.Unlock()
+	return s
+}
+
+// Close: ブローカーの停止と全購読の終了
+func (h *eventHub) Close() error {
+	h.mu.Lock()
+	for s := range h.subscriptions {
+		delete(h.subscriptions, s)
+		close(s.events)
+	}
+	h.mu.Unlock()
+	return h.broker.Close()
+}
+
+// unsubscribe: 購読の削除
+func (h *eventHub) unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscriptions[s]; ok {
+		delete(h.subscriptions, s)
+		close(s.events)
+	}
+}
+
+// deliver: ブローカーから受信したイベントを購読者に配信
+// 受信が追いつかない購読者へのイベントは破棄し、他の購読者を待たせない
+func (h *eventHub) deliver(payload []byte) {
+	logger := log.GetBaseLogger().Sugar()
+
+	var event DogrunEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		logger.Errorf("ドッグランイベントの変換に失敗: %v", err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for s := range h.subscriptions {
+		if !s.accepts(event) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			logger.Warnf("購読者のバッファが溢れたため、イベントを破棄: %s dogrun:%d", event.Type, event.DogrunID)
+		}
+	}
+}
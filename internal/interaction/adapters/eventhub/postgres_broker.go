@@ -0,0 +1,118 @@
+package eventhub
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+)
+
+// LISTEN接続が切断された場合の再接続間隔
+const postgresReconnectInterval = 5 * time.Second
+
+type postgresBroker struct {
+	db      *gorm.DB
+	connURL string
+	channel string
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewPostgresBroker: postgresのLISTEN/NOTIFYを使用するブローカーの生成
+// 複数インスタンスで稼働する場合に、全インスタンスの購読者へ配信する
+//
+// args:
+//   - *gorm.DB:	NOTIFYの発行に使用するDB
+//   - string:	LISTEN用の接続URL
+//   - string:	チャネル名
+//
+// return:
+//   - IEventBroker:	ブローカー
+func NewPostgresBroker(db *gorm.DB, connURL string, channel string) IEventBroker {
+	return &postgresBroker{
+		db:      db,
+		connURL: connURL,
+		channel: channel,
+	}
+}
+
+// Start: LISTEN用の専用接続を確立し、受信ループを開始
+// 初回の接続に失敗した場合はエラーを返す。以降の切断は再接続する
+func (b *postgresBroker) Start(deliver func([]byte)) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	conn, err := b.listen(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	b.cancel = cancel
+	b.done = make(chan struct{})
+	go b.receive(ctx, conn, deliver)
+	return nil
+}
+
+// Publish: NOTIFYの発行
+func (b *postgresBroker) Publish(ctx context.Context, payload []byte) error {
+	return b.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", b.channel, string(payload)).Error
+}
+
+// Close: 受信ループの停止
+func (b *postgresBroker) Close() error {
+	if b.cancel == nil {
+		return nil
+	}
+	b.cancel()
+	<-b.done
+	return nil
+}
+
+// listen: 接続してチャネルをLISTEN
+func (b *postgresBroker) listen(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, b.connURL)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{b.channel}.Sanitize()); err != nil {
+		_ = conn.Close(context.Background())
+		return nil, err
+	}
+	return conn, nil
+}
+
+// receive: 通知の受信ループ
+// 切断された場合は一定間隔で再接続する。再接続までの通知は失われる
+func (b *postgresBroker) receive(ctx context.Context, conn *pgx.Conn, deliver func([]byte)) {
+	logger := log.GetBaseLogger().Sugar()
+	defer close(b.done)
+
+	for {
+		if conn == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(postgresReconnectInterval):
+			}
+			c, err := b.listen(ctx)
+			if err != nil {
+				logger.Errorf("イベントチャネルの再接続に失敗: %v", err)
+				continue
+			}
+			logger.Infof("イベントチャネルに再接続: %s", b.channel)
+			conn = c
+		}
+
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			_ = conn.Close(context.Background())
+			conn = nil
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Errorf("イベントチャネルの受信に失敗: %v", err)
+			continue
+		}
+		deliver([]byte(notification.Payload))
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+)
+
+// IAutoCheckoutRepository: 自動チェックアウト用のリポジトリ
+// リクエスト外(バックグラウンド)で実行するため、echo.Contextではなくcontext.Contextを受け取る
+type IAutoCheckoutRepository interface {
+	FindOpenDogrunVisits(context.Context) ([]model.DogrunVisit, error)
+	CloseDogrunVisit(context.Context, model.DogrunVisit) (bool, error)
+	CountOpenDogrunVisits(context.Context, []int64) ([]model.DogrunOccupancy, error)
+}
+
+type autoCheckoutRepository struct {
+	db *gorm.DB
+}
+
+func NewAutoCheckoutRepository(db *gorm.DB) IAutoCheckoutRepository {
+	return &autoCheckoutRepository{db}
+}
+
+// FindOpenDogrunVisits: 滞在中のvisitを、ドッグランの営業時間を含めて取得
+//
+// args:
+//   - context.Context:	コンテキスト
+//
+// return:
+//   - []model.DogrunVisit:	滞在中のvisit
+//   - error:	エラー
+func (r *autoCheckoutRepository) FindOpenDogrunVisits(ctx context.Context) ([]model.DogrunVisit, error) {
+	logger := log.GetBaseLogger().Sugar()
+
+	visits := []model.DogrunVisit{}
+	if err := r.db.WithContext(ctx).
+		Preload("Dogrun.RegularBusinessHours").
+		Preload("Dogrun.SpecialBusinessHours").
+		Where("checkout_at IS NULL").
+		Order("checkin_at").
+		Find(&visits).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_visitsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return nil, err
+	}
+	return visits, nil
+}
+
+// CloseDogrunVisit: visitの退場情報を保存
+// 手動のチェックアウトや他のインスタンスと競合しないよう、滞在中の場合のみ更新する
+//
+// args:
+//   - context.Context:	コンテキスト
+//   - model.DogrunVisit:	退場情報をセットしたvisit
+//
+// return:
+//   - bool:	更新したか。すでに退場済みの場合はfalse
+//   - error:	エラー
+func (r *autoCheckoutRepository) CloseDogrunVisit(ctx context.Context, visit model.DogrunVisit) (bool, error) {
+	logger := log.GetBaseLogger().Sugar()
+
+	result := r.db.WithContext(ctx).
+		Model(&model.DogrunVisit{}).
+		Where("dogrun_visit_id = ?", visit.DogrunVisitID.Int64).
+		Where("checkout_at IS NULL").
+		Updates(map[string]interface{}{
+			"checkout_at":        visit.CheckoutAt,
+			"checkout_reason":    visit.CheckoutReason,
+			"is_system_checkout": visit.SystemCheckout,
+			"upd_at":             time.Now(),
+		})
+	if result.Error != nil {
+		logger.Error(result.Error)
+		err := errors.NewWRError(result.Error, "dogrun_visitsの更新に失敗しました。", errors.NewInteractionServerErrorEType())
+		return false, err
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountOpenDogrunVisits: ドッグランごとに滞在中のdog数をサイズ区分別に集計
+//
+// args:
+//   - context.Context:	コンテキスト
+//   - []int64:	集計対象のdogrunIDs
+//
+// return:
+//   - []model.DogrunOccupancy:	集計結果。滞在中のdogがいないドッグランは含まない
+//   - error:	エラー
+func (r *autoCheckoutRepository) CountOpenDogrunVisits(ctx context.Context, dogrunIDs []int64) ([]model.DogrunOccupancy, error) {
+	logger := log.GetBaseLogger().Sugar()
+
+	occupancies, err := countOpenDogrunVisits(r.db.WithContext(ctx), dogrunIDs)
+	if err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_visitsの集計に失敗しました。", errors.NewInteractionServerErrorEType())
+		return nil, err
+	}
+	return occupancies, nil
+}
@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/labstack/echo/v4"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+)
+
+type IBookmarkCollectionRepository interface {
+	FindCollections(echo.Context, int64) ([]model.BookmarkCollection, error)
+	FindCollection(echo.Context, int64, int64) (model.BookmarkCollection, error)
+	FindCollectionByName(echo.Context, int64, string) (model.BookmarkCollection, error)
+	FindCollectionByShareTokenHash(echo.Context, string) (model.BookmarkCollection, error)
+	CountCollections(echo.Context, int64) (int64, error)
+	CreateCollection(echo.Context, *model.BookmarkCollection) error
+	UpdateCollection(echo.Context, model.BookmarkCollection) error
+	UpdateCollectionShareToken(echo.Context, int64, sql.NullString, sql.NullTime) error
+	DeleteCollection(echo.Context, int64) error
+	AddCollectionItems(echo.Context, []model.BookmarkCollectionItem) error
+	UpdateCollectionItemNote(echo.Context, int64, int64, sql.NullString) (bool, error)
+	DeleteCollectionItems(echo.Context, int64, []int64) error
+}
+
+type bookmarkCollectionRepository struct {
+	db *gorm.DB
+}
+
+func NewBookmarkCollectionRepository(db *gorm.DB) IBookmarkCollectionRepository {
+	return &bookmarkCollectionRepository{db}
+}
+
+// FindCollections: dogownerのコレクションを、含まれるドッグランとあわせて作成順に取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogownerID
+//
+// return:
+//   - []model.BookmarkCollection:	検索結果
+//   - error:	エラー
+func (r *bookmarkCollectionRepository) FindCollections(c echo.Context, dogownerID int64) ([]model.BookmarkCollection, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	collections := []model.BookmarkCollection{}
+	if err := r.db.
+		Preload("Items", preloadCollectionItems).
+		Where("dog_owner_id = ?", dogownerID).
+		Order("bookmark_collection_id").
+		Find(&collections).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "bookmark_collectionsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return nil, err
+	}
+	return collections, nil
+}
+
+// FindCollection: dogownerのコレクションを、含まれるドッグランとあわせて取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//   - int64:	dogownerID
+//
+// return:
+//   - model.BookmarkCollection:	検索結果。存在しない場合は空
+//   - error:	エラー
+func (r *bookmarkCollectionRepository) FindCollection(c echo.Context, collectionID int64, dogownerID int64) (model.BookmarkCollection, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	collection := model.BookmarkCollection{}
+	if err := r.db.
+		Preload("Items", preloadCollectionItems).
+		Where("bookmark_collection_id = ?", collectionID).
+		Where("dog_owner_id = ?", dogownerID).
+		Find(&collection).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "bookmark_collectionsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return collection, err
+	}
+	return collection, nil
+}
+
+// FindCollectionByName: dogownerのコレクションを名前で取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogownerID
+//   - string:	コレクション名
+//
+// return:
+//   - model.BookmarkCollection:	検索結果。存在しない場合は空
+//   - error:	エラー
+func (r *bookmarkCollectionRepository) FindCollectionByName(c echo.Context, dogownerID int64, name string) (model.BookmarkCollection, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	collection := model.BookmarkCollection{}
+	if err := r.db.
+		Where("dog_owner_id = ?", dogownerID).
+		Where("name = ?", name).
+		Find(&collection).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "bookmark_collectionsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return collection, err
+	}
+	return collection, nil
+}
+
+// FindCollectionByShareTokenHash: 共有リンクのトークン(ハッシュ)でコレクションを取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - string:	トークンのハッシュ
+//
+// return:
+//   - model.BookmarkCollection:	検索結果。存在しない場合は空
+//   - error:	エラー
+func (r *bookmarkCollectionRepository) FindCollectionByShareTokenHash(c echo.Context, tokenHash string) (model.BookmarkCollection, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	collection := model.BookmarkCollection{}
+	if err := r.db.
+		Preload("Items", preloadCollectionItems).
+		Where("share_token_hash = ?", tokenHash).
+		Find(&collection).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "bookmark_collectionsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return collection, err
+	}
+	return collection, nil
+}
+
+// CountCollections: dogownerのコレクション数を取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogownerID
+//
+// return:
+//   - int64:	コレクション数
+//   - error:	エラー
+func (r *bookmarkCollectionRepository) CountCollections(c echo.Context, dogownerID int64) (int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	var count int64
+	if err := r.db.Model(&model.BookmarkCollection{}).
+		Where("dog_owner_id = ?", dogownerID).
+		Count(&count).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "bookmark_collectionsの件数取得に失敗しました。", errors.NewInteractionServerErrorEType())
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateCollection: コレクションの登録
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - *model.BookmarkCollection:	登録内容。発行されたIDがセットされる
+//
+// return:
+//   - error:	エラー
+func (r *bookmarkCollectionRepository) CreateCollection(c echo.Context, collection *model.BookmarkCollection) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := r.db.Omit("Items").Create(collection).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "bookmark_collectionの登録に失敗しました。", errors.NewInteractionServerErrorEType())
+		return err
+	}
+	return nil
+}
+
+// UpdateCollection: コレクションの名前とメモの更新
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - model.BookmarkCollection:	更新内容
+//
+// return:
+//   - error:	エラー
+func (r *bookmarkCollectionRepository) UpdateCollection(c echo.Context, collection model.BookmarkCollection) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := r.db.Model(&model.BookmarkCollection{}).
+		Where("bookmark_collection_id = ?", collection.BookmarkCollectionID.Int64).
+		Updates(map[string]interface{}{
+			"name": collection.Name,
+			"note": collection.Note,
+		}).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "bookmark_collectionの更新に失敗しました。", errors.NewInteractionServerErrorEType())
+		return err
+	}
+	return nil
+}
+
+// UpdateCollectionShareToken: 共有リンクのトークン(ハッシュ)の更新
+// トークンをNULLにすると共有リンクは無効になる
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//   - sql.NullString:	トークンのハッシュ
+//   - sql.NullTime:	共有リンクの発行日時
+//
+// return:
+//   - error:	エラー
+func (r *bookmarkCollectionRepository) UpdateCollectionShareToken(c echo.Context, collectionID int64, tokenHash sql.NullString, sharedAt sql.NullTime) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := r.db.Model(&model.BookmarkCollection{}).
+		Where("bookmark_collection_id = ?", collectionID).
+		Updates(map[string]interface{}{
+			"share_token_hash": tokenHash,
+			"shared_at":        sharedAt,
+		}).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "bookmark_collectionの共有リンクの更新に失敗しました。", errors.NewInteractionServerErrorEType())
+		return err
+	}
+	return nil
+}
+
+// DeleteCollection: コレクションと含まれるドッグランの削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//
+// return:
+//   - error:	エラー
+func (r *bookmarkCollectionRepository) DeleteCollection(c echo.Context, collectionID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("bookmark_collection_id = ?", collectionID).
+			Delete(&model.BookmarkCollectionItem{}).Error; err != nil {
+			return err
+		}
+		return tx.
+			Where("bookmark_collection_id = ?", collectionID).
+			Delete(&model.BookmarkCollection{}).Error
+	})
+	if err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "bookmark_collectionの削除に失敗しました。", errors.NewInteractionServerErrorEType())
+		return err
+	}
+	return nil
+}
+
+// AddCollectionItems: コレクションへのドッグランの追加
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []model.BookmarkCollectionItem:	追加内容
+//
+// return:
+//   - error:	エラー
+func (r *bookmarkCollectionRepository) AddCollectionItems(c echo.Context, items []model.BookmarkCollectionItem) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if len(items) == 0 {
+		return nil
+	}
+	if err := r.db.Create(&items).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "bookmark_collection_itemsの登録に失敗しました。", errors.NewInteractionServerErrorEType())
+		return err
+	}
+	return nil
+}
+
+// UpdateCollectionItemNote: コレクション内のドッグランのメモを更新
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//   - int64:	dogrunID
+//   - sql.NullString:	メモ
+//
+// return:
+//   - bool:	更新対象が存在したか
+//   - error:	エラー
+func (r *bookmarkCollectionRepository) UpdateCollectionItemNote(c echo.Context, collectionID int64, dogrunID int64, note sql.NullString) (bool, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	result := r.db.Model(&model.BookmarkCollectionItem{}).
+		Where("bookmark_collection_id = ?", collectionID).
+		Where("dogrun_id = ?", dogrunID).
+		Update("note", note)
+	if result.Error != nil {
+		logger.Error(result.Error)
+		err := errors.NewWRError(result.Error, "bookmark_collection_itemの更新に失敗しました。", errors.NewInteractionServerErrorEType())
+		return false, err
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteCollectionItems: コレクションからドッグランを削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//   - []int64:	dogrunIDs
+//
+// return:
+//   - error:	エラー
+func (r *bookmarkCollectionRepository) DeleteCollectionItems(c echo.Context, collectionID int64, dogrunIDs []int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := r.db.
+		Where("bookmark_collection_id = ?", collectionID).
+		Where("dogrun_id IN ?", dogrunIDs).
+		Delete(&model.BookmarkCollectionItem{}).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "bookmark_collection_itemsの削除に失敗しました。", errors.NewInteractionServerErrorEType())
+		return err
+	}
+	return nil
+}
+
+/*
+コレクション内のドッグランを追加順に取得する
+*/
+func preloadCollectionItems(db *gorm.DB) *gorm.DB {
+	return db.Order("added_at, bookmark_collection_item_id")
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"gorm.io/gorm"
+)
+
+type IDogrunReviewRepository interface {
+	FindDogrunReviews(echo.Context, dto.DogrunReviewCondition, int, int) ([]model.DogrunReview, int64, error)
+	FindDogrunReview(echo.Context, int64) (model.DogrunReview, error)
+	FindDogrunReviewByDogowner(echo.Context, int64, int64) (model.DogrunReview, error)
+	CreateDogrunReview(echo.Context, *model.DogrunReview) error
+	UpdateDogrunReview(echo.Context, model.DogrunReview) error
+	DeleteDogrunReview(echo.Context, int64) error
+	SaveDogrunReviewReply(echo.Context, *model.DogrunReviewReply) error
+	DeleteDogrunReviewReply(echo.Context, int64) error
+	SummarizeDogrunRatings(echo.Context, []int64) ([]model.DogrunRatingSummary, error)
+}
+
+type dogrunReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewDogrunReviewRepository(db *gorm.DB) IDogrunReviewRepository {
+	return &dogrunReviewRepository{db}
+}
+
+// FindDogrunReviews: ドッグランのレビューを、投稿者と返信とあわせてページングで取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.DogrunReviewCondition:	検索条件
+//   - int:	オフセット
+//   - int:	取得件数
+//
+// return:
+//   - []model.DogrunReview:	検索結果
+//   - int64:	条件に一致する全件数
+//   - error:	エラー
+func (r *dogrunReviewRepository) FindDogrunReviews(c echo.Context, condition dto.DogrunReviewCondition, offset int, limit int) ([]model.DogrunReview, int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	query := r.db.Model(&model.DogrunReview{}).
+		Where("dogrun_id = ?", condition.DogrunID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_reviewsの件数取得に失敗しました。", errors.NewInteractionServerErrorEType())
+		return nil, 0, err
+	}
+
+	switch condition.Sort {
+	case dto.REVIEW_SORT_RATING_HIGH:
+		query = query.Order("rating DESC")
+	case dto.REVIEW_SORT_RATING_LOW:
+		query = query.Order("rating")
+	}
+
+	reviews := []model.DogrunReview{}
+	if err := query.
+		Preload("DogOwner").
+		Preload("Reply").
+		Order("reg_at DESC").
+		Order("dogrun_review_id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&reviews).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_reviewsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+// FindDogrunReview: レビューを、投稿者と返信とあわせて取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	レビューID
+//
+// return:
+//   - model.DogrunReview:	検索結果。存在しない場合は空
+//   - error:	エラー
+func (r *dogrunReviewRepository) FindDogrunReview(c echo.Context, reviewID int64) (model.DogrunReview, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	review := model.DogrunReview{}
+	if err := r.db.
+		Preload("DogOwner").
+		Preload("Reply").
+		Where("dogrun_review_id = ?", reviewID).
+		Find(&review).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_reviewsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return review, err
+	}
+	return review, nil
+}
+
+// FindDogrunReviewByDogowner: dogownerのドッグランへのレビューを取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - int64:	dogownerID
+//
+// return:
+//   - model.DogrunReview:	検索結果。存在しない場合は空
+//   - error:	エラー
+func (r *dogrunReviewRepository) FindDogrunReviewByDogowner(c echo.Context, dogrunID int64, dogownerID int64) (model.DogrunReview, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	review := model.DogrunReview{}
+	if err := r.db.
+		Where("dogrun_id = ?", dogrunID).
+		Where("dog_owner_id = ?", dogownerID).
+		Find(&review).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_reviewsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return review, err
+	}
+	return review, nil
+}
+
+// CreateDogrunReview: レビューの登録
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - *model.DogrunReview:	登録内容。発行されたIDがセットされる
+//
+// return:
+//   - error:	エラー
+func (r *dogrunReviewRepository) CreateDogrunReview(c echo.Context, review *model.DogrunReview) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := r.db.Omit("DogOwner", "Reply").Create(review).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_reviewの登録に失敗しました。", errors.NewInteractionServerErrorEType())
+		return err
+	}
+	return nil
+}
+
+// UpdateDogrunReview: レビューの評価と本文の更新
+// 観点別の評価はNULLへの更新も含めて上書きする
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - model.DogrunReview:	更新内容
+//
+// return:
+//   - error:	エラー
+func (r *dogrunReviewRepository) UpdateDogrunReview(c echo.Context, review model.DogrunReview) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := r.db.Model(&model.DogrunReview{}).
+		Where("dogrun_review_id = ?", review.DogrunReviewID.Int64).
+		Updates(map[string]interface{}{
+			"rating":            review.Rating,
+			"comment":           review.Comment,
+			"cleanliness_score": review.CleanlinessScore,
+			"size_score":        review.SizeScore,
+			"crowding_score":    review.CrowdingScore,
+		}).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_reviewの更新に失敗しました。", errors.NewInteractionServerErrorEType())
+		return err
+	}
+	return nil
+}
+
+// DeleteDogrunReview: レビューと返信の削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	レビューID
+//
+// return:
+//   - error:	エラー
+func (r *dogrunReviewRepository) DeleteDogrunReview(c echo.Context, reviewID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("dogrun_review_id = ?", reviewID).
+			Delete(&model.DogrunReviewReply{}).Error; err != nil {
+			return err
+		}
+		return tx.
+			Where("dogrun_review_id = ?", reviewID).
+			Delete(&model.DogrunReview{}).Error
+	})
+	if err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_reviewの削除に失敗しました。", errors.NewInteractionServerErrorEType())
+		return err
+	}
+	return nil
+}
+
+// SaveDogrunReviewReply: レビューへの返信の登録・更新
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - *model.DogrunReviewReply:	保存内容。IDがない場合は登録する
+//
+// return:
+//   - error:	エラー
+func (r *dogrunReviewRepository) SaveDogrunReviewReply(c echo.Context, reply *model.DogrunReviewReply) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := r.db.Save(reply).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_review_replyの保存に失敗しました。", errors.NewInteractionServerErrorEType())
+		return err
+	}
+	return nil
+}
+
+// DeleteDogrunReviewReply: レビューへの返信の削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	レビューID
+//
+// return:
+//   - error:	エラー
+func (r *dogrunReviewRepository) DeleteDogrunReviewReply(c echo.Context, reviewID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := r.db.
+		Where("dogrun_review_id = ?", reviewID).
+		Delete(&model.DogrunReviewReply{}).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_review_replyの削除に失敗しました。", errors.NewInteractionServerErrorEType())
+		return err
+	}
+	return nil
+}
+
+// SummarizeDogrunRatings: ドッグランごとにレビューの評価を集計
+// 平均は小数第1位に丸める
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []int64:	集計対象のdogrunIDs
+//
+// return:
+//   - []model.DogrunRatingSummary:	集計結果。レビューのないドッグランは含まない
+//   - error:	エラー
+func (r *dogrunReviewRepository) SummarizeDogrunRatings(c echo.Context, dogrunIDs []int64) ([]model.DogrunRatingSummary, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	summaries := []model.DogrunRatingSummary{}
+	if len(dogrunIDs) == 0 {
+		return summaries, nil
+	}
+	if err := r.db.Model(&model.DogrunReview{}).
+		Select(`dogrun_id,
+			COUNT(*) AS review_count,
+			ROUND(AVG(rating)::numeric, 1) AS avg_rating,
+			ROUND(AVG(cleanliness_score)::numeric, 1) AS avg_cleanliness,
+			ROUND(AVG(size_score)::numeric, 1) AS avg_size,
+			ROUND(AVG(crowding_score)::numeric, 1) AS avg_crowding`).
+		Where("dogrun_id IN ?", dogrunIDs).
+		Group("dogrun_id").
+		Scan(&summaries).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_reviewsの集計に失敗しました。", errors.NewInteractionServerErrorEType())
+		return nil, err
+	}
+	return summaries, nil
+}
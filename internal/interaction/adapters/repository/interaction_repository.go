@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/dto"
 	model "github.com/wanrun-develop/wanrun/internal/models"
 	"github.com/wanrun-develop/wanrun/pkg/errors"
 	"github.com/wanrun-develop/wanrun/pkg/log"
@@ -102,6 +103,7 @@ func (r *bookmarkRepository) FindDogrunBookmark(c echo.Context, dogrunID int64,
 }
 
 // AddBookmark: 複数ドックランのブックマーク削除
+// dogownerのコレクションからも削除する
 //
 // args:
 //   - echo.Context:	コンテキスト
@@ -114,10 +116,21 @@ func (r *bookmarkRepository) DeleteBookmark(c echo.Context, dogrunIDs []int64, d
 
 	logger := log.GetLogger(c).Sugar()
 
-	if err := r.db.
-		Where("dog_owner_id = ?", dogownerID).
-		Where("dogrun_id IN ?", dogrunIDs).
-		Delete(&model.DogrunBookmark{}).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("dogrun_id IN ?", dogrunIDs).
+			Where("bookmark_collection_id IN (?)", tx.Model(&model.BookmarkCollection{}).
+				Select("bookmark_collection_id").
+				Where("dog_owner_id = ?", dogownerID)).
+			Delete(&model.BookmarkCollectionItem{}).Error; err != nil {
+			return err
+		}
+		return tx.
+			Where("dog_owner_id = ?", dogownerID).
+			Where("dogrun_id IN ?", dogrunIDs).
+			Delete(&model.DogrunBookmark{}).Error
+	})
+	if err != nil {
 		logger.Error(err)
 		err := errors.NewWRError(err, "dogrun_bookmarkの削除に失敗しました。", errors.NewInteractionServerErrorEType())
 		return err
@@ -126,11 +139,14 @@ func (r *bookmarkRepository) DeleteBookmark(c echo.Context, dogrunIDs []int64, d
 }
 
 type ICheckInOutRepository interface {
-	FindTodayDogrunCheckin(echo.Context, int64, int64) (model.DogrunCheckin, error)
-	SaveDogrunCheckins(echo.Context, []model.DogrunCheckin) ([]model.DogrunCheckin, error)
-	FindTodayDogrunCheckout(echo.Context, int64, int64) (model.DogrunCheckout, error)
-	SaveDogrunCheckouts(echo.Context, []model.DogrunCheckout) ([]model.DogrunCheckout, error)
-	GetTodayCheckinsByDogownerID(echo.Context, int64) ([]model.DogrunCheckin, error)
+	FindOpenDogrunVisit(echo.Context, int64) (model.DogrunVisit, error)
+	FindTodayDogrunCheckin(echo.Context, int64, int64) (model.DogrunVisit, error)
+	SaveDogrunVisits(echo.Context, []model.DogrunVisit) ([]model.DogrunVisit, error)
+	GetTodayCheckinsByDogownerID(echo.Context, int64) ([]model.DogrunVisit, error)
+	CountOpenDogrunVisits(echo.Context, []int64) ([]model.DogrunOccupancy, error)
+	FindDogrunVisitHistory(echo.Context, dto.VisitHistoryCondition, int, int) ([]model.DogrunVisit, int64, error)
+	FindDogrunVisitsByCondition(echo.Context, dto.VisitHistoryCondition) ([]model.DogrunVisit, error)
+	ExistsDogrunVisitByDogowner(echo.Context, int64, int64) (bool, error)
 }
 
 type checkInOutRepository struct {
@@ -141,136 +157,299 @@ func NewCheckInOutRepository(db *gorm.DB) ICheckInOutRepository {
 	return &checkInOutRepository{db}
 }
 
-// FindTodayDogrunCheckin: dogIDとdogownerIDでcheckinへ検索
+// FindOpenDogrunVisit: dogの滞在中のvisitを検索
+// 滞在中のvisitはdogごとに1件のみ
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogIDで条件指定
+//
+// return:
+//   - model.DogrunVisit:	検索結果構造体。滞在中でなければ空
+//   - error:	エラー
+func (r *checkInOutRepository) FindOpenDogrunVisit(c echo.Context, dogID int64) (model.DogrunVisit, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	visit := model.DogrunVisit{}
+	if err := r.db.
+		Where("dog_id = ?", dogID).
+		Where("checkout_at IS NULL").
+		Find(&visit).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_visitsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return visit, err
+	}
+
+	return visit, nil
+}
+
+// FindTodayDogrunCheckin: dogrunIDとdogIDで今日の最新のvisitを検索
 //
 //	今日分ですでにチェックインしているかどうか
 //
 // args:
 //   - echo.Context:	コンテキスト
+//   - int64:	dogrunIDで条件指定
 //   - int64:	dogIDで条件指定
-//   - int64:	dogownerIDで条件指定
 //
 // return:
-//   - model.DogrunCheckin:	検索結果構造体
+//   - model.DogrunVisit:	検索結果構造体
 //   - error:	エラー
-func (r *checkInOutRepository) FindTodayDogrunCheckin(c echo.Context, dogrunID int64, dogID int64) (model.DogrunCheckin, error) {
+func (r *checkInOutRepository) FindTodayDogrunCheckin(c echo.Context, dogrunID int64, dogID int64) (model.DogrunVisit, error) {
 	logger := log.GetLogger(c).Sugar()
 
-	startOfDay := time.Now().Truncate(24 * time.Hour)
-	endOfDay := startOfDay.Add(24 * time.Hour)
+	startOfDay, endOfDay := todayRange()
 
-	checkin := model.DogrunCheckin{}
+	visit := model.DogrunVisit{}
 	if err := r.db.
 		Where("dogrun_id = ?", dogrunID).
 		Where("dog_id = ?", dogID).
 		Where("checkin_at >= ? AND checkin_at < ?", startOfDay, endOfDay).
-		Find(&checkin).Error; err != nil {
+		Order("checkin_at DESC").
+		Limit(1).
+		Find(&visit).Error; err != nil {
 		logger.Error(err)
-		err := errors.NewWRError(err, "dogrun_checkinの検索に失敗しました。", errors.NewInteractionServerErrorEType())
-		return checkin, err
+		err := errors.NewWRError(err, "dogrun_visitsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return visit, err
 	}
 
-	return checkin, nil
+	return visit, nil
 }
 
-// SaveDogrunCheckins: dogrunCheckinの一括保存
+// SaveDogrunVisits: dogrunVisitの一括保存
+// 滞在中のvisitの一意制約があるため、終了するvisitを先に保存できるよう順番に保存する
 //
 // args:
 //   - echo.Context:	コンテキスト
-//   - []model.DogrunCheckin:	保存対象dogrunCheckin構造体スライス
+//   - []model.DogrunVisit:	保存対象DogrunVisit構造体スライス
 //
 // return:
-//   - []model.DogrunCheckin:	保存結果DogrunCheckins構造体スライス
+//   - []model.DogrunVisit:	保存結果DogrunVisit構造体スライス
 //   - error:	エラー
-func (r *checkInOutRepository) SaveDogrunCheckins(c echo.Context, checkins []model.DogrunCheckin) ([]model.DogrunCheckin, error) {
+func (r *checkInOutRepository) SaveDogrunVisits(c echo.Context, visits []model.DogrunVisit) ([]model.DogrunVisit, error) {
 	logger := log.GetLogger(c).Sugar()
 
-	if err := r.db.Save(&checkins).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range visits {
+			if err := tx.Omit("Dog", "Dogrun").Save(&visits[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		logger.Error(err)
-		err := errors.NewWRError(err, "dogrun_checkinの保存に失敗しました。", errors.NewInteractionServerErrorEType())
+		err := errors.NewWRError(err, "dogrun_visitsの保存に失敗しました。", errors.NewInteractionServerErrorEType())
 		return nil, err
 	}
 
-	return checkins, nil
+	return visits, nil
 }
 
-// FindTodayDogrunCheckout: dogIDとdogownerIDでcheckoutへ検索
-//
-//	今日分ですでにチェックアウトしているかどうか
+// GetTodayCheckinsByDogownerID: dogownerIDよりその所有dogの今日分のvisitを取得
+// 今日チェックインしたvisitに加え、前日以前から滞在中のvisitも含む
 //
 // args:
 //   - echo.Context:	コンテキスト
-//   - int64:	dogIDで条件指定
-//   - int64:	dogownerIDで条件指定
+//   - int64:	検索対象のdogownerID
 //
 // return:
-//   - model.DogrunCheckout:	検索結果構造体
+//   - []model.DogrunVisit:	検索結果DogrunVisit構造体スライス
 //   - error:	エラー
-func (r *checkInOutRepository) FindTodayDogrunCheckout(c echo.Context, dogrunID int64, dogID int64) (model.DogrunCheckout, error) {
+func (r *checkInOutRepository) GetTodayCheckinsByDogownerID(c echo.Context, dogownerID int64) ([]model.DogrunVisit, error) {
 	logger := log.GetLogger(c).Sugar()
 
-	startOfDay := time.Now().Truncate(24 * time.Hour)
-	endOfDay := startOfDay.Add(24 * time.Hour)
+	startOfDay, endOfDay := todayRange()
+
+	visits := []model.DogrunVisit{}
+	if err := r.db.Joins("inner join dogs on dogrun_visits.dog_id = dogs.dog_id").
+		Where("dogs.dog_owner_id = ?", dogownerID).
+		Where(r.db.Where("checkin_at >= ? AND checkin_at < ?", startOfDay, endOfDay).
+			Or("checkout_at IS NULL")).
+		Order("dogrun_visits.checkin_at").
+		Find(&visits).Error; err != nil {
 
-	checkout := model.DogrunCheckout{}
-	if err := r.db.
-		Where("dogrun_id = ?", dogrunID).
-		Where("dog_id = ?", dogID).
-		Where("checkout_at >= ? AND checkout_at < ?", startOfDay, endOfDay).
-		Find(&checkout).Error; err != nil {
 		logger.Error(err)
-		err := errors.NewWRError(err, "dogrun_checkoutの検索に失敗しました。", errors.NewInteractionServerErrorEType())
-		return checkout, err
+		err := errors.NewWRError(err, "dogrun_visitsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return nil, err
 	}
-
-	return checkout, nil
+	return visits, nil
 }
 
-// SaveDogrunCheckouts: dogrunCheckoutの一括保存
+// CountOpenDogrunVisits: ドッグランごとに滞在中のdog数をサイズ区分別に集計
+// サイズ区分はmodel.Dog.Sizeと同じ体重の閾値で判定
 //
 // args:
 //   - echo.Context:	コンテキスト
-//   - []model.DogrunCheckout:	保存対象DogrunCheckout構造体スライス
+//   - []int64:	集計対象のdogrunIDs
 //
 // return:
-//   - []model.DogrunCheckout:	保存結果DogrunCheckouts構造体スライス
+//   - []model.DogrunOccupancy:	集計結果。滞在中のdogがいないドッグランは含まない
 //   - error:	エラー
-func (r *checkInOutRepository) SaveDogrunCheckouts(c echo.Context, checkouts []model.DogrunCheckout) ([]model.DogrunCheckout, error) {
+func (r *checkInOutRepository) CountOpenDogrunVisits(c echo.Context, dogrunIDs []int64) ([]model.DogrunOccupancy, error) {
 	logger := log.GetLogger(c).Sugar()
 
-	if err := r.db.Save(&checkouts).Error; err != nil {
+	occupancies, err := countOpenDogrunVisits(r.db, dogrunIDs)
+	if err != nil {
 		logger.Error(err)
-		err := errors.NewWRError(err, "dogrun_checkoutの保存に失敗しました。", errors.NewInteractionServerErrorEType())
+		err := errors.NewWRError(err, "dogrun_visitsの集計に失敗しました。", errors.NewInteractionServerErrorEType())
 		return nil, err
 	}
-
-	return checkouts, nil
+	return occupancies, nil
 }
 
-// GetCheckinsByDogownerID: dogownerIDよりその所有dogの今日分のチェックイン履歴を取得
+// FindDogrunVisitHistory: 条件に一致するvisitをdog・ドッグラン情報を含めてページ単位で取得
+// 入場日時の新しい順
 //
 // args:
 //   - echo.Context:	コンテキスト
-//   - int64:	検索対象のdogownerID
+//   - dto.VisitHistoryCondition:	検索条件
+//   - int:	オフセット
+//   - int:	取得件数
 //
 // return:
-//   - []model.DogrunCheckin:	保存結果DogrunCheckouts構造体スライス
+//   - []model.DogrunVisit:	検索結果
+//   - int64:	条件に一致する総件数
 //   - error:	エラー
-func (r *checkInOutRepository) GetTodayCheckinsByDogownerID(c echo.Context, dogownerID int64) ([]model.DogrunCheckin, error) {
+func (r *checkInOutRepository) FindDogrunVisitHistory(c echo.Context, condition dto.VisitHistoryCondition, offset int, limit int) ([]model.DogrunVisit, int64, error) {
 	logger := log.GetLogger(c).Sugar()
 
-	startOfDay := time.Now().Truncate(24 * time.Hour)
-	endOfDay := startOfDay.Add(24 * time.Hour)
+	var total int64
+	if err := visitHistoryQuery(r.db, condition).Count(&total).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_visitsの件数取得に失敗しました。", errors.NewInteractionServerErrorEType())
+		return nil, 0, err
+	}
 
-	checkins := []model.DogrunCheckin{}
-	if err := r.db.Joins("inner join dogs on dogrun_checkin.dog_id = dogs.dog_id").
-		Where("dogs.dog_owner_id = ?", dogownerID).
-		Where("checkin_at >= ? AND checkin_at < ?", startOfDay, endOfDay).
-		Find(&checkins).Error; err != nil {
+	visits := []model.DogrunVisit{}
+	if err := visitHistoryQuery(r.db, condition).
+		Preload("Dog").
+		Preload("Dogrun").
+		Order("dogrun_visits.checkin_at DESC").
+		Order("dogrun_visits.dogrun_visit_id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&visits).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_visitsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return nil, 0, err
+	}
+	return visits, total, nil
+}
 
+// FindDogrunVisitsByCondition: 条件に一致するvisitをドッグラン情報を含めてすべて取得
+// 統計の集計に使用する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.VisitHistoryCondition:	検索条件
+//
+// return:
+//   - []model.DogrunVisit:	検索結果
+//   - error:	エラー
+func (r *checkInOutRepository) FindDogrunVisitsByCondition(c echo.Context, condition dto.VisitHistoryCondition) ([]model.DogrunVisit, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	visits := []model.DogrunVisit{}
+	if err := visitHistoryQuery(r.db, condition).
+		Preload("Dogrun").
+		Order("dogrun_visits.checkin_at").
+		Find(&visits).Error; err != nil {
 		logger.Error(err)
-		err := errors.NewWRError(err, "dogrun_checkinの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		err := errors.NewWRError(err, "dogrun_visitsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
 		return nil, err
 	}
-	return checkins, nil
+	return visits, nil
+}
+
+// ExistsDogrunVisitByDogowner: dogownerの所有dogが、ドッグランにチェックインしたことがあるか
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - int64:	dogownerID
+//
+// return:
+//   - bool:	チェックインしたことがあるか
+//   - error:	エラー
+func (r *checkInOutRepository) ExistsDogrunVisitByDogowner(c echo.Context, dogrunID int64, dogownerID int64) (bool, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	var count int64
+	if err := visitHistoryQuery(r.db, dto.VisitHistoryCondition{DogownerID: dogownerID}).
+		Where("dogrun_visits.dogrun_id = ?", dogrunID).
+		Limit(1).
+		Count(&count).Error; err != nil {
+		logger.Error(err)
+		err := errors.NewWRError(err, "dogrun_visitsの検索に失敗しました。", errors.NewInteractionServerErrorEType())
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// visitHistoryQuery: チェックイン履歴の検索条件のクエリ
+//
+// args:
+//   - *gorm.DB:	DB
+//   - dto.VisitHistoryCondition:	検索条件
+//
+// return:
+//   - *gorm.DB:	条件を設定したクエリ
+func visitHistoryQuery(db *gorm.DB, condition dto.VisitHistoryCondition) *gorm.DB {
+	query := db.Model(&model.DogrunVisit{}).
+		Joins("inner join dogs on dogrun_visits.dog_id = dogs.dog_id").
+		Where("dogs.dog_owner_id = ?", condition.DogownerID)
+	if condition.DogID != 0 {
+		query = query.Where("dogrun_visits.dog_id = ?", condition.DogID)
+	}
+	if condition.From != nil {
+		query = query.Where("dogrun_visits.checkin_at >= ?", *condition.From)
+	}
+	if condition.To != nil {
+		query = query.Where("dogrun_visits.checkin_at < ?", *condition.To)
+	}
+	return query
+}
+
+// countOpenDogrunVisits: ドッグランごとの滞在中のdog数をサイズ区分別に集計
+//
+// args:
+//   - *gorm.DB:	DB
+//   - []int64:	集計対象のdogrunIDs
+//
+// return:
+//   - []model.DogrunOccupancy:	集計結果。滞在中のdogがいないドッグランは含まない
+//   - error:	エラー
+func countOpenDogrunVisits(db *gorm.DB, dogrunIDs []int64) ([]model.DogrunOccupancy, error) {
+	occupancies := []model.DogrunOccupancy{}
+	err := db.Model(&model.DogrunVisit{}).
+		Select(
+			"dogrun_visits.dogrun_id, "+
+				"COUNT(*) AS total, "+
+				"COUNT(*) FILTER (WHERE dogs.weight > 0 AND dogs.weight < ?) AS small, "+
+				"COUNT(*) FILTER (WHERE dogs.weight >= ? AND dogs.weight < ?) AS medium, "+
+				"COUNT(*) FILTER (WHERE dogs.weight >= ?) AS large, "+
+				"COUNT(*) FILTER (WHERE dogs.weight IS NULL OR dogs.weight <= 0) AS unknown",
+			model.DOG_SIZE_SMALL_MAX_WEIGHT,
+			model.DOG_SIZE_SMALL_MAX_WEIGHT, model.DOG_SIZE_LARGE_MIN_WEIGHT,
+			model.DOG_SIZE_LARGE_MIN_WEIGHT,
+		).
+		Joins("inner join dogs on dogrun_visits.dog_id = dogs.dog_id").
+		Where("dogrun_visits.dogrun_id in ?", dogrunIDs).
+		Where("dogrun_visits.checkout_at IS NULL").
+		Group("dogrun_visits.dogrun_id").
+		Scan(&occupancies).Error
+	return occupancies, err
+}
+
+// todayRange: 今日の開始日時と終了日時
+// Truncate(24時間)はUTCで切り捨てるため、ローカルタイムゾーンの0時を開始日時とする
+//
+// return:
+//   - time.Time:	今日の開始日時
+//   - time.Time:	明日の開始日時
+func todayRange() (time.Time, time.Time) {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	return startOfDay, startOfDay.AddDate(0, 0, 1)
 }
@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/configs"
+	"github.com/wanrun-develop/wanrun/internal/interaction/adapters/eventhub"
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/dto"
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/handler"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"golang.org/x/net/websocket"
+)
+
+type IDogrunEventController interface {
+	StreamEvents(echo.Context) error
+	StreamEventsWebSocket(echo.Context) error
+}
+
+type dogrunEventController struct {
+	h handler.IDogrunEventHandler
+}
+
+func NewDogrunEventController(h handler.IDogrunEventHandler) IDogrunEventController {
+	return &dogrunEventController{h}
+}
+
+// StreamEvents: ドッグランのイベントをSSE(text/event-stream)で配信
+// 接続直後に現在の混雑状況を送り、以降はイベントの発生ごとに送る
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dec *dogrunEventController) StreamEvents(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	subscription, snapshot, err := dec.subscribe(c)
+	if err != nil {
+		return err
+	}
+	defer subscription.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // リバースプロキシでのバッファリングを無効化
+	res.WriteHeader(http.StatusOK)
+
+	for _, event := range snapshot {
+		if err := writeSSEEvent(res, event); err != nil {
+			logger.Info("SSEの送信に失敗したため終了: ", err)
+			return nil
+		}
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval())
+	defer heartbeat.Stop()
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("クライアントの切断によりSSEを終了")
+			return nil
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return nil
+			}
+			if err := writeSSEEvent(res, event); err != nil {
+				logger.Info("SSEの送信に失敗したため終了: ", err)
+				return nil
+			}
+			res.Flush()
+		case <-heartbeat.C:
+			// 無通信によるプロキシの切断を防ぐコメント行
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				logger.Info("SSEの送信に失敗したため終了: ", err)
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// StreamEventsWebSocket: ドッグランのイベントをWebSocketで配信
+// 送信はサーバーからのみ。クライアントからの受信は切断の検知にのみ使用する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - error:	エラー
+func (dec *dogrunEventController) StreamEventsWebSocket(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	subscription, snapshot, err := dec.subscribe(c)
+	if err != nil {
+		return err
+	}
+	defer subscription.Close()
+
+	server := websocket.Server{
+		// CORSと同様にoriginは制限しない(認証はJWTで行う)
+		Handshake: func(*websocket.Config, *http.Request) error {
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var msg string
+				for {
+					if err := websocket.Message.Receive(ws, &msg); err != nil {
+						return
+					}
+				}
+			}()
+
+			for _, event := range snapshot {
+				if err := websocket.JSON.Send(ws, event); err != nil {
+					logger.Info("WebSocketの送信に失敗したため終了: ", err)
+					return
+				}
+			}
+			for {
+				select {
+				case <-closed:
+					logger.Info("クライアントの切断によりWebSocketを終了")
+					return
+				case event, ok := <-subscription.Events:
+					if !ok {
+						return
+					}
+					if err := websocket.JSON.Send(ws, event); err != nil {
+						logger.Info("WebSocketの送信に失敗したため終了: ", err)
+						return
+					}
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// subscribe: リクエストのバインドとバリデーション、購読の開始
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - *eventhub.Subscription:	購読
+//   - []eventhub.DogrunEvent:	購読開始時点の混雑状況
+//   - error:	エラー
+func (dec *dogrunEventController) subscribe(c echo.Context) (*eventhub.Subscription, []eventhub.DogrunEvent, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	req := dto.DogrunEventSubscribeReq{}
+	if err := c.Bind(&req); err != nil {
+		err = errors.NewWRError(err, "イベント購読リクエストが不正です", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return nil, nil, err
+	}
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		err = errors.NewWRError(err, "リクエストがバリデーションに違反しています", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	return dec.h.Subscribe(c, req)
+}
+
+// writeSSEEvent: イベントをSSEの形式で書き込む
+//
+// args:
+//   - *echo.Response:	レスポンス
+//   - eventhub.DogrunEvent:	イベント
+//
+// return:
+//   - error:	エラー
+func writeSSEEvent(res *echo.Response, event eventhub.DogrunEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
+
+// heartbeatInterval: ストリームのハートビート間隔
+func heartbeatInterval() time.Duration {
+	seconds := configs.FetchConfigInt("event.stream.heartbeat")
+	if seconds <= 0 {
+		seconds = 15
+	}
+	return time.Duration(seconds) * time.Second
+}
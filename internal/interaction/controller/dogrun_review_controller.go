@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/dto"
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/handler"
+)
+
+type IDogrunReviewController interface {
+	GetDogrunReviews(echo.Context) error
+	PostDogrunReview(echo.Context) error
+	UpdateDogrunReview(echo.Context) error
+	DeleteDogrunReview(echo.Context) error
+	ReplyDogrunReview(echo.Context) error
+	DeleteDogrunReviewReply(echo.Context) error
+}
+
+type dogrunReviewController struct {
+	h handler.IDogrunReviewHandler
+}
+
+func NewDogrunReviewController(h handler.IDogrunReviewHandler) IDogrunReviewController {
+	return &dogrunReviewController{h}
+}
+
+// GetDogrunReviews: ドッグランのレビュー一覧の取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (rc *dogrunReviewController) GetDogrunReviews(c echo.Context) error {
+	dogrunID, err := parseNaturalParam(c, "dogrunId")
+	if err != nil {
+		return err
+	}
+	req := dto.DogrunReviewsReq{}
+	if err := bindAndValidate(c, &req, "レビュー一覧リクエストが不正です"); err != nil {
+		return err
+	}
+
+	res, err := rc.h.GetDogrunReviews(c, dogrunID, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// PostDogrunReview: ドッグランのレビューの投稿
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (rc *dogrunReviewController) PostDogrunReview(c echo.Context) error {
+	dogrunID, err := parseNaturalParam(c, "dogrunId")
+	if err != nil {
+		return err
+	}
+	reqBody := dto.DogrunReviewReq{}
+	if err := bindAndValidate(c, &reqBody, "レビュー投稿リクエストが不正です"); err != nil {
+		return err
+	}
+
+	res, err := rc.h.PostDogrunReview(c, dogrunID, reqBody)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// UpdateDogrunReview: 自分のレビューの編集
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (rc *dogrunReviewController) UpdateDogrunReview(c echo.Context) error {
+	reviewID, err := parseNaturalParam(c, "reviewId")
+	if err != nil {
+		return err
+	}
+	reqBody := dto.DogrunReviewReq{}
+	if err := bindAndValidate(c, &reqBody, "レビュー編集リクエストが不正です"); err != nil {
+		return err
+	}
+
+	res, err := rc.h.UpdateDogrunReview(c, reviewID, reqBody)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteDogrunReview: 自分のレビューの削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (rc *dogrunReviewController) DeleteDogrunReview(c echo.Context) error {
+	reviewID, err := parseNaturalParam(c, "reviewId")
+	if err != nil {
+		return err
+	}
+
+	if err := rc.h.DeleteDogrunReview(c, reviewID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// ReplyDogrunReview: ドッグラン管理者によるレビューへの返信
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (rc *dogrunReviewController) ReplyDogrunReview(c echo.Context) error {
+	reviewID, err := parseNaturalParam(c, "reviewId")
+	if err != nil {
+		return err
+	}
+	reqBody := dto.DogrunReviewReplyReq{}
+	if err := bindAndValidate(c, &reqBody, "レビューへの返信リクエストが不正です"); err != nil {
+		return err
+	}
+
+	res, err := rc.h.ReplyDogrunReview(c, reviewID, reqBody)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteDogrunReviewReply: ドッグラン管理者によるレビューへの返信の削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (rc *dogrunReviewController) DeleteDogrunReviewReply(c echo.Context) error {
+	reviewID, err := parseNaturalParam(c, "reviewId")
+	if err != nil {
+		return err
+	}
+
+	if err := rc.h.DeleteDogrunReviewReply(c, reviewID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -16,17 +17,32 @@ type IInteractionController interface {
 	AddBookmark(echo.Context) error
 	DeleteBookmarks(echo.Context) error
 	CheckinDogrun(echo.Context) error
+	EvaluateCheckinEligibility(echo.Context) error
 	CheckoutDogrun(echo.Context) error
 	GetTodayCheckins(echo.Context) error
+	IssueCheckinQrToken(echo.Context) error
+	GetVisitHistory(echo.Context) error
+	GetVisitStats(echo.Context) error
+	GetBookmarkCollections(echo.Context) error
+	CreateBookmarkCollection(echo.Context) error
+	UpdateBookmarkCollection(echo.Context) error
+	DeleteBookmarkCollection(echo.Context) error
+	AddBookmarkCollectionItems(echo.Context) error
+	UpdateBookmarkCollectionItem(echo.Context) error
+	DeleteBookmarkCollectionItems(echo.Context) error
+	ShareBookmarkCollection(echo.Context) error
+	RevokeBookmarkCollectionShare(echo.Context) error
 }
 
 type interactionController struct {
-	bh handler.IBookmarkHandler
-	ch handler.ICheckInOutHandler
+	bh  handler.IBookmarkHandler
+	ch  handler.ICheckInOutHandler
+	vh  handler.IVisitHistoryHandler
+	bch handler.IBookmarkCollectionHandler
 }
 
-func NewInteractionController(bh handler.IBookmarkHandler, ch handler.ICheckInOutHandler) IInteractionController {
-	return &interactionController{bh, ch}
+func NewInteractionController(bh handler.IBookmarkHandler, ch handler.ICheckInOutHandler, vh handler.IVisitHistoryHandler, bch handler.IBookmarkCollectionHandler) IInteractionController {
+	return &interactionController{bh, ch, vh, bch}
 }
 
 // AddBookmark: ブックマークの追加
@@ -126,11 +142,47 @@ func (ic *interactionController) CheckinDogrun(c echo.Context) error {
 		return err
 	}
 
-	err := ic.ch.CheckinDogrun(c, reqBody)
+	eligibilityRes, err := ic.ch.CheckinDogrun(c, reqBody)
 	if err != nil {
 		return err
 	}
-	return c.NoContent(http.StatusCreated)
+	// 入場条件を満たさないdogがいる場合は、dogごとの理由を返す
+	if !eligibilityRes.Eligible {
+		return c.JSON(http.StatusUnprocessableEntity, eligibilityRes)
+	}
+	return c.JSON(http.StatusCreated, eligibilityRes)
+}
+
+// EvaluateCheckinEligibility: ドッグランへのチェックイン可否の判定（入場記録はしない）
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) EvaluateCheckinEligibility(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	reqBody := dto.CheckinReq{}
+	if err := c.Bind(&reqBody); err != nil {
+		err = errors.NewWRError(err, "チェックイン可否判定リクエストが不正です", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	// バリデータのインスタンス作成
+	validate := validator.New()
+	//リクエストボディのバリデーション
+	if err := validate.Struct(reqBody); err != nil {
+		err = errors.NewWRError(err, "リクエストがバリデーションに違反しています", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	eligibilityRes, err := ic.ch.EvaluateCheckinEligibility(c, reqBody)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, eligibilityRes)
 }
 
 // CheckoutDogrun: ドッグランへのチェックアウト（退場記録）
@@ -179,3 +231,320 @@ func (ic *interactionController) GetTodayCheckins(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, checkins)
 }
+
+// IssueCheckinQrToken: ドッグランに表示するチェックイン用QRコードのトークンの発行
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) IssueCheckinQrToken(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunID, err := strconv.ParseInt(c.Param("dogrunId"), 10, 64)
+	if err != nil || dogrunID <= 0 {
+		err = errors.NewWRError(err, errors.M_REQUEST_PARAM_MUST_BE_NATURAL, errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	res, err := ic.ch.IssueCheckinQrToken(c, dogrunID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetVisitHistory: 所有dogのチェックイン履歴の取得(ページング)
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) GetVisitHistory(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	req := dto.VisitHistoryReq{}
+	if err := c.Bind(&req); err != nil {
+		err = errors.NewWRError(err, "チェックイン履歴リクエストが不正です", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	// バリデータのインスタンス作成
+	validate := validator.New()
+	//リクエストのバリデーション
+	if err := validate.Struct(req); err != nil {
+		err = errors.NewWRError(err, "リクエストがバリデーションに違反しています", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	res, err := ic.vh.GetVisitHistory(c, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetVisitStats: 所有dogのチェックイン統計の取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) GetVisitStats(c echo.Context) error {
+	logger := log.GetLogger(c).Sugar()
+
+	req := dto.VisitStatsReq{}
+	if err := c.Bind(&req); err != nil {
+		err = errors.NewWRError(err, "チェックイン統計リクエストが不正です", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	// バリデータのインスタンス作成
+	validate := validator.New()
+	//リクエストのバリデーション
+	if err := validate.Struct(req); err != nil {
+		err = errors.NewWRError(err, "リクエストがバリデーションに違反しています", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+
+	res, err := ic.vh.GetVisitStats(c, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetBookmarkCollections: ブックマークのコレクション一覧の取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) GetBookmarkCollections(c echo.Context) error {
+	res, err := ic.bch.GetCollections(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// CreateBookmarkCollection: ブックマークのコレクションの作成
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) CreateBookmarkCollection(c echo.Context) error {
+	reqBody := dto.BookmarkCollectionReq{}
+	if err := bindAndValidate(c, &reqBody, "コレクション登録リクエストが不正です"); err != nil {
+		return err
+	}
+
+	res, err := ic.bch.CreateCollection(c, reqBody)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// UpdateBookmarkCollection: ブックマークのコレクションの名前とメモの更新
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) UpdateBookmarkCollection(c echo.Context) error {
+	collectionID, err := parseNaturalParam(c, "collectionId")
+	if err != nil {
+		return err
+	}
+	reqBody := dto.BookmarkCollectionReq{}
+	if err := bindAndValidate(c, &reqBody, "コレクション更新リクエストが不正です"); err != nil {
+		return err
+	}
+
+	res, err := ic.bch.UpdateCollection(c, collectionID, reqBody)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteBookmarkCollection: ブックマークのコレクションの削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) DeleteBookmarkCollection(c echo.Context) error {
+	collectionID, err := parseNaturalParam(c, "collectionId")
+	if err != nil {
+		return err
+	}
+
+	if err := ic.bch.DeleteCollection(c, collectionID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// AddBookmarkCollectionItems: コレクションへのドッグランの追加
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) AddBookmarkCollectionItems(c echo.Context) error {
+	collectionID, err := parseNaturalParam(c, "collectionId")
+	if err != nil {
+		return err
+	}
+	reqBody := dto.BookmarkCollectionItemAddReq{}
+	if err := bindAndValidate(c, &reqBody, "コレクションへの追加リクエストが不正です"); err != nil {
+		return err
+	}
+
+	res, err := ic.bch.AddCollectionItems(c, collectionID, reqBody)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// UpdateBookmarkCollectionItem: コレクション内のドッグランのメモの更新
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) UpdateBookmarkCollectionItem(c echo.Context) error {
+	collectionID, err := parseNaturalParam(c, "collectionId")
+	if err != nil {
+		return err
+	}
+	dogrunID, err := parseNaturalParam(c, "dogrunId")
+	if err != nil {
+		return err
+	}
+	reqBody := dto.BookmarkCollectionItemUpdateReq{}
+	if err := bindAndValidate(c, &reqBody, "コレクション内のメモ更新リクエストが不正です"); err != nil {
+		return err
+	}
+
+	if err := ic.bch.UpdateCollectionItem(c, collectionID, dogrunID, reqBody); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// DeleteBookmarkCollectionItems: コレクションからドッグランの削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) DeleteBookmarkCollectionItems(c echo.Context) error {
+	collectionID, err := parseNaturalParam(c, "collectionId")
+	if err != nil {
+		return err
+	}
+	reqBody := dto.BookmarkCollectionItemDeleteReq{}
+	if err := bindAndValidate(c, &reqBody, "コレクションからの削除リクエストが不正です"); err != nil {
+		return err
+	}
+
+	if err := ic.bch.DeleteCollectionItems(c, collectionID, reqBody); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// ShareBookmarkCollection: コレクションの共有リンクの発行
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) ShareBookmarkCollection(c echo.Context) error {
+	collectionID, err := parseNaturalParam(c, "collectionId")
+	if err != nil {
+		return err
+	}
+
+	res, err := ic.bch.ShareCollection(c, collectionID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// RevokeBookmarkCollectionShare: コレクションの共有リンクの無効化
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+// error:	エラー
+func (ic *interactionController) RevokeBookmarkCollectionShare(c echo.Context) error {
+	collectionID, err := parseNaturalParam(c, "collectionId")
+	if err != nil {
+		return err
+	}
+
+	if err := ic.bch.RevokeCollectionShare(c, collectionID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+/*
+パスパラメータの自然数の取得とバリデーション
+*/
+func parseNaturalParam(c echo.Context, name string) (int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	value, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || value <= 0 {
+		err = errors.NewWRError(err, errors.M_REQUEST_PARAM_MUST_BE_NATURAL, errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return 0, err
+	}
+	return value, nil
+}
+
+/*
+リクエストボディのバインドとバリデーション
+*/
+func bindAndValidate(c echo.Context, reqBody any, message string) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if err := c.Bind(reqBody); err != nil {
+		err = errors.NewWRError(err, message, errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	// バリデータのインスタンス作成
+	validate := validator.New()
+	_ = validate.RegisterValidation("notEmpty", common.VNotEmpty)
+	//リクエストボディのバリデーション
+	if err := validate.Struct(reqBody); err != nil {
+		err = errors.NewWRError(err, "リクエストがバリデーションに違反しています", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	return nil
+}
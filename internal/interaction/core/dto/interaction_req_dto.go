@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // bookmark 登録用
 type BookmarkAddReq struct {
 	DogrunIDs []int64 `json:"bookmark_dogrun_id" validate:"required,notEmpty"`
@@ -9,12 +11,112 @@ type BookmarkDeleteReq struct {
 	DogrunIDs []int64 `json:"bookmark_dogrun_id" validate:"required,notEmpty"`
 }
 
+// ブックマークのコレクション登録・更新用
+type BookmarkCollectionReq struct {
+	Name string `json:"name" validate:"required,max=64"`
+	Note string `json:"note" validate:"max=1000"`
+}
+
+// コレクションへのドッグラン追加用
+// ブックマーク済みのドッグランのみ追加できる
+type BookmarkCollectionItemAddReq struct {
+	DogrunIDs []int64 `json:"dogrun_id" validate:"required,notEmpty,max=100,dive,min=1"`
+	Note      string  `json:"note" validate:"max=1000"` // 追加するドッグランすべてに設定するメモ
+}
+
+// コレクション内のドッグランのメモ更新用
+type BookmarkCollectionItemUpdateReq struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+// コレクションからのドッグラン削除用
+type BookmarkCollectionItemDeleteReq struct {
+	DogrunIDs []int64 `json:"dogrun_id" validate:"required,notEmpty,max=100"`
+}
+
 type CheckinReq struct {
-	DogrunID int64   `json:"dogrun_id" validate:"required"`
-	DogIDs   []int64 `json:"dog_id" validate:"required"`
+	DogrunID int64            `json:"dogrun_id" validate:"required"`
+	DogIDs   []int64          `json:"dog_id" validate:"required,min=1,unique,dive,min=1"` // 同じdogの重複指定は不可
+	QrToken  string           `json:"qr_token"`                                           // ドッグランで表示しているQRコードのトークン
+	Location *CheckinLocation `json:"location"`                                           // クライアントの現在地
+}
+
+// チェックイン時のクライアントの現在地
+type CheckinLocation struct {
+	Latitude  float64 `json:"latitude" validate:"required,latitude"`
+	Longitude float64 `json:"longitude" validate:"required,longitude"`
 }
 
 type CheckoutReq struct {
 	DogrunID int64   `json:"dogrun_id" validate:"required"`
 	DogIDs   []int64 `json:"dog_id" validate:"required"`
 }
+
+// ドッグランのイベントストリームの購読用
+// dogrun管理者は省略すると管理するドッグランすべてを購読する
+type DogrunEventSubscribeReq struct {
+	DogrunIDs []int64 `query:"dogrunId" validate:"max=100,dive,min=1"`
+}
+
+// 日付指定のフォーマット(チェックイン履歴の期間指定など)
+const VISIT_DATE_FORMAT = "2006-01-02"
+
+// チェックイン履歴の取得用
+// 期間は入場日で指定し、from・toどちらも指定日を含む
+type VisitHistoryReq struct {
+	DogID    int64  `query:"dogId" validate:"omitempty,min=1"` // 未指定の場合は所有dogすべて
+	From     string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To       string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	PageSize int    `query:"pageSize" validate:"omitempty,gte=1,lte=100"` // 未指定の場合は20件
+	Cursor   string `query:"cursor" validate:"omitempty,max=256"`         // 前回レスポンスのnext_cursor
+}
+
+// チェックイン統計の取得用
+type VisitStatsReq struct {
+	DogID int64  `query:"dogId" validate:"omitempty,min=1"` // 未指定の場合は所有dogすべて
+	From  string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To    string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// チェックイン履歴の検索条件
+type VisitHistoryCondition struct {
+	DogownerID int64
+	DogID      int64      // 0の場合は指定なし
+	From       *time.Time // この日時以降の入場
+	To         *time.Time // この日時より前の入場
+}
+
+// レビュー一覧の並び順
+const (
+	REVIEW_SORT_NEWEST      = "newest"     // 投稿が新しい順
+	REVIEW_SORT_RATING_HIGH = "ratingHigh" // 評価が高い順
+	REVIEW_SORT_RATING_LOW  = "ratingLow"  // 評価が低い順
+)
+
+// ドッグランのレビュー投稿・編集用
+// 観点別の評価は未評価の場合は省略する
+type DogrunReviewReq struct {
+	Rating      int64  `json:"rating" validate:"required,min=1,max=5"`
+	Comment     string `json:"comment" validate:"max=2000"`
+	Cleanliness int64  `json:"cleanliness" validate:"omitempty,min=1,max=5"` // 清潔さ
+	Size        int64  `json:"size" validate:"omitempty,min=1,max=5"`        // 広さ
+	Crowding    int64  `json:"crowding" validate:"omitempty,min=1,max=5"`    // 混雑具合(空いているほど高い)
+}
+
+// ドッグランのレビュー一覧の取得用
+type DogrunReviewsReq struct {
+	Sort     string `query:"sort" validate:"omitempty,oneof=newest ratingHigh ratingLow"` // 未指定の場合はnewest
+	PageSize int    `query:"pageSize" validate:"omitempty,gte=1,lte=100"`                 // 未指定の場合は20件
+	Cursor   string `query:"cursor" validate:"omitempty,max=256"`                         // 前回レスポンスのnext_cursor
+}
+
+// ドッグランのレビュー一覧の検索条件
+type DogrunReviewCondition struct {
+	DogrunID int64
+	Sort     string
+}
+
+// レビューへの返信用
+type DogrunReviewReplyReq struct {
+	Comment string `json:"comment" validate:"required,max=2000"`
+}
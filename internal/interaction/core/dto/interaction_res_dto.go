@@ -3,8 +3,183 @@ package dto
 import "time"
 
 type CheckinsRes struct {
-	DogID       int64     `json:"dog_id"`
-	DogrunID    int64     `json:"dogrun_id"`
-	CheckinAt   time.Time `json:"checkin_at"`
-	ReCheckinAt time.Time `json:"re_checkin_at"`
+	DogrunVisitID    int64      `json:"dogrun_visit_id"`
+	DogID            int64      `json:"dog_id"`
+	DogrunID         int64      `json:"dogrun_id"`
+	CheckinAt        time.Time  `json:"checkin_at"`
+	CheckoutAt       *time.Time `json:"checkout_at,omitempty"`
+	IsOpen           bool       `json:"is_open"`
+	IsSystemCheckout bool       `json:"is_system_checkout"` // 自動チェックアウトなどシステムによる退場か
+}
+
+// ブックマークのコレクション
+// 含まれるドッグランの詳細は、ブックマーク一覧をコレクションIDで絞り込んで取得する
+type BookmarkCollectionRes struct {
+	BookmarkCollectionID int64                       `json:"bookmark_collection_id"`
+	Name                 string                      `json:"name"`
+	Note                 string                      `json:"note,omitempty"`
+	Items                []BookmarkCollectionItemRes `json:"items"`
+	IsShared             bool                        `json:"is_shared"`
+	SharedAt             *time.Time                  `json:"shared_at,omitempty"`
+	CreateAt             time.Time                   `json:"create_at"`
+	UpdateAt             time.Time                   `json:"update_at"`
+}
+
+// コレクション内のドッグラン
+type BookmarkCollectionItemRes struct {
+	DogrunID int64     `json:"dogrun_id"`
+	Note     string    `json:"note,omitempty"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+// コレクションの共有リンク
+// トークンは発行時のみ返す。再発行すると以前のリンクは無効になる
+type BookmarkCollectionShareRes struct {
+	BookmarkCollectionID int64     `json:"bookmark_collection_id"`
+	Token                string    `json:"token"`
+	SharePath            string    `json:"share_path"`
+	SharedAt             time.Time `json:"shared_at"`
+}
+
+// チェックイン用QRコードのトークン
+// RotatesAtに次のトークンに切り替わる。切り替え前のトークンもExpiresAtまでは有効
+type CheckinQrTokenRes struct {
+	DogrunID  int64     `json:"dogrun_id"`
+	Token     string    `json:"token"`
+	RotatesAt time.Time `json:"rotates_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// 入場条件の判定レベル
+const (
+	ELIGIBILITY_LEVEL_REJECT = "reject" // 入場不可
+	ELIGIBILITY_LEVEL_WARN   = "warn"   // 入場可能だが注意あり
+)
+
+// 入場条件の判定理由コード
+const (
+	ELIGIBILITY_CODE_VACCINATION_MISSING    = "VACCINATION_MISSING"    // 予防接種証明書が未登録
+	ELIGIBILITY_CODE_VACCINATION_EXPIRED    = "VACCINATION_EXPIRED"    // 予防接種証明書が有効期限外
+	ELIGIBILITY_CODE_VACCINATION_UNVERIFIED = "VACCINATION_UNVERIFIED" // 予防接種証明書が未確認
+	ELIGIBILITY_CODE_LARGE_DOG_NOT_ALLOWED  = "LARGE_DOG_NOT_ALLOWED"  // 大型犬NG
+	ELIGIBILITY_CODE_SMALL_DOG_ONLY         = "SMALL_DOG_ONLY"         // 小型犬専用
+	ELIGIBILITY_CODE_DOG_WEIGHT_UNKNOWN     = "DOG_WEIGHT_UNKNOWN"     // 体重未登録でサイズ判定不可
+	ELIGIBILITY_CODE_MANNER_PANTS_REQUIRED  = "MANNER_PANTS_REQUIRED"  // パンツ必須
+)
+
+// チェックイン可否の判定結果
+type CheckinEligibilityRes struct {
+	DogrunID int64               `json:"dogrun_id"`
+	Eligible bool                `json:"eligible"`
+	Dogs     []DogEligibilityRes `json:"dogs"`
+}
+
+// dogごとのチェックイン可否
+type DogEligibilityRes struct {
+	DogID    int64               `json:"dog_id"`
+	Eligible bool                `json:"eligible"`
+	Reasons  []EligibilityReason `json:"reasons"`
+}
+
+// チェックイン可否の理由
+type EligibilityReason struct {
+	Code        string `json:"code"`
+	Level       string `json:"level"`
+	Message     string `json:"message"`
+	VaccineType int64  `json:"vaccine_type,omitempty"`
+}
+
+// チェックイン履歴
+type VisitHistoryRes struct {
+	Visits     []VisitLogRes `json:"visits"`
+	TotalCount int64         `json:"total_count"`
+	NextCursor string        `json:"next_cursor,omitempty"` // 次のページがない場合は空
+}
+
+// チェックイン履歴の1件(ドッグラン情報を含む)
+type VisitLogRes struct {
+	DogrunVisitID    int64      `json:"dogrun_visit_id"`
+	DogID            int64      `json:"dog_id"`
+	DogName          string     `json:"dog_name"`
+	DogrunID         int64      `json:"dogrun_id"`
+	DogrunName       string     `json:"dogrun_name"`
+	Address          string     `json:"address"`
+	Latitude         float64    `json:"latitude"`
+	Longitude        float64    `json:"longitude"`
+	CheckinAt        time.Time  `json:"checkin_at"`
+	CheckoutAt       *time.Time `json:"checkout_at,omitempty"`
+	DurationMinutes  int64      `json:"duration_minutes"` // 滞在中の場合は現在までの滞在時間
+	IsOpen           bool       `json:"is_open"`
+	IsSystemCheckout bool       `json:"is_system_checkout"`
+}
+
+// チェックイン統計
+type VisitStatsRes struct {
+	TotalVisits          int64               `json:"total_visits"`
+	TotalMinutes         int64               `json:"total_minutes"`
+	FavoriteDogruns      []FavoriteDogrunRes `json:"favorite_dogruns"` // 訪問回数の多い順
+	Monthly              []MonthlyVisitRes   `json:"monthly"`          // 月の古い順。訪問のない月は含まない
+	CurrentMonthlyStreak int                 `json:"current_monthly_streak"`
+	LongestMonthlyStreak int                 `json:"longest_monthly_streak"`
+}
+
+// よく訪れるドッグラン
+type FavoriteDogrunRes struct {
+	DogrunID     int64   `json:"dogrun_id"`
+	DogrunName   string  `json:"dogrun_name"`
+	Address      string  `json:"address"`
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	VisitCount   int64   `json:"visit_count"`
+	TotalMinutes int64   `json:"total_minutes"`
+}
+
+// 月ごとの訪問
+type MonthlyVisitRes struct {
+	Month        string `json:"month"` // yyyy-MM
+	VisitCount   int64  `json:"visit_count"`
+	TotalMinutes int64  `json:"total_minutes"`
+}
+
+// ドッグランのレビュー一覧
+type DogrunReviewsRes struct {
+	DogrunID   int64                  `json:"dogrun_id"`
+	Summary    DogrunRatingSummaryRes `json:"summary"`
+	Reviews    []DogrunReviewRes      `json:"reviews"`
+	TotalCount int64                  `json:"total_count"`
+	NextCursor string                 `json:"next_cursor,omitempty"` // 次のページがない場合は空
+}
+
+// ドッグランのレビューの集計
+// 観点別の平均は評価がない場合は省略
+type DogrunRatingSummaryRes struct {
+	ReviewCount   int64    `json:"review_count"`
+	AverageRating float64  `json:"average_rating"`
+	Cleanliness   *float64 `json:"cleanliness,omitempty"`
+	Size          *float64 `json:"size,omitempty"`
+	Crowding      *float64 `json:"crowding,omitempty"`
+}
+
+// ドッグランのレビュー
+type DogrunReviewRes struct {
+	DogrunReviewID int64                 `json:"dogrun_review_id"`
+	DogrunID       int64                 `json:"dogrun_id"`
+	DogOwnerID     int64                 `json:"dog_owner_id"`
+	DogOwnerName   string                `json:"dog_owner_name"`
+	Rating         int64                 `json:"rating"`
+	Comment        string                `json:"comment,omitempty"`
+	Cleanliness    *int64                `json:"cleanliness,omitempty"`
+	Size           *int64                `json:"size,omitempty"`
+	Crowding       *int64                `json:"crowding,omitempty"`
+	Reply          *DogrunReviewReplyRes `json:"reply,omitempty"`
+	IsMine         bool                  `json:"is_mine"` // ログインユーザーのレビューか
+	CreateAt       time.Time             `json:"create_at"`
+	UpdateAt       time.Time             `json:"update_at"`
+}
+
+// ドッグラン管理者によるレビューへの返信
+type DogrunReviewReplyRes struct {
+	Comment  string    `json:"comment"`
+	CreateAt time.Time `json:"create_at"`
+	UpdateAt time.Time `json:"update_at"`
 }
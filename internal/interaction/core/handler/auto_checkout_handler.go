@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanrun-develop/wanrun/internal/interaction/adapters/eventhub"
+	"github.com/wanrun-develop/wanrun/internal/interaction/adapters/repository"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+type IAutoCheckoutHandler interface {
+	CloseOverdueVisits(context.Context, time.Time) (int, error)
+}
+
+type autoCheckoutHandler struct {
+	r           repository.IAutoCheckoutRepository
+	eh          eventhub.IEventHub
+	maxDuration time.Duration
+}
+
+func NewAutoCheckoutHandler(acr repository.IAutoCheckoutRepository, eh eventhub.IEventHub, maxDuration time.Duration) IAutoCheckoutHandler {
+	return &autoCheckoutHandler{acr, eh, maxDuration}
+}
+
+// CloseOverdueVisits: 退場時刻を過ぎた滞在中のvisitを自動チェックアウトする
+// 退場時刻はドッグランの閉店時刻。閉店時刻がない(24時間営業・営業時間未登録)場合は最大滞在時間とする
+// 退場日時は処理日時ではなく、閉店時刻または最大滞在時間の経過時刻とする
+//
+// args:
+//   - context.Context:	コンテキスト
+//   - time.Time:	判定日時
+//
+// return:
+//   - int:	自動チェックアウトしたvisit数
+//   - error:	エラー
+func (h *autoCheckoutHandler) CloseOverdueVisits(ctx context.Context, now time.Time) (int, error) {
+	logger := log.GetBaseLogger().Sugar()
+
+	visits, err := h.r.FindOpenDogrunVisits(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	checkouts := make(map[int64][]int64)
+	for _, visit := range visits {
+		checkoutAt, reason := h.checkoutTime(visit)
+		if now.Before(checkoutAt) {
+			continue
+		}
+
+		visit.CloseBySystem(checkoutAt, reason)
+		updated, err := h.r.CloseDogrunVisit(ctx, visit)
+		if err != nil {
+			return closed, err
+		}
+		if !updated {
+			// 処理中に手動でチェックアウトされた
+			continue
+		}
+		logger.Infof("dog %d のドッグラン %d の滞在を自動チェックアウト. 退場日時: %v, 理由: %d", visit.DogID.Int64, visit.DogrunID.Int64, checkoutAt, reason)
+		checkouts[visit.DogrunID.Int64] = append(checkouts[visit.DogrunID.Int64], visit.DogID.Int64)
+		closed++
+	}
+
+	h.publishAutoCheckoutEvents(ctx, checkouts, now)
+	return closed, nil
+}
+
+// checkoutTime: visitの自動チェックアウトの退場日時と理由
+//
+// args:
+//   - model.DogrunVisit:	滞在中のvisit(ドッグランの営業時間を含む)
+//
+// return:
+//   - time.Time:	退場日時
+//   - int64:	退場理由
+func (h *autoCheckoutHandler) checkoutTime(visit model.DogrunVisit) (time.Time, int64) {
+	checkinAt := asLocalWallClock(visit.CheckinAt.Time)
+	if closingAt, ok := visit.Dogrun.ClosingTimeAfter(checkinAt); ok {
+		return closingAt, model.VISIT_CHECKOUT_REASON_CLOSING
+	}
+	return checkinAt.Add(h.maxDuration), model.VISIT_CHECKOUT_REASON_EXPIRED
+}
+
+// publishAutoCheckoutEvents: 自動チェックアウトと、それに伴う混雑状況のイベントを発行
+// 発行に失敗してもチェックアウト自体は完了しているため、ログのみ出力する
+//
+// args:
+//   - context.Context:	コンテキスト
+//   - map[int64][]int64:	dogrunIDごとのチェックアウトしたdogIDs
+//   - time.Time:	発生日時
+func (h *autoCheckoutHandler) publishAutoCheckoutEvents(ctx context.Context, checkouts map[int64][]int64, at time.Time) {
+	logger := log.GetBaseLogger().Sugar()
+	if len(checkouts) == 0 {
+		return
+	}
+
+	events := []eventhub.DogrunEvent{}
+	dogrunIDs := []int64{}
+	for dogrunID, dogIDs := range checkouts {
+		events = append(events, eventhub.DogrunEvent{
+			Type:       eventhub.EVENT_TYPE_CHECKOUT,
+			DogrunID:   dogrunID,
+			DogIDs:     dogIDs,
+			System:     true,
+			OccurredAt: at,
+		})
+		dogrunIDs = append(dogrunIDs, dogrunID)
+	}
+
+	occupancies, err := h.r.CountOpenDogrunVisits(ctx, dogrunIDs)
+	if err != nil {
+		logger.Errorf("混雑状況イベントの集計に失敗: %v", err)
+	} else {
+		events = append(events, toOccupancyEvents(dogrunIDs, occupancies, at)...)
+	}
+
+	for _, event := range events {
+		if err := h.eh.Publish(ctx, event); err != nil {
+			logger.Errorf("ドッグランイベントの発行に失敗: %s dogrun:%d %v", event.Type, event.DogrunID, err)
+		}
+	}
+}
+
+// asLocalWallClock: DBの日時(timestamp)をローカルタイムゾーンの日時として扱う
+// timestampはタイムゾーンを持たずUTCとして読み込まれるため、時刻はそのままにタイムゾーンのみ置き換える
+//
+// args:
+//   - time.Time:	DBから取得した日時
+//
+// return:
+//   - time.Time:	ローカルタイムゾーンの日時
+func asLocalWallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+}
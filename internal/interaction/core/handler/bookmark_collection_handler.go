@@ -0,0 +1,419 @@
+package handler
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/interaction/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/internal/wrcontext"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+)
+
+const (
+	MAX_BOOKMARK_COLLECTIONS       = 50                 // dogownerごとのコレクション数の上限
+	BOOKMARK_COLLECTION_TOKEN_SIZE = 32                 // 共有リンクのトークンのバイト数
+	BOOKMARK_COLLECTION_SHARE_PATH = "/share/bookmark/" // 共有リンクのパス(トークンを付与する)
+)
+
+type IBookmarkCollectionHandler interface {
+	GetCollections(echo.Context) ([]dto.BookmarkCollectionRes, error)
+	CreateCollection(echo.Context, dto.BookmarkCollectionReq) (dto.BookmarkCollectionRes, error)
+	UpdateCollection(echo.Context, int64, dto.BookmarkCollectionReq) (dto.BookmarkCollectionRes, error)
+	DeleteCollection(echo.Context, int64) error
+	AddCollectionItems(echo.Context, int64, dto.BookmarkCollectionItemAddReq) (dto.BookmarkCollectionRes, error)
+	UpdateCollectionItem(echo.Context, int64, int64, dto.BookmarkCollectionItemUpdateReq) error
+	DeleteCollectionItems(echo.Context, int64, dto.BookmarkCollectionItemDeleteReq) error
+	ShareCollection(echo.Context, int64) (dto.BookmarkCollectionShareRes, error)
+	RevokeCollectionShare(echo.Context, int64) error
+}
+
+type bookmarkCollectionHandler struct {
+	bcr repository.IBookmarkCollectionRepository
+	br  repository.IBookmarkRepository
+}
+
+func NewBookmarkCollectionHandler(bcr repository.IBookmarkCollectionRepository, br repository.IBookmarkRepository) IBookmarkCollectionHandler {
+	return &bookmarkCollectionHandler{bcr, br}
+}
+
+// GetCollections: ログインユーザーのコレクションを取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - []dto.BookmarkCollectionRes:	コレクション
+//   - error:	エラー
+func (h *bookmarkCollectionHandler) GetCollections(c echo.Context) ([]dto.BookmarkCollectionRes, error) {
+	userID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return nil, err
+	}
+
+	collections, err := h.bcr.FindCollections(c, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []dto.BookmarkCollectionRes{}
+	for _, collection := range collections {
+		res = append(res, convertToBookmarkCollectionRes(collection))
+	}
+	return res, nil
+}
+
+// CreateCollection: コレクションの作成
+// コレクション名はログインユーザーのコレクション内で一意
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.BookmarkCollectionReq:	リクエストボディ
+//
+// return:
+//   - dto.BookmarkCollectionRes:	作成したコレクション
+//   - error:	エラー
+func (h *bookmarkCollectionHandler) CreateCollection(c echo.Context, reqBody dto.BookmarkCollectionReq) (dto.BookmarkCollectionRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	userID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return dto.BookmarkCollectionRes{}, err
+	}
+
+	count, err := h.bcr.CountCollections(c, userID)
+	if err != nil {
+		return dto.BookmarkCollectionRes{}, err
+	}
+	if count >= MAX_BOOKMARK_COLLECTIONS {
+		err = errors.NewWRError(nil, fmt.Sprintf("コレクションは%d件まで作成できます。", MAX_BOOKMARK_COLLECTIONS), errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return dto.BookmarkCollectionRes{}, err
+	}
+	if err := h.checkCollectionNameUnique(c, userID, 0, reqBody.Name); err != nil {
+		return dto.BookmarkCollectionRes{}, err
+	}
+
+	collection := model.BookmarkCollection{
+		DogOwnerID: util.NewSqlNullInt64(userID),
+		Name:       util.NewSqlNullString(reqBody.Name),
+		Note:       util.NewSqlNullString(reqBody.Note),
+	}
+	if err := h.bcr.CreateCollection(c, &collection); err != nil {
+		return dto.BookmarkCollectionRes{}, err
+	}
+	logger.Infof("コレクション作成. bookmarkCollectionID: %d", collection.BookmarkCollectionID.Int64)
+
+	return convertToBookmarkCollectionRes(collection), nil
+}
+
+// UpdateCollection: コレクションの名前とメモの更新
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//   - dto.BookmarkCollectionReq:	リクエストボディ
+//
+// return:
+//   - dto.BookmarkCollectionRes:	更新後のコレクション
+//   - error:	エラー
+func (h *bookmarkCollectionHandler) UpdateCollection(c echo.Context, collectionID int64, reqBody dto.BookmarkCollectionReq) (dto.BookmarkCollectionRes, error) {
+	collection, err := h.findOwnedCollection(c, collectionID)
+	if err != nil {
+		return dto.BookmarkCollectionRes{}, err
+	}
+	if err := h.checkCollectionNameUnique(c, collection.DogOwnerID.Int64, collectionID, reqBody.Name); err != nil {
+		return dto.BookmarkCollectionRes{}, err
+	}
+
+	collection.Name = util.NewSqlNullString(reqBody.Name)
+	collection.Note = util.NewSqlNullString(reqBody.Note)
+	if err := h.bcr.UpdateCollection(c, collection); err != nil {
+		return dto.BookmarkCollectionRes{}, err
+	}
+
+	return h.getOwnedCollectionRes(c, collectionID)
+}
+
+// DeleteCollection: コレクションの削除
+// コレクションに含まれるドッグランのブックマークは削除しない
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//
+// return:
+//   - error:	エラー
+func (h *bookmarkCollectionHandler) DeleteCollection(c echo.Context, collectionID int64) error {
+	if _, err := h.findOwnedCollection(c, collectionID); err != nil {
+		return err
+	}
+	return h.bcr.DeleteCollection(c, collectionID)
+}
+
+// AddCollectionItems: コレクションへのドッグランの追加
+// ブックマークしていないドッグランは追加できない。追加済みのドッグランは何もしない
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//   - dto.BookmarkCollectionItemAddReq:	リクエストボディ
+//
+// return:
+//   - dto.BookmarkCollectionRes:	追加後のコレクション
+//   - error:	エラー
+func (h *bookmarkCollectionHandler) AddCollectionItems(c echo.Context, collectionID int64, reqBody dto.BookmarkCollectionItemAddReq) (dto.BookmarkCollectionRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	collection, err := h.findOwnedCollection(c, collectionID)
+	if err != nil {
+		return dto.BookmarkCollectionRes{}, err
+	}
+
+	//ブックマーク済みかチェック
+	bookmarks, err := h.br.GetBookmarks(c, collection.DogOwnerID.Int64)
+	if err != nil {
+		return dto.BookmarkCollectionRes{}, err
+	}
+	bookmarkedDogrunIDMap := util.ConvertSliceToMap(bookmarks, func(b model.DogrunBookmark) int64 { return b.DogrunID.Int64 })
+	for _, dogrunID := range reqBody.DogrunIDs {
+		if _, exist := bookmarkedDogrunIDMap[dogrunID]; !exist {
+			err = errors.NewWRError(nil, fmt.Sprintf("ドッグランID:%dはブックマークに登録されていません。", dogrunID), errors.NewInteractionClientErrorEType())
+			logger.Error(err)
+			return dto.BookmarkCollectionRes{}, err
+		}
+	}
+
+	//追加済み・リクエスト内の重複は除外
+	addedDogrunIDMap := util.ConvertSliceToMap(collection.Items, func(i model.BookmarkCollectionItem) int64 { return i.DogrunID.Int64 })
+	items := []model.BookmarkCollectionItem{}
+	for _, dogrunID := range reqBody.DogrunIDs {
+		if _, added := addedDogrunIDMap[dogrunID]; added {
+			continue
+		}
+		addedDogrunIDMap[dogrunID] = model.BookmarkCollectionItem{}
+		items = append(items, model.BookmarkCollectionItem{
+			BookmarkCollectionID: util.NewSqlNullInt64(collectionID),
+			DogrunID:             util.NewSqlNullInt64(dogrunID),
+			Note:                 util.NewSqlNullString(reqBody.Note),
+		})
+	}
+	if err := h.bcr.AddCollectionItems(c, items); err != nil {
+		return dto.BookmarkCollectionRes{}, err
+	}
+	logger.Infof("コレクションにドッグランを追加. bookmarkCollectionID: %d, 件数: %d", collectionID, len(items))
+
+	return h.getOwnedCollectionRes(c, collectionID)
+}
+
+// UpdateCollectionItem: コレクション内のドッグランのメモを更新
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//   - int64:	dogrunID
+//   - dto.BookmarkCollectionItemUpdateReq:	リクエストボディ
+//
+// return:
+//   - error:	エラー
+func (h *bookmarkCollectionHandler) UpdateCollectionItem(c echo.Context, collectionID int64, dogrunID int64, reqBody dto.BookmarkCollectionItemUpdateReq) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if _, err := h.findOwnedCollection(c, collectionID); err != nil {
+		return err
+	}
+
+	updated, err := h.bcr.UpdateCollectionItemNote(c, collectionID, dogrunID, util.NewSqlNullString(reqBody.Note))
+	if err != nil {
+		return err
+	}
+	if !updated {
+		err = errors.NewWRError(nil, fmt.Sprintf("ドッグランID:%dはコレクションに含まれていません。", dogrunID), errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteCollectionItems: コレクションからドッグランを削除
+// ドッグランのブックマークは削除しない
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//   - dto.BookmarkCollectionItemDeleteReq:	リクエストボディ
+//
+// return:
+//   - error:	エラー
+func (h *bookmarkCollectionHandler) DeleteCollectionItems(c echo.Context, collectionID int64, reqBody dto.BookmarkCollectionItemDeleteReq) error {
+	if _, err := h.findOwnedCollection(c, collectionID); err != nil {
+		return err
+	}
+	return h.bcr.DeleteCollectionItems(c, collectionID, reqBody.DogrunIDs)
+}
+
+// ShareCollection: コレクションの共有リンクを発行する
+// トークンはハッシュのみ保存するため、発行時にしか返せない
+// 発行済みの場合は再発行し、以前のリンクは無効になる
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//
+// return:
+//   - dto.BookmarkCollectionShareRes:	共有リンク
+//   - error:	エラー
+func (h *bookmarkCollectionHandler) ShareCollection(c echo.Context, collectionID int64) (dto.BookmarkCollectionShareRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	if _, err := h.findOwnedCollection(c, collectionID); err != nil {
+		return dto.BookmarkCollectionShareRes{}, err
+	}
+
+	token, err := util.GenerateRandomToken(BOOKMARK_COLLECTION_TOKEN_SIZE)
+	if err != nil {
+		err = errors.NewWRError(err, "共有リンクのトークン生成に失敗しました。", errors.NewInteractionServerErrorEType())
+		logger.Error(err)
+		return dto.BookmarkCollectionShareRes{}, err
+	}
+	sharedAt := time.Now()
+	if err := h.bcr.UpdateCollectionShareToken(
+		c,
+		collectionID,
+		util.NewSqlNullString(util.HashToken(token)),
+		sql.NullTime{Time: sharedAt, Valid: true},
+	); err != nil {
+		return dto.BookmarkCollectionShareRes{}, err
+	}
+	logger.Infof("コレクションの共有リンクを発行. bookmarkCollectionID: %d", collectionID)
+
+	return dto.BookmarkCollectionShareRes{
+		BookmarkCollectionID: collectionID,
+		Token:                token,
+		SharePath:            BOOKMARK_COLLECTION_SHARE_PATH + token,
+		SharedAt:             sharedAt,
+	}, nil
+}
+
+// RevokeCollectionShare: コレクションの共有リンクを無効にする
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//
+// return:
+//   - error:	エラー
+func (h *bookmarkCollectionHandler) RevokeCollectionShare(c echo.Context, collectionID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	collection, err := h.findOwnedCollection(c, collectionID)
+	if err != nil {
+		return err
+	}
+	if !collection.IsShared() {
+		return nil
+	}
+	if err := h.bcr.UpdateCollectionShareToken(c, collectionID, sql.NullString{}, sql.NullTime{}); err != nil {
+		return err
+	}
+	logger.Infof("コレクションの共有リンクを無効化. bookmarkCollectionID: %d", collectionID)
+	return nil
+}
+
+// findOwnedCollection: ログインユーザーのコレクションを取得
+// 存在しない場合、他のユーザーのコレクションの場合はエラー
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//
+// return:
+//   - model.BookmarkCollection:	コレクション
+//   - error:	エラー
+func (h *bookmarkCollectionHandler) findOwnedCollection(c echo.Context, collectionID int64) (model.BookmarkCollection, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	userID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return model.BookmarkCollection{}, err
+	}
+
+	collection, err := h.bcr.FindCollection(c, collectionID, userID)
+	if err != nil {
+		return model.BookmarkCollection{}, err
+	}
+	if collection.IsEmpty() {
+		err = errors.NewWRError(nil, "指定されたコレクションが存在しません。", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return model.BookmarkCollection{}, err
+	}
+	return collection, nil
+}
+
+/*
+更新後のコレクションを取得してレスポンスに変換
+*/
+func (h *bookmarkCollectionHandler) getOwnedCollectionRes(c echo.Context, collectionID int64) (dto.BookmarkCollectionRes, error) {
+	collection, err := h.findOwnedCollection(c, collectionID)
+	if err != nil {
+		return dto.BookmarkCollectionRes{}, err
+	}
+	return convertToBookmarkCollectionRes(collection), nil
+}
+
+// checkCollectionNameUnique: コレクション名がdogownerのコレクション内で重複していないかチェック
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogownerID
+//   - int64:	更新対象のコレクションID。作成時は0
+//   - string:	コレクション名
+//
+// return:
+//   - error:	重複している場合はエラー
+func (h *bookmarkCollectionHandler) checkCollectionNameUnique(c echo.Context, dogownerID int64, collectionID int64, name string) error {
+	logger := log.GetLogger(c).Sugar()
+
+	collection, err := h.bcr.FindCollectionByName(c, dogownerID, name)
+	if err != nil {
+		return err
+	}
+	if collection.IsNotEmpty() && collection.BookmarkCollectionID.Int64 != collectionID {
+		err = errors.NewWRError(nil, fmt.Sprintf("コレクション名:%sはすでに使用されています。", name), errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+/*
+コレクションをレスポンスに変換
+*/
+func convertToBookmarkCollectionRes(collection model.BookmarkCollection) dto.BookmarkCollectionRes {
+	res := dto.BookmarkCollectionRes{
+		BookmarkCollectionID: collection.BookmarkCollectionID.Int64,
+		Name:                 collection.Name.String,
+		Note:                 collection.Note.String,
+		Items:                []dto.BookmarkCollectionItemRes{},
+		IsShared:             collection.IsShared(),
+		CreateAt:             collection.CreateAt.Time,
+		UpdateAt:             collection.UpdateAt.Time,
+	}
+	if collection.IsShared() && collection.SharedAt.Valid {
+		sharedAt := collection.SharedAt.Time
+		res.SharedAt = &sharedAt
+	}
+	for _, item := range collection.Items {
+		res.Items = append(res.Items, dto.BookmarkCollectionItemRes{
+			DogrunID: item.DogrunID.Int64,
+			Note:     item.Note.String,
+			AddedAt:  item.AddedAt.Time,
+		})
+	}
+	return res
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+)
+
+// 予防接種必須のドッグランで必要なワクチン種別
+var requiredVaccineTypes = []int64{
+	model.VACCINE_TYPE_RABIES,
+	model.VACCINE_TYPE_COMBINATION,
+}
+
+// ワクチン種別の表示名
+var vaccineTypeNames = map[int64]string{
+	model.VACCINE_TYPE_RABIES:      "狂犬病",
+	model.VACCINE_TYPE_COMBINATION: "混合ワクチン",
+}
+
+// checkinEligibility: ドッグランの入場条件
+// ドッグランのタグから入場条件を組み立て、dogごとに判定する
+type checkinEligibility struct {
+	vaccinationRequired bool
+	largeDogNG          bool
+	smallDogOnly        bool
+	mannerPantsRequired bool
+}
+
+// newCheckinEligibility: ドッグランのタグから入場条件を生成
+//
+// args:
+//   - []int64:	ドッグランのタグIDs
+//
+// return:
+//   - checkinEligibility:	入場条件
+func newCheckinEligibility(tagIDs []int64) checkinEligibility {
+	e := checkinEligibility{}
+	for _, tagID := range tagIDs {
+		switch tagID {
+		case model.TAG_ID_VACCINATION_REQUIRED:
+			e.vaccinationRequired = true
+		case model.TAG_ID_LARGE_DOG_NG:
+			e.largeDogNG = true
+		case model.TAG_ID_SMALL_DOG_ONLY:
+			e.smallDogOnly = true
+		case model.TAG_ID_MANNER_PANTS_REQUIRED:
+			e.mannerPantsRequired = true
+		}
+	}
+	return e
+}
+
+// evaluate: dogの入場可否を判定
+// reject理由が1つでもあれば入場不可。warn理由のみであれば入場可能
+//
+// args:
+//   - model.Dog:	判定対象のdog
+//   - []model.InjectionCertification:	dogの予防接種証明書
+//   - time.Time:	判定日時
+//
+// return:
+//   - dto.DogEligibilityRes:	判定結果
+func (e checkinEligibility) evaluate(dog model.Dog, certifications []model.InjectionCertification, now time.Time) dto.DogEligibilityRes {
+	reasons := []dto.EligibilityReason{}
+
+	if e.vaccinationRequired {
+		reasons = append(reasons, evaluateVaccination(certifications, now)...)
+	}
+
+	size := dog.Size()
+	if (e.largeDogNG || e.smallDogOnly) && size == model.DOG_SIZE_UNKNOWN {
+		reasons = append(reasons, dto.EligibilityReason{
+			Code:    dto.ELIGIBILITY_CODE_DOG_WEIGHT_UNKNOWN,
+			Level:   dto.ELIGIBILITY_LEVEL_WARN,
+			Message: "体重が未登録のため、サイズの入場条件を判定できません。",
+		})
+	}
+	if e.largeDogNG && size == model.DOG_SIZE_LARGE {
+		reasons = append(reasons, dto.EligibilityReason{
+			Code:    dto.ELIGIBILITY_CODE_LARGE_DOG_NOT_ALLOWED,
+			Level:   dto.ELIGIBILITY_LEVEL_REJECT,
+			Message: "このドッグランは大型犬の入場ができません。",
+		})
+	}
+	if e.smallDogOnly && (size == model.DOG_SIZE_MEDIUM || size == model.DOG_SIZE_LARGE) {
+		reasons = append(reasons, dto.EligibilityReason{
+			Code:    dto.ELIGIBILITY_CODE_SMALL_DOG_ONLY,
+			Level:   dto.ELIGIBILITY_LEVEL_REJECT,
+			Message: "このドッグランは小型犬専用です。",
+		})
+	}
+
+	if e.mannerPantsRequired {
+		reasons = append(reasons, dto.EligibilityReason{
+			Code:    dto.ELIGIBILITY_CODE_MANNER_PANTS_REQUIRED,
+			Level:   dto.ELIGIBILITY_LEVEL_WARN,
+			Message: "このドッグランではマナーパンツの着用が必須です。",
+		})
+	}
+
+	eligible := true
+	for _, reason := range reasons {
+		if reason.Level == dto.ELIGIBILITY_LEVEL_REJECT {
+			eligible = false
+			break
+		}
+	}
+
+	return dto.DogEligibilityRes{
+		DogID:    dog.DogID.Int64,
+		Eligible: eligible,
+		Reasons:  reasons,
+	}
+}
+
+// evaluateVaccination: 必要なワクチン種別ごとに予防接種証明書を判定
+// 未登録・有効期限外は入場不可。有効だがdogrun管理者が未確認の場合は注意
+//
+// args:
+//   - []model.InjectionCertification:	dogの予防接種証明書
+//   - time.Time:	判定日時
+//
+// return:
+//   - []dto.EligibilityReason:	判定理由
+func evaluateVaccination(certifications []model.InjectionCertification, now time.Time) []dto.EligibilityReason {
+	reasons := []dto.EligibilityReason{}
+
+	for _, vaccineType := range requiredVaccineTypes {
+		registered, valid, verified := false, false, false
+		for _, ic := range certifications {
+			if ic.Type.Int64 != vaccineType {
+				continue
+			}
+			registered = true
+			if ic.IsValidAt(now) {
+				valid = true
+				verified = verified || ic.IsVerified()
+			}
+		}
+
+		name := vaccineTypeNames[vaccineType]
+		switch {
+		case !registered:
+			reasons = append(reasons, dto.EligibilityReason{
+				Code:        dto.ELIGIBILITY_CODE_VACCINATION_MISSING,
+				Level:       dto.ELIGIBILITY_LEVEL_REJECT,
+				Message:     fmt.Sprintf("%sの予防接種証明書が登録されていません。", name),
+				VaccineType: vaccineType,
+			})
+		case !valid:
+			reasons = append(reasons, dto.EligibilityReason{
+				Code:        dto.ELIGIBILITY_CODE_VACCINATION_EXPIRED,
+				Level:       dto.ELIGIBILITY_LEVEL_REJECT,
+				Message:     fmt.Sprintf("%sの予防接種証明書が有効期限外です。", name),
+				VaccineType: vaccineType,
+			})
+		case !verified:
+			reasons = append(reasons, dto.EligibilityReason{
+				Code:        dto.ELIGIBILITY_CODE_VACCINATION_UNVERIFIED,
+				Level:       dto.ELIGIBILITY_LEVEL_WARN,
+				Message:     fmt.Sprintf("%sの予防接種証明書がドッグランで未確認です。受付で提示してください。", name),
+				VaccineType: vaccineType,
+			})
+		}
+	}
+	return reasons
+}
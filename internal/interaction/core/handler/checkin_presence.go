@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/configs"
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+)
+
+// チェックイン用QRコードのトークンのaudience
+// 認証用のJWTと署名鍵を共有する場合でも、取り違えないように区別する
+const CHECKIN_QR_AUDIENCE = "wanrun-checkin-qr"
+
+// CheckinQrClaims: チェックイン用QRコードのトークンのクレーム
+type CheckinQrClaims struct {
+	DogrunID int64 `json:"dogrun_id"`
+	jwt.RegisteredClaims
+}
+
+// issueCheckinQrToken: ドッグランのチェックイン用QRコードのトークンを発行
+// 切り替え間隔ごとの区間で同じトークンとなるよう、発行日時は区間の開始日時とする
+// 切り替え直前に読み取ったトークンも使えるよう、有効期限は次の区間の終わりまでとする
+//
+// args:
+//   - int64:	dogrunID
+//   - time.Time:	発行日時
+//
+// return:
+//   - dto.CheckinQrTokenRes:	トークン
+//   - error:	エラー
+func issueCheckinQrToken(dogrunID int64, now time.Time) (dto.CheckinQrTokenRes, error) {
+	rotation := checkinQrRotation()
+	windowStart := now.Truncate(rotation)
+	rotatesAt := windowStart.Add(rotation)
+	expiresAt := rotatesAt.Add(rotation)
+
+	claims := CheckinQrClaims{
+		DogrunID: dogrunID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{CHECKIN_QR_AUDIENCE},
+			IssuedAt:  jwt.NewNumericDate(windowStart),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(checkinQrSecretKey())
+	if err != nil {
+		return dto.CheckinQrTokenRes{}, err
+	}
+
+	return dto.CheckinQrTokenRes{
+		DogrunID:  dogrunID,
+		Token:     token,
+		RotatesAt: rotatesAt,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// verifyCheckinQrToken: チェックイン用QRコードのトークンの検証
+//
+// args:
+//   - string:	トークン
+//   - int64:	チェックイン先のdogrunID
+//   - time.Time:	検証日時
+//
+// return:
+//   - bool:	有効なトークンか
+func verifyCheckinQrToken(token string, dogrunID int64, now time.Time) bool {
+	claims := CheckinQrClaims{}
+	parsed, err := jwt.ParseWithClaims(token, &claims,
+		func(*jwt.Token) (interface{}, error) {
+			return checkinQrSecretKey(), nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(CHECKIN_QR_AUDIENCE),
+		jwt.WithTimeFunc(func() time.Time { return now }),
+	)
+	if err != nil || !parsed.Valid {
+		return false
+	}
+	return claims.DogrunID == dogrunID
+}
+
+// verifyCheckinPresence: チェックインするユーザーがドッグランにいることの確認
+// 管理されているドッグランはQRコードのトークン、または位置情報で確認する
+// 管理されていないドッグランはQRコードを表示できないため、位置情報のみで確認する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - model.Dogrun:	チェックイン先のドッグラン
+//   - dto.CheckinReq:	リクエストボディ
+//   - time.Time:	チェックイン日時
+//
+// return:
+//   - error:	エラー。確認できない場合はinteractionのクライアントエラー
+func verifyCheckinPresence(c echo.Context, dogrun model.Dogrun, reqBody dto.CheckinReq, now time.Time) error {
+	logger := log.GetLogger(c).Sugar()
+
+	if !configs.FetchConfigBool("checkin.presence.required") {
+		return nil
+	}
+
+	dogrunID := dogrun.DogrunID.Int64
+	managed := dogrun.DogrunManagerID.Valid
+	if reqBody.QrToken != "" {
+		if managed && verifyCheckinQrToken(reqBody.QrToken, dogrunID, now) {
+			logger.Infof("QRコードでドッグラン %d の現地確認済み", dogrunID)
+			return nil
+		}
+		if reqBody.Location == nil {
+			return presenceError(c, "QRコードが無効、または有効期限切れです。もう一度読み取ってください。")
+		}
+		logger.Info("QRコードが無効のため、位置情報で現地確認")
+	}
+
+	if reqBody.Location == nil {
+		if managed {
+			return presenceError(c, "チェックインにはドッグランのQRコードの読み取り、または位置情報が必要です。")
+		}
+		return presenceError(c, "チェックインには位置情報が必要です。")
+	}
+	if !dogrun.Latitude.Valid || !dogrun.Longitude.Valid {
+		return presenceError(c, "ドッグランの位置が登録されていないため、位置情報で確認できません。")
+	}
+
+	radius := float64(configs.FetchConfigInt("checkin.geofence.radius"))
+	distance := util.CalcDistanceMeter(
+		reqBody.Location.Latitude, reqBody.Location.Longitude,
+		dogrun.Latitude.Float64, dogrun.Longitude.Float64,
+	)
+	if distance > radius {
+		return presenceError(c, fmt.Sprintf("ドッグランから離れているためチェックインできません。(約%.0fm)", distance))
+	}
+	logger.Infof("位置情報でドッグラン %d の現地確認済み. 距離: %.0fm", dogrunID, distance)
+	return nil
+}
+
+// presenceError: 現地確認できない場合のエラー
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - string:	メッセージ
+//
+// return:
+//   - error:	エラー
+func presenceError(c echo.Context, msg string) error {
+	logger := log.GetLogger(c).Sugar()
+	err := errors.NewWRError(nil, msg, errors.NewInteractionClientErrorEType())
+	logger.Error(err)
+	return err
+}
+
+// checkinQrRotation: QRコードのトークンの切り替え間隔
+func checkinQrRotation() time.Duration {
+	seconds := configs.FetchConfigInt("checkin.qr.rotation")
+	if seconds <= 0 {
+		seconds = 180
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// checkinQrSecretKey: QRコードのトークンの署名鍵
+// 未設定の場合はjwtの秘密鍵を使用する(audienceで区別する)
+func checkinQrSecretKey() []byte {
+	if key := configs.FetchConfigStr("checkin.qr.secret.key"); key != "" {
+		return []byte(key)
+	}
+	return []byte(configs.FetchConfigStr("jwt.os.secret.key"))
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/auth/core"
+	dogrunFacade "github.com/wanrun-develop/wanrun/internal/dogrun/facade"
+	"github.com/wanrun-develop/wanrun/internal/interaction/adapters/eventhub"
+	"github.com/wanrun-develop/wanrun/internal/interaction/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/internal/wrcontext"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+type IDogrunEventHandler interface {
+	Subscribe(echo.Context, dto.DogrunEventSubscribeReq) (*eventhub.Subscription, []eventhub.DogrunEvent, error)
+}
+
+type dogrunEventHandler struct {
+	r   repository.ICheckInOutRepository
+	drf dogrunFacade.IDogrunFacade
+	eh  eventhub.IEventHub
+}
+
+func NewDogrunEventHandler(cr repository.ICheckInOutRepository, drf dogrunFacade.IDogrunFacade, eh eventhub.IEventHub) IDogrunEventHandler {
+	return &dogrunEventHandler{cr, drf, eh}
+}
+
+// Subscribe: ドッグランのイベントの購読開始
+// dogrun管理者は管理するドッグランのすべてのイベント、それ以外は混雑状況のイベントのみ購読できる
+// 購読開始時点の混雑状況を初期イベントとして返す
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.DogrunEventSubscribeReq:	リクエスト
+//
+// return:
+//   - *eventhub.Subscription:	購読。呼び出し側でCloseすること
+//   - []eventhub.DogrunEvent:	購読開始時点の混雑状況
+//   - error:	エラー
+func (h *dogrunEventHandler) Subscribe(c echo.Context, req dto.DogrunEventSubscribeReq) (*eventhub.Subscription, []eventhub.DogrunEvent, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	filter, err := h.resolveFilter(c, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.Infof("ドッグランのイベント購読開始. dogrunIDs: %v, occupancyOnly: %v", filter.DogrunIDs, filter.OccupancyOnly)
+
+	// 初期イベントとの間のイベントを取りこぼさないよう、先に購読する
+	subscription := h.eh.Subscribe(filter)
+	snapshot, err := occupancyEvents(c, h.r, filter.DogrunIDs, time.Now())
+	if err != nil {
+		subscription.Close()
+		return nil, nil, err
+	}
+	return subscription, snapshot, nil
+}
+
+// resolveFilter: ログインユーザーのロールから購読条件を決定
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.DogrunEventSubscribeReq:	リクエスト
+//
+// return:
+//   - eventhub.SubscribeFilter:	購読条件
+//   - error:	エラー
+func (h *dogrunEventHandler) resolveFilter(c echo.Context, req dto.DogrunEventSubscribeReq) (eventhub.SubscribeFilter, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	role, err := wrcontext.GetLoginUserRole(c)
+	if err != nil {
+		return eventhub.SubscribeFilter{}, err
+	}
+
+	if role == core.DOGRUNMG_ROLE || role == core.DOGRUNMG_ADMIN_ROLE {
+		dogrunmgID, err := wrcontext.GetLoginUserID(c)
+		if err != nil {
+			return eventhub.SubscribeFilter{}, err
+		}
+		managedIDs, err := h.drf.GetManagedDogrunIDs(c, dogrunmgID)
+		if err != nil {
+			return eventhub.SubscribeFilter{}, err
+		}
+		if len(req.DogrunIDs) == 0 {
+			if len(managedIDs) == 0 {
+				err := errors.NewWRError(nil, "管理しているドッグランがありません。", errors.NewInteractionClientErrorEType())
+				logger.Error(err)
+				return eventhub.SubscribeFilter{}, err
+			}
+			return eventhub.SubscribeFilter{DogrunIDs: managedIDs}, nil
+		}
+
+		managed := make(map[int64]struct{})
+		for _, dogrunID := range managedIDs {
+			managed[dogrunID] = struct{}{}
+		}
+		for _, dogrunID := range req.DogrunIDs {
+			if _, ok := managed[dogrunID]; !ok {
+				err := errors.NewWRError(nil, fmt.Sprintf("ドッグランID:%dの管理権限がありません。", dogrunID), errors.NewInteractionClientErrorEType())
+				logger.Errorf("dogrunmg %d による管理外のdogrunのイベント購読: %v", dogrunmgID, err)
+				return eventhub.SubscribeFilter{}, err
+			}
+		}
+		return eventhub.SubscribeFilter{DogrunIDs: req.DogrunIDs}, nil
+	}
+
+	if len(req.DogrunIDs) == 0 {
+		err := errors.NewWRError(nil, "購読するドッグランIDを指定してください。", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return eventhub.SubscribeFilter{}, err
+	}
+	if err := h.drf.CheckDogrunExistByIDs(c, req.DogrunIDs); err != nil {
+		return eventhub.SubscribeFilter{}, err
+	}
+	return eventhub.SubscribeFilter{
+		DogrunIDs:     req.DogrunIDs,
+		OccupancyOnly: role != core.SYSTEM,
+	}, nil
+}
+
+// publishVisitEvents: チェックイン・チェックアウトと、それに伴う混雑状況のイベントを発行
+// イベントの発行に失敗してもチェックイン・チェックアウト自体は成功しているため、ログのみ出力する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - repository.ICheckInOutRepository:	混雑状況の集計に使用するリポジトリ
+//   - eventhub.IEventHub:	イベントハブ
+//   - map[int64][]int64:	dogrunIDごとのチェックインしたdogIDs
+//   - map[int64][]int64:	dogrunIDごとのチェックアウトしたdogIDs
+//   - time.Time:	発生日時
+func publishVisitEvents(c echo.Context, r repository.ICheckInOutRepository, eh eventhub.IEventHub, checkins map[int64][]int64, checkouts map[int64][]int64, at time.Time) {
+	logger := log.GetLogger(c).Sugar()
+
+	events := []eventhub.DogrunEvent{}
+	affectedIDs := []int64{}
+	for dogrunID, dogIDs := range checkouts {
+		if len(dogIDs) == 0 {
+			continue
+		}
+		events = append(events, eventhub.DogrunEvent{
+			Type:       eventhub.EVENT_TYPE_CHECKOUT,
+			DogrunID:   dogrunID,
+			DogIDs:     dogIDs,
+			OccurredAt: at,
+		})
+		affectedIDs = append(affectedIDs, dogrunID)
+	}
+	for dogrunID, dogIDs := range checkins {
+		if len(dogIDs) == 0 {
+			continue
+		}
+		events = append(events, eventhub.DogrunEvent{
+			Type:       eventhub.EVENT_TYPE_CHECKIN,
+			DogrunID:   dogrunID,
+			DogIDs:     dogIDs,
+			OccurredAt: at,
+		})
+		affectedIDs = append(affectedIDs, dogrunID)
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	occupancies, err := occupancyEvents(c, r, affectedIDs, at)
+	if err != nil {
+		logger.Errorf("混雑状況イベントの集計に失敗: %v", err)
+	}
+	events = append(events, occupancies...)
+
+	for _, event := range events {
+		if err := eh.Publish(c.Request().Context(), event); err != nil {
+			logger.Errorf("ドッグランイベントの発行に失敗: %s dogrun:%d %v", event.Type, event.DogrunID, err)
+		}
+	}
+}
+
+// occupancyEvents: ドッグランごとの混雑状況を集計し、イベントを生成
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - repository.ICheckInOutRepository:	リポジトリ
+//   - []int64:	dogrunIDs
+//   - time.Time:	発生日時
+//
+// return:
+//   - []eventhub.DogrunEvent:	混雑状況のイベント
+//   - error:	エラー
+func occupancyEvents(c echo.Context, r repository.ICheckInOutRepository, dogrunIDs []int64, at time.Time) ([]eventhub.DogrunEvent, error) {
+	occupancies, err := r.CountOpenDogrunVisits(c, dogrunIDs)
+	if err != nil {
+		return nil, err
+	}
+	return toOccupancyEvents(dogrunIDs, occupancies, at), nil
+}
+
+// toOccupancyEvents: 集計結果から混雑状況のイベントを生成
+// 滞在中のdogがいないドッグランも0件として含める
+//
+// args:
+//   - []int64:	dogrunIDs
+//   - []model.DogrunOccupancy:	集計結果
+//   - time.Time:	発生日時
+//
+// return:
+//   - []eventhub.DogrunEvent:	混雑状況のイベント
+func toOccupancyEvents(dogrunIDs []int64, occupancies []model.DogrunOccupancy, at time.Time) []eventhub.DogrunEvent {
+	occupancyMap := make(map[int64]model.DogrunOccupancy)
+	for _, occupancy := range occupancies {
+		occupancyMap[occupancy.DogrunID] = occupancy
+	}
+
+	events := []eventhub.DogrunEvent{}
+	for _, dogrunID := range dogrunIDs {
+		occupancy := occupancyMap[dogrunID]
+		events = append(events, eventhub.DogrunEvent{
+			Type:     eventhub.EVENT_TYPE_OCCUPANCY,
+			DogrunID: dogrunID,
+			Occupancy: &eventhub.EventOccupancy{
+				Total:   occupancy.Total,
+				Small:   occupancy.Small,
+				Medium:  occupancy.Medium,
+				Large:   occupancy.Large,
+				Unknown: occupancy.Unknown,
+			},
+			OccurredAt: at,
+		})
+	}
+	return events
+}
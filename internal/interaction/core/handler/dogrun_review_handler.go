@@ -0,0 +1,386 @@
+package handler
+
+import (
+	"database/sql"
+
+	"github.com/labstack/echo/v4"
+	"github.com/wanrun-develop/wanrun/internal/auth/core"
+	dogrunFacade "github.com/wanrun-develop/wanrun/internal/dogrun/facade"
+	"github.com/wanrun-develop/wanrun/internal/interaction/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/internal/wrcontext"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+)
+
+const (
+	DOGRUN_REVIEW_DEFAULT_PAGE_SIZE = 20 // レビュー一覧のデフォルトのページサイズ
+)
+
+type IDogrunReviewHandler interface {
+	GetDogrunReviews(echo.Context, int64, dto.DogrunReviewsReq) (dto.DogrunReviewsRes, error)
+	PostDogrunReview(echo.Context, int64, dto.DogrunReviewReq) (dto.DogrunReviewRes, error)
+	UpdateDogrunReview(echo.Context, int64, dto.DogrunReviewReq) (dto.DogrunReviewRes, error)
+	DeleteDogrunReview(echo.Context, int64) error
+	ReplyDogrunReview(echo.Context, int64, dto.DogrunReviewReplyReq) (dto.DogrunReviewRes, error)
+	DeleteDogrunReviewReply(echo.Context, int64) error
+}
+
+type dogrunReviewHandler struct {
+	r   repository.IDogrunReviewRepository
+	cr  repository.ICheckInOutRepository
+	drf dogrunFacade.IDogrunFacade
+}
+
+func NewDogrunReviewHandler(rr repository.IDogrunReviewRepository, cr repository.ICheckInOutRepository, drf dogrunFacade.IDogrunFacade) IDogrunReviewHandler {
+	return &dogrunReviewHandler{rr, cr, drf}
+}
+
+// GetDogrunReviews: ドッグランのレビューを集計とあわせてページ単位で取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - dto.DogrunReviewsReq:	リクエスト
+//
+// return:
+//   - dto.DogrunReviewsRes:	レビュー一覧
+//   - error:	エラー
+func (h *dogrunReviewHandler) GetDogrunReviews(c echo.Context, dogrunID int64, req dto.DogrunReviewsReq) (dto.DogrunReviewsRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	//dogrun存在チェック
+	if _, err := h.drf.GetDogrun(c, dogrunID); err != nil {
+		return dto.DogrunReviewsRes{}, err
+	}
+
+	offset, err := util.DecodeOffsetCursor(req.Cursor)
+	if err != nil {
+		err = errors.NewWRError(err, "カーソルが不正です", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return dto.DogrunReviewsRes{}, err
+	}
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = DOGRUN_REVIEW_DEFAULT_PAGE_SIZE
+	}
+
+	condition := dto.DogrunReviewCondition{
+		DogrunID: dogrunID,
+		Sort:     req.Sort,
+	}
+	reviews, total, err := h.r.FindDogrunReviews(c, condition, offset, pageSize)
+	if err != nil {
+		return dto.DogrunReviewsRes{}, err
+	}
+	summaries, err := h.r.SummarizeDogrunRatings(c, []int64{dogrunID})
+	if err != nil {
+		return dto.DogrunReviewsRes{}, err
+	}
+
+	loginDogownerID, err := loginDogownerIDIfAny(c)
+	if err != nil {
+		return dto.DogrunReviewsRes{}, err
+	}
+
+	res := dto.DogrunReviewsRes{
+		DogrunID:   dogrunID,
+		Reviews:    []dto.DogrunReviewRes{},
+		TotalCount: total,
+	}
+	if len(summaries) > 0 {
+		res.Summary = convertToRatingSummaryRes(summaries[0])
+	}
+	for _, review := range reviews {
+		res.Reviews = append(res.Reviews, convertToDogrunReviewRes(review, loginDogownerID))
+	}
+	if next := offset + len(reviews); int64(next) < total {
+		res.NextCursor = util.EncodeOffsetCursor(next)
+	}
+	return res, nil
+}
+
+// PostDogrunReview: ドッグランのレビューを投稿
+// チェックインしたことのあるドッグランにのみ、dogownerごとに1件投稿できる
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - dto.DogrunReviewReq:	リクエストボディ
+//
+// return:
+//   - dto.DogrunReviewRes:	投稿したレビュー
+//   - error:	エラー
+func (h *dogrunReviewHandler) PostDogrunReview(c echo.Context, dogrunID int64, reqBody dto.DogrunReviewReq) (dto.DogrunReviewRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogownerID, err := wrcontext.GetLoginDogownerID(c)
+	if err != nil {
+		return dto.DogrunReviewRes{}, err
+	}
+	//dogrun存在チェック
+	if _, err := h.drf.GetDogrun(c, dogrunID); err != nil {
+		return dto.DogrunReviewRes{}, err
+	}
+
+	//チェックイン済みかのチェック
+	visited, err := h.cr.ExistsDogrunVisitByDogowner(c, dogrunID, dogownerID)
+	if err != nil {
+		return dto.DogrunReviewRes{}, err
+	}
+	if !visited {
+		err := errors.NewWRError(nil, "チェックインしたことのないドッグランはレビューできません。", errors.NewInteractionClientErrorEType())
+		logger.Errorf("dogowner %d による未訪問のdogrun %d のレビュー: %v", dogownerID, dogrunID, err)
+		return dto.DogrunReviewRes{}, err
+	}
+
+	//投稿済みかのチェック
+	exist, err := h.r.FindDogrunReviewByDogowner(c, dogrunID, dogownerID)
+	if err != nil {
+		return dto.DogrunReviewRes{}, err
+	}
+	if exist.IsNotEmpty() {
+		err := errors.NewWRError(nil, "このドッグランはすでにレビュー済みです。", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return dto.DogrunReviewRes{}, err
+	}
+
+	review := model.DogrunReview{
+		DogrunID:   util.NewSqlNullInt64(dogrunID),
+		DogOwnerID: util.NewSqlNullInt64(dogownerID),
+	}
+	setDogrunReviewContent(&review, reqBody)
+	if err := h.r.CreateDogrunReview(c, &review); err != nil {
+		return dto.DogrunReviewRes{}, err
+	}
+	return h.findDogrunReviewRes(c, review.DogrunReviewID.Int64, dogownerID)
+}
+
+// UpdateDogrunReview: 自分のレビューを編集
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	レビューID
+//   - dto.DogrunReviewReq:	リクエストボディ
+//
+// return:
+//   - dto.DogrunReviewRes:	編集後のレビュー
+//   - error:	エラー
+func (h *dogrunReviewHandler) UpdateDogrunReview(c echo.Context, reviewID int64, reqBody dto.DogrunReviewReq) (dto.DogrunReviewRes, error) {
+	dogownerID, err := wrcontext.GetLoginDogownerID(c)
+	if err != nil {
+		return dto.DogrunReviewRes{}, err
+	}
+	review, err := h.findOwnDogrunReview(c, reviewID, dogownerID)
+	if err != nil {
+		return dto.DogrunReviewRes{}, err
+	}
+
+	setDogrunReviewContent(&review, reqBody)
+	if err := h.r.UpdateDogrunReview(c, review); err != nil {
+		return dto.DogrunReviewRes{}, err
+	}
+	return h.findDogrunReviewRes(c, reviewID, dogownerID)
+}
+
+// DeleteDogrunReview: 自分のレビューを返信とあわせて削除
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	レビューID
+//
+// return:
+//   - error:	エラー
+func (h *dogrunReviewHandler) DeleteDogrunReview(c echo.Context, reviewID int64) error {
+	dogownerID, err := wrcontext.GetLoginDogownerID(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.findOwnDogrunReview(c, reviewID, dogownerID); err != nil {
+		return err
+	}
+	return h.r.DeleteDogrunReview(c, reviewID)
+}
+
+// ReplyDogrunReview: レビューへ返信する。返信済みの場合は上書きする
+// レビューされたドッグランの管理者のみ返信できる
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	レビューID
+//   - dto.DogrunReviewReplyReq:	リクエストボディ
+//
+// return:
+//   - dto.DogrunReviewRes:	返信後のレビュー
+//   - error:	エラー
+func (h *dogrunReviewHandler) ReplyDogrunReview(c echo.Context, reviewID int64, reqBody dto.DogrunReviewReplyReq) (dto.DogrunReviewRes, error) {
+	review, dogrunmgID, err := h.findManagedDogrunReview(c, reviewID)
+	if err != nil {
+		return dto.DogrunReviewRes{}, err
+	}
+
+	reply := review.Reply
+	if reply.IsEmpty() {
+		reply = model.DogrunReviewReply{
+			DogrunReviewID: review.DogrunReviewID,
+		}
+	}
+	reply.DogrunManagerID = util.NewSqlNullInt64(dogrunmgID)
+	reply.Comment = util.NewSqlNullString(reqBody.Comment)
+	if err := h.r.SaveDogrunReviewReply(c, &reply); err != nil {
+		return dto.DogrunReviewRes{}, err
+	}
+	return h.findDogrunReviewRes(c, reviewID, 0)
+}
+
+// DeleteDogrunReviewReply: レビューへの返信を削除
+// レビューされたドッグランの管理者のみ削除できる
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	レビューID
+//
+// return:
+//   - error:	エラー
+func (h *dogrunReviewHandler) DeleteDogrunReviewReply(c echo.Context, reviewID int64) error {
+	if _, _, err := h.findManagedDogrunReview(c, reviewID); err != nil {
+		return err
+	}
+	return h.r.DeleteDogrunReviewReply(c, reviewID)
+}
+
+/*
+ログインdogownerのレビューを取得する。存在しない場合はエラー
+*/
+func (h *dogrunReviewHandler) findOwnDogrunReview(c echo.Context, reviewID int64, dogownerID int64) (model.DogrunReview, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	review, err := h.r.FindDogrunReview(c, reviewID)
+	if err != nil {
+		return model.DogrunReview{}, err
+	}
+	if review.IsEmpty() || review.DogOwnerID.Int64 != dogownerID {
+		err := errors.NewWRError(nil, "指定されたレビューが存在しません。", errors.NewInteractionClientErrorEType())
+		logger.Errorf("dogowner %d によるreview %d の操作: %v", dogownerID, reviewID, err)
+		return model.DogrunReview{}, err
+	}
+	return review, nil
+}
+
+/*
+ログインユーザーが管理するドッグランのレビューを取得する。存在しない場合、管理外の場合はエラー
+*/
+func (h *dogrunReviewHandler) findManagedDogrunReview(c echo.Context, reviewID int64) (model.DogrunReview, int64, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunmgID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return model.DogrunReview{}, 0, err
+	}
+	review, err := h.r.FindDogrunReview(c, reviewID)
+	if err != nil {
+		return model.DogrunReview{}, 0, err
+	}
+	if review.IsEmpty() {
+		err := errors.NewWRError(nil, "指定されたレビューが存在しません。", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return model.DogrunReview{}, 0, err
+	}
+	dogrun, err := h.drf.GetDogrun(c, review.DogrunID.Int64)
+	if err != nil {
+		return model.DogrunReview{}, 0, err
+	}
+	if !dogrun.DogrunManagerID.Valid || dogrun.DogrunManagerID.Int64 != dogrunmgID {
+		err := errors.NewWRError(nil, "指定されたドッグランの管理権限がありません。", errors.NewInteractionClientErrorEType())
+		logger.Errorf("dogrunmg %d による管理外のdogrun %d のレビューへの返信: %v", dogrunmgID, review.DogrunID.Int64, err)
+		return model.DogrunReview{}, 0, err
+	}
+	return review, dogrunmgID, nil
+}
+
+/*
+保存後のレビューを取得してレスポンスに変換する
+*/
+func (h *dogrunReviewHandler) findDogrunReviewRes(c echo.Context, reviewID int64, loginDogownerID int64) (dto.DogrunReviewRes, error) {
+	review, err := h.r.FindDogrunReview(c, reviewID)
+	if err != nil {
+		return dto.DogrunReviewRes{}, err
+	}
+	return convertToDogrunReviewRes(review, loginDogownerID), nil
+}
+
+/*
+ログインユーザーがdogownerの場合はdogownerIDを、それ以外は0を返す
+*/
+func loginDogownerIDIfAny(c echo.Context) (int64, error) {
+	role, err := wrcontext.GetLoginUserRole(c)
+	if err != nil {
+		return 0, err
+	}
+	if role != core.DOGOWNER_ROLE {
+		return 0, nil
+	}
+	return wrcontext.GetLoginDogownerID(c)
+}
+
+/*
+リクエストの評価と本文をレビューに詰める。観点別の評価は未指定の場合NULL
+*/
+func setDogrunReviewContent(review *model.DogrunReview, reqBody dto.DogrunReviewReq) {
+	review.Rating = util.NewSqlNullInt64(reqBody.Rating)
+	review.Comment = util.NewSqlNullString(reqBody.Comment)
+	review.CleanlinessScore = util.NewSqlNullInt64(reqBody.Cleanliness)
+	review.SizeScore = util.NewSqlNullInt64(reqBody.Size)
+	review.CrowdingScore = util.NewSqlNullInt64(reqBody.Crowding)
+}
+
+func convertToDogrunReviewRes(review model.DogrunReview, loginDogownerID int64) dto.DogrunReviewRes {
+	res := dto.DogrunReviewRes{
+		DogrunReviewID: review.DogrunReviewID.Int64,
+		DogrunID:       review.DogrunID.Int64,
+		DogOwnerID:     review.DogOwnerID.Int64,
+		DogOwnerName:   review.DogOwner.Name.String,
+		Rating:         review.Rating.Int64,
+		Comment:        review.Comment.String,
+		Cleanliness:    nullInt64Ptr(review.CleanlinessScore),
+		Size:           nullInt64Ptr(review.SizeScore),
+		Crowding:       nullInt64Ptr(review.CrowdingScore),
+		IsMine:         loginDogownerID != 0 && review.DogOwnerID.Int64 == loginDogownerID,
+		CreateAt:       review.CreateAt.Time,
+		UpdateAt:       review.UpdateAt.Time,
+	}
+	if !review.Reply.IsEmpty() {
+		res.Reply = &dto.DogrunReviewReplyRes{
+			Comment:  review.Reply.Comment.String,
+			CreateAt: review.Reply.CreateAt.Time,
+			UpdateAt: review.Reply.UpdateAt.Time,
+		}
+	}
+	return res
+}
+
+func convertToRatingSummaryRes(summary model.DogrunRatingSummary) dto.DogrunRatingSummaryRes {
+	return dto.DogrunRatingSummaryRes{
+		ReviewCount:   summary.ReviewCount,
+		AverageRating: summary.AvgRating,
+		Cleanliness:   nullFloat64Ptr(summary.AvgCleanliness),
+		Size:          nullFloat64Ptr(summary.AvgSize),
+		Crowding:      nullFloat64Ptr(summary.AvgCrowding),
+	}
+}
+
+func nullInt64Ptr(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+func nullFloat64Ptr(v sql.NullFloat64) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Float64
+}
@@ -2,10 +2,12 @@ package handler
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	dogFacade "github.com/wanrun-develop/wanrun/internal/dog/facade"
 	dogrunFacade "github.com/wanrun-develop/wanrun/internal/dogrun/facade"
+	"github.com/wanrun-develop/wanrun/internal/interaction/adapters/eventhub"
 	"github.com/wanrun-develop/wanrun/internal/interaction/adapters/repository"
 	"github.com/wanrun-develop/wanrun/internal/interaction/core/dto"
 	model "github.com/wanrun-develop/wanrun/internal/models"
@@ -105,70 +107,156 @@ func (h *bookmarkHandler) DeleteBookmark(c echo.Context, reqBody dto.BookmarkDel
 }
 
 type ICheckInOutHandler interface {
-	CheckinDogrun(echo.Context, dto.CheckinReq) error
+	CheckinDogrun(echo.Context, dto.CheckinReq) (dto.CheckinEligibilityRes, error)
+	EvaluateCheckinEligibility(echo.Context, dto.CheckinReq) (dto.CheckinEligibilityRes, error)
 	CheckoutDogrun(echo.Context, dto.CheckoutReq) error
 	GetTodayCheckins(c echo.Context) ([]dto.CheckinsRes, error)
+	IssueCheckinQrToken(echo.Context, int64) (dto.CheckinQrTokenRes, error)
 }
 
 type checkInOutHandler struct {
 	r   repository.ICheckInOutRepository
 	drf dogrunFacade.IDogrunFacade
 	df  dogFacade.IDogFacade
+	eh  eventhub.IEventHub
 }
 
-func NewCheckInOutHandler(br repository.ICheckInOutRepository, drf dogrunFacade.IDogrunFacade, df dogFacade.IDogFacade) ICheckInOutHandler {
-	return &checkInOutHandler{br, drf, df}
+func NewCheckInOutHandler(br repository.ICheckInOutRepository, drf dogrunFacade.IDogrunFacade, df dogFacade.IDogFacade, eh eventhub.IEventHub) ICheckInOutHandler {
+	return &checkInOutHandler{br, drf, df, eh}
 }
 
 // CheckinDogrun: ドッグランにチェックインする
-// すでに一度チェックイン済みなら、re_checkin_atのみの更新
+// QRコードまたは位置情報でドッグランにいることを確認できない場合はエラー
+// 入場条件を満たさないdogが含まれる場合はチェックインせず、判定結果のみ返す
+// チェックインごとにvisitを開始する。すでに同じドッグランに滞在中なら何もしない
+// 保存後、購読者へチェックイン・混雑状況のイベントを発行する
 //
 // args:
 //   - echo.Context:	コンテキスト
 //   - dto.CheckinReq:	リクエストボディ
 //
 // return:
+//   - dto.CheckinEligibilityRes:	入場条件の判定結果
 //   - error:	エラー
-func (h checkInOutHandler) CheckinDogrun(c echo.Context, reqBody dto.CheckinReq) error {
+func (h checkInOutHandler) CheckinDogrun(c echo.Context, reqBody dto.CheckinReq) (dto.CheckinEligibilityRes, error) {
 	logger := log.GetLogger(c).Sugar()
 
-	//dogrun存在チェック
-	dogrunID := reqBody.DogrunID
-	if err := h.drf.CheckDogrunExistByIDs(c, []int64{dogrunID}); err != nil {
-		return err
+	//現地確認(dogrun存在チェックを含む)
+	dogrun, err := h.drf.GetDogrun(c, reqBody.DogrunID)
+	if err != nil {
+		return dto.CheckinEligibilityRes{}, err
+	}
+	now := time.Now()
+	if err := verifyCheckinPresence(c, dogrun, reqBody, now); err != nil {
+		return dto.CheckinEligibilityRes{}, err
 	}
 
-	//dogのdogownerチェック
-	checkinDogIDs := reqBody.DogIDs
-	if err := h.df.CheckDogownerValid(c, checkinDogIDs); err != nil {
-		return err
+	//入場条件の判定(dogのdogownerチェックを含む)
+	eligibilityRes, err := h.EvaluateCheckinEligibility(c, reqBody)
+	if err != nil {
+		return dto.CheckinEligibilityRes{}, err
+	}
+	if !eligibilityRes.Eligible {
+		logger.Infof("入場条件を満たさないためチェックイン不可: %v", eligibilityRes)
+		return eligibilityRes, nil
 	}
 
-	saveCheckins := []model.DogrunCheckin{}
-	for _, dogID := range checkinDogIDs {
-		checkinResult, err := h.r.FindTodayDogrunCheckin(c, dogrunID, dogID)
+	dogrunID := reqBody.DogrunID
+	saveVisits := []model.DogrunVisit{}
+	checkins := make(map[int64][]int64)
+	checkouts := make(map[int64][]int64)
+	for _, dogID := range reqBody.DogIDs {
+		openVisit, err := h.r.FindOpenDogrunVisit(c, dogID)
 		if err != nil {
-			return err
+			return dto.CheckinEligibilityRes{}, err
 		}
-		if checkinResult.IsEmpty() {
-			logger.Info("今日の新規チェックイン")
-			checkinResult.DogrunID = util.NewSqlNullInt64(dogrunID)
-			checkinResult.DogID = util.NewSqlNullInt64(dogID)
+		if openVisit.IsOpen() {
+			if openVisit.DogrunID.Int64 == dogrunID {
+				logger.Infof("dog %d はすでにドッグラン %d に滞在中", dogID, dogrunID)
+				continue
+			}
+			//他のドッグランに滞在中であれば、そのvisitを終了してからチェックイン
+			logger.Infof("dog %d のドッグラン %d の滞在を終了", dogID, openVisit.DogrunID.Int64)
+			openVisit.Close(now, model.VISIT_CHECKOUT_REASON_MOVED)
+			saveVisits = append(saveVisits, openVisit)
+			checkouts[openVisit.DogrunID.Int64] = append(checkouts[openVisit.DogrunID.Int64], dogID)
 		}
-		saveCheckins = append(saveCheckins, checkinResult)
+		logger.Info("新規チェックイン")
+		saveVisits = append(saveVisits, model.DogrunVisit{
+			DogrunID:  util.NewSqlNullInt64(dogrunID),
+			DogID:     util.NewSqlNullInt64(dogID),
+			CheckinAt: util.NewSqlNullTime(now),
+		})
+		checkins[dogrunID] = append(checkins[dogrunID], dogID)
 	}
 
 	//保存
-	_, err := h.r.SaveDogrunCheckins(c, saveCheckins)
+	if _, err := h.r.SaveDogrunVisits(c, saveVisits); err != nil {
+		return dto.CheckinEligibilityRes{}, err
+	}
+
+	publishVisitEvents(c, h.r, h.eh, checkins, checkouts, now)
+
+	return eligibilityRes, nil
+}
+
+// EvaluateCheckinEligibility: ドッグランの入場条件をdogごとに判定する
+// ドッグランのタグ(予防接種必須・大型犬NGなど)と、dogの体重・予防接種証明書から判定
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.CheckinReq:	リクエストボディ
+//
+// return:
+//   - dto.CheckinEligibilityRes:	入場条件の判定結果
+//   - error:	エラー
+func (h checkInOutHandler) EvaluateCheckinEligibility(c echo.Context, reqBody dto.CheckinReq) (dto.CheckinEligibilityRes, error) {
+	//dogrun存在チェックとタグの取得
+	dogrunID := reqBody.DogrunID
+	tagIDs, err := h.drf.GetDogrunTagIDs(c, dogrunID)
 	if err != nil {
-		return err
+		return dto.CheckinEligibilityRes{}, err
 	}
 
-	return nil
+	//dogのdogownerチェック
+	dogIDs := reqBody.DogIDs
+	if err := h.df.CheckDogownerValid(c, dogIDs); err != nil {
+		return dto.CheckinEligibilityRes{}, err
+	}
+
+	dogs, err := h.df.GetDogsByIDs(c, dogIDs)
+	if err != nil {
+		return dto.CheckinEligibilityRes{}, err
+	}
+	dogMap := make(map[int64]model.Dog)
+	for _, dog := range dogs {
+		dogMap[dog.DogID.Int64] = dog
+	}
+
+	certificationMap, err := h.df.GetInjectionCertificationsByDogIDs(c, dogIDs)
+	if err != nil {
+		return dto.CheckinEligibilityRes{}, err
+	}
+
+	eligibility := newCheckinEligibility(tagIDs)
+	now := time.Now()
+
+	res := dto.CheckinEligibilityRes{
+		DogrunID: dogrunID,
+		Eligible: true,
+		Dogs:     []dto.DogEligibilityRes{},
+	}
+	for _, dogID := range dogIDs {
+		dogRes := eligibility.evaluate(dogMap[dogID], certificationMap[dogID], now)
+		res.Eligible = res.Eligible && dogRes.Eligible
+		res.Dogs = append(res.Dogs, dogRes)
+	}
+	return res, nil
 }
 
 // CheckoutDogrun: ドッグランにチェックアウトする
-// すでに一度チェックアウト済みならre_checkout_atのみの更新
+// 滞在中のvisitを終了する
+// 保存後、購読者へチェックアウト・混雑状況のイベントを発行する
 //
 // args:
 //   - echo.Context:	コンテキスト
@@ -191,40 +279,94 @@ func (h checkInOutHandler) CheckoutDogrun(c echo.Context, reqBody dto.CheckoutRe
 		return err
 	}
 
-	saveCheckouts := []model.DogrunCheckout{}
+	now := time.Now()
+	saveVisits := []model.DogrunVisit{}
 	for _, dogID := range checkoutDogIDs {
 		//入場しているかチェック
-		checkinResult, err := h.r.FindTodayDogrunCheckin(c, dogrunID, dogID)
+		openVisit, err := h.r.FindOpenDogrunVisit(c, dogID)
 		if err != nil {
 			return err
 		}
-		if checkinResult.IsEmpty() {
-			err := errors.NewWRError(nil, fmt.Sprintf("今日ドッグID:%dはドッグラン%dに入場していません", dogID, dogID), errors.NewInteractionClientErrorEType())
-			logger.Error(err, "入場していないドッグランへの退場リクエストのためエラー")
-			return err
-		}
-		//すでに一度チェックアウト（退場）済みかをチェック
-		checkoutResult, err := h.r.FindTodayDogrunCheckout(c, dogrunID, dogID)
-		if err != nil {
-			return err
-		}
-		if checkoutResult.IsEmpty() {
-			logger.Info("今日の新規チェックアウト")
-			checkoutResult.DogrunID = util.NewSqlNullInt64(dogrunID)
-			checkoutResult.DogID = util.NewSqlNullInt64(dogID)
+		if !openVisit.IsOpen() || openVisit.DogrunID.Int64 != dogrunID {
+			return h.notCheckedInError(c, dogrunID, dogID)
 		}
-		saveCheckouts = append(saveCheckouts, checkoutResult)
+		logger.Infof("dog %d のドッグラン %d の滞在を終了", dogID, dogrunID)
+		openVisit.Close(now, model.VISIT_CHECKOUT_REASON_MANUAL)
+		saveVisits = append(saveVisits, openVisit)
 	}
 
 	//保存
-	_, err := h.r.SaveDogrunCheckouts(c, saveCheckouts)
-	if err != nil {
+	if _, err := h.r.SaveDogrunVisits(c, saveVisits); err != nil {
 		return err
 	}
 
+	publishVisitEvents(c, h.r, h.eh, nil, map[int64][]int64{dogrunID: checkoutDogIDs}, now)
 	return nil
 }
 
+// notCheckedInError: 滞在中でないdogのチェックアウトのエラー
+// 今日すでにチェックアウト済みかどうかでメッセージを分ける
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//   - int64:	dogID
+//
+// return:
+//   - error:	エラー
+func (h checkInOutHandler) notCheckedInError(c echo.Context, dogrunID int64, dogID int64) error {
+	logger := log.GetLogger(c).Sugar()
+
+	todayVisit, err := h.r.FindTodayDogrunCheckin(c, dogrunID, dogID)
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("ドッグID:%dはドッグラン%dに入場していません", dogID, dogrunID)
+	if todayVisit.IsNotEmpty() {
+		msg = fmt.Sprintf("ドッグID:%dはドッグラン%dからすでに退場済みです", dogID, dogrunID)
+	}
+	err = errors.NewWRError(nil, msg, errors.NewInteractionClientErrorEType())
+	logger.Error(err, "入場していないドッグランへの退場リクエストのためエラー")
+	return err
+}
+
+// IssueCheckinQrToken: ドッグランに表示するチェックイン用QRコードのトークンを発行
+// ドッグランの管理者のみ発行できる
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogrunID
+//
+// return:
+//   - dto.CheckinQrTokenRes:	トークン
+//   - error:	エラー
+func (h checkInOutHandler) IssueCheckinQrToken(c echo.Context, dogrunID int64) (dto.CheckinQrTokenRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogrunmgID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return dto.CheckinQrTokenRes{}, err
+	}
+	dogrun, err := h.drf.GetDogrun(c, dogrunID)
+	if err != nil {
+		return dto.CheckinQrTokenRes{}, err
+	}
+	if !dogrun.DogrunManagerID.Valid || dogrun.DogrunManagerID.Int64 != dogrunmgID {
+		err := errors.NewWRError(nil, "指定されたドッグランの管理権限がありません。", errors.NewInteractionClientErrorEType())
+		logger.Errorf("dogrunmg %d による管理外のdogrun %d のQRコード発行: %v", dogrunmgID, dogrunID, err)
+		return dto.CheckinQrTokenRes{}, err
+	}
+
+	res, err := issueCheckinQrToken(dogrunID, time.Now())
+	if err != nil {
+		err = errors.NewWRError(err, "QRコードのトークンの発行に失敗しました。", errors.NewInteractionServerErrorEType())
+		logger.Error(err)
+		return dto.CheckinQrTokenRes{}, err
+	}
+	return res, nil
+}
+
 // GetTodayCheckins: すべての所有dogの今日のチェックイン履歴の取得
 //
 // args:
@@ -246,12 +388,18 @@ func (h checkInOutHandler) GetTodayCheckins(c echo.Context) ([]dto.CheckinsRes,
 
 	//検索結果をレスポンスに詰める
 	checkinsRes := []dto.CheckinsRes{}
-	for _, checkinResult := range checkinsResult {
+	for _, visit := range checkinsResult {
 		checkinRes := dto.CheckinsRes{
-			DogID:       checkinResult.DogID.Int64,
-			DogrunID:    checkinResult.DogrunID.Int64,
-			CheckinAt:   checkinResult.CheckinAt.Time,
-			ReCheckinAt: checkinResult.ReCheckinAt.Time,
+			DogrunVisitID:    visit.DogrunVisitID.Int64,
+			DogID:            visit.DogID.Int64,
+			DogrunID:         visit.DogrunID.Int64,
+			CheckinAt:        visit.CheckinAt.Time,
+			IsOpen:           visit.IsOpen(),
+			IsSystemCheckout: visit.IsSystemCheckout(),
+		}
+		if visit.CheckoutAt.Valid {
+			checkoutAt := visit.CheckoutAt.Time
+			checkinRes.CheckoutAt = &checkoutAt
 		}
 		checkinsRes = append(checkinsRes, checkinRes)
 	}
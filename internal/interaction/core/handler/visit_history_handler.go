@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	dogFacade "github.com/wanrun-develop/wanrun/internal/dog/facade"
+	"github.com/wanrun-develop/wanrun/internal/interaction/adapters/repository"
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/dto"
+	model "github.com/wanrun-develop/wanrun/internal/models"
+	"github.com/wanrun-develop/wanrun/internal/wrcontext"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
+)
+
+const (
+	VISIT_HISTORY_DEFAULT_PAGE_SIZE = 20 // チェックイン履歴のデフォルトのページサイズ
+	VISIT_STATS_FAVORITE_LIMIT      = 5  // 統計のよく訪れるドッグランの件数
+	VISIT_STATS_MONTH_FORMAT        = "2006-01"
+)
+
+type IVisitHistoryHandler interface {
+	GetVisitHistory(echo.Context, dto.VisitHistoryReq) (dto.VisitHistoryRes, error)
+	GetVisitStats(echo.Context, dto.VisitStatsReq) (dto.VisitStatsRes, error)
+}
+
+type visitHistoryHandler struct {
+	r  repository.ICheckInOutRepository
+	df dogFacade.IDogFacade
+}
+
+func NewVisitHistoryHandler(cr repository.ICheckInOutRepository, df dogFacade.IDogFacade) IVisitHistoryHandler {
+	return &visitHistoryHandler{cr, df}
+}
+
+// GetVisitHistory: 所有dogのチェックイン履歴をページ単位で取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.VisitHistoryReq:	リクエスト
+//
+// return:
+//   - dto.VisitHistoryRes:	チェックイン履歴
+//   - error:	エラー
+func (h *visitHistoryHandler) GetVisitHistory(c echo.Context, req dto.VisitHistoryReq) (dto.VisitHistoryRes, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	condition, err := h.buildCondition(c, req.DogID, req.From, req.To)
+	if err != nil {
+		return dto.VisitHistoryRes{}, err
+	}
+
+	offset, err := util.DecodeOffsetCursor(req.Cursor)
+	if err != nil {
+		err = errors.NewWRError(err, "カーソルが不正です", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return dto.VisitHistoryRes{}, err
+	}
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = VISIT_HISTORY_DEFAULT_PAGE_SIZE
+	}
+
+	visits, total, err := h.r.FindDogrunVisitHistory(c, condition, offset, pageSize)
+	if err != nil {
+		return dto.VisitHistoryRes{}, err
+	}
+
+	now := time.Now()
+	res := dto.VisitHistoryRes{
+		Visits:     []dto.VisitLogRes{},
+		TotalCount: total,
+	}
+	for _, visit := range visits {
+		res.Visits = append(res.Visits, convertToVisitLogRes(visit, now))
+	}
+	if next := offset + len(visits); int64(next) < total {
+		res.NextCursor = util.EncodeOffsetCursor(next)
+	}
+	return res, nil
+}
+
+// GetVisitStats: 所有dogのチェックイン統計を取得
+// 総訪問回数・総滞在時間・よく訪れるドッグラン・月ごとの訪問と連続訪問月数を集計する
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - dto.VisitStatsReq:	リクエスト
+//
+// return:
+//   - dto.VisitStatsRes:	チェックイン統計
+//   - error:	エラー
+func (h *visitHistoryHandler) GetVisitStats(c echo.Context, req dto.VisitStatsReq) (dto.VisitStatsRes, error) {
+	condition, err := h.buildCondition(c, req.DogID, req.From, req.To)
+	if err != nil {
+		return dto.VisitStatsRes{}, err
+	}
+
+	visits, err := h.r.FindDogrunVisitsByCondition(c, condition)
+	if err != nil {
+		return dto.VisitStatsRes{}, err
+	}
+	return aggregateVisitStats(visits, time.Now()), nil
+}
+
+// buildCondition: リクエストから検索条件を生成
+// dogの指定がある場合は、ログインユーザーの所有dogであるかをチェックする
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	dogID。0の場合は指定なし
+//   - string:	期間の開始日
+//   - string:	期間の終了日
+//
+// return:
+//   - dto.VisitHistoryCondition:	検索条件
+//   - error:	エラー
+func (h *visitHistoryHandler) buildCondition(c echo.Context, dogID int64, from string, to string) (dto.VisitHistoryCondition, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	dogownerID, err := wrcontext.GetLoginDogownerID(c)
+	if err != nil {
+		return dto.VisitHistoryCondition{}, err
+	}
+	if dogID != 0 {
+		if err := h.df.CheckDogownerValid(c, []int64{dogID}); err != nil {
+			return dto.VisitHistoryCondition{}, err
+		}
+	}
+
+	condition := dto.VisitHistoryCondition{
+		DogownerID: dogownerID,
+		DogID:      dogID,
+	}
+	// 入場日で指定するため、終了日は翌日の0時より前とする
+	if from != "" {
+		fromAt, _ := time.ParseInLocation(dto.VISIT_DATE_FORMAT, from, time.Local)
+		condition.From = &fromAt
+	}
+	if to != "" {
+		toDate, _ := time.ParseInLocation(dto.VISIT_DATE_FORMAT, to, time.Local)
+		toAt := toDate.AddDate(0, 0, 1)
+		condition.To = &toAt
+	}
+	if condition.From != nil && condition.To != nil && !condition.From.Before(*condition.To) {
+		err := errors.NewWRError(nil, "期間の開始日は終了日以前を指定してください。", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return dto.VisitHistoryCondition{}, err
+	}
+	return condition, nil
+}
+
+// aggregateVisitStats: visitからチェックイン統計を集計
+//
+// args:
+//   - []model.DogrunVisit:	集計対象のvisit(ドッグラン情報を含む)
+//   - time.Time:	集計日時(滞在中のvisitの滞在時間・連続訪問月数の基準)
+//
+// return:
+//   - dto.VisitStatsRes:	チェックイン統計
+func aggregateVisitStats(visits []model.DogrunVisit, now time.Time) dto.VisitStatsRes {
+	res := dto.VisitStatsRes{
+		FavoriteDogruns: []dto.FavoriteDogrunRes{},
+		Monthly:         []dto.MonthlyVisitRes{},
+	}
+
+	favoriteMap := make(map[int64]*dto.FavoriteDogrunRes)
+	monthlyMap := make(map[string]*dto.MonthlyVisitRes)
+	for _, visit := range visits {
+		minutes := visitDurationMinutes(visit, now)
+		res.TotalVisits++
+		res.TotalMinutes += minutes
+
+		dogrunID := visit.DogrunID.Int64
+		favorite, ok := favoriteMap[dogrunID]
+		if !ok {
+			favorite = &dto.FavoriteDogrunRes{
+				DogrunID:   dogrunID,
+				DogrunName: visit.Dogrun.Name.String,
+				Address:    visit.Dogrun.Address.String,
+				Latitude:   visit.Dogrun.Latitude.Float64,
+				Longitude:  visit.Dogrun.Longitude.Float64,
+			}
+			favoriteMap[dogrunID] = favorite
+		}
+		favorite.VisitCount++
+		favorite.TotalMinutes += minutes
+
+		month := visit.CheckinAt.Time.Format(VISIT_STATS_MONTH_FORMAT)
+		monthly, ok := monthlyMap[month]
+		if !ok {
+			monthly = &dto.MonthlyVisitRes{Month: month}
+			monthlyMap[month] = monthly
+		}
+		monthly.VisitCount++
+		monthly.TotalMinutes += minutes
+	}
+
+	for _, favorite := range favoriteMap {
+		res.FavoriteDogruns = append(res.FavoriteDogruns, *favorite)
+	}
+	sort.Slice(res.FavoriteDogruns, func(i, j int) bool {
+		a, b := res.FavoriteDogruns[i], res.FavoriteDogruns[j]
+		if a.VisitCount != b.VisitCount {
+			return a.VisitCount > b.VisitCount
+		}
+		if a.TotalMinutes != b.TotalMinutes {
+			return a.TotalMinutes > b.TotalMinutes
+		}
+		return a.DogrunID < b.DogrunID
+	})
+	if len(res.FavoriteDogruns) > VISIT_STATS_FAVORITE_LIMIT {
+		res.FavoriteDogruns = res.FavoriteDogruns[:VISIT_STATS_FAVORITE_LIMIT]
+	}
+
+	for _, monthly := range monthlyMap {
+		res.Monthly = append(res.Monthly, *monthly)
+	}
+	// yyyy-MMは文字列の順序と月の順序が一致する
+	sort.Slice(res.Monthly, func(i, j int) bool {
+		return res.Monthly[i].Month < res.Monthly[j].Month
+	})
+
+	res.CurrentMonthlyStreak, res.LongestMonthlyStreak = monthlyStreaks(monthlyMap, now)
+	return res
+}
+
+// monthlyStreaks: 訪問のあった月から連続訪問月数を算出
+// 今月の訪問がまだない場合も、先月まで連続していれば継続中とする
+//
+// args:
+//   - map[string]*dto.MonthlyVisitRes:	訪問のあった月(yyyy-MM)
+//   - time.Time:	基準日時
+//
+// return:
+//   - int:	現在の連続訪問月数
+//   - int:	最長の連続訪問月数
+func monthlyStreaks(monthlyMap map[string]*dto.MonthlyVisitRes, now time.Time) (int, int) {
+	visited := func(month time.Time) bool {
+		_, ok := monthlyMap[month.Format(VISIT_STATS_MONTH_FORMAT)]
+		return ok
+	}
+
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	current := 0
+	month := thisMonth
+	if !visited(month) {
+		month = month.AddDate(0, -1, 0)
+	}
+	for visited(month) {
+		current++
+		month = month.AddDate(0, -1, 0)
+	}
+
+	longest := 0
+	for key := range monthlyMap {
+		start, err := time.ParseInLocation(VISIT_STATS_MONTH_FORMAT, key, now.Location())
+		// 連続の先頭の月からのみ数える
+		if err != nil || visited(start.AddDate(0, -1, 0)) {
+			continue
+		}
+		streak := 0
+		for m := start; visited(m); m = m.AddDate(0, 1, 0) {
+			streak++
+		}
+		if streak > longest {
+			longest = streak
+		}
+	}
+	return current, longest
+}
+
+// convertToVisitLogRes: visitをチェックイン履歴のレスポンスに変換
+//
+// args:
+//   - model.DogrunVisit:	visit(dog・ドッグラン情報を含む)
+//   - time.Time:	滞在中のvisitの滞在時間の基準日時
+//
+// return:
+//   - dto.VisitLogRes:	チェックイン履歴の1件
+func convertToVisitLogRes(visit model.DogrunVisit, now time.Time) dto.VisitLogRes {
+	res := dto.VisitLogRes{
+		DogrunVisitID:    visit.DogrunVisitID.Int64,
+		DogID:            visit.DogID.Int64,
+		DogName:          visit.Dog.Name.String,
+		DogrunID:         visit.DogrunID.Int64,
+		DogrunName:       visit.Dogrun.Name.String,
+		Address:          visit.Dogrun.Address.String,
+		Latitude:         visit.Dogrun.Latitude.Float64,
+		Longitude:        visit.Dogrun.Longitude.Float64,
+		CheckinAt:        visit.CheckinAt.Time,
+		DurationMinutes:  visitDurationMinutes(visit, now),
+		IsOpen:           visit.IsOpen(),
+		IsSystemCheckout: visit.IsSystemCheckout(),
+	}
+	if visit.CheckoutAt.Valid {
+		checkoutAt := visit.CheckoutAt.Time
+		res.CheckoutAt = &checkoutAt
+	}
+	return res
+}
+
+// visitDurationMinutes: visitの滞在時間(分)
+// 滞在中の場合は基準日時までの滞在時間とする
+//
+// args:
+//   - model.DogrunVisit:	visit
+//   - time.Time:	基準日時
+//
+// return:
+//   - int64:	滞在時間(分)
+func visitDurationMinutes(visit model.DogrunVisit, now time.Time) int64 {
+	var duration time.Duration
+	if visit.CheckoutAt.Valid {
+		duration = visit.CheckoutAt.Time.Sub(visit.CheckinAt.Time)
+	} else {
+		duration = now.Sub(asLocalWallClock(visit.CheckinAt.Time))
+	}
+	if duration < 0 {
+		return 0
+	}
+	return int64(duration / time.Minute)
+}
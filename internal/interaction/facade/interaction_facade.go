@@ -3,19 +3,27 @@ package facade
 import (
 	"github.com/labstack/echo/v4"
 	"github.com/wanrun-develop/wanrun/internal/interaction/adapters/repository"
+	model "github.com/wanrun-develop/wanrun/internal/models"
 	"github.com/wanrun-develop/wanrun/internal/wrcontext"
+	"github.com/wanrun-develop/wanrun/pkg/errors"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+	"github.com/wanrun-develop/wanrun/pkg/util"
 )
 
 type IBookmarkFacade interface {
 	GetAllUserBookmarks(echo.Context) ([]int64, error)
+	GetUserBookmarks(echo.Context) ([]model.DogrunBookmark, error)
+	GetUserBookmarkCollection(echo.Context, int64) (model.BookmarkCollection, error)
+	GetSharedBookmarkCollection(echo.Context, string) (model.BookmarkCollection, error)
 }
 
 type bookmarkFacade struct {
-	r repository.IBookmarkRepository
+	r   repository.IBookmarkRepository
+	bcr repository.IBookmarkCollectionRepository
 }
 
-func NewBookmarkFacade(br repository.IBookmarkRepository) IBookmarkFacade {
-	return &bookmarkFacade{br}
+func NewBookmarkFacade(br repository.IBookmarkRepository, bcr repository.IBookmarkCollectionRepository) IBookmarkFacade {
+	return &bookmarkFacade{br, bcr}
 }
 
 // GetAllUserBookmarks: ログインユーザーのブックマークを取得
@@ -45,3 +53,151 @@ func (f *bookmarkFacade) GetAllUserBookmarks(c echo.Context) ([]int64, error) {
 
 	return bookmarkedDogrunIDs, nil
 }
+
+// GetUserBookmarks: ログインユーザーのブックマークを取得
+//
+// args:
+//   - echo.Context:	コンテキスト
+//
+// return:
+//   - []model.DogrunBookmark:	ブックマーク(登録日時を含む)
+//   - error:	エラー
+func (f *bookmarkFacade) GetUserBookmarks(c echo.Context) ([]model.DogrunBookmark, error) {
+	userID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return nil, err
+	}
+	return f.r.GetBookmarks(c, userID)
+}
+
+// GetUserBookmarkCollection: ログインユーザーのコレクションを、含まれるドッグランとあわせて取得
+// 存在しない場合、他のユーザーのコレクションの場合はエラー
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - int64:	コレクションID
+//
+// return:
+//   - model.BookmarkCollection:	コレクション
+//   - error:	エラー
+func (f *bookmarkFacade) GetUserBookmarkCollection(c echo.Context, collectionID int64) (model.BookmarkCollection, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	userID, err := wrcontext.GetLoginUserID(c)
+	if err != nil {
+		return model.BookmarkCollection{}, err
+	}
+
+	collection, err := f.bcr.FindCollection(c, collectionID, userID)
+	if err != nil {
+		return model.BookmarkCollection{}, err
+	}
+	if collection.IsEmpty() {
+		err = errors.NewWRError(nil, "指定されたコレクションが存在しません。", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return model.BookmarkCollection{}, err
+	}
+	return collection, nil
+}
+
+// GetSharedBookmarkCollection: 共有リンクのトークンでコレクションを、含まれるドッグランとあわせて取得
+// トークンが無効(未発行・再発行済み・無効化済み)の場合はエラー
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - string:	共有リンクのトークン
+//
+// return:
+//   - model.BookmarkCollection:	コレクション
+//   - error:	エラー
+func (f *bookmarkFacade) GetSharedBookmarkCollection(c echo.Context, token string) (model.BookmarkCollection, error) {
+	logger := log.GetLogger(c).Sugar()
+
+	collection, err := f.bcr.FindCollectionByShareTokenHash(c, util.HashToken(token))
+	if err != nil {
+		return model.BookmarkCollection{}, err
+	}
+	if collection.IsEmpty() {
+		err = errors.NewWRError(nil, "共有リンクが無効です。", errors.NewInteractionClientErrorEType())
+		logger.Error(err)
+		return model.BookmarkCollection{}, err
+	}
+	return collection, nil
+}
+
+type IVisitFacade interface {
+	GetOccupancies(echo.Context, []int64) (map[int64]model.DogrunOccupancy, error)
+}
+
+type visitFacade struct {
+	r repository.ICheckInOutRepository
+}
+
+func NewVisitFacade(cr repository.ICheckInOutRepository) IVisitFacade {
+	return &visitFacade{cr}
+}
+
+// GetOccupancies: ドッグランごとの滞在中のdog数を取得
+// 滞在中のdogがいないドッグランも0件として含める
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []int64:	dogrunIDs
+//
+// return:
+//   - map[int64]model.DogrunOccupancy:	dogrunIDごとの滞在状況
+//   - error:	エラー
+func (f *visitFacade) GetOccupancies(c echo.Context, dogrunIDs []int64) (map[int64]model.DogrunOccupancy, error) {
+	occupancyMap := make(map[int64]model.DogrunOccupancy)
+	if len(dogrunIDs) == 0 {
+		return occupancyMap, nil
+	}
+
+	occupancies, err := f.r.CountOpenDogrunVisits(c, dogrunIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dogrunID := range dogrunIDs {
+		occupancyMap[dogrunID] = model.DogrunOccupancy{DogrunID: dogrunID}
+	}
+	for _, occupancy := range occupancies {
+		occupancyMap[occupancy.DogrunID] = occupancy
+	}
+	return occupancyMap, nil
+}
+
+type IReviewFacade interface {
+	GetRatingSummaries(echo.Context, []int64) (map[int64]model.DogrunRatingSummary, error)
+}
+
+type reviewFacade struct {
+	r repository.IDogrunReviewRepository
+}
+
+func NewReviewFacade(rr repository.IDogrunReviewRepository) IReviewFacade {
+	return &reviewFacade{rr}
+}
+
+// GetRatingSummaries: ドッグランごとのレビューの集計を取得
+// レビューのないドッグランは含まない
+//
+// args:
+//   - echo.Context:	コンテキスト
+//   - []int64:	dogrunIDs
+//
+// return:
+//   - map[int64]model.DogrunRatingSummary:	dogrunIDごとのレビューの集計
+//   - error:	エラー
+func (f *reviewFacade) GetRatingSummaries(c echo.Context, dogrunIDs []int64) (map[int64]model.DogrunRatingSummary, error) {
+	summaries, err := f.r.SummarizeDogrunRatings(c, dogrunIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	summaryMap := make(map[int64]model.DogrunRatingSummary, len(summaries))
+	for _, summary := range summaries {
+		summaryMap[summary.DogrunID] = summary
+	}
+	return summaryMap, nil
+}
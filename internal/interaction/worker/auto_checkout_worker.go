@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanrun-develop/wanrun/internal/interaction/core/handler"
+	"github.com/wanrun-develop/wanrun/pkg/log"
+)
+
+// IAutoCheckoutWorker: 自動チェックアウトを定期実行するワーカー
+type IAutoCheckoutWorker interface {
+	Run(context.Context)
+}
+
+type autoCheckoutWorker struct {
+	h        handler.IAutoCheckoutHandler
+	interval time.Duration
+}
+
+// 実行間隔が未設定の場合のデフォルト
+const defaultAutoCheckoutInterval = 5 * time.Minute
+
+func NewAutoCheckoutWorker(h handler.IAutoCheckoutHandler, interval time.Duration) IAutoCheckoutWorker {
+	if interval <= 0 {
+		interval = defaultAutoCheckoutInterval
+	}
+	return &autoCheckoutWorker{h, interval}
+}
+
+// Run: 起動直後と一定間隔ごとに自動チェックアウトを実行
+// コンテキストがキャンセルされるまでブロックするため、goroutineで実行すること
+//
+// args:
+//   - context.Context:	停止用のコンテキスト
+func (w *autoCheckoutWorker) Run(ctx context.Context) {
+	logger := log.GetBaseLogger().Sugar()
+	logger.Infof("自動チェックアウトのワーカーを開始. 実行間隔: %v", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			logger.Info("自動チェックアウトのワーカーを停止")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce: 自動チェックアウトを1回実行
+// 失敗しても次回の実行で再度対象となるため、ログのみ出力してワーカーは継続する
+//
+// args:
+//   - context.Context:	コンテキスト
+func (w *autoCheckoutWorker) runOnce(ctx context.Context) {
+	logger := log.GetBaseLogger().Sugar()
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("自動チェックアウトで予期せぬエラー: %v", r)
+		}
+	}()
+
+	closed, err := w.h.CloseOverdueVisits(ctx, time.Now())
+	if err != nil {
+		logger.Errorf("自動チェックアウトに失敗: %v", err)
+	}
+	if closed > 0 {
+		logger.Infof("自動チェックアウト件数: %d", closed)
+	}
+}
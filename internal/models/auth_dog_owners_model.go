@@ -18,7 +18,6 @@ type AuthDogOwner struct {
 	RefreshToken           sql.NullString  `gorm:"size:512;column:refresh_token"`
 	AccessTokenExpiration  util.CustomTime `gorm:"column:access_token_expiration"`
 	RefreshTokenExpiration util.CustomTime `gorm:"column:refresh_token_expiration"`
-	JwtID                  sql.NullString  `gorm:"size:45;column:jwt_id"`
 	LoginAt                time.Time       `gorm:"column:login_at;not null;autoCreateTime"`
 
 	DogOwner   DogOwner      `gorm:"foreignKey:DogOwnerID;references:DogOwnerID"`
@@ -26,15 +25,24 @@ type AuthDogOwner struct {
 }
 
 type DogOwnerCredential struct {
-	CredentialID sql.NullInt64 `gorm:"primaryKey;column:credential_id;autoIncrement"`
-	// ProviderName   sql.NullString `gorm:"size:50;column:provider_name"`
-	ProviderUserID sql.NullString `gorm:"size:256;column:provider_user_id"`
-	Email          sql.NullString `gorm:"size:256;column:email"`
-	PhoneNumber    sql.NullString `gorm:"size:15;column:phone_number"`
-	Password       sql.NullString `gorm:"size:256;column:password"`
-	GrantType      sql.NullString `gorm:"column:grant_type"`
-	LoginAt        sql.NullTime   `gorm:"column:login_at;autoCreateTime"`
+	CredentialID    sql.NullInt64  `gorm:"primaryKey;column:credential_id;autoIncrement"`
+	ProviderName    sql.NullString `gorm:"size:50;column:provider_name"`
+	ProviderUserID  sql.NullString `gorm:"size:256;column:provider_user_id"`
+	Email           sql.NullString `gorm:"size:256;column:email"`
+	PhoneNumber     sql.NullString `gorm:"size:15;column:phone_number"`
+	Password        sql.NullString `gorm:"size:256;column:password"`
+	GrantType       sql.NullString `gorm:"column:grant_type"`
+	EmailVerifiedAt sql.NullTime   `gorm:"column:email_verified_at"` // メールアドレスの確認日時。未確認の場合はNULL
+	PhoneVerifiedAt sql.NullTime   `gorm:"column:phone_verified_at"` // 電話番号の確認日時。未確認の場合はNULL
+	LoginAt         sql.NullTime   `gorm:"column:login_at;autoCreateTime"`
 
 	AuthDogOwner   AuthDogOwner  `gorm:"foreignKey:AuthDogOwnerID;references:AuthDogOwnerID"`
 	AuthDogOwnerID sql.NullInt64 `gorm:"column:auth_dog_owner_id;not null"`
 }
+
+/*
+メールアドレスか電話番号のどちらかが確認済みであるか
+*/
+func (doc *DogOwnerCredential) IsVerified() bool {
+	return doc.EmailVerifiedAt.Valid || doc.PhoneVerifiedAt.Valid
+}
@@ -6,20 +6,21 @@ import (
 )
 
 type AuthDogrunmg struct {
-	AuthDogrunmgID sql.NullInt64  `gorm:"primaryKey;column:auth_dogrun_manager_id;autoIncrement"`
-	JwtID          sql.NullString `gorm:"size:45;column:jwt_id"`
-	IsAdmin        sql.NullBool   `gorm:"column:is_admin"`
-	LoginAt        time.Time      `gorm:"column:login_at;not null;autoCreateTime"`
+	AuthDogrunmgID sql.NullInt64 `gorm:"primaryKey;column:auth_dogrun_manager_id;autoIncrement"`
+	IsAdmin        sql.NullBool  `gorm:"column:is_admin"`
+	IsActive       sql.NullBool  `gorm:"column:is_active;default:true"` // 無効化されたスタッフはfalse
+	LoginAt        time.Time     `gorm:"column:login_at;not null;autoCreateTime"`
 
 	Dogrunmg   Dogrunmg      `gorm:"foreignKey:DogrunmgID;references:DogrunmgID"`
 	DogrunmgID sql.NullInt64 `gorm:"column:dogrun_manager_id;not null"`
 }
 
 type DogrunmgCredential struct {
-	CredentialID sql.NullInt64  `gorm:"primaryKey;column:credential_id;autoIncrement"`
-	Email        sql.NullString `gorm:"size:255;column:email"`
-	Password     sql.NullString `gorm:"size:256;column:password"`
-	LoginAt      sql.NullTime   `gorm:"column:login_at;autoCreateTime"`
+	CredentialID    sql.NullInt64  `gorm:"primaryKey;column:credential_id;autoIncrement"`
+	Email           sql.NullString `gorm:"size:255;column:email"`
+	Password        sql.NullString `gorm:"size:256;column:password"`
+	EmailVerifiedAt sql.NullTime   `gorm:"column:email_verified_at"` // メールアドレスの確認日時。未確認の場合はNULL
+	LoginAt         sql.NullTime   `gorm:"column:login_at;autoCreateTime"`
 
 	AuthDogrunmg   AuthDogrunmg  `gorm:"foreignKey:AuthDogrunmgID;references:AuthDogrunmgID"`
 	AuthDogrunmgID sql.NullInt64 `gorm:"column:auth_dogrun_manager_id;not null"`
@@ -32,3 +33,10 @@ func (AuthDogrunmg) TableName() string {
 func (DogrunmgCredential) TableName() string {
 	return "dogrun_manager_credentials"
 }
+
+/*
+AuthDogrunmgが無効化されているか
+*/
+func (adm *AuthDogrunmg) IsDeactivated() bool {
+	return adm.IsActive.Valid && !adm.IsActive.Bool
+}
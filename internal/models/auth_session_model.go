@@ -0,0 +1,40 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ログインセッション
+// jwtのjti(jwt_id)ごとに管理し、dogownerかdogrunmgのどちらかに紐づく
+type AuthSession struct {
+	SessionID  sql.NullInt64  `gorm:"primaryKey;column:session_id;autoIncrement"`
+	JwtID      sql.NullString `gorm:"size:45;column:jwt_id;not null"`
+	DogOwnerID sql.NullInt64  `gorm:"column:dog_owner_id"`
+	DogrunmgID sql.NullInt64  `gorm:"column:dogrun_manager_id"`
+	DeviceName sql.NullString `gorm:"size:128;column:device_name"`
+	UserAgent  sql.NullString `gorm:"size:512;column:user_agent"`
+	IPAddress  sql.NullString `gorm:"size:45;column:ip_address"`
+	LastSeenAt sql.NullTime   `gorm:"column:last_seen_at;not null"`
+	ExpiresAt  sql.NullTime   `gorm:"column:expires_at;not null"` // リフレッシュトークンの有効期限と同じ
+	RevokedAt  sql.NullTime   `gorm:"column:revoked_at"`
+	CreateAt   sql.NullTime   `gorm:"column:reg_at;not null;autoCreateTime"`
+}
+
+func (AuthSession) TableName() string {
+	return "auth_sessions"
+}
+
+/*
+セッションが空であるか
+*/
+func (as *AuthSession) IsEmpty() bool {
+	return !as.SessionID.Valid
+}
+
+/*
+セッションが使用可能であるか(失効・期限切れでない)
+*/
+func (as *AuthSession) IsActive(now time.Time) bool {
+	return !as.IsEmpty() && !as.RevokedAt.Valid && as.ExpiresAt.Valid && now.Before(as.ExpiresAt.Time)
+}
@@ -17,7 +17,9 @@ type S3FileInfo struct {
 
 	// DogOwnerとのリレーション
 	DogOwner   DogOwner      `gorm:"foreignKey:DogOwnerID;references:DogOwnerID"`
-	DogOwnerID sql.NullInt64 `gorm:"column:dog_owner_id;not null"` // dog_ownersのFK
+	DogOwnerID sql.NullInt64 `gorm:"column:dog_owner_id"` // dog_ownersのFK(dogownerがアップロードした場合)
+
+	DogrunManagerID sql.NullInt64 `gorm:"column:dogrun_manager_id"` // dogrun_managersのFK(dogrun_managerがアップロードした場合)
 }
 
 func (S3FileInfo) TableName() string {
@@ -4,6 +4,20 @@ import (
 	"database/sql"
 )
 
+// 犬のサイズ区分
+const (
+	DOG_SIZE_UNKNOWN = "unknown" // 体重未登録
+	DOG_SIZE_SMALL   = "small"   // 小型犬
+	DOG_SIZE_MEDIUM  = "medium"  // 中型犬
+	DOG_SIZE_LARGE   = "large"   // 大型犬
+)
+
+// サイズ区分の体重(kg)の閾値
+const (
+	DOG_SIZE_SMALL_MAX_WEIGHT int64 = 10 // 10kg未満は小型犬
+	DOG_SIZE_LARGE_MIN_WEIGHT int64 = 25 // 25kg以上は大型犬
+)
+
 type Dog struct {
 	DogID      sql.NullInt64  `gorm:"primaryKey;column:dog_id;autoIncrement"`
 	DogOwnerID sql.NullInt64  `gorm:"column:dog_owner_id;not null;foreignKey:DogOwnerID"`
@@ -24,6 +38,21 @@ func (d *Dog) IsEmpty() bool {
 	return !d.DogID.Valid
 }
 
+// 体重からサイズ区分を判定
+func (d *Dog) Size() string {
+	if !d.Weight.Valid || d.Weight.Int64 <= 0 {
+		return DOG_SIZE_UNKNOWN
+	}
+	switch {
+	case d.Weight.Int64 < DOG_SIZE_SMALL_MAX_WEIGHT:
+		return DOG_SIZE_SMALL
+	case d.Weight.Int64 >= DOG_SIZE_LARGE_MIN_WEIGHT:
+		return DOG_SIZE_LARGE
+	default:
+		return DOG_SIZE_MEDIUM
+	}
+}
+
 type DogTypeMst struct {
 	DogTypeID int    `gorm:"primaryKey;column:dog_type_id"`
 	Name      string `gorm:"column:name;not null"`
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"database/sql"
+)
+
+// クレームのステータス
+const (
+	DOGRUN_CLAIM_STATUS_PENDING  int64 = 1 // 申請中
+	DOGRUN_CLAIM_STATUS_APPROVED int64 = 2 // 承認
+	DOGRUN_CLAIM_STATUS_REJECTED int64 = 3 // 却下
+)
+
+type DogrunClaim struct {
+	DogrunClaimID  sql.NullInt64  `gorm:"primaryKey;column:dogrun_claim_id;autoIncrement"`
+	DogrunID       sql.NullInt64  `gorm:"column:dogrun_id;not null"`
+	DogrunmgID     sql.NullInt64  `gorm:"column:dogrun_manager_id;not null"`
+	OrganizationID sql.NullInt64  `gorm:"column:organization_id;not null"`
+	Evidence       sql.NullString `gorm:"type:text;column:evidence;not null"`
+	EvidenceURL    sql.NullString `gorm:"type:text;column:evidence_url"`
+	Status         sql.NullInt64  `gorm:"column:status;not null"`
+	CreateAt       sql.NullTime   `gorm:"column:reg_at;not null;autoCreateTime"`
+	UpdateAt       sql.NullTime   `gorm:"column:upd_at;not null;autoUpdateTime"`
+
+	//リレーション
+	Dogrun    Dogrun               `gorm:"foreignKey:DogrunID;references:DogrunID"`
+	Histories []DogrunClaimHistory `gorm:"foreignKey:DogrunClaimID;references:DogrunClaimID"`
+}
+
+/*
+DogrunClaimが空であるか
+*/
+func (dc *DogrunClaim) IsEmpty() bool {
+	return !dc.DogrunClaimID.Valid
+}
+
+/*
+DogrunClaimが申請中であるか
+*/
+func (dc *DogrunClaim) IsPending() bool {
+	return dc.Status.Int64 == DOGRUN_CLAIM_STATUS_PENDING
+}
+
+type DogrunClaimHistory struct {
+	DogrunClaimHistoryID sql.NullInt64  `gorm:"primaryKey;column:dogrun_claim_history_id;autoIncrement"`
+	DogrunClaimID        sql.NullInt64  `gorm:"column:dogrun_claim_id;not null"`
+	Status               sql.NullInt64  `gorm:"column:status;not null"`
+	ChangedBy            sql.NullInt64  `gorm:"column:changed_by"`
+	ChangedByRole        sql.NullInt64  `gorm:"column:changed_by_role;not null"`
+	Comment              sql.NullString `gorm:"type:text;column:comment"`
+	CreateAt             sql.NullTime   `gorm:"column:reg_at;not null;autoCreateTime"`
+}
@@ -23,6 +23,7 @@ type Dogrun struct {
 	IsManaged       sql.NullBool    `gorm:"column:is_managed"`
 	CreateAt        sql.NullTime    `gorm:"column:reg_at;not null;autoCreateTime"`
 	UpdateAt        sql.NullTime    `gorm:"column:upd_at;not null;autoUpdateTime"`
+	Distance        sql.NullFloat64 `gorm:"->;column:distance;-:migration"` // 位置検索時の基準点からの距離(m)。検索時のみ
 
 	//リレーション
 	DogrunTags           []DogrunTag           `gorm:"foreignKey:DogrunID;references:DogrunID"`
@@ -131,6 +132,94 @@ func (d *Dogrun) FetchTargetDateSpecialBusinessHour(date time.Time) SpecialBusin
 	return SpecialBusinessHour{}
 }
 
+/*
+指定日時に入場した滞在の閉店日時を返す
+前日(深夜営業)と当日の営業時間のうち、指定日時より後で最も早い閉店日時とする
+当日が24時間営業の場合、または定休日・営業時間未登録で閉店日時がない場合はfalse
+*/
+func (d *Dogrun) ClosingTimeAfter(at time.Time) (time.Time, bool) {
+	if period := d.businessPeriodOn(at); period.isAllDay {
+		return time.Time{}, false
+	}
+
+	var closing time.Time
+	found := false
+	for _, day := range []time.Time{at.AddDate(0, 0, -1), at} {
+		period := d.businessPeriodOn(day)
+		if !period.hasHours() || !period.closeAt.After(at) {
+			continue
+		}
+		if !found || period.closeAt.Before(closing) {
+			closing = period.closeAt
+			found = true
+		}
+	}
+	return closing, found
+}
+
+/*
+指定日の営業時間
+特別営業時間がある場合は優先し、ない場合は曜日の通常営業時間とする
+閉店時間が開店時間以前の場合は、翌日の閉店(深夜営業)とする
+*/
+func (d *Dogrun) businessPeriodOn(day time.Time) businessPeriod {
+	//特別営業日はdate型(UTCの0時)で保持しているため、日付のみで比較する
+	targetDate := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	special := d.FetchTargetDateSpecialBusinessHour(targetDate)
+
+	var openTime, closeTime sql.NullString
+	switch regular := d.FetchTargetRegularBusinessHour(int(day.Weekday())); {
+	case special.IsValid():
+		if special.IsAllDay.Bool {
+			return businessPeriod{isAllDay: true}
+		}
+		if special.IsClosed.Bool {
+			return businessPeriod{}
+		}
+		openTime, closeTime = special.OpenTime, special.CloseTime
+	case regular.IsValid():
+		if regular.IsClosed.Bool {
+			return businessPeriod{}
+		}
+		if regular.IsAllDay.Bool {
+			return businessPeriod{isAllDay: true}
+		}
+		openTime, closeTime = regular.OpenTime, regular.CloseTime
+	default:
+		return businessPeriod{}
+	}
+
+	openClock, openErr := time.Parse(businessTimeFormat, openTime.String)
+	closeClock, closeErr := time.Parse(businessTimeFormat, closeTime.String)
+	if !openTime.Valid || !closeTime.Valid || openErr != nil || closeErr != nil {
+		return businessPeriod{}
+	}
+
+	openAt := time.Date(day.Year(), day.Month(), day.Day(), openClock.Hour(), openClock.Minute(), openClock.Second(), 0, day.Location())
+	closeAt := time.Date(day.Year(), day.Month(), day.Day(), closeClock.Hour(), closeClock.Minute(), closeClock.Second(), 0, day.Location())
+	if !closeAt.After(openAt) {
+		closeAt = closeAt.AddDate(0, 0, 1)
+	}
+	return businessPeriod{openAt: openAt, closeAt: closeAt}
+}
+
+// 営業時間の時刻フォーマット
+const businessTimeFormat = "15:04:05"
+
+// businessPeriod: ある日の営業時間
+type businessPeriod struct {
+	openAt   time.Time
+	closeAt  time.Time
+	isAllDay bool
+}
+
+/*
+開店・閉店時刻のある営業時間か
+*/
+func (p businessPeriod) hasHours() bool {
+	return !p.closeAt.IsZero()
+}
+
 type RegularBusinessHour struct {
 	RegularBusinessHourID sql.NullInt64  `gorm:"primaryKey;column:regular_business_hours_id;autoIncrement"`
 	DogrunID              sql.NullInt64  `gorm:"not null;column:dogrun_id"`
@@ -218,6 +307,14 @@ type DogrunTag struct {
 	TagID       sql.NullInt64 `gorm:"column:tag_id;not null"`
 }
 
+// 入場条件に関わるタグ(tag_mst)
+const (
+	TAG_ID_VACCINATION_REQUIRED  int64 = 5  // 予防接種必須
+	TAG_ID_LARGE_DOG_NG          int64 = 11 // 大型犬NG
+	TAG_ID_MANNER_PANTS_REQUIRED int64 = 22 // パンツ必須
+	TAG_ID_SMALL_DOG_ONLY        int64 = 27 // 小型犬専用
+)
+
 type TagMst struct {
 	TagID       sql.NullInt64  `gorm:"primaryKey;column:tag_id;autoIncrement"`
 	TagName     sql.NullString `gorm:"size:64;column:tag_name;not null"`
@@ -229,13 +326,56 @@ func (TagMst) TableName() string {
 	return "tag_mst"
 }
 
+// ギャラリー画像のステータス
+const (
+	DOGRUN_IMAGE_STATUS_PENDING  int64 = 1 // 審査待ち
+	DOGRUN_IMAGE_STATUS_APPROVED int64 = 2 // 公開
+	DOGRUN_IMAGE_STATUS_REJECTED int64 = 3 // 却下
+)
+
+// DogrunImage: ドッグランのギャラリー画像
+// 管理者のアップロードは公開、dogownerの投稿は審査待ちで登録する
 type DogrunImage struct {
-	DogrunImageID int64         `gorm:"primaryKey;column:dogrun_image_id;autoIncrement"`
-	DogrunID      int64         `gorm:"column:dogrun_id;not null"`
-	Image         string        `gorm:"type:text;column:image;not null"`
-	SortOrder     sql.NullInt64 `gorm:"column:sort_order"`
-	UploadAt      sql.NullTime  `gorm:"column:upload_at"`
+	DogrunImageID   sql.NullInt64  `gorm:"primaryKey;column:dogrun_image_id;autoIncrement"`
+	DogrunID        sql.NullInt64  `gorm:"column:dogrun_id;not null"`
+	Image           sql.NullString `gorm:"type:text;column:image;not null"` // s3のオブジェクトキー(file_idがない場合は画像URL)
+	FileID          sql.NullString `gorm:"size:64;column:file_id"`          // s3_file_info.file_id
+	Caption         sql.NullString `gorm:"size:200;column:caption"`
+	SortOrder       sql.NullInt64  `gorm:"column:sort_order"` // 公開中の画像のみ
+	Status          sql.NullInt64  `gorm:"column:status;not null"`
+	DogOwnerID      sql.NullInt64  `gorm:"column:dog_owner_id"`      // 投稿したdogowner
+	DogrunManagerID sql.NullInt64  `gorm:"column:dogrun_manager_id"` // アップロードしたdogrun_manager
+	ReviewedBy      sql.NullInt64  `gorm:"column:reviewed_by"`
+	ReviewedAt      sql.NullTime   `gorm:"column:reviewed_at"`
+	RejectReason    sql.NullString `gorm:"size:500;column:reject_reason"`
+	UploadAt        sql.NullTime   `gorm:"column:upload_at;not null;autoCreateTime"`
+	UpdateAt        sql.NullTime   `gorm:"column:upd_at;not null;autoUpdateTime"`
 
 	//リレーション
-	Dogrun Dogrun `gorm:"foreignKey:DogrunID"`
+	Dogrun Dogrun `gorm:"foreignKey:DogrunID;references:DogrunID"`
+}
+
+func (DogrunImage) TableName() string {
+	return "dogrun_images"
+}
+
+/*
+DogrunImageが空であるか
+*/
+func (di *DogrunImage) IsEmpty() bool {
+	return !di.DogrunImageID.Valid
+}
+
+/*
+DogrunImageが審査待ちであるか
+*/
+func (di *DogrunImage) IsPending() bool {
+	return di.Status.Int64 == DOGRUN_IMAGE_STATUS_PENDING
+}
+
+/*
+DogrunImageが公開中であるか
+*/
+func (di *DogrunImage) IsApproved() bool {
+	return di.Status.Int64 == DOGRUN_IMAGE_STATUS_APPROVED
 }
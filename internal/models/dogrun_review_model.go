@@ -0,0 +1,81 @@
+package model
+
+import (
+	"database/sql"
+)
+
+// レビューの評価の範囲
+const (
+	REVIEW_RATING_MIN = 1
+	REVIEW_RATING_MAX = 5
+)
+
+// DogrunReview: チェックインしたことのあるdogownerによるドッグランのレビュー
+// dogownerごとにドッグラン1件
+type DogrunReview struct {
+	DogrunReviewID   sql.NullInt64  `gorm:"column:dogrun_review_id;primaryKey;autoIncrement"`
+	DogrunID         sql.NullInt64  `gorm:"column:dogrun_id;not null"`
+	DogOwnerID       sql.NullInt64  `gorm:"column:dog_owner_id;not null"`
+	Rating           sql.NullInt64  `gorm:"column:rating;not null"`
+	Comment          sql.NullString `gorm:"column:comment"`
+	CleanlinessScore sql.NullInt64  `gorm:"column:cleanliness_score"`
+	SizeScore        sql.NullInt64  `gorm:"column:size_score"`
+	CrowdingScore    sql.NullInt64  `gorm:"column:crowding_score"` // 空いているほど高い
+	CreateAt         sql.NullTime   `gorm:"column:reg_at;not null;autoCreateTime"`
+	UpdateAt         sql.NullTime   `gorm:"column:upd_at;not null;autoUpdateTime"`
+
+	//リレーション
+	DogOwner DogOwner          `gorm:"foreignKey:DogOwnerID;references:DogOwnerID"`
+	Reply    DogrunReviewReply `gorm:"foreignKey:DogrunReviewID;references:DogrunReviewID"`
+}
+
+func (DogrunReview) TableName() string {
+	return "dogrun_reviews"
+}
+
+/*
+DogrunReviewが空であるか
+*/
+func (r *DogrunReview) IsEmpty() bool {
+	return !r.IsNotEmpty()
+}
+
+/*
+DogrunReviewが空でないか
+*/
+func (r *DogrunReview) IsNotEmpty() bool {
+	return r.DogrunReviewID.Valid
+}
+
+// DogrunReviewReply: ドッグラン管理者によるレビューへの返信
+// レビューごとに1件
+type DogrunReviewReply struct {
+	DogrunReviewReplyID sql.NullInt64  `gorm:"column:dogrun_review_reply_id;primaryKey;autoIncrement"`
+	DogrunReviewID      sql.NullInt64  `gorm:"column:dogrun_review_id;not null"`
+	DogrunManagerID     sql.NullInt64  `gorm:"column:dogrun_manager_id;not null"`
+	Comment             sql.NullString `gorm:"column:comment;not null"`
+	CreateAt            sql.NullTime   `gorm:"column:reg_at;not null;autoCreateTime"`
+	UpdateAt            sql.NullTime   `gorm:"column:upd_at;not null;autoUpdateTime"`
+}
+
+func (DogrunReviewReply) TableName() string {
+	return "dogrun_review_replies"
+}
+
+/*
+DogrunReviewReplyが空であるか
+*/
+func (r *DogrunReviewReply) IsEmpty() bool {
+	return !r.DogrunReviewReplyID.Valid
+}
+
+// DogrunRatingSummary: ドッグランのレビューの集計
+// dogrun_reviewsの集計結果。観点別の平均は評価がない場合NULL
+type DogrunRatingSummary struct {
+	DogrunID       int64           `gorm:"column:dogrun_id"`
+	ReviewCount    int64           `gorm:"column:review_count"`
+	AvgRating      float64         `gorm:"column:avg_rating"`
+	AvgCleanliness sql.NullFloat64 `gorm:"column:avg_cleanliness"`
+	AvgSize        sql.NullFloat64 `gorm:"column:avg_size"`
+	AvgCrowding    sql.NullFloat64 `gorm:"column:avg_crowding"`
+}
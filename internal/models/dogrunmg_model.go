@@ -2,6 +2,7 @@ package model
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/wanrun-develop/wanrun/pkg/util"
 )
@@ -36,3 +37,39 @@ Dogrunmgが空でないか
 func (dm *Dogrunmg) IsNotEmpty() bool {
 	return dm.DogrunmgID.Valid
 }
+
+type DogrunmgInvitation struct {
+	InvitationID   sql.NullInt64  `gorm:"primaryKey;column:invitation_id;autoIncrement"`
+	OrganizationID sql.NullInt64  `gorm:"column:organization_id;not null"`
+	InvitedBy      sql.NullInt64  `gorm:"column:invited_by;not null"` // 招待したdogrunmgのID
+	Email          sql.NullString `gorm:"size:255;column:email;not null"`
+	TokenHash      sql.NullString `gorm:"size:64;column:token_hash;not null"` // 招待トークンのハッシュ値
+	ExpiresAt      sql.NullTime   `gorm:"column:expires_at;not null"`
+	UsedAt         sql.NullTime   `gorm:"column:used_at"`
+	CreateAt       sql.NullTime   `gorm:"column:reg_at;not null;autoCreateTime"`
+}
+
+func (DogrunmgInvitation) TableName() string {
+	return "dogrun_manager_invitations"
+}
+
+/*
+招待が空であるか
+*/
+func (i *DogrunmgInvitation) IsEmpty() bool {
+	return !i.InvitationID.Valid
+}
+
+/*
+招待が使用済みであるか
+*/
+func (i *DogrunmgInvitation) IsUsed() bool {
+	return i.UsedAt.Valid
+}
+
+/*
+招待が有効期限切れであるか
+*/
+func (i *DogrunmgInvitation) IsExpired(now time.Time) bool {
+	return !i.ExpiresAt.Valid || now.After(i.ExpiresAt.Time)
+}
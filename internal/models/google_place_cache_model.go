@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+// google place apiのレスポンスのキャッシュ
+type GooglePlaceCache struct {
+	CacheKey  string    `gorm:"size:512;primaryKey;column:cache_key"`
+	Value     []byte    `gorm:"column:value;not null"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null"`
+	UpdateAt  time.Time `gorm:"column:upd_at;not null;autoUpdateTime"`
+}
+
+func (GooglePlaceCache) TableName() string {
+	return "google_place_caches"
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ワクチン種別
+const (
+	VACCINE_TYPE_RABIES      int64 = 1 // 狂犬病
+	VACCINE_TYPE_COMBINATION int64 = 2 // 混合ワクチン
+)
+
+type InjectionCertification struct {
+	InjectionCertificationID sql.NullInt64  `gorm:"primaryKey;column:injection_certification_id;autoIncrement"`
+	DogID                    sql.NullInt64  `gorm:"column:dog_id;not null"`
+	Type                     sql.NullInt64  `gorm:"column:type;not null"`
+	FileID                   sql.NullString `gorm:"type:text;column:file;not null"` // s3_file_info.file_id
+	ValidFrom                sql.NullTime   `gorm:"type:date;column:valid_from"`
+	ValidUntil               sql.NullTime   `gorm:"type:date;column:valid_until"`
+	VerifiedBy               sql.NullInt64  `gorm:"column:verified_by"`
+	VerifiedAt               sql.NullTime   `gorm:"column:verified_at"`
+	CreateAt                 sql.NullTime   `gorm:"column:reg_at;not null;autoCreateTime"`
+	UpdateAt                 sql.NullTime   `gorm:"column:upd_at;not null;autoUpdateTime"`
+
+	//リレーション
+	Dog Dog `gorm:"foreignKey:DogID;references:DogID"`
+}
+
+func (InjectionCertification) TableName() string {
+	return "injection_certifications"
+}
+
+/*
+InjectionCertificationが空であるか
+*/
+func (ic *InjectionCertification) IsEmpty() bool {
+	return !ic.InjectionCertificationID.Valid
+}
+
+/*
+dogrun管理者による確認済みであるか
+*/
+func (ic *InjectionCertification) IsVerified() bool {
+	return ic.VerifiedAt.Valid
+}
+
+/*
+指定日時点で有効期限内であるか
+*/
+func (ic *InjectionCertification) IsValidAt(at time.Time) bool {
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	if ic.ValidFrom.Valid && day.Before(ic.ValidFrom.Time) {
+		return false
+	}
+	if ic.ValidUntil.Valid && day.After(ic.ValidUntil.Time) {
+		return false
+	}
+	return true
+}
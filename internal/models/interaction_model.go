@@ -2,6 +2,7 @@ package model
 
 import (
 	"database/sql"
+	"time"
 )
 
 type DogrunBookmark struct {
@@ -25,57 +26,142 @@ func (b *DogrunBookmark) IsNotEmpty() bool {
 	return b.DogrunBookmarkID.Valid
 }
 
-type DogrunCheckin struct {
-	DogrunCheckinID sql.NullInt64 `gorm:"column:dogrun_checkin_id;primaryKey"`
-	DogrunID        sql.NullInt64 `gorm:"column:dogrun_id;not null"`
-	DogID           sql.NullInt64 `gorm:"column:dog_id;not null"`
-	CheckinAt       sql.NullTime  `gorm:"column:checkin_at;autoCreateTime"`
-	ReCheckinAt     sql.NullTime  `gorm:"column:re_checkin_at;autoUpdateTime"`
+// visitの退場理由
+const (
+	VISIT_CHECKOUT_REASON_MANUAL   int64 = 1 // 手動でのチェックアウト
+	VISIT_CHECKOUT_REASON_MOVED    int64 = 2 // 他のドッグランへのチェックイン
+	VISIT_CHECKOUT_REASON_MIGRATED int64 = 3 // 移行時の補完
+	VISIT_CHECKOUT_REASON_CLOSING  int64 = 4 // 閉店時刻での自動チェックアウト
+	VISIT_CHECKOUT_REASON_EXPIRED  int64 = 5 // 最大滞在時間での自動チェックアウト
+)
+
+// DogrunVisit: ドッグランへの1回の滞在
+// チェックインで開始し、チェックアウトで終了する。滞在中はCheckoutAtがNULL
+type DogrunVisit struct {
+	DogrunVisitID  sql.NullInt64 `gorm:"column:dogrun_visit_id;primaryKey;autoIncrement"`
+	DogID          sql.NullInt64 `gorm:"column:dog_id;not null"`
+	DogrunID       sql.NullInt64 `gorm:"column:dogrun_id;not null"`
+	CheckinAt      sql.NullTime  `gorm:"column:checkin_at;not null"`
+	CheckoutAt     sql.NullTime  `gorm:"column:checkout_at"`
+	CheckoutReason sql.NullInt64 `gorm:"column:checkout_reason"`
+	SystemCheckout sql.NullBool  `gorm:"column:is_system_checkout;not null;default:false"` // システムによる退場か
+	CreateAt       sql.NullTime  `gorm:"column:reg_at;not null;autoCreateTime"`
+	UpdateAt       sql.NullTime  `gorm:"column:upd_at;not null;autoUpdateTime"`
 
 	//リレーション
-	Dog Dog `gorm:"foreignKey:DogID;references:DogID"`
+	Dog    Dog    `gorm:"foreignKey:DogID;references:DogID"`
+	Dogrun Dogrun `gorm:"foreignKey:DogrunID;references:DogrunID"`
 }
 
-func (DogrunCheckin) TableName() string {
-	return "dogrun_checkin"
+func (DogrunVisit) TableName() string {
+	return "dogrun_visits"
 }
 
 /*
-DogrunCheckinが空であるか
+DogrunVisitが空であるか
 */
-func (ci *DogrunCheckin) IsEmpty() bool {
-	return !ci.IsNotEmpty()
+func (v *DogrunVisit) IsEmpty() bool {
+	return !v.IsNotEmpty()
 }
 
 /*
-DogrunCheckinが空でないか
+DogrunVisitが空でないか
 */
-func (ci *DogrunCheckin) IsNotEmpty() bool {
-	return ci.DogrunCheckinID.Valid
+func (v *DogrunVisit) IsNotEmpty() bool {
+	return v.DogrunVisitID.Valid
 }
 
-type DogrunCheckout struct {
-	DogrunCheckoutID sql.NullInt64 `gorm:"column:dogrun_checkout_id;primaryKey"`
-	DogrunID         sql.NullInt64 `gorm:"column:dogrun_id;not null"`
-	DogID            sql.NullInt64 `gorm:"column:dog_id;not null"`
-	CheckoutAt       sql.NullTime  `gorm:"column:checkout_at;autoCreateTime"`
-	ReCheckoutAt     sql.NullTime  `gorm:"column:re_checkout_at;autoUpdateTime"`
+/*
+滞在中であるか
+*/
+func (v *DogrunVisit) IsOpen() bool {
+	return v.IsNotEmpty() && !v.CheckoutAt.Valid
 }
 
-func (DogrunCheckout) TableName() string {
-	return "dogrun_checkout"
+/*
+visitを終了する
+*/
+func (v *DogrunVisit) Close(at time.Time, reason int64) {
+	v.CheckoutAt = sql.NullTime{Time: at, Valid: true}
+	v.CheckoutReason = sql.NullInt64{Int64: reason, Valid: true}
 }
 
 /*
-DogrunCheckinが空であるか
+システムによりvisitを終了する(自動チェックアウト)
 */
-func (co *DogrunCheckout) IsEmpty() bool {
-	return !co.IsNotEmpty()
+func (v *DogrunVisit) CloseBySystem(at time.Time, reason int64) {
+	v.Close(at, reason)
+	v.SystemCheckout = sql.NullBool{Bool: true, Valid: true}
 }
 
 /*
-DogrunCheckinが空でないか
+システムにより終了したvisitであるか
 */
-func (co *DogrunCheckout) IsNotEmpty() bool {
-	return co.DogrunCheckoutID.Valid
+func (v *DogrunVisit) IsSystemCheckout() bool {
+	return v.SystemCheckout.Valid && v.SystemCheckout.Bool
+}
+
+// DogrunOccupancy: ドッグランに滞在中のdog数(サイズ区分別)
+// dogrun_visitsの集計結果
+type DogrunOccupancy struct {
+	DogrunID int64 `gorm:"column:dogrun_id"`
+	Total    int64 `gorm:"column:total"`
+	Small    int64 `gorm:"column:small"`
+	Medium   int64 `gorm:"column:medium"`
+	Large    int64 `gorm:"column:large"`
+	Unknown  int64 `gorm:"column:unknown"`
+}
+
+// BookmarkCollection: ブックマークをまとめた名前付きのコレクション
+// 共有リンクを発行すると、ログインなしで閲覧できる
+type BookmarkCollection struct {
+	BookmarkCollectionID sql.NullInt64  `gorm:"column:bookmark_collection_id;primaryKey;autoIncrement"`
+	DogOwnerID           sql.NullInt64  `gorm:"column:dog_owner_id;not null"`
+	Name                 sql.NullString `gorm:"column:name;not null"`
+	Note                 sql.NullString `gorm:"column:note"`
+	ShareTokenHash       sql.NullString `gorm:"column:share_token_hash"` // 共有リンクのトークン(sha256)
+	SharedAt             sql.NullTime   `gorm:"column:shared_at"`
+	CreateAt             sql.NullTime   `gorm:"column:reg_at;not null;autoCreateTime"`
+	UpdateAt             sql.NullTime   `gorm:"column:upd_at;not null;autoUpdateTime"`
+
+	//リレーション
+	Items []BookmarkCollectionItem `gorm:"foreignKey:BookmarkCollectionID;references:BookmarkCollectionID"`
+}
+
+func (BookmarkCollection) TableName() string {
+	return "bookmark_collections"
+}
+
+/*
+BookmarkCollectionが空であるか
+*/
+func (bc *BookmarkCollection) IsEmpty() bool {
+	return !bc.IsNotEmpty()
+}
+
+/*
+BookmarkCollectionが空でないか
+*/
+func (bc *BookmarkCollection) IsNotEmpty() bool {
+	return bc.BookmarkCollectionID.Valid
+}
+
+/*
+共有リンクを発行済みであるか
+*/
+func (bc *BookmarkCollection) IsShared() bool {
+	return bc.ShareTokenHash.Valid && bc.ShareTokenHash.String != ""
+}
+
+// BookmarkCollectionItem: コレクションに含めるブックマーク済みドッグラン
+type BookmarkCollectionItem struct {
+	BookmarkCollectionItemID sql.NullInt64  `gorm:"column:bookmark_collection_item_id;primaryKey;autoIncrement"`
+	BookmarkCollectionID     sql.NullInt64  `gorm:"column:bookmark_collection_id;not null"`
+	DogrunID                 sql.NullInt64  `gorm:"column:dogrun_id;not null"`
+	Note                     sql.NullString `gorm:"column:note"`
+	AddedAt                  sql.NullTime   `gorm:"column:added_at;not null;autoCreateTime"`
+}
+
+func (BookmarkCollectionItem) TableName() string {
+	return "bookmark_collection_items"
 }
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// パスワード再設定トークン
+// dogownerかdogrunmgのどちらかに発行する
+type PasswordResetToken struct {
+	PasswordResetTokenID sql.NullInt64  `gorm:"primaryKey;column:password_reset_token_id;autoIncrement"`
+	TokenHash            sql.NullString `gorm:"size:64;column:token_hash;not null"` // 再設定トークンのハッシュ値
+	DogOwnerID           sql.NullInt64  `gorm:"column:dog_owner_id"`
+	DogrunmgID           sql.NullInt64  `gorm:"column:dogrun_manager_id"`
+	ExpiresAt            sql.NullTime   `gorm:"column:expires_at;not null"`
+	UsedAt               sql.NullTime   `gorm:"column:used_at"`
+	CreateAt             sql.NullTime   `gorm:"column:reg_at;not null;autoCreateTime"`
+}
+
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
+
+/*
+再設定トークンが空であるか
+*/
+func (prt *PasswordResetToken) IsEmpty() bool {
+	return !prt.PasswordResetTokenID.Valid
+}
+
+/*
+使用済みであるか
+*/
+func (prt *PasswordResetToken) IsUsed() bool {
+	return prt.UsedAt.Valid
+}
+
+/*
+有効期限切れであるか
+*/
+func (prt *PasswordResetToken) IsExpired(now time.Time) bool {
+	return !prt.ExpiresAt.Valid || now.After(prt.ExpiresAt.Time)
+}
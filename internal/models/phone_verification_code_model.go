@@ -0,0 +1,44 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// 電話番号確認用のワンタイムコード
+// dogownerの電話番号のクレデンシャルに発行する
+type PhoneVerificationCode struct {
+	PhoneVerificationCodeID sql.NullInt64  `gorm:"primaryKey;column:phone_verification_code_id;autoIncrement"`
+	CredentialID            sql.NullInt64  `gorm:"column:credential_id;not null"`
+	PhoneNumber             sql.NullString `gorm:"size:15;column:phone_number;not null"`
+	CodeHash                sql.NullString `gorm:"size:64;column:code_hash;not null"` // ワンタイムコードのハッシュ値
+	Attempts                int            `gorm:"column:attempts;not null;default:0"`
+	ExpiresAt               sql.NullTime   `gorm:"column:expires_at;not null"`
+	UsedAt                  sql.NullTime   `gorm:"column:used_at"`
+	CreateAt                sql.NullTime   `gorm:"column:reg_at;not null;autoCreateTime"`
+}
+
+func (PhoneVerificationCode) TableName() string {
+	return "phone_verification_codes"
+}
+
+/*
+ワンタイムコードが空であるか
+*/
+func (pvc *PhoneVerificationCode) IsEmpty() bool {
+	return !pvc.PhoneVerificationCodeID.Valid
+}
+
+/*
+使用済みであるか
+*/
+func (pvc *PhoneVerificationCode) IsUsed() bool {
+	return pvc.UsedAt.Valid
+}
+
+/*
+有効期限切れであるか
+*/
+func (pvc *PhoneVerificationCode) IsExpired(now time.Time) bool {
+	return !pvc.ExpiresAt.Valid || now.After(pvc.ExpiresAt.Time)
+}
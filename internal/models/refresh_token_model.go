@@ -0,0 +1,52 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// アクセストークン再発行用のリフレッシュトークン
+// dogownerかdogrunmgのどちらかに発行する
+type RefreshToken struct {
+	RefreshTokenID sql.NullInt64  `gorm:"primaryKey;column:refresh_token_id;autoIncrement"`
+	TokenHash      sql.NullString `gorm:"size:64;column:token_hash;not null"` // リフレッシュトークンのハッシュ値
+	FamilyID       sql.NullString `gorm:"size:45;column:family_id;not null"`  // トークンファミリー(セッションのjwt_id)
+	DogOwnerID     sql.NullInt64  `gorm:"column:dog_owner_id"`
+	DogrunmgID     sql.NullInt64  `gorm:"column:dogrun_manager_id"`
+	ExpiresAt      sql.NullTime   `gorm:"column:expires_at;not null"`
+	RotatedAt      sql.NullTime   `gorm:"column:rotated_at"`
+	RevokedAt      sql.NullTime   `gorm:"column:revoked_at"`
+	CreateAt       sql.NullTime   `gorm:"column:reg_at;not null;autoCreateTime"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+/*
+リフレッシュトークンが空であるか
+*/
+func (rt *RefreshToken) IsEmpty() bool {
+	return !rt.RefreshTokenID.Valid
+}
+
+/*
+ローテーション済み(使用済み)であるか
+*/
+func (rt *RefreshToken) IsRotated() bool {
+	return rt.RotatedAt.Valid
+}
+
+/*
+失効済みであるか
+*/
+func (rt *RefreshToken) IsRevoked() bool {
+	return rt.RevokedAt.Valid
+}
+
+/*
+有効期限切れであるか
+*/
+func (rt *RefreshToken) IsExpired(now time.Time) bool {
+	return !rt.ExpiresAt.Valid || now.After(rt.ExpiresAt.Time)
+}
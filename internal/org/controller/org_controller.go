@@ -57,13 +57,11 @@ func (o *orgController) OrgSignUp(c echo.Context) error {
 	}
 
 	// organizationのSignUp
-	token, wrErr := o.oh.OrgSignUp(c, orgReq)
+	res, wrErr := o.oh.OrgSignUp(c, orgReq)
 
 	if wrErr != nil {
 		return wrErr
 	}
 
-	return c.JSON(http.StatusCreated, map[string]string{
-		"accessToken": token,
-	})
+	return c.JSON(http.StatusCreated, res)
 }
@@ -20,7 +20,7 @@ import (
 )
 
 type IOrgHandler interface {
-	OrgSignUp(c echo.Context, orgReq dto.OrgReq) (string, error)
+	OrgSignUp(c echo.Context, orgReq dto.OrgReq) (authDTO.AuthTokenRes, error)
 }
 
 type orgHandler struct {
@@ -50,7 +50,7 @@ func NewOrgHandler(
 func (oh *orgHandler) OrgSignUp(
 	c echo.Context,
 	orgReq dto.OrgReq,
-) (string, error) {
+) (authDTO.AuthTokenRes, error) {
 	logger := log.GetLogger(c).Sugar()
 
 	// パスワードのハッシュ化
@@ -63,19 +63,12 @@ func (oh *orgHandler) OrgSignUp(
 			wrErrors.NewOrgClientErrorEType(),
 		)
 		logger.Error(wrErr)
-		return "", wrErr
-	}
-
-	// JWT IDの生成
-	jwtID, wrErr := authHandler.GenerateJwtID(c)
-
-	if wrErr != nil {
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// orgのEmailバリデーション
 	if wrErr := oh.af.OrgEmailValidate(c, orgReq.ContactEmail); wrErr != nil {
-		return "", wrErr
+		return authDTO.AuthTokenRes{}, wrErr
 	}
 
 	// requestからorgの構造体に詰め替え
@@ -83,7 +76,6 @@ func (oh *orgHandler) OrgSignUp(
 		Email:    wrUtil.NewSqlNullString(orgReq.ContactEmail),
 		Password: wrUtil.NewSqlNullString(string(hash)),
 		AuthDogrunmg: model.AuthDogrunmg{
-			JwtID:   wrUtil.NewSqlNullString(jwtID),
 			IsAdmin: wrUtil.NewSqlNullBool(true), // 初期adminユーザーのため
 			Dogrunmg: model.Dogrunmg{
 				Name: wrUtil.NewSqlNullString("admin"),
@@ -102,6 +94,10 @@ func (oh *orgHandler) OrgSignUp(
 
 	ctx := c.Request().Context()
 
+	// 発行したリフレッシュトークン(平文)とセッション
+	var refreshToken string
+	var session model.AuthSession
+
 	// organizationの作成トランザクション
 	if err := oh.tm.DoInTransaction(c, ctx, func(tx *gorm.DB) error {
 
@@ -140,29 +136,46 @@ func (oh *orgHandler) OrgSignUp(
 			return wrErr
 		}
 
+		// ログインセッションとリフレッシュトークンを作成
+		token, as, rt, wrErr := authHandler.NewLoginSession(c, authDTO.UserAuthInfoDTO{
+			UserID: dmID.Int64,
+			RoleID: core.DOGRUNMG_ADMIN_ROLE,
+		}, "")
+
+		if wrErr != nil {
+			return wrErr
+		}
+
+		if wrErr := oh.asr.CreateAuthSession(tx, c, &as); wrErr != nil {
+			return wrErr
+		}
+
+		if wrErr := oh.asr.CreateRefreshToken(tx, c, &rt); wrErr != nil {
+			return wrErr
+		}
+		refreshToken = token
+		session = as
+
 		// 正常に完了
 		return nil
 
 	}); err != nil {
 		logger.Error("Transaction failed:", err)
-		return "", err
+		return authDTO.AuthTokenRes{}, err
 	}
 
 	// 作成したdogrunmgの情報をdto詰め替え
 	dogrunmgrDetail := authDTO.UserAuthInfoDTO{
 		UserID: orgInfo.AuthDogrunmg.DogrunmgID.Int64,
-		JwtID:  orgInfo.AuthDogrunmg.JwtID.String,
+		JwtID:  session.JwtID.String,
 		RoleID: core.DOGRUNMG_ADMIN_ROLE,
 	}
 
 	logger.Infof("dogrunmgDetail: %v", dogrunmgrDetail)
 
-	// 署名済みのjwt token取得
-	token, wrErr := authHandler.GetSignedJwt(c, dogrunmgrDetail)
-
-	if wrErr != nil {
-		return "", wrErr
-	}
+	// メールアドレスの確認を送信(失敗してもサインアップは完了させる)
+	oh.af.SendSignUpVerification(c, dogrunmgrDetail)
 
-	return token, nil
+	// 署名済みのjwt token取得
+	return authHandler.NewAuthTokenRes(c, dogrunmgrDetail, refreshToken)
 }
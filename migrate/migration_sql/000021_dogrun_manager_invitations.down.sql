@@ -0,0 +1 @@
+DROP TABLE IF EXISTS dogrun_manager_invitations CASCADE;
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS dogrun_manager_invitations CASCADE;
+CREATE TABLE IF NOT EXISTS dogrun_manager_invitations (
+    invitation_id serial primary key,             -- PK
+    organization_id bigint not null,              -- 招待先のorganization
+    invited_by bigint not null,                   -- 招待したdogrun_manager_id(admin)
+    email varchar(255) not null,                  -- 招待先のメールアドレス
+    token_hash varchar(64) unique not null,       -- 招待トークンのハッシュ値(sha256)
+    expires_at timestamp not null,                -- 有効期限
+    used_at timestamp,                            -- 使用日時。使用済みの場合はNULL以外
+    reg_at timestamp not null                     -- 登録日
+);
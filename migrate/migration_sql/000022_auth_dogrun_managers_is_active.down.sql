@@ -0,0 +1 @@
+ALTER TABLE auth_dogrun_managers DROP COLUMN IF EXISTS is_active;
@@ -0,0 +1 @@
+ALTER TABLE auth_dogrun_managers ADD COLUMN IF NOT EXISTS is_active boolean not null default true; -- 無効化されたスタッフはfalse
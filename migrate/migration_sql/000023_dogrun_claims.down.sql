@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS dogrun_claim_histories CASCADE;
+DROP TABLE IF EXISTS dogrun_claims CASCADE;
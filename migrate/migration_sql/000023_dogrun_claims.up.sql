@@ -0,0 +1,30 @@
+DROP TABLE IF EXISTS dogrun_claims CASCADE;
+CREATE TABLE IF NOT EXISTS dogrun_claims (
+    dogrun_claim_id serial primary key,           -- PK
+    dogrun_id bigint not null,                    -- 申請対象のドッグラン
+    dogrun_manager_id bigint not null,            -- 申請したdogrun_manager
+    organization_id bigint not null,              -- 申請したorganization
+    evidence text not null,                       -- 管理者であることの根拠(説明)
+    evidence_url text,                            -- 根拠資料のURL
+    status smallint not null,                     -- 1:申請中, 2:承認, 3:却下
+    reg_at timestamp not null,                    -- 登録日
+    upd_at timestamp not null                     -- 更新日
+);
+
+-- 同じドッグランに対する申請中のクレームは1件のみ
+CREATE UNIQUE INDEX idx_dogrun_claims_dogrun_id_pending
+ON dogrun_claims (dogrun_id) WHERE status = 1;
+
+DROP TABLE IF EXISTS dogrun_claim_histories CASCADE;
+CREATE TABLE IF NOT EXISTS dogrun_claim_histories (
+    dogrun_claim_history_id serial primary key,   -- PK
+    dogrun_claim_id bigint not null,              -- dogrun_claimsへの外部キー
+    status smallint not null,                     -- 変更後のステータス
+    changed_by bigint,                            -- 変更したユーザーのID
+    changed_by_role int not null,                 -- 変更したユーザーのロール
+    comment text,                                 -- 承認・却下時のコメント
+    reg_at timestamp not null                     -- 登録日
+);
+
+CREATE INDEX idx_dogrun_claim_histories_dogrun_claim_id
+ON dogrun_claim_histories (dogrun_claim_id);
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS trg_dogruns_sync_geog ON dogruns;
+DROP FUNCTION IF EXISTS dogruns_sync_geog();
+DROP INDEX IF EXISTS idx_dogruns_geog_geometry;
+DROP INDEX IF EXISTS idx_dogruns_geog;
+ALTER TABLE dogruns DROP COLUMN IF EXISTS geog;
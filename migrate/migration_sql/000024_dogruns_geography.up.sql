@@ -0,0 +1,30 @@
+CREATE EXTENSION IF NOT EXISTS postgis;
+
+-- 緯度経度から生成する位置情報(WGS84)
+ALTER TABLE dogruns ADD COLUMN IF NOT EXISTS geog geography(Point, 4326);
+
+UPDATE dogruns
+SET geog = ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography
+WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
+
+-- 半径検索・近傍検索用
+CREATE INDEX IF NOT EXISTS idx_dogruns_geog ON dogruns USING GIST (geog);
+-- 矩形検索用(緯度経度の直線で囲う矩形はgeometryで判定する)
+CREATE INDEX IF NOT EXISTS idx_dogruns_geog_geometry ON dogruns USING GIST ((geog::geometry));
+
+-- latitude/longitudeの変更時にgeogを同期する
+CREATE OR REPLACE FUNCTION dogruns_sync_geog() RETURNS trigger AS $$
+BEGIN
+    IF NEW.latitude IS NULL OR NEW.longitude IS NULL THEN
+        NEW.geog := NULL;
+    ELSE
+        NEW.geog := ST_SetSRID(ST_MakePoint(NEW.longitude, NEW.latitude), 4326)::geography;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_dogruns_sync_geog ON dogruns;
+CREATE TRIGGER trg_dogruns_sync_geog
+BEFORE INSERT OR UPDATE OF latitude, longitude ON dogruns
+FOR EACH ROW EXECUTE FUNCTION dogruns_sync_geog();
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_injection_certifications_dog_id;
+
+COMMENT ON COLUMN injection_certifications.type IS NULL;
+COMMENT ON COLUMN injection_certifications.file IS NULL;
+
+ALTER TABLE injection_certifications
+    DROP COLUMN IF EXISTS verified_at,
+    DROP COLUMN IF EXISTS verified_by,
+    DROP COLUMN IF EXISTS valid_until,
+    DROP COLUMN IF EXISTS valid_from;
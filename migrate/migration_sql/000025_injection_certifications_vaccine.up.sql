@@ -0,0 +1,13 @@
+-- 予防接種証明書にワクチン種別・有効期間・確認者の情報を追加
+-- file にはcmsでアップロードしたs3_file_infoのfile_idを格納する
+ALTER TABLE injection_certifications
+    ADD COLUMN IF NOT EXISTS valid_from date,        -- 接種日(有効期間の開始日)
+    ADD COLUMN IF NOT EXISTS valid_until date,       -- 有効期限
+    ADD COLUMN IF NOT EXISTS verified_by bigint,     -- 確認したdogrun_manager
+    ADD COLUMN IF NOT EXISTS verified_at timestamp;  -- 確認日時
+
+COMMENT ON COLUMN injection_certifications.type IS '1:狂犬病, 2:混合ワクチン';
+COMMENT ON COLUMN injection_certifications.file IS 's3_file_info.file_id';
+
+CREATE INDEX IF NOT EXISTS idx_injection_certifications_dog_id
+ON injection_certifications (dog_id);
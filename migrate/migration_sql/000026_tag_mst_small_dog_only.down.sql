@@ -0,0 +1,2 @@
+delete from dogrun_tags where tag_id = 27;
+delete from tag_mst where tag_id = 27;
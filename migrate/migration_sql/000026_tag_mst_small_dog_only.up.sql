@@ -0,0 +1,3 @@
+-- 入場条件として小型犬のみ利用可能なドッグラン用のタグを追加
+insert into tag_mst (tag_id, tag_name, description) values (27, '小型犬専用', '小型犬のみご利用いただけます。')
+on conflict (tag_id) do nothing;
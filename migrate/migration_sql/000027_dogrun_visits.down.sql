@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS dogrun_checkin (
+    dogrun_checkin_id serial primary key,
+    dog_id bigint not null,
+    dogrun_id bigint not null,
+    checkin_at timestamp,
+    re_checkin_at timestamp
+);
+
+CREATE INDEX idx_dogrun_checkin_dogid_dogrunid_checkinat
+ON dogrun_checkin (dog_id, dogrun_id, checkin_at);
+
+CREATE TABLE IF NOT EXISTS dogrun_checkout (
+    dogrun_checkout_id serial primary key,
+    dog_id bigint not null,
+    dogrun_id bigint not null,
+    checkout_at timestamp,
+    re_checkout_at timestamp
+);
+
+CREATE INDEX idx_dogrun_checkout_dogid_dogrunid_checkoutat
+ON dogrun_checkout (dog_id, dogrun_id, checkout_at);
+
+-- visitを1日1行に集約して戻す(最初と最後の入退場のみ残る)
+INSERT INTO dogrun_checkin (dog_id, dogrun_id, checkin_at, re_checkin_at)
+SELECT dog_id, dogrun_id, MIN(checkin_at), MAX(checkin_at)
+FROM dogrun_visits
+GROUP BY dog_id, dogrun_id, checkin_at::date;
+
+INSERT INTO dogrun_checkout (dog_id, dogrun_id, checkout_at, re_checkout_at)
+SELECT dog_id, dogrun_id, MIN(checkout_at), MAX(checkout_at)
+FROM dogrun_visits
+WHERE checkout_at IS NOT NULL
+GROUP BY dog_id, dogrun_id, checkout_at::date;
+
+DROP TABLE IF EXISTS dogrun_visits CASCADE;
@@ -0,0 +1,81 @@
+-- チェックイン・チェックアウトを1日1行から、入退場ごとの滞在(visit)単位に変更
+CREATE TABLE IF NOT EXISTS dogrun_visits (
+    dogrun_visit_id serial primary key,           -- PK
+    dog_id bigint not null,                       -- 入場したdog
+    dogrun_id bigint not null,                    -- 入場したドッグラン
+    checkin_at timestamp not null,                -- 入場日時
+    checkout_at timestamp,                        -- 退場日時(滞在中はNULL)
+    checkout_reason smallint,                     -- 1:手動, 2:他ドッグランへの移動, 3:移行時の補完
+    reg_at timestamp not null,                    -- 登録日
+    upd_at timestamp not null                     -- 更新日
+);
+
+-- 滞在中のvisitはdogごとに1件のみ
+CREATE UNIQUE INDEX idx_dogrun_visits_dog_id_open
+ON dogrun_visits (dog_id) WHERE checkout_at IS NULL;
+
+CREATE INDEX idx_dogrun_visits_dogrun_id_checkin_at
+ON dogrun_visits (dogrun_id, checkin_at);
+
+CREATE INDEX idx_dogrun_visits_dog_id_checkin_at
+ON dogrun_visits (dog_id, checkin_at);
+
+-- 既存データの移行
+-- 1日1行のcheckin/checkoutから、最初の入退場と再入退場の最大2件のvisitを復元する
+-- 退場記録のないvisitは、同じdogの次のvisitの入場日時またはその日の終わりで補完する
+-- ただしdogごとの最新のvisitが今日のものであれば滞在中のままとする
+INSERT INTO dogrun_visits (dog_id, dogrun_id, checkin_at, checkout_at, checkout_reason, reg_at, upd_at)
+WITH legacy_visits AS (
+SELECT
+    ci.dog_id,
+    ci.dogrun_id,
+    ci.checkin_at,
+    CASE WHEN co.checkout_at >= ci.checkin_at THEN co.checkout_at END AS checkout_at
+FROM dogrun_checkin ci
+LEFT JOIN dogrun_checkout co
+    ON co.dog_id = ci.dog_id
+    AND co.dogrun_id = ci.dogrun_id
+    AND co.checkout_at::date = ci.checkin_at::date
+WHERE ci.checkin_at IS NOT NULL
+UNION ALL
+SELECT
+    ci.dog_id,
+    ci.dogrun_id,
+    ci.re_checkin_at,
+    CASE WHEN co.re_checkout_at >= ci.re_checkin_at THEN co.re_checkout_at END
+FROM dogrun_checkin ci
+LEFT JOIN dogrun_checkout co
+    ON co.dog_id = ci.dog_id
+    AND co.dogrun_id = ci.dogrun_id
+    AND co.checkout_at::date = ci.checkin_at::date
+WHERE ci.re_checkin_at > COALESCE(co.checkout_at, ci.checkin_at)
+)
+SELECT
+    dog_id,
+    dogrun_id,
+    checkin_at,
+    CASE
+        WHEN checkout_at IS NOT NULL THEN checkout_at
+        WHEN next_checkin_at IS NULL AND checkin_at >= CURRENT_DATE THEN NULL
+        ELSE LEAST(
+            COALESCE(next_checkin_at, checkin_at::date + INTERVAL '1 day'),
+            checkin_at::date + INTERVAL '1 day'
+        )
+    END,
+    CASE
+        WHEN checkout_at IS NOT NULL THEN 1
+        WHEN next_checkin_at IS NULL AND checkin_at >= CURRENT_DATE THEN NULL
+        ELSE 3
+    END,
+    NOW(),
+    NOW()
+FROM (
+    SELECT
+        t.*,
+        LEAD(t.checkin_at) OVER (PARTITION BY t.dog_id ORDER BY t.checkin_at) AS next_checkin_at
+    FROM legacy_visits t
+) v
+ORDER BY checkin_at;
+
+DROP TABLE IF EXISTS dogrun_checkin CASCADE;
+DROP TABLE IF EXISTS dogrun_checkout CASCADE;
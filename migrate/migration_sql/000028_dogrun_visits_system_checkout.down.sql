@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_dogrun_visits_open;
+
+COMMENT ON COLUMN dogrun_visits.checkout_reason IS NULL;
+
+ALTER TABLE dogrun_visits
+    DROP COLUMN IF EXISTS is_system_checkout;
@@ -0,0 +1,12 @@
+-- 自動チェックアウトなど、システムが終了したvisitを区別するフラグを追加
+ALTER TABLE dogrun_visits
+    ADD COLUMN IF NOT EXISTS is_system_checkout boolean not null default false; -- システムによる退場か
+
+COMMENT ON COLUMN dogrun_visits.checkout_reason IS '1:手動, 2:他ドッグランへの移動, 3:移行時の補完, 4:閉店時刻での自動退場, 5:最大滞在時間での自動退場';
+
+-- 移行時に補完したvisitもシステムによる退場とする
+UPDATE dogrun_visits SET is_system_checkout = true WHERE checkout_reason = 3;
+
+-- 自動チェックアウトの対象(滞在中のvisit)の検索用
+CREATE INDEX IF NOT EXISTS idx_dogrun_visits_open
+ON dogrun_visits (checkin_at) WHERE checkout_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_dogrun_bookmarks_dog_owner_id;
+DROP TABLE IF EXISTS bookmark_collection_items CASCADE;
+DROP TABLE IF EXISTS bookmark_collections CASCADE;
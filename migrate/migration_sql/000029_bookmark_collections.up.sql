@@ -0,0 +1,34 @@
+-- ブックマークをまとめるコレクション
+CREATE TABLE IF NOT EXISTS bookmark_collections (
+    bookmark_collection_id serial primary key,    -- PK
+    dog_owner_id bigint not null,                 -- 作成したdogowner
+    name varchar(64) not null,                    -- コレクション名
+    note varchar(1000),                           -- メモ
+    share_token_hash varchar(64),                 -- 共有リンクのトークン(sha256)。共有していない場合はNULL
+    shared_at timestamp,                          -- 共有リンクの発行日時
+    reg_at timestamp not null,                    -- 登録日
+    upd_at timestamp not null                     -- 更新日
+);
+
+-- コレクション名はdogownerごとに一意
+CREATE UNIQUE INDEX idx_bookmark_collections_dog_owner_id_name
+ON bookmark_collections (dog_owner_id, name);
+
+CREATE UNIQUE INDEX idx_bookmark_collections_share_token_hash
+ON bookmark_collections (share_token_hash) WHERE share_token_hash IS NOT NULL;
+
+-- コレクションに含めるブックマーク済みドッグラン
+CREATE TABLE IF NOT EXISTS bookmark_collection_items (
+    bookmark_collection_item_id serial primary key, -- PK
+    bookmark_collection_id bigint not null,         -- コレクション
+    dogrun_id bigint not null,                      -- ドッグラン
+    note varchar(1000),                             -- メモ
+    added_at timestamp not null                     -- 追加日時
+);
+
+CREATE UNIQUE INDEX idx_bookmark_collection_items_collection_id_dogrun_id
+ON bookmark_collection_items (bookmark_collection_id, dogrun_id);
+
+-- ブックマーク一覧の取得用
+CREATE INDEX idx_dogrun_bookmarks_dog_owner_id
+ON dogrun_bookmarks (dog_owner_id);
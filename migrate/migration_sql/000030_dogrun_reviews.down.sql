@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS dogrun_review_replies CASCADE;
+DROP TABLE IF EXISTS dogrun_reviews CASCADE;
@@ -0,0 +1,30 @@
+-- ドッグランのレビュー(チェックインしたことのあるdogownerのみ投稿できる)
+CREATE TABLE IF NOT EXISTS dogrun_reviews (
+    dogrun_review_id serial primary key,                                  -- PK
+    dogrun_id bigint not null,                                            -- レビュー対象のドッグラン
+    dog_owner_id bigint not null,                                         -- 投稿したdogowner
+    rating smallint not null check (rating between 1 and 5),              -- 総合評価
+    comment varchar(2000),                                                -- 本文
+    cleanliness_score smallint check (cleanliness_score between 1 and 5), -- 清潔さ
+    size_score smallint check (size_score between 1 and 5),               -- 広さ
+    crowding_score smallint check (crowding_score between 1 and 5),       -- 混雑具合(空いているほど高い)
+    reg_at timestamp not null,                                            -- 登録日
+    upd_at timestamp not null                                             -- 更新日
+);
+
+-- レビューはdogownerごとにドッグラン1件
+CREATE UNIQUE INDEX idx_dogrun_reviews_dogrun_id_dog_owner_id
+ON dogrun_reviews (dogrun_id, dog_owner_id);
+
+-- ドッグランのレビューへの返信(ドッグラン管理者のみ。レビューごとに1件)
+CREATE TABLE IF NOT EXISTS dogrun_review_replies (
+    dogrun_review_reply_id serial primary key, -- PK
+    dogrun_review_id bigint not null,          -- 返信対象のレビュー
+    dogrun_manager_id bigint not null,         -- 返信したドッグラン管理者
+    comment varchar(2000) not null,            -- 本文
+    reg_at timestamp not null,                 -- 登録日
+    upd_at timestamp not null                  -- 更新日
+);
+
+CREATE UNIQUE INDEX idx_dogrun_review_replies_dogrun_review_id
+ON dogrun_review_replies (dogrun_review_id);
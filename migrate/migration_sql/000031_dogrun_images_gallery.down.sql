@@ -0,0 +1,25 @@
+ALTER TABLE s3_file_info
+    DROP CONSTRAINT IF EXISTS s3_file_info_uploader_check;
+
+DELETE FROM s3_file_info WHERE dog_owner_id IS NULL;
+
+ALTER TABLE s3_file_info
+    DROP COLUMN IF EXISTS dogrun_manager_id,
+    ALTER COLUMN dog_owner_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_dogrun_images_dogrun_id_status;
+DROP INDEX IF EXISTS idx_dogrun_images_file_id;
+
+COMMENT ON COLUMN dogrun_images.status IS NULL;
+
+ALTER TABLE dogrun_images
+    ALTER COLUMN upload_at DROP NOT NULL,
+    DROP COLUMN IF EXISTS file_id,
+    DROP COLUMN IF EXISTS caption,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS dog_owner_id,
+    DROP COLUMN IF EXISTS dogrun_manager_id,
+    DROP COLUMN IF EXISTS reviewed_by,
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS reject_reason,
+    DROP COLUMN IF EXISTS upd_at;
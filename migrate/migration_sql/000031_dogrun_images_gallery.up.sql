@@ -0,0 +1,36 @@
+-- ドッグランのギャラリー画像にcmsのファイル・キャプション・投稿の審査状況を追加
+-- image にはcmsでアップロードしたs3_file_infoのs3_object_keyを格納する(file_idがNULLの既存データは画像URL)
+ALTER TABLE dogrun_images
+    ADD COLUMN IF NOT EXISTS file_id varchar(64),                       -- s3_file_info.file_id
+    ADD COLUMN IF NOT EXISTS caption varchar(200),                      -- キャプション
+    ADD COLUMN IF NOT EXISTS status smallint not null default 2,        -- 1:審査待ち, 2:公開, 3:却下
+    ADD COLUMN IF NOT EXISTS dog_owner_id bigint,                       -- 投稿したdogowner
+    ADD COLUMN IF NOT EXISTS dogrun_manager_id bigint,                  -- アップロードしたdogrun_manager
+    ADD COLUMN IF NOT EXISTS reviewed_by bigint,                        -- 審査したdogrun_manager
+    ADD COLUMN IF NOT EXISTS reviewed_at timestamp,                     -- 審査日時
+    ADD COLUMN IF NOT EXISTS reject_reason varchar(500),                -- 却下理由
+    ADD COLUMN IF NOT EXISTS upd_at timestamp;                          -- 更新日
+
+UPDATE dogrun_images SET upload_at = NOW() WHERE upload_at IS NULL;
+UPDATE dogrun_images SET upd_at = upload_at;
+
+ALTER TABLE dogrun_images
+    ALTER COLUMN upload_at SET NOT NULL,
+    ALTER COLUMN upd_at SET NOT NULL;
+
+COMMENT ON COLUMN dogrun_images.status IS '1:審査待ち, 2:公開, 3:却下';
+
+-- 同じファイルは1つの画像にのみ使用できる
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dogrun_images_file_id
+ON dogrun_images (file_id) WHERE file_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_dogrun_images_dogrun_id_status
+ON dogrun_images (dogrun_id, status, sort_order);
+
+-- dogrun_managerもcmsでアップロードできるように、アップロードしたユーザーをどちらかで持つ
+ALTER TABLE s3_file_info
+    ALTER COLUMN dog_owner_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS dogrun_manager_id bigint;                  -- アップロードしたdogrun_manager
+
+ALTER TABLE s3_file_info
+    ADD CONSTRAINT s3_file_info_uploader_check CHECK (dog_owner_id IS NOT NULL OR dogrun_manager_id IS NOT NULL);
@@ -0,0 +1,26 @@
+-- アクセストークン再発行用のリフレッシュトークン(dogowner・dogrun_manager共通)
+-- 使用するたびにローテーションし、同じファミリー(ログイン単位)の新しいトークンを発行する
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    refresh_token_id serial primary key,    -- PK
+    token_hash varchar(64) not null,        -- リフレッシュトークンのハッシュ(sha256)
+    family_id varchar(45) not null,         -- トークンファミリーのID(ログインごとに発行)
+    dog_owner_id bigint,                    -- 発行先のdogowner
+    dogrun_manager_id bigint,               -- 発行先のdogrun_manager
+    expires_at timestamp not null,          -- 有効期限
+    rotated_at timestamp,                   -- ローテーション日時(使用済み)
+    revoked_at timestamp,                   -- 失効日時
+    reg_at timestamp not null,              -- 登録日
+    CONSTRAINT refresh_tokens_user_check CHECK (num_nonnulls(dog_owner_id, dogrun_manager_id) = 1)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash
+ON refresh_tokens (token_hash);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id
+ON refresh_tokens (family_id);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_dog_owner_id
+ON refresh_tokens (dog_owner_id) WHERE dog_owner_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_dogrun_manager_id
+ON refresh_tokens (dogrun_manager_id) WHERE dogrun_manager_id IS NOT NULL;
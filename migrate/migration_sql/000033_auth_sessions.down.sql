@@ -0,0 +1,4 @@
+ALTER TABLE auth_dog_owners ADD COLUMN IF NOT EXISTS jwt_id varchar(45);
+ALTER TABLE auth_dogrun_managers ADD COLUMN IF NOT EXISTS jwt_id varchar(45);
+
+DROP TABLE IF EXISTS auth_sessions CASCADE;
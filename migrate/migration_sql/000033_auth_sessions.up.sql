@@ -0,0 +1,30 @@
+-- ログインセッション(dogowner・dogrun_manager共通)
+-- 端末ごとにログインを保持するため、jwtのjti(jwt_id)ごとにセッションを管理する
+-- リフレッシュトークンのファミリー(family_id)はセッションのjwt_idと同じ値を使用する
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    session_id serial primary key,          -- PK
+    jwt_id varchar(45) not null,            -- jwtのjti
+    dog_owner_id bigint,                    -- ログインしているdogowner
+    dogrun_manager_id bigint,               -- ログインしているdogrun_manager
+    device_name varchar(128),               -- 端末名(ログイン時にクライアントが指定)
+    user_agent varchar(512),                -- ログイン時のUser-Agent
+    ip_address varchar(45),                 -- ログイン時のIPアドレス
+    last_seen_at timestamp not null,        -- 最終利用日時
+    expires_at timestamp not null,          -- 有効期限(リフレッシュトークンの有効期限と同じ)
+    revoked_at timestamp,                   -- 失効日時(ログアウト)
+    reg_at timestamp not null,              -- 登録日(ログイン日時)
+    CONSTRAINT auth_sessions_user_check CHECK (num_nonnulls(dog_owner_id, dogrun_manager_id) = 1)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_auth_sessions_jwt_id
+ON auth_sessions (jwt_id);
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_dog_owner_id
+ON auth_sessions (dog_owner_id) WHERE dog_owner_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_dogrun_manager_id
+ON auth_sessions (dogrun_manager_id) WHERE dogrun_manager_id IS NOT NULL;
+
+-- アカウントごとのjwt_idはセッションに置き換えたため削除
+ALTER TABLE auth_dog_owners DROP COLUMN IF EXISTS jwt_id;
+ALTER TABLE auth_dogrun_managers DROP COLUMN IF EXISTS jwt_id;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_dog_owner_credentials_email;
+DROP INDEX IF EXISTS idx_dog_owner_credentials_provider_user;
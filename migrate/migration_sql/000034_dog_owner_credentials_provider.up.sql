@@ -0,0 +1,7 @@
+-- OAuthのプロバイダのユーザーは1つのクレデンシャルにのみ紐づける
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dog_owner_credentials_provider_user
+ON dog_owner_credentials (provider_name, provider_user_id) WHERE provider_user_id IS NOT NULL;
+
+-- メールアドレスでの既存アカウントの検索用
+CREATE INDEX IF NOT EXISTS idx_dog_owner_credentials_email
+ON dog_owner_credentials (email);
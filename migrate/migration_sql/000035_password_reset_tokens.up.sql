@@ -0,0 +1,21 @@
+-- パスワード再設定トークン(dogowner・dogrun_manager共通)
+-- トークンはメールで送信し、DBにはハッシュ値のみ保存する
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    password_reset_token_id serial primary key, -- PK
+    token_hash varchar(64) not null,            -- 再設定トークンのハッシュ値(sha256)
+    dog_owner_id bigint,                        -- 再設定するdogowner
+    dogrun_manager_id bigint,                   -- 再設定するdogrun_manager
+    expires_at timestamp not null,              -- 有効期限
+    used_at timestamp,                          -- 使用日時。再発行で無効にした場合も設定する
+    reg_at timestamp not null,                  -- 登録日
+    CONSTRAINT password_reset_tokens_user_check CHECK (num_nonnulls(dog_owner_id, dogrun_manager_id) = 1)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_password_reset_tokens_token_hash
+ON password_reset_tokens (token_hash);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_dog_owner_id
+ON password_reset_tokens (dog_owner_id) WHERE dog_owner_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_dogrun_manager_id
+ON password_reset_tokens (dogrun_manager_id) WHERE dogrun_manager_id IS NOT NULL;
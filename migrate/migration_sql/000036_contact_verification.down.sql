@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS phone_verification_codes CASCADE;
+
+ALTER TABLE organizations DROP COLUMN IF EXISTS contact_email_verified_at;
+ALTER TABLE dogrun_manager_credentials DROP COLUMN IF EXISTS email_verified_at;
+ALTER TABLE dog_owner_credentials DROP COLUMN IF EXISTS phone_verified_at;
+ALTER TABLE dog_owner_credentials DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,22 @@
+-- 連絡先(メールアドレス・電話番号)の確認状態
+-- NULLの場合は未確認。確認後に連絡先が変わった場合は再確認が必要
+ALTER TABLE dog_owner_credentials ADD COLUMN IF NOT EXISTS email_verified_at timestamp;       -- メールアドレスの確認日時
+ALTER TABLE dog_owner_credentials ADD COLUMN IF NOT EXISTS phone_verified_at timestamp;       -- 電話番号の確認日時
+ALTER TABLE dogrun_manager_credentials ADD COLUMN IF NOT EXISTS email_verified_at timestamp;  -- メールアドレスの確認日時
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS contact_email_verified_at timestamp;        -- 連絡先メールアドレスの確認日時
+
+-- 電話番号確認用のワンタイムコード(SMSで送信)
+-- コードは平文で保存せず、ハッシュ値のみ保存する
+CREATE TABLE IF NOT EXISTS phone_verification_codes (
+    phone_verification_code_id serial primary key, -- PK
+    credential_id bigint not null,                 -- 確認するdog_owner_credentials
+    phone_number varchar(15) not null,             -- 送信先の電話番号
+    code_hash varchar(64) not null,                -- ワンタイムコードのハッシュ値(sha256)
+    attempts int not null default 0,               -- 入力の失敗回数
+    expires_at timestamp not null,                 -- 有効期限
+    used_at timestamp,                             -- 使用日時。再送信で無効にした場合も設定する
+    reg_at timestamp not null                      -- 登録日(送信日時)
+);
+
+CREATE INDEX IF NOT EXISTS idx_phone_verification_codes_credential_id
+ON phone_verification_codes (credential_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_dogruns_place_id;
@@ -0,0 +1,4 @@
+-- 同じplaceIdのドッグランが同時に作成されないよう、placeIdを一意にする
+-- (同時のクレーム・検索でそれぞれドッグランが作成され、審査待ちのクレームが重複するのを防ぐ)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dogruns_place_id
+ON dogruns (place_id) WHERE place_id IS NOT NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS google_place_caches;
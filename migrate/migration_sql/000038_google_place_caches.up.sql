@@ -0,0 +1,11 @@
+-- google place apiのレスポンスのキャッシュ(google.place.cache.backend=postgres)
+-- 複数インスタンスでキャッシュを共有するために使用する
+CREATE TABLE IF NOT EXISTS google_place_caches (
+    cache_key varchar(512) primary key,     -- キャッシュキー(リクエスト種別とパラメータ)
+    value bytea not null,                   -- レスポンス
+    expires_at timestamp not null,          -- 有効期限
+    upd_at timestamp not null               -- 更新日
+);
+
+CREATE INDEX IF NOT EXISTS idx_google_place_caches_expires_at
+ON google_place_caches (expires_at);
@@ -21,8 +21,39 @@ alter table auth_dogrun_managers drop constraint dev_auth_dogrun_managers_dogrun
 alter table dogrun_bookmarks drop constraint dev_dogrun_bookmarks_dogrun_id_fkey;
 alter table dogrun_bookmarks drop constraint dev_dogrun_bookmarks_dog_owner_id_fkey; 
 
-alter table dogrun_checkin drop constraint dev_dogrun_checkin_dogrun_id_fkey;
-alter table dogrun_checkin drop constraint dev_dogrun_checkin_dog_id_fkey; 
+alter table dogrun_visits drop constraint dev_dogrun_visits_dogrun_id_fkey;
+alter table dogrun_visits drop constraint dev_dogrun_visits_dog_id_fkey;
 
-alter table dogrun_checkout drop constraint dev_dogrun_checkout_dogrun_id_fkey;
-alter table dogrun_checkout drop constraint dev_dogrun_checkout_dog_id_fkey; 
\ No newline at end of file
+alter table dogrun_manager_invitations drop constraint dev_dogrun_manager_invitations_organization_id_fkey;
+alter table dogrun_manager_invitations drop constraint dev_dogrun_manager_invitations_invited_by_fkey;
+
+alter table dogrun_claims drop constraint dev_dogrun_claims_dogrun_id_fkey;
+alter table dogrun_claims drop constraint dev_dogrun_claims_dogrun_manager_id_fkey;
+alter table dogrun_claims drop constraint dev_dogrun_claims_organization_id_fkey;
+alter table dogrun_claim_histories drop constraint dev_dogrun_claim_histories_dogrun_claim_id_fkey;
+alter table injection_certifications drop constraint dev_injection_certifications_verified_by_fkey;
+
+alter table bookmark_collections drop constraint dev_bookmark_collections_dog_owner_id_fkey;
+alter table bookmark_collection_items drop constraint dev_bookmark_collection_items_bookmark_collection_id_fkey;
+alter table bookmark_collection_items drop constraint dev_bookmark_collection_items_dogrun_id_fkey;
+
+alter table dogrun_reviews drop constraint dev_dogrun_reviews_dogrun_id_fkey;
+alter table dogrun_reviews drop constraint dev_dogrun_reviews_dog_owner_id_fkey;
+alter table dogrun_review_replies drop constraint dev_dogrun_review_replies_dogrun_review_id_fkey;
+alter table dogrun_review_replies drop constraint dev_dogrun_review_replies_dogrun_manager_id_fkey;
+
+alter table dogrun_images drop constraint dev_dogrun_images_dog_owner_id_fkey;
+alter table dogrun_images drop constraint dev_dogrun_images_dogrun_manager_id_fkey;
+alter table dogrun_images drop constraint dev_dogrun_images_reviewed_by_fkey;
+alter table s3_file_info drop constraint dev_s3_file_info_dogrun_manager_id_fkey;
+
+alter table refresh_tokens drop constraint dev_refresh_tokens_dog_owner_id_fkey;
+alter table refresh_tokens drop constraint dev_refresh_tokens_dogrun_manager_id_fkey;
+
+alter table auth_sessions drop constraint dev_auth_sessions_dog_owner_id_fkey;
+alter table auth_sessions drop constraint dev_auth_sessions_dogrun_manager_id_fkey;
+
+alter table password_reset_tokens drop constraint dev_password_reset_tokens_dog_owner_id_fkey;
+alter table password_reset_tokens drop constraint dev_password_reset_tokens_dogrun_manager_id_fkey;
+
+alter table phone_verification_codes drop constraint dev_phone_verification_codes_credential_id_fkey;
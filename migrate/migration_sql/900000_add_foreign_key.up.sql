@@ -32,8 +32,40 @@ alter table auth_dogrun_managers add constraint dev_auth_dogrun_managers_dogrun_
 -- `auth_dogrun_managers`と`dogrun_manager_credentials`のリレーション
 alter table dogrun_manager_credentials add constraint dev_dogrun_manager_credentials_auth_dogrun_manager_id_fkey foreign key (auth_dogrun_manager_id) references auth_dogrun_managers (auth_dogrun_manager_id);
 
-alter table dogrun_checkin add constraint dev_dogrun_checkin_dogrun_id_fkey foreign key (dogrun_id) references dogruns (dogrun_id);
-alter table dogrun_checkin add constraint dev_dogrun_checkin_dog_id_fkey foreign key (dog_id) references dogs (dog_id);
+alter table dogrun_visits add constraint dev_dogrun_visits_dogrun_id_fkey foreign key (dogrun_id) references dogruns (dogrun_id);
+alter table dogrun_visits add constraint dev_dogrun_visits_dog_id_fkey foreign key (dog_id) references dogs (dog_id);
 
-alter table dogrun_checkout add constraint dev_dogrun_checkout_dogrun_id_fkey foreign key (dogrun_id) references dogruns (dogrun_id);
-alter table dogrun_checkout add constraint dev_dogrun_checkout_dog_id_fkey foreign key (dog_id) references dogs (dog_id);
\ No newline at end of file
+-- `organizations`と`dogrun_manager_invitations`のリレーション
+alter table dogrun_manager_invitations add constraint dev_dogrun_manager_invitations_organization_id_fkey foreign key (organization_id) references organizations (organization_id);
+alter table dogrun_manager_invitations add constraint dev_dogrun_manager_invitations_invited_by_fkey foreign key (invited_by) references dogrun_managers (dogrun_manager_id);
+
+alter table dogrun_claims add constraint dev_dogrun_claims_dogrun_id_fkey foreign key (dogrun_id) references dogruns (dogrun_id);
+alter table dogrun_claims add constraint dev_dogrun_claims_dogrun_manager_id_fkey foreign key (dogrun_manager_id) references dogrun_managers (dogrun_manager_id);
+alter table dogrun_claims add constraint dev_dogrun_claims_organization_id_fkey foreign key (organization_id) references organizations (organization_id);
+alter table dogrun_claim_histories add constraint dev_dogrun_claim_histories_dogrun_claim_id_fkey foreign key (dogrun_claim_id) references dogrun_claims (dogrun_claim_id);
+alter table injection_certifications add constraint dev_injection_certifications_verified_by_fkey foreign key (verified_by) references dogrun_managers (dogrun_manager_id);
+
+alter table bookmark_collections add constraint dev_bookmark_collections_dog_owner_id_fkey foreign key (dog_owner_id) references dog_owners (dog_owner_id);
+alter table bookmark_collection_items add constraint dev_bookmark_collection_items_bookmark_collection_id_fkey foreign key (bookmark_collection_id) references bookmark_collections (bookmark_collection_id);
+alter table bookmark_collection_items add constraint dev_bookmark_collection_items_dogrun_id_fkey foreign key (dogrun_id) references dogruns (dogrun_id);
+
+alter table dogrun_reviews add constraint dev_dogrun_reviews_dogrun_id_fkey foreign key (dogrun_id) references dogruns (dogrun_id);
+alter table dogrun_reviews add constraint dev_dogrun_reviews_dog_owner_id_fkey foreign key (dog_owner_id) references dog_owners (dog_owner_id);
+alter table dogrun_review_replies add constraint dev_dogrun_review_replies_dogrun_review_id_fkey foreign key (dogrun_review_id) references dogrun_reviews (dogrun_review_id);
+alter table dogrun_review_replies add constraint dev_dogrun_review_replies_dogrun_manager_id_fkey foreign key (dogrun_manager_id) references dogrun_managers (dogrun_manager_id);
+
+alter table dogrun_images add constraint dev_dogrun_images_dog_owner_id_fkey foreign key (dog_owner_id) references dog_owners (dog_owner_id);
+alter table dogrun_images add constraint dev_dogrun_images_dogrun_manager_id_fkey foreign key (dogrun_manager_id) references dogrun_managers (dogrun_manager_id);
+alter table dogrun_images add constraint dev_dogrun_images_reviewed_by_fkey foreign key (reviewed_by) references dogrun_managers (dogrun_manager_id);
+alter table s3_file_info add constraint dev_s3_file_info_dogrun_manager_id_fkey foreign key (dogrun_manager_id) references dogrun_managers (dogrun_manager_id);
+
+alter table refresh_tokens add constraint dev_refresh_tokens_dog_owner_id_fkey foreign key (dog_owner_id) references dog_owners (dog_owner_id);
+alter table refresh_tokens add constraint dev_refresh_tokens_dogrun_manager_id_fkey foreign key (dogrun_manager_id) references dogrun_managers (dogrun_manager_id);
+
+alter table auth_sessions add constraint dev_auth_sessions_dog_owner_id_fkey foreign key (dog_owner_id) references dog_owners (dog_owner_id);
+alter table auth_sessions add constraint dev_auth_sessions_dogrun_manager_id_fkey foreign key (dogrun_manager_id) references dogrun_managers (dogrun_manager_id);
+
+alter table password_reset_tokens add constraint dev_password_reset_tokens_dog_owner_id_fkey foreign key (dog_owner_id) references dog_owners (dog_owner_id);
+alter table password_reset_tokens add constraint dev_password_reset_tokens_dogrun_manager_id_fkey foreign key (dogrun_manager_id) references dogrun_managers (dogrun_manager_id);
+
+alter table phone_verification_codes add constraint dev_phone_verification_codes_credential_id_fkey foreign key (credential_id) references dog_owner_credentials (credential_id);
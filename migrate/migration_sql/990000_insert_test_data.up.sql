@@ -6,19 +6,19 @@ INSERT INTO dog_owners (name, image, sex, reg_at, upd_at) VALUES
 ('William Taylor', 'https://example.com/images/william.jpg', 'M', NOW(), NOW());
 
 -- auth_dog_ownersテーブルにデータを挿入
-INSERT INTO auth_dog_owners (dog_owner_id, access_token, refresh_token, access_token_expiration, refresh_token_expiration, jwt_id, si_refresh_token, login_at) VALUES
-(1, 'access_token_1', 'refresh_token_1', NOW() + INTERVAL '1 hour', NOW() + INTERVAL '7 days', 'session1', 'si_refresh_token_1', NOW()),
-(2, NULL, NULL, NULL, NULL, 'session2', 'si_refresh_token_2', NOW()),
-(3, NULL, NULL, NULL, NULL, 'session3', 'si_refresh_token_3', NOW()),
-(4, 'access_token_4', 'refresh_token_4', NOW() + INTERVAL '1 hour', NOW() + INTERVAL '7 days', 'session4', 'si_refresh_token_4', NOW());
+INSERT INTO auth_dog_owners (dog_owner_id, access_token, refresh_token, access_token_expiration, refresh_token_expiration, si_refresh_token, login_at) VALUES
+(1, 'access_token_1', 'refresh_token_1', NOW() + INTERVAL '1 hour', NOW() + INTERVAL '7 days', 'si_refresh_token_1', NOW()),
+(2, NULL, NULL, NULL, NULL, 'si_refresh_token_2', NOW()),
+(3, NULL, NULL, NULL, NULL, 'si_refresh_token_3', NOW()),
+(4, 'access_token_4', 'refresh_token_4', NOW() + INTERVAL '1 hour', NOW() + INTERVAL '7 days', 'si_refresh_token_4', NOW());
 
 -- dog_owner_credentialsテーブルにデータを挿入
-INSERT INTO dog_owner_credentials (auth_dog_owner_id, provider_name, grant_type, email, phone_number, provider_user_id, password, login_at) VALUES
-(1, 'google', 'oauth', 'emily@example.com', NULL, 'google_user_1', NULL, NOW()),
-(1, 'facebook', 'oauth', 'emily@example.com', NULL, 'facebook_user_2', NULL, NOW()),
-(2, NULL, 'PASSWORD', 'olivia@example.com', NULL, NULL, '$2a$10$dfdZ5z74pRE2.7RzwSmHtuU7x1Ir8ul0nD/jwakDg/Pd5uE8/f36C', NOW()),
-(3, NULL, 'PASSWORD', NULL, '0987654321', NULL, 'password_hash_3', NOW()),
-(4, 'google', 'oauth', 'dev@example.com', NULL, 'google_user_4', NULL, NOW());
+INSERT INTO dog_owner_credentials (auth_dog_owner_id, provider_name, grant_type, email, phone_number, provider_user_id, password, email_verified_at, phone_verified_at, login_at) VALUES
+(1, 'google', 'oauth', 'emily@example.com', NULL, 'google_user_1', NULL, NOW(), NULL, NOW()),
+(1, 'facebook', 'oauth', 'emily@example.com', NULL, 'facebook_user_2', NULL, NOW(), NULL, NOW()),
+(2, NULL, 'PASSWORD', 'olivia@example.com', NULL, NULL, '$2a$10$dfdZ5z74pRE2.7RzwSmHtuU7x1Ir8ul0nD/jwakDg/Pd5uE8/f36C', NOW(), NULL, NOW()),
+(3, NULL, 'PASSWORD', NULL, '0987654321', NULL, 'password_hash_3', NULL, NULL, NOW()),
+(4, 'google', 'oauth', 'dev@example.com', NULL, 'google_user_4', NULL, NOW(), NULL, NOW());
 
 -- dogs テーブルに追加のテストデータを挿入
 INSERT INTO dogs (dog_owner_id, name, dog_type_id, weight, sex, image, reg_at, upd_at) VALUES
@@ -80,13 +80,13 @@ INSERT INTO special_business_hours (dogrun_id, date, open_time, close_time, is_c
 (4, '2024-10-15', null, null, TRUE, FALSE, NOW(), NOW());  -- 臨時休業
 
 -- dogrun_images テーブルに追加のテストデータを挿入
-INSERT INTO dogrun_images (dogrun_id, image, sort_order, upload_at) VALUES
-(1, 'https://example.com/images/city_dog_park1.jpg', 1, NOW()),
-(1, 'https://example.com/images/city_dog_park2.jpg', 2, NOW()),
-(2, 'https://example.com/images/pawsome_adventure1.jpg', 1, NOW()),
-(2, 'https://example.com/images/pawsome_adventure2.jpg', 2, NOW()),
-(3, 'https://example.com/images/happy_tails1.jpg', 1, NOW()),
-(3, 'https://example.com/images/happy_tails2.jpg', 2, NOW());
+INSERT INTO dogrun_images (dogrun_id, image, sort_order, upload_at, upd_at) VALUES
+(1, 'https://example.com/images/city_dog_park1.jpg', 1, NOW(), NOW()),
+(1, 'https://example.com/images/city_dog_park2.jpg', 2, NOW(), NOW()),
+(2, 'https://example.com/images/pawsome_adventure1.jpg', 1, NOW(), NOW()),
+(2, 'https://example.com/images/pawsome_adventure2.jpg', 2, NOW(), NOW()),
+(3, 'https://example.com/images/happy_tails1.jpg', 1, NOW(), NOW()),
+(3, 'https://example.com/images/happy_tails2.jpg', 2, NOW(), NOW());
 
 -- dogrun_tags テーブルに追加のテストデータを挿入
 INSERT INTO dogrun_tags (dogrun_id, tag_id) VALUES
@@ -67,6 +67,17 @@ func SetLogger(l *zap.Logger) {
 	gLogger = l
 }
 
+/*
+リクエストコンテキストのない処理(バックグラウンド処理など)用のloggerを取得
+未設定の場合は何も出力しないloggerを返す
+*/
+func GetBaseLogger() *zap.Logger {
+	if gLogger == nil {
+		return zap.NewNop()
+	}
+	return gLogger
+}
+
 func NewWanRunLogger() *zap.Logger {
 	level := zap.NewAtomicLevel()
 	// ログレベルを文字列から設定
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ページングのカーソル(オフセットのみ)
+type offsetCursor struct {
+	Offset int `json:"o"`
+}
+
+// EncodeOffsetCursor: 次のページのオフセットをカーソル文字列に変換する
+// Args:
+//
+//	int: 次のページのオフセット
+//
+// Returns:
+//
+//	string: URLセーフなbase64文字列
+func EncodeOffsetCursor(offset int) string {
+	b, _ := json.Marshal(offsetCursor{Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeOffsetCursor: カーソル文字列をオフセットに変換する。空文字の場合は先頭(0)
+// Args:
+//
+//	string: 前回レスポンスのカーソル
+//
+// Returns:
+//
+//	int: オフセット
+//	error: 不正なカーソルの場合のエラー情報
+func DecodeOffsetCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	oc := offsetCursor{}
+	if err == nil {
+		err = json.Unmarshal(b, &oc)
+	}
+	if err == nil && oc.Offset < 0 {
+		err = errors.New("negative cursor offset")
+	}
+	if err != nil {
+		return 0, err
+	}
+	return oc.Offset, nil
+}
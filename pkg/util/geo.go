@@ -0,0 +1,22 @@
+package util
+
+import "math"
+
+// 地球の半径(m)
+const EARTH_RADIUS_METER = 6371000.0
+
+/*
+2点間の距離(m)をhaversine式で算出
+*/
+func CalcDistanceMeter(lat1, lng1, lat2, lng2 float64) float64 {
+	rLat1, rLat2 := toRadian(lat1), toRadian(lat2)
+	dLat := rLat2 - rLat1
+	dLng := toRadian(lng2 - lng1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rLat1)*math.Cos(rLat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EARTH_RADIUS_METER * math.Asin(math.Sqrt(math.Min(1, a)))
+}
+
+func toRadian(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -1,7 +1,11 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"strings"
 	"time"
 
@@ -135,3 +139,33 @@ func ConvertStringPointer(ptr *string) string {
 	}
 	return *ptr
 }
+
+// GenerateRandomToken: 推測不可能なランダムトークンを生成する
+// Args:
+//
+//	int: 生成するバイト数
+//
+// Returns:
+//
+//	string: URLセーフなbase64文字列
+//	error: エラー情報
+func GenerateRandomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken: トークンをDB保存用にハッシュ化する(sha256)
+// Args:
+//
+//	string: ハッシュ化するトークン
+//
+// Returns:
+//
+//	string: 16進数のハッシュ文字列(64文字)
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}